@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"net"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,6 +14,7 @@ import (
 	"we_ride/api/internal/clients"
 	"we_ride/api/internal/config"
 	"we_ride/api/internal/router"
+	"we_ride/internal/pkg/jwks"
 	"we_ride/internal/pkg/logger"
 )
 
@@ -27,7 +29,7 @@ func main() {
 		logger.GetLoggerFromCtx(ctx).Fatal(ctx, "failed to load config", zap.Error(err))
 	}
 
-	userClient, err := clients.NewUserServiceClient(cfg.UserServiceAddr)
+	userClient, err := clients.NewUserServiceClient(cfg.UserServiceAddr, cfg.GatewaySecret)
 	if err != nil {
 		logger.GetLoggerFromCtx(ctx).Fatal(ctx, "failed to create user gRPC client", zap.Error(err))
 	}
@@ -45,8 +47,26 @@ func main() {
 	}
 	defer paymentClient.Close()
 
+	keys := jwks.NewCache(cfg.JWKSURL, cfg.JWKSRefresh)
+	if err := keys.Start(ctx); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to load JWKS, will retry on demand", zap.Error(err))
+	}
+
 	e := echo.New()
-	router.InitRoutes(e, userClient, roomClient, paymentClient, []byte(cfg.JWTSecret))
+	// Заголовкам X-Forwarded-For и X-Real-IP верим только от своих прокси, иначе клиент подставит любой IP
+	e.IPExtractor = echo.ExtractIPDirect()
+	if len(cfg.TrustedProxies) > 0 {
+		trust := []echo.TrustOption{echo.TrustLoopback(false), echo.TrustLinkLocal(false), echo.TrustPrivateNet(false)}
+		for _, cidr := range cfg.TrustedProxies {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				logger.GetLoggerFromCtx(ctx).Fatal(ctx, "invalid TRUSTED_PROXIES", zap.String("cidr", cidr), zap.Error(err))
+			}
+			trust = append(trust, echo.TrustIPRange(ipNet))
+		}
+		e.IPExtractor = echo.ExtractIPFromXFFHeader(trust...)
+	}
+	router.InitRoutes(e, userClient, roomClient, paymentClient, keys)
 
 	go func() {
 		logger.GetLoggerFromCtx(ctx).Info(ctx, "starting HTTP server", zap.String("addr", cfg.RestPort))
@@ -8,6 +8,8 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+
+	"we_ride/internal/pkg/identity"
 )
 
 type PaymentServiceClient struct {
@@ -16,7 +18,11 @@ type PaymentServiceClient struct {
 }
 
 func NewPaymentServiceClient(addr string) (*PaymentServiceClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(identity.StreamClientInterceptor()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("fail to create grpc client for payment service: %v", err)
 	}
@@ -52,3 +58,140 @@ func (p *PaymentServiceClient) GetPaymentHistory(ctx context.Context, req *pb.Ge
 	}
 	return resp, nil
 }
+
+func (p *PaymentServiceClient) SearchPayments(ctx context.Context, req *pb.SearchPaymentsRequest) (*pb.SearchPaymentsResponse, error) {
+	resp, err := p.client.SearchPayments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("SearchPayments: %w", err)
+	}
+	return resp, nil
+}
+
+// StreamPaymentUpdates открывает стрим обновлений платежей пользователя из токена контекста
+func (p *PaymentServiceClient) StreamPaymentUpdates(ctx context.Context) (grpc.ServerStreamingClient[pb.PaymentUpdate], error) {
+	stream, err := p.client.StreamPaymentUpdates(ctx, &pb.StreamPaymentUpdatesRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("StreamPaymentUpdates: %w", err)
+	}
+	return stream, nil
+}
+
+func (p *PaymentServiceClient) ListPaymentMethods(ctx context.Context, req *pb.ListPaymentMethodsRequest) (*pb.ListPaymentMethodsResponse, error) {
+	resp, err := p.client.ListPaymentMethods(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ListPaymentMethods: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) AddPaymentMethod(ctx context.Context, req *pb.AddPaymentMethodRequest) (*pb.AddPaymentMethodResponse, error) {
+	resp, err := p.client.AddPaymentMethod(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("AddPaymentMethod: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) RemovePaymentMethod(ctx context.Context, req *pb.RemovePaymentMethodRequest) (*pb.RemovePaymentMethodResponse, error) {
+	resp, err := p.client.RemovePaymentMethod(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("RemovePaymentMethod: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) SetDefaultPaymentMethod(ctx context.Context, req *pb.SetDefaultPaymentMethodRequest) (*pb.SetDefaultPaymentMethodResponse, error) {
+	resp, err := p.client.SetDefaultPaymentMethod(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("SetDefaultPaymentMethod: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) GetWallet(ctx context.Context, req *pb.GetWalletRequest) (*pb.GetWalletResponse, error) {
+	resp, err := p.client.GetWallet(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetWallet: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) GetDriverEarnings(ctx context.Context, req *pb.GetDriverEarningsRequest) (*pb.GetDriverEarningsResponse, error) {
+	resp, err := p.client.GetDriverEarnings(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetDriverEarnings: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) RetryFailedPayments(ctx context.Context, req *pb.RetryFailedPaymentsRequest) (*pb.RetryFailedPaymentsResponse, error) {
+	resp, err := p.client.RetryFailedPayments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("RetryFailedPayments: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) GetRoomPayments(ctx context.Context, req *pb.GetRoomPaymentsRequest) (*pb.GetRoomPaymentsResponse, error) {
+	resp, err := p.client.GetRoomPayments(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetRoomPayments: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) SetReceiptContact(ctx context.Context, req *pb.SetReceiptContactRequest) (*pb.SetReceiptContactResponse, error) {
+	resp, err := p.client.SetReceiptContact(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("SetReceiptContact: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) GetReceipt(ctx context.Context, req *pb.GetReceiptRequest) (*pb.GetReceiptResponse, error) {
+	resp, err := p.client.GetReceipt(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetReceipt: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) RequestStatement(ctx context.Context, req *pb.RequestStatementRequest) (*pb.RequestStatementResponse, error) {
+	resp, err := p.client.RequestStatement(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("RequestStatement: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	resp, err := p.client.GetStatement(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetStatement: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) DownloadStatement(ctx context.Context, req *pb.DownloadStatementRequest) (*pb.DownloadStatementResponse, error) {
+	resp, err := p.client.DownloadStatement(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("DownloadStatement: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) CreatePromoCode(ctx context.Context, req *pb.CreatePromoCodeRequest) (*pb.CreatePromoCodeResponse, error) {
+	resp, err := p.client.CreatePromoCode(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CreatePromoCode: %w", err)
+	}
+	return resp, nil
+}
+
+func (p *PaymentServiceClient) ListPromoCodes(ctx context.Context, req *pb.ListPromoCodesRequest) (*pb.ListPromoCodesResponse, error) {
+	resp, err := p.client.ListPromoCodes(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ListPromoCodes: %w", err)
+	}
+	return resp, nil
+}
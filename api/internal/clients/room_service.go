@@ -8,6 +8,8 @@ import (
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+
+	"we_ride/internal/pkg/identity"
 )
 
 type RoomServiceClient struct {
@@ -16,7 +18,10 @@ type RoomServiceClient struct {
 }
 
 func NewRoomServiceClient(addr string) (*RoomServiceClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("fail to create grpc client for room service: %v", err)
 	}
@@ -69,11 +74,50 @@ func (r *RoomServiceClient) GetRoomDetails(ctx context.Context, req *pb.GetRoomD
 	return resp, nil
 }
 
-// CompleteRide вызывает метод через прямой Invoke, т.к. он не входит в сгенерированный pb клиент
+func (r *RoomServiceClient) StartRide(ctx context.Context, req *pb.StartRideRequest) (*pb.StartRideResponse, error) {
+	resp, err := r.client.StartRide(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("StartRide: %w", err)
+	}
+	return resp, nil
+}
+
 func (r *RoomServiceClient) CompleteRide(ctx context.Context, req *pb.CompleteRideRequest) (*pb.CompleteRideResponse, error) {
-	resp, err := pb.CompleteRideClient(r.conn, ctx, req)
+	resp, err := r.client.CompleteRide(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("CompleteRide: %w", err)
 	}
 	return resp, nil
 }
+
+func (r *RoomServiceClient) ApplyPromoCode(ctx context.Context, req *pb.ApplyPromoCodeRequest) (*pb.ApplyPromoCodeResponse, error) {
+	resp, err := r.client.ApplyPromoCode(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("ApplyPromoCode: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *RoomServiceClient) SearchRooms(ctx context.Context, req *pb.SearchRoomsRequest) (*pb.SearchRoomsResponse, error) {
+	resp, err := r.client.SearchRooms(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("SearchRooms: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *RoomServiceClient) CancelRoom(ctx context.Context, req *pb.CancelRoomRequest) (*pb.CancelRoomResponse, error) {
+	resp, err := r.client.CancelRoom(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("CancelRoom: %w", err)
+	}
+	return resp, nil
+}
+
+func (r *RoomServiceClient) GetRoomTimeline(ctx context.Context, req *pb.GetRoomTimelineRequest) (*pb.GetRoomTimelineResponse, error) {
+	resp, err := r.client.GetRoomTimeline(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("GetRoomTimeline: %w", err)
+	}
+	return resp, nil
+}
@@ -5,30 +5,43 @@ import (
 	"fmt"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"we_ride/internal/pkg/identity"
 	pb "we_ride/internal/services/user_service/protoc/gen/go"
 )
 
 type UserServiceClient struct {
 	client pb.AuthClient
 	conn   *grpc.ClientConn
+	secret string // подтверждает user_service, что x-real-ip выставил gateway
 }
 
-func NewUserServiceClient(addr string) (*UserServiceClient, error) {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func NewUserServiceClient(addr, gatewaySecret string) (*UserServiceClient, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
 	if err != nil {
 		return nil, fmt.Errorf("fail to create grpc client: %v", err)
 	}
 	client := pb.NewAuthClient(conn)
-	return &UserServiceClient{client, conn}, nil
+	return &UserServiceClient{client, conn, gatewaySecret}, nil
 }
 
-func (u *UserServiceClient) Login(ctx context.Context, email string, password string) (string, error) {
+// withClientIP передаёт IP клиента в метаданных: user_service считает по нему неудачные проверки пароля.
+// Без секрета gateway user_service метаданным не верит и берёт адрес соединения.
+func (u *UserServiceClient) withClientIP(ctx context.Context, clientIP string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-real-ip", clientIP, "x-gateway-secret", u.secret)
+}
+
+func (u *UserServiceClient) Login(ctx context.Context, email, password, clientIP string) (string, error) {
+	ctx = u.withClientIP(ctx, clientIP)
 	resp, err := u.client.Login(ctx, &pb.LoginRequest{
 		Email:    email,
 		Password: password,
 	})
 	if err != nil {
-		return "", fmt.Errorf("fail to login: %v", err)
+		return "", fmt.Errorf("fail to login: %w", err)
 	}
 	return resp.GetToken(), nil
 }
@@ -41,6 +54,59 @@ func (u *UserServiceClient) HistoryOfRoutes(ctx context.Context) (*pb.HistoryOfR
 	return u.client.HistoryOfRoutes(ctx, &pb.HistoryOfRoutesRequest{})
 }
 
+func (u *UserServiceClient) VerifyEmail(ctx context.Context, token string) (*pb.VerifyEmailResponse, error) {
+	return u.client.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: token})
+}
+
+func (u *UserServiceClient) ResendVerification(ctx context.Context) (*pb.ResendVerificationResponse, error) {
+	return u.client.ResendVerification(ctx, &pb.ResendVerificationRequest{})
+}
+
+func (u *UserServiceClient) RequestPasswordReset(ctx context.Context, email string) (*pb.RequestPasswordResetResponse, error) {
+	return u.client.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: email})
+}
+
+func (u *UserServiceClient) ResetPassword(ctx context.Context, token, newPassword string) (*pb.ResetPasswordResponse, error) {
+	return u.client.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: token, NewPassword: newPassword})
+}
+
+func (u *UserServiceClient) GetProfile(ctx context.Context) (*pb.GetProfileResponse, error) {
+	return u.client.GetProfile(ctx, &pb.GetProfileRequest{})
+}
+
+func (u *UserServiceClient) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
+	return u.client.UpdateProfile(ctx, req)
+}
+
+func (u *UserServiceClient) ChangePassword(ctx context.Context, currentPassword, newPassword, clientIP string) (*pb.ChangePasswordResponse, error) {
+	return u.client.ChangePassword(u.withClientIP(ctx, clientIP), &pb.ChangePasswordRequest{CurrentPassword: currentPassword, NewPassword: newPassword})
+}
+
+func (u *UserServiceClient) DeleteAccount(ctx context.Context, password, clientIP string) (*pb.DeleteAccountResponse, error) {
+	return u.client.DeleteAccount(u.withClientIP(ctx, clientIP), &pb.DeleteAccountRequest{Password: password})
+}
+
+func (u *UserServiceClient) SetUserRole(ctx context.Context, userID, role string) (*pb.SetUserRoleResponse, error) {
+	return u.client.SetUserRole(ctx, &pb.SetUserRoleRequest{UserId: userID, Role: role})
+}
+
+func (u *UserServiceClient) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	return u.client.SearchUsers(ctx, req)
+}
+
+func (u *UserServiceClient) BlockUser(ctx context.Context, userID string, blocked bool, reason string) (*pb.BlockUserResponse, error) {
+	return u.client.BlockUser(ctx, &pb.BlockUserRequest{UserId: userID, Blocked: blocked, Reason: reason})
+}
+
+func (u *UserServiceClient) RecordAuditEvent(ctx context.Context, req *pb.RecordAuditEventRequest) error {
+	_, err := u.client.RecordAuditEvent(ctx, req)
+	return err
+}
+
+func (u *UserServiceClient) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	return u.client.ListAuditEvents(ctx, req)
+}
+
 func (u *UserServiceClient) Close() {
 	if u.conn != nil {
 		_ = u.conn.Close()
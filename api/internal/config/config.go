@@ -2,16 +2,24 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type Config struct {
-	JWTSecret          string `yaml:"JWT_SECRET"           env:"JWT_SECRET"           env-default:"your_secret_key_here"`
-	UserServiceAddr    string `yaml:"USER_SERVICE_ADDR"    env:"USER_SERVICE_ADDR"    env-default:"localhost:50052"`
-	RoomServiceAddr    string `yaml:"ROOM_SERVICE_ADDR"    env:"ROOM_SERVICE_ADDR"    env-default:"localhost:50051"`
-	PaymentServiceAddr string `yaml:"PAYMENT_SERVICE_ADDR" env:"PAYMENT_SERVICE_ADDR" env-default:"localhost:50053"`
-	RestPort           string `yaml:"REST_PORT"            env:"REST_PORT"            env-default:"8080"`
+	JWKSURL            string        `yaml:"JWKS_URL"              env:"JWKS_URL"              env-default:"http://localhost:8082/.well-known/jwks.json"`
+	JWKSRefresh        time.Duration `yaml:"JWKS_REFRESH_INTERVAL" env:"JWKS_REFRESH_INTERVAL" env-default:"5m"`
+	UserServiceAddr    string        `yaml:"USER_SERVICE_ADDR"     env:"USER_SERVICE_ADDR"     env-default:"localhost:50052"`
+	RoomServiceAddr    string        `yaml:"ROOM_SERVICE_ADDR"     env:"ROOM_SERVICE_ADDR"     env-default:"localhost:50051"`
+	PaymentServiceAddr string        `yaml:"PAYMENT_SERVICE_ADDR"  env:"PAYMENT_SERVICE_ADDR"  env-default:"localhost:50053"`
+	RestPort           string        `yaml:"REST_PORT"             env:"REST_PORT"             env-default:"8080"`
+
+	// TrustedProxies — подсети прокси перед gateway (CIDR через запятую). Только от них принимается
+	// X-Forwarded-For; пустое значение — IP клиента берётся из адреса соединения.
+	TrustedProxies []string `yaml:"TRUSTED_PROXIES" env:"TRUSTED_PROXIES"`
+	// GatewaySecret — общий с user_service секрет, подтверждающий переданный в метаданных IP клиента
+	GatewaySecret string `yaml:"GATEWAY_SECRET" env:"GATEWAY_SECRET"`
 }
 
 func New() (*Config, error) {
@@ -0,0 +1,273 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb_payment "we_ride/internal/services/payment_service/pb"
+	pb_room "we_ride/internal/services/room_service/pb"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+// ===== Admin =====
+// Все маршруты группы /admin проходят через middlewares.Audit и попадают в журнал.
+// Общие query-параметры поиска: from, to (RFC3339) и limit.
+
+// adminError переводит gRPC-ошибку сервиса в HTTP-ответ консоли поддержки
+func adminError(c echo.Context, err error, msg string) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.PermissionDenied:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
+	}
+}
+
+func queryLimit(c echo.Context) int32 {
+	n, _ := strconv.Atoi(c.QueryParam("limit"))
+	return int32(n)
+}
+
+// userIDsByEmail находит id пользователей по подстроке email: по email ищут жалобы в поддержке
+func (h *APIHandler) userIDsByEmail(c echo.Context, email string) ([]string, error) {
+	resp, err := h.userService.SearchUsers(c.Request().Context(), &pb.SearchUsersRequest{Email: email, Limit: 200})
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0, len(resp.Users))
+	for _, u := range resp.Users {
+		ids = append(ids, u.UserId)
+	}
+	return ids, nil
+}
+
+// SearchUsers — GET /admin/users?id=&email=&from=&to=&limit=
+func (h *APIHandler) SearchUsers(c echo.Context) error {
+	resp, err := h.userService.SearchUsers(c.Request().Context(), &pb.SearchUsersRequest{
+		UserId:      c.QueryParam("id"),
+		Email:       c.QueryParam("email"),
+		CreatedFrom: c.QueryParam("from"),
+		CreatedTo:   c.QueryParam("to"),
+		Limit:       queryLimit(c),
+	})
+	if err != nil {
+		return adminError(c, err, "Failed to search users")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// BlockUser — POST /admin/users/:id/block
+// Body: { "blocked": true, "reason": "..." }, blocked=false снимает блокировку
+func (h *APIHandler) BlockUser(c echo.Context) error {
+	body := struct {
+		Blocked *bool  `json:"blocked"`
+		Reason  string `json:"reason"`
+	}{}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	blocked := body.Blocked == nil || *body.Blocked
+	resp, err := h.userService.BlockUser(c.Request().Context(), c.Param("id"), blocked, body.Reason)
+	if err != nil {
+		return adminError(c, err, "Failed to block user")
+	}
+	return c.JSON(http.StatusOK, resp.Profile)
+}
+
+// SearchRooms — GET /admin/rooms?id=&user_id=&email=&status=&from=&to=&limit=
+// status — имя без префикса: waiting, full, completed, cancelled
+func (h *APIHandler) SearchRooms(c echo.Context) error {
+	req := &pb_room.SearchRoomsRequest{
+		RoomId: c.QueryParam("id"),
+		UserId: c.QueryParam("user_id"),
+		Limit:  queryLimit(c),
+	}
+	if s := c.QueryParam("status"); s != "" {
+		v, ok := pb_room.RoomStatus_value["ROOM_STATUS_"+strings.ToUpper(s)]
+		if !ok {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "unknown status"})
+		}
+		req.Status = pb_room.RoomStatus(v)
+	}
+	for param, dst := range map[string]**timestamppb.Timestamp{"from": &req.CreatedFrom, "to": &req.CreatedTo} {
+		if v := c.QueryParam(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": param + " must be RFC3339"})
+			}
+			*dst = timestamppb.New(t)
+		}
+	}
+	if email := c.QueryParam("email"); email != "" && req.UserId == "" {
+		ids, err := h.userIDsByEmail(c, email)
+		if err != nil {
+			return adminError(c, err, "Failed to search users")
+		}
+		switch len(ids) {
+		case 0:
+			return c.JSON(http.StatusOK, &pb_room.SearchRoomsResponse{})
+		case 1:
+			req.UserId = ids[0]
+		default:
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "email matches several users, use user_id"})
+		}
+	}
+
+	resp, err := h.roomService.SearchRooms(c.Request().Context(), req)
+	if err != nil {
+		return adminError(c, err, "Failed to search rooms")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CancelRoom — POST /admin/rooms/:id/cancel
+// Body: { "reason": "..." }
+func (h *APIHandler) CancelRoom(c echo.Context) error {
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.roomService.CancelRoom(c.Request().Context(), &pb_room.CancelRoomRequest{RoomId: c.Param("id"), Reason: body.Reason})
+	if err != nil {
+		return adminError(c, err, "Failed to cancel room")
+	}
+	return c.JSON(http.StatusOK, resp.Room)
+}
+
+// timelineEntry — событие комнаты или платёж в общей хронологии
+type timelineEntry struct {
+	At      time.Time `json:"at"`
+	Source  string    `json:"source"` // room | payment
+	Type    string    `json:"type"`
+	ActorID string    `json:"actor_id,omitempty"`
+	Details string    `json:"details,omitempty"`
+}
+
+// GetRoomTimeline — GET /admin/rooms/:id/timeline
+// Склеивает события room_service и платежи комнаты из payment_service в одну ленту по времени
+func (h *APIHandler) GetRoomTimeline(c echo.Context) error {
+	ctx := c.Request().Context()
+	roomID := c.Param("id")
+	room, err := h.roomService.GetRoomTimeline(ctx, &pb_room.GetRoomTimelineRequest{RoomId: roomID})
+	if err != nil {
+		return adminError(c, err, "Failed to get room timeline")
+	}
+	payments, err := h.paymentService.SearchPayments(ctx, &pb_payment.SearchPaymentsRequest{RoomId: roomID, Limit: 200})
+	if err != nil {
+		return adminError(c, err, "Failed to get room payments")
+	}
+
+	entries := make([]timelineEntry, 0, len(room.Events)+len(payments.Payments))
+	for _, e := range room.Events {
+		entries = append(entries, timelineEntry{
+			At:      e.CreatedAt.AsTime(),
+			Source:  "room",
+			Type:    e.Type,
+			ActorID: e.ActorId,
+			Details: e.Details,
+		})
+	}
+	for _, p := range payments.Payments {
+		entries = append(entries, timelineEntry{
+			At:      p.CreatedAt.AsTime(),
+			Source:  "payment",
+			Type:    "payment_" + p.Status,
+			ActorID: p.UserId,
+			Details: strconv.FormatFloat(float64(p.Amount), 'f', 2, 32) + " " + p.Currency + " " + p.PaymentId,
+		})
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].At.Before(entries[j].At) })
+
+	return c.JSON(http.StatusOK, map[string]any{
+		"room":     room.Room,
+		"members":  room.Members,
+		"payments": payments.Payments,
+		"timeline": entries,
+	})
+}
+
+// SearchPayments — GET /admin/payments?id=&room_id=&user_id=&email=&status=&from=&to=&limit=
+func (h *APIHandler) SearchPayments(c echo.Context) error {
+	req := &pb_payment.SearchPaymentsRequest{
+		PaymentId:   c.QueryParam("id"),
+		RoomId:      c.QueryParam("room_id"),
+		Status:      c.QueryParam("status"),
+		CreatedFrom: c.QueryParam("from"),
+		CreatedTo:   c.QueryParam("to"),
+		Limit:       queryLimit(c),
+	}
+	if userID := c.QueryParam("user_id"); userID != "" {
+		req.UserIds = []string{userID}
+	} else if email := c.QueryParam("email"); email != "" {
+		ids, err := h.userIDsByEmail(c, email)
+		if err != nil {
+			return adminError(c, err, "Failed to search users")
+		}
+		if len(ids) == 0 {
+			return c.JSON(http.StatusOK, &pb_payment.SearchPaymentsResponse{})
+		}
+		req.UserIds = ids
+	}
+
+	resp, err := h.paymentService.SearchPayments(c.Request().Context(), req)
+	if err != nil {
+		return adminError(c, err, "Failed to search payments")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreatePromoCode — POST /admin/promo-codes
+// Body: { "code": "WELCOME", "type": "percent|fixed", "value": 20, "currency": "RUB", "max_uses": 100,
+// "max_uses_per_user": 1, "first_ride_only": true, "valid_from": "RFC3339", "valid_until": "RFC3339" }
+func (h *APIHandler) CreatePromoCode(c echo.Context) error {
+	var req pb_payment.CreatePromoCodeRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.paymentService.CreatePromoCode(c.Request().Context(), &req)
+	if err != nil {
+		return adminError(c, err, "Failed to create promo code")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListPromoCodes — GET /admin/promo-codes
+func (h *APIHandler) ListPromoCodes(c echo.Context) error {
+	resp, err := h.paymentService.ListPromoCodes(c.Request().Context(), &pb_payment.ListPromoCodesRequest{})
+	if err != nil {
+		return adminError(c, err, "Failed to list promo codes")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListAuditEvents — GET /admin/audit?actor_id=&target=&from=&to=&limit=
+func (h *APIHandler) ListAuditEvents(c echo.Context) error {
+	resp, err := h.userService.ListAuditEvents(c.Request().Context(), &pb.ListAuditEventsRequest{
+		ActorId: c.QueryParam("actor_id"),
+		Target:  c.QueryParam("target"),
+		From:    c.QueryParam("from"),
+		To:      c.QueryParam("to"),
+		Limit:   queryLimit(c),
+	})
+	if err != nil {
+		return adminError(c, err, "Failed to list audit events")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
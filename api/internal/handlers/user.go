@@ -1,11 +1,16 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
-	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"we_ride/api/internal/clients"
 	pb_payment "we_ride/internal/services/payment_service/pb"
@@ -63,20 +68,159 @@ func (h *APIHandler) Login(c echo.Context) error {
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 	}
-	token, err := h.userService.Login(c.Request().Context(), req.Email, req.Password)
+	token, err := h.userService.Login(c.Request().Context(), req.Email, req.Password, c.RealIP())
 	if err != nil {
+		if status.Code(err) == codes.ResourceExhausted {
+			return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many failed login attempts, try again later"})
+		}
 		return c.JSON(http.StatusUnauthorized, map[string]string{"error": "Invalid credentials"})
 	}
 	return c.JSON(http.StatusOK, map[string]string{"token": token})
 }
 
-func (h *APIHandler) HistoryOfRoutes(c echo.Context) error {
-	userID, err := getUserIDFromCtx(c)
+// VerifyEmail — POST /auth/verify-email
+// Body: { "token": "..." }
+func (h *APIHandler) VerifyEmail(c echo.Context) error {
+	var req pb.VerifyEmailRequest
+	if err := c.Bind(&req); err != nil || req.Token == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "token is required"})
+	}
+	resp, err := h.userService.VerifyEmail(c.Request().Context(), req.Token)
 	if err != nil {
-		return err
+		if status.Code(err) == codes.InvalidArgument {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid or expired token"})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to verify email"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ResendVerification — POST /auth/verify-email/resend
+func (h *APIHandler) ResendVerification(c echo.Context) error {
+	resp, err := h.userService.ResendVerification(c.Request().Context())
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to send verification email"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RequestPasswordReset — POST /auth/password-reset/request
+// Body: { "email": "..." }. Ответ одинаковый для существующих и несуществующих email.
+func (h *APIHandler) RequestPasswordReset(c echo.Context) error {
+	var req pb.RequestPasswordResetRequest
+	if err := c.Bind(&req); err != nil || req.Email == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "email is required"})
+	}
+	if _, err := h.userService.RequestPasswordReset(c.Request().Context(), req.Email); err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to request password reset"})
+	}
+	return c.NoContent(http.StatusAccepted)
+}
+
+// ResetPassword — POST /auth/password-reset/confirm
+// Body: { "token": "...", "new_password": "..." }
+func (h *APIHandler) ResetPassword(c echo.Context) error {
+	var req pb.ResetPasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.userService.ResetPassword(c.Request().Context(), req.Token, req.NewPassword)
+	if err != nil {
+		if st, ok := status.FromError(err); ok && st.Code() == codes.InvalidArgument {
+			return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+		}
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to reset password"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ===== Users =====
+
+// profileError переводит gRPC-ошибку user_service в HTTP-ответ
+func profileError(c echo.Context, err error, msg string) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.PermissionDenied:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "User not found"})
+	case codes.ResourceExhausted:
+		return c.JSON(http.StatusTooManyRequests, map[string]string{"error": "Too many failed password attempts, try again later"})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
 	}
-	ctx := metadata.AppendToOutgoingContext(c.Request().Context(), "user_id", userID)
-	resp, err := h.userService.HistoryOfRoutes(ctx)
+}
+
+// GetProfile — GET /users/me
+func (h *APIHandler) GetProfile(c echo.Context) error {
+	resp, err := h.userService.GetProfile(c.Request().Context())
+	if err != nil {
+		return profileError(c, err, "Failed to get profile")
+	}
+	return c.JSON(http.StatusOK, resp.Profile)
+}
+
+// UpdateProfile — PATCH /users/me
+// Body: { "first_name": "...", "last_name": "...", "avatar_url": "...", "gender": 1 }, все поля необязательны
+func (h *APIHandler) UpdateProfile(c echo.Context) error {
+	var req pb.UpdateProfileRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.userService.UpdateProfile(c.Request().Context(), &req)
+	if err != nil {
+		return profileError(c, err, "Failed to update profile")
+	}
+	return c.JSON(http.StatusOK, resp.Profile)
+}
+
+// ChangePassword — POST /users/me/password
+// Body: { "current_password": "...", "new_password": "..." }
+func (h *APIHandler) ChangePassword(c echo.Context) error {
+	var req pb.ChangePasswordRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.userService.ChangePassword(c.Request().Context(), req.CurrentPassword, req.NewPassword, c.RealIP())
+	if err != nil {
+		return profileError(c, err, "Failed to change password")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DeleteAccount — DELETE /users/me
+// Body: { "password": "..." }
+func (h *APIHandler) DeleteAccount(c echo.Context) error {
+	var req pb.DeleteAccountRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	if _, err := h.userService.DeleteAccount(c.Request().Context(), req.Password, c.RealIP()); err != nil {
+		return profileError(c, err, "Failed to delete account")
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// SetUserRole — PUT /admin/users/:id/role
+// Body: { "role": "rider" | "driver" | "admin" }
+func (h *APIHandler) SetUserRole(c echo.Context) error {
+	var body struct {
+		Role string `json:"role"`
+	}
+	if err := c.Bind(&body); err != nil || body.Role == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "role is required"})
+	}
+	resp, err := h.userService.SetUserRole(c.Request().Context(), c.Param("id"), body.Role)
+	if err != nil {
+		return profileError(c, err, "Failed to set role")
+	}
+	return c.JSON(http.StatusOK, resp.Profile)
+}
+
+func (h *APIHandler) HistoryOfRoutes(c echo.Context) error {
+	resp, err := h.userService.HistoryOfRoutes(c.Request().Context())
 	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get routes"})
 	}
@@ -85,6 +229,23 @@ func (h *APIHandler) HistoryOfRoutes(c echo.Context) error {
 
 // ===== Rooms =====
 
+// rideError переводит ошибку room_service в HTTP-ответ пассажиру или водителю
+func rideError(c echo.Context, err error, msg string) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.PermissionDenied:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition, codes.AlreadyExists:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
+	}
+}
+
 func (h *APIHandler) CreateRoom(c echo.Context) error {
 	userID, err := getUserIDFromCtx(c)
 	if err != nil {
@@ -113,8 +274,36 @@ func (h *APIHandler) JoinRoom(c echo.Context) error {
 	}
 	resp, err := h.roomService.JoinRoom(c.Request().Context(), &pb_room.JoinRoomRequest{RoomId: roomID, UserId: userID})
 	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return c.JSON(http.StatusConflict, map[string]string{"error": status.Convert(err).Message()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to join room"})
 	}
+	if resp.PaymentPending {
+		// Холд ждёт подтверждения по ссылке, после него вступление повторяется
+		return c.JSON(http.StatusAccepted, resp)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ApplyPromoCode — POST /rooms/:id/promo
+// Промокод участника поездки; скидка вычитается из его доли при оплате
+// Body: { "code": "..." }
+func (h *APIHandler) ApplyPromoCode(c echo.Context) error {
+	userID, err := getUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		Code string `json:"code"`
+	}
+	if err := c.Bind(&body); err != nil || body.Code == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "code is required"})
+	}
+	resp, err := h.roomService.ApplyPromoCode(c.Request().Context(), &pb_room.ApplyPromoCodeRequest{RoomId: c.Param("id"), UserId: userID, Code: body.Code})
+	if err != nil {
+		return rideError(c, err, "Failed to apply promo code")
+	}
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -129,6 +318,9 @@ func (h *APIHandler) ExitRoom(c echo.Context) error {
 	}
 	resp, err := h.roomService.ExitRoom(c.Request().Context(), &pb_room.ExitRoomRequest{RoomId: roomID, UserId: userID})
 	if err != nil {
+		if status.Code(err) == codes.FailedPrecondition {
+			return c.JSON(http.StatusConflict, map[string]string{"error": status.Convert(err).Message()})
+		}
 		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to exit room"})
 	}
 	return c.JSON(http.StatusOK, resp)
@@ -156,9 +348,27 @@ func (h *APIHandler) GetRoomDetails(c echo.Context) error {
 
 // ===== Payments =====
 
-// ProcessPayment — POST /payments/process
+// paymentError переводит ошибку payment_service в HTTP-ответ пользователю; маршруты /admin используют adminError
+func paymentError(c echo.Context, err error, msg string) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.PermissionDenied:
+		return c.JSON(http.StatusForbidden, map[string]string{"error": st.Message()})
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": st.Message()})
+	case codes.FailedPrecondition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
+	}
+}
+
+// ProcessPayment — POST /admin/payments/process
 // Вызывается после завершения поездки (когда room статус = COMPLETED)
-// Body: { "room_id": "...", "user_ids": ["..."], "amount_per_user": 500.00, "description": "..." }
+// Body: { "room_id": "...", "user_ids": ["..."], "amount_per_user": 500.00, "description": "...", "idempotency_key": "..." }
+// Ключ можно передать и заголовком Idempotency-Key; повтор с тем же ключом возвращает те же платежи
 func (h *APIHandler) ProcessPayment(c echo.Context) error {
 	var req pb_payment.ProcessPaymentRequest
 	if err := c.Bind(&req); err != nil {
@@ -167,41 +377,312 @@ func (h *APIHandler) ProcessPayment(c echo.Context) error {
 	if req.RoomId == "" || len(req.UserIds) == 0 || req.AmountPerUser <= 0 {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "room_id, user_ids and amount_per_user are required"})
 	}
+	if req.IdempotencyKey == "" {
+		req.IdempotencyKey = c.Request().Header.Get("Idempotency-Key")
+	}
 	resp, err := h.paymentService.ProcessPayment(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to process payment"})
+		return adminError(c, err, "Failed to process payment")
 	}
 	return c.JSON(http.StatusOK, resp)
 }
 
-// RefundPayment — POST /payments/refund
-// Вызывается при отмене поездки
-// Body: { "room_id": "...", "reason": "..." }
-func (h *APIHandler) RefundPayment(c echo.Context) error {
-	var req pb_payment.RefundPaymentRequest
+// RetryFailedPayments — POST /admin/payments/retry
+// Повторяет неудавшиеся платежи комнаты; ответ, как у ProcessPayment, с итогом по каждому пассажиру
+// Body: { "room_id": "...", "user_ids": ["..."] }
+func (h *APIHandler) RetryFailedPayments(c echo.Context) error {
+	var req pb_payment.RetryFailedPaymentsRequest
 	if err := c.Bind(&req); err != nil {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
 	}
 	if req.RoomId == "" {
 		return c.JSON(http.StatusBadRequest, map[string]string{"error": "room_id is required"})
 	}
+	resp, err := h.paymentService.RetryFailedPayments(c.Request().Context(), &req)
+	if err != nil {
+		return adminError(c, err, "Failed to retry payments")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RefundPayment — POST /admin/payments/refund
+// Полный или частичный возврат: по всей комнате, по пассажирам или по отдельным платежам
+// Body: { "room_id": "...", "payment_ids": ["..."], "user_ids": ["..."], "amount": 100.0, "amounts": {"<payment_id>": 50.0}, "reason": "..." }
+func (h *APIHandler) RefundPayment(c echo.Context) error {
+	var req pb_payment.RefundPaymentRequest
+	if err := c.Bind(&req); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	if req.RoomId == "" && len(req.PaymentIds) == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "room_id or payment_ids is required"})
+	}
 	resp, err := h.paymentService.RefundPayment(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to refund payment"})
+		return adminError(c, err, "Failed to refund payment")
 	}
 	return c.JSON(http.StatusOK, resp)
 }
 
-// GetPaymentHistory — GET /payments/history
-// Возвращает историю транзакций текущего пользователя
+// GetPaymentHistory — GET /payments/history?limit=&cursor=&status=&room_id=&kind=&from=&to=
+// Страница истории платежей текущего пользователя, новые первыми, и итоги по валютам.
+// Следующая страница — с cursor из next_cursor; from, to — RFC3339
 func (h *APIHandler) GetPaymentHistory(c echo.Context) error {
 	userID, err := getUserIDFromCtx(c)
 	if err != nil {
 		return err
 	}
-	resp, err := h.paymentService.GetPaymentHistory(c.Request().Context(), &pb_payment.GetPaymentHistoryRequest{UserId: userID})
+	req := &pb_payment.GetPaymentHistoryRequest{
+		UserId: userID,
+		Limit:  queryLimit(c),
+		Cursor: c.QueryParam("cursor"),
+		Status: c.QueryParam("status"),
+		RoomId: c.QueryParam("room_id"),
+		Kind:   c.QueryParam("kind"),
+	}
+	for param, dst := range map[string]**timestamppb.Timestamp{"from": &req.From, "to": &req.To} {
+		if v := c.QueryParam(param); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return c.JSON(http.StatusBadRequest, map[string]string{"error": param + " must be RFC3339"})
+			}
+			*dst = timestamppb.New(t)
+		}
+	}
+	resp, err := h.paymentService.GetPaymentHistory(c.Request().Context(), req)
+	if err != nil {
+		return paymentError(c, err, "Failed to get payment history")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetRoomPayments — GET /rooms/:id/payments
+// Кто из пассажиров оплатил свою долю; водитель поездки видит всех, пассажир — свою долю со ссылкой на оплату
+func (h *APIHandler) GetRoomPayments(c echo.Context) error {
+	roomID := c.Param("id")
+	if roomID == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Room ID is required"})
+	}
+	resp, err := h.paymentService.GetRoomPayments(c.Request().Context(), &pb_payment.GetRoomPaymentsRequest{RoomId: roomID})
+	if err != nil {
+		return paymentError(c, err, "Failed to get room payments")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetReceiptContact — PUT /payments/receipt-contact
+// Email и/или телефон, на которые приходят чеки по 54-ФЗ
+// Body: { "email": "...", "phone": "+79001234567" }
+func (h *APIHandler) SetReceiptContact(c echo.Context) error {
+	var body struct {
+		Email string `json:"email"`
+		Phone string `json:"phone"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.paymentService.SetReceiptContact(c.Request().Context(), &pb_payment.SetReceiptContactRequest{Email: body.Email, Phone: body.Phone})
+	if err != nil {
+		return paymentError(c, err, "Failed to save receipt contact")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetReceipt — GET /payments/:id/receipt?format=html|json
+// Чеки платежа (приход и возвраты): HTML для просмотра и печати или данные чеков в JSON
+func (h *APIHandler) GetReceipt(c echo.Context) error {
+	resp, err := h.paymentService.GetReceipt(c.Request().Context(), &pb_payment.GetReceiptRequest{PaymentId: c.Param("id")})
+	if err != nil {
+		return paymentError(c, err, "Failed to get receipt")
+	}
+	if c.QueryParam("format") == "json" {
+		return c.JSON(http.StatusOK, resp)
+	}
+	return c.HTML(http.StatusOK, resp.Html)
+}
+
+// RequestStatement — POST /statements
+// Выписка текущего пользователя за месяц; готовится в фоне, состояние — GET /statements/:id
+// Body: { "month": "2026-09", "format": "csv|pdf", "role": "rider|driver" }
+func (h *APIHandler) RequestStatement(c echo.Context) error {
+	var body struct {
+		Month  string `json:"month"`
+		Format string `json:"format"`
+		Role   string `json:"role"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.paymentService.RequestStatement(c.Request().Context(), &pb_payment.RequestStatementRequest{
+		Month:  body.Month,
+		Format: body.Format,
+		Role:   body.Role,
+	})
+	if err != nil {
+		return paymentError(c, err, "Failed to request statement")
+	}
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// GetStatement — GET /statements/:id
+func (h *APIHandler) GetStatement(c echo.Context) error {
+	resp, err := h.paymentService.GetStatement(c.Request().Context(), &pb_payment.GetStatementRequest{StatementId: c.Param("id")})
+	if err != nil {
+		return paymentError(c, err, "Failed to get statement")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DownloadStatement — GET /statements/:id/download
+// Файл готовой выписки; пока выписка готовится или если она не удалась — 409
+func (h *APIHandler) DownloadStatement(c echo.Context) error {
+	resp, err := h.paymentService.DownloadStatement(c.Request().Context(), &pb_payment.DownloadStatementRequest{StatementId: c.Param("id")})
+	if err != nil {
+		return paymentError(c, err, "Failed to download statement")
+	}
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", resp.FileName))
+	return c.Blob(http.StatusOK, resp.ContentType, resp.Content)
+}
+
+// GetWallet — GET /wallet?limit=
+// Счета текущего пользователя во внутреннем журнале с балансами и последние проводки
+func (h *APIHandler) GetWallet(c echo.Context) error {
+	resp, err := h.paymentService.GetWallet(c.Request().Context(), &pb_payment.GetWalletRequest{Limit: queryLimit(c)})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get wallet"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetDriverEarnings — GET /driver/earnings?from=&to=&currency=
+// Заработок текущего водителя по поездкам за период (RFC3339), комиссия платформы и выплаты
+func (h *APIHandler) GetDriverEarnings(c echo.Context) error {
+	resp, err := h.paymentService.GetDriverEarnings(c.Request().Context(), &pb_payment.GetDriverEarningsRequest{
+		From:     c.QueryParam("from"),
+		To:       c.QueryParam("to"),
+		Currency: c.QueryParam("currency"),
+	})
+	if err != nil {
+		return paymentError(c, err, "Failed to get driver earnings")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// paymentMethodError переводит ошибку способов оплаты payment_service в HTTP-ответ
+func paymentMethodError(c echo.Context, err error, msg string) error {
+	st, _ := status.FromError(err)
+	switch st.Code() {
+	case codes.InvalidArgument:
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": st.Message()})
+	case codes.NotFound:
+		return c.JSON(http.StatusNotFound, map[string]string{"error": "Payment method not found"})
+	case codes.FailedPrecondition:
+		return c.JSON(http.StatusConflict, map[string]string{"error": st.Message()})
+	default:
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": msg})
+	}
+}
+
+// ListPaymentMethods — GET /payments/methods
+// Сохранённые способы оплаты текущего пользователя, способ по умолчанию первым
+func (h *APIHandler) ListPaymentMethods(c echo.Context) error {
+	resp, err := h.paymentService.ListPaymentMethods(c.Request().Context(), &pb_payment.ListPaymentMethodsRequest{})
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get payment methods"})
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// AddPaymentMethod — POST /payments/methods
+// Начинает привязку карты: пользователь подтверждает её по confirmation_url,
+// способ станет активным после уведомления провайдера.
+// Body: { "return_url": "..." }
+func (h *APIHandler) AddPaymentMethod(c echo.Context) error {
+	var body struct {
+		ReturnURL string `json:"return_url"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid request"})
+	}
+	resp, err := h.paymentService.AddPaymentMethod(c.Request().Context(), &pb_payment.AddPaymentMethodRequest{ReturnUrl: body.ReturnURL})
+	if err != nil {
+		return paymentMethodError(c, err, "Failed to add payment method")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RemovePaymentMethod — DELETE /payments/methods/:id
+func (h *APIHandler) RemovePaymentMethod(c echo.Context) error {
+	resp, err := h.paymentService.RemovePaymentMethod(c.Request().Context(), &pb_payment.RemovePaymentMethodRequest{MethodId: c.Param("id")})
+	if err != nil {
+		return paymentMethodError(c, err, "Failed to remove payment method")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetDefaultPaymentMethod — PUT /payments/methods/:id/default
+// Этим способом списываются следующие поездки; 409 — привязка ещё не подтверждена
+func (h *APIHandler) SetDefaultPaymentMethod(c echo.Context) error {
+	resp, err := h.paymentService.SetDefaultPaymentMethod(c.Request().Context(), &pb_payment.SetDefaultPaymentMethodRequest{MethodId: c.Param("id")})
+	if err != nil {
+		return paymentMethodError(c, err, "Failed to set default payment method")
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PaymentUpdates — GET /payments/updates
+// Server-Sent Events: каждое изменение статуса платежа или возврата текущего пользователя
+// приходит событием "payment" с JSON PaymentUpdate
+func (h *APIHandler) PaymentUpdates(c echo.Context) error {
+	stream, err := h.paymentService.StreamPaymentUpdates(c.Request().Context())
+	if err != nil {
+		return paymentError(c, err, "Failed to subscribe to payment updates")
+	}
+
+	w := c.Response()
+	w.Header().Set(echo.HeaderContentType, "text/event-stream")
+	w.Header().Set(echo.HeaderCacheControl, "no-cache")
+	w.Header().Set(echo.HeaderConnection, "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.Flush()
+
+	for {
+		update, err := stream.Recv()
+		if err != nil {
+			// Клиент отключился или payment_service закрыл стрим: клиент переподключится сам
+			return nil
+		}
+		data, err := json.Marshal(update)
+		if err != nil {
+			return nil
+		}
+		if _, err := fmt.Fprintf(w, "event: payment\ndata: %s\n\n", data); err != nil {
+			return nil
+		}
+		w.Flush()
+	}
+}
+
+// StartRide — POST /rooms/:id/start
+// Водитель начинает поездку; не пришедшие пассажиры исключаются со штрафом за неявку по правилам комнаты
+// Body: { "no_show_user_ids": ["..."] }
+func (h *APIHandler) StartRide(c echo.Context) error {
+	driverID, err := getUserIDFromCtx(c)
+	if err != nil {
+		return err
+	}
+	var body struct {
+		NoShowUserIDs []string `json:"no_show_user_ids"`
+	}
+	if err := c.Bind(&body); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+	}
+	resp, err := h.roomService.StartRide(c.Request().Context(), &pb_room.StartRideRequest{
+		RoomId:        c.Param("id"),
+		DriverId:      driverID,
+		NoShowUserIds: body.NoShowUserIDs,
+	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to get payment history"})
+		return rideError(c, err, "Failed to start ride")
 	}
 	return c.JSON(http.StatusOK, resp)
 }
@@ -233,7 +714,7 @@ func (h *APIHandler) CompleteRide(c echo.Context) error {
 		DistanceKm: body.DistanceKm,
 	})
 	if err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]string{"error": "Failed to complete ride"})
+		return rideError(c, err, "Failed to complete ride")
 	}
 	return c.JSON(http.StatusOK, resp)
 }
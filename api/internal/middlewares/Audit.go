@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+// maxAuditBody — сколько байт тела запроса попадает в details записи аудита
+const maxAuditBody = 1024
+
+// AuditRecorder — хранилище журнала действий поддержки (обычно *clients.UserServiceClient)
+type AuditRecorder interface {
+	RecordAuditEvent(ctx context.Context, req *pb.RecordAuditEventRequest) error
+}
+
+// Audit пишет в журнал каждый запрос группы: маршрут, параметры пути, query, тело и итоговый статус.
+// Ставится после JWT: администратор определяется user_service по токену из контекста.
+// Ошибка записи журнала не ломает ответ, а только логируется.
+func Audit(recorder AuditRecorder) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			var body []byte
+			if req.Body != nil {
+				body, _ = io.ReadAll(req.Body)
+				req.Body = io.NopCloser(bytes.NewReader(body))
+			}
+
+			err := next(c)
+
+			code := c.Response().Status
+			if err != nil {
+				code = http.StatusInternalServerError
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					code = he.Code
+				}
+			}
+
+			details := req.URL.RawQuery
+			if len(body) > 0 {
+				if len(body) > maxAuditBody {
+					body = body[:maxAuditBody]
+				}
+				details = strings.TrimSpace(details + " " + string(body))
+			}
+
+			event := &pb.RecordAuditEventRequest{
+				Action:  req.Method + " " + c.Path(),
+				Target:  auditTarget(c),
+				Details: details,
+				Status:  int32(code),
+			}
+			if auditErr := recorder.RecordAuditEvent(context.WithoutCancel(req.Context()), event); auditErr != nil {
+				c.Logger().Errorf("failed to record audit event %s: %v", event.Action, auditErr)
+			}
+			return err
+		}
+	}
+}
+
+// auditTarget — значения параметров пути (id пользователя, комнаты), смысл задаёт маршрут в action
+func auditTarget(c echo.Context) string {
+	return strings.Join(c.ParamValues(), " ")
+}
@@ -1,15 +1,27 @@
 package middlewares
 
 import (
+	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"net/http"
 	"strings"
+
+	"we_ride/internal/pkg/identity"
 )
 
-func JWT(secret []byte) echo.MiddlewareFunc {
+// KeyProvider — источник публичных ключей user_service (обычно *jwks.Cache)
+type KeyProvider interface {
+	Lookup(ctx context.Context, kid string) (alg string, key crypto.PublicKey, err error)
+}
+
+// validMethods — только асимметричные алгоритмы: общий секрет больше не используется
+var validMethods = []string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}
+
+func JWT(keys KeyProvider) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
 
@@ -24,27 +36,36 @@ func JWT(secret []byte) echo.MiddlewareFunc {
 			}
 
 			// Парсим и проверяем токен
-			token, err := parseJWT(tokenString, secret)
+			token, err := parseJWT(c.Request().Context(), tokenString, keys)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusUnauthorized, fmt.Sprintf("Invalid token: %v", err))
 			}
 
 			// Добавляем информацию о токене в контекст запроса
 			c.Set("user", token.Claims)
+			// Исходный токен уходит в gRPC-метаданные каждого вызова сервисов
+			c.SetRequest(c.Request().WithContext(identity.WithToken(c.Request().Context(), tokenString)))
 
 			return next(c)
 		}
 	}
 }
 
-func parseJWT(tokenString string, secret []byte) (*jwt.Token, error) {
+func parseJWT(ctx context.Context, tokenString string, keys KeyProvider) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		kid, _ := token.Header["kid"].(string)
+		if kid == "" {
+			return nil, errors.New("Missing kid header")
+		}
+		alg, key, err := keys.Lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if token.Method.Alg() != alg {
 			return nil, errors.New("Unexpected signing method")
 		}
-		return secret, nil
-	})
+		return key, nil
+	}, jwt.WithValidMethods(validMethods), jwt.WithExpirationRequired())
 
 	if err != nil {
 		return nil, err
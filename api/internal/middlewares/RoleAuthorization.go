@@ -0,0 +1,28 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+
+	"we_ride/internal/pkg/identity"
+)
+
+// RequireRole пропускает запрос, только если клейм role токена входит в roles.
+// Ставится после JWT: клеймы берутся из контекста echo.
+func RequireRole(roles ...string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			claims, ok := c.Get("user").(jwt.MapClaims)
+			if !ok {
+				return echo.NewHTTPError(http.StatusUnauthorized, "Invalid token claims")
+			}
+			role, _ := claims["role"].(string)
+			if !(identity.Identity{Role: role}).HasRole(roles...) {
+				return echo.NewHTTPError(http.StatusForbidden, "Insufficient role")
+			}
+			return next(c)
+		}
+	}
+}
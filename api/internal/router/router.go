@@ -5,6 +5,8 @@ import (
 	"we_ride/api/internal/clients"
 	"we_ride/api/internal/handlers"
 	"we_ride/api/internal/middlewares"
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/jwks"
 )
 
 func InitRoutes(
@@ -12,31 +14,69 @@ func InitRoutes(
 	userService *clients.UserServiceClient,
 	roomService *clients.RoomServiceClient,
 	paymentService *clients.PaymentServiceClient,
-	jwtSecret []byte,
+	keys *jwks.Cache,
 ) {
 	handler := handlers.NewAPIHandler(userService, roomService, paymentService)
 
 	// Публичные
 	e.POST("/auth/register", handler.Register)
 	e.POST("/auth/login", handler.Login)
+	e.POST("/auth/verify-email", handler.VerifyEmail)
+	e.POST("/auth/password-reset/request", handler.RequestPasswordReset)
+	e.POST("/auth/password-reset/confirm", handler.ResetPassword)
+	e.GET(jwks.Path, echo.WrapHandler(jwks.Handler(keys.Set)))
 
 	// Защищённые
 	protected := e.Group("")
-	protected.Use(middlewares.JWT(jwtSecret))
+	protected.Use(middlewares.JWT(keys))
 
 	// Users
 	protected.GET("/auth/history", handler.HistoryOfRoutes)
+	protected.POST("/auth/verify-email/resend", handler.ResendVerification)
+	protected.GET("/users/me", handler.GetProfile)
+	protected.PATCH("/users/me", handler.UpdateProfile)
+	protected.DELETE("/users/me", handler.DeleteAccount)
+	protected.POST("/users/me/password", handler.ChangePassword)
 
 	// Rooms
-	protected.POST("/rooms", handler.CreateRoom)
+	protected.POST("/rooms", handler.CreateRoom, middlewares.RequireRole(identity.RoleDriver))
 	protected.GET("/rooms", handler.FindRoom)
 	protected.GET("/rooms/:id", handler.GetRoomDetails)
+	protected.GET("/rooms/:id/payments", handler.GetRoomPayments)
 	protected.POST("/rooms/:id/join", handler.JoinRoom)
 	protected.POST("/rooms/:id/exit", handler.ExitRoom)
-	protected.POST("/rooms/:id/complete", handler.CompleteRide) // триггер оплаты
+	protected.POST("/rooms/:id/promo", handler.ApplyPromoCode)
+	protected.POST("/rooms/:id/start", handler.StartRide, middlewares.RequireRole(identity.RoleDriver))
+	protected.POST("/rooms/:id/complete", handler.CompleteRide, middlewares.RequireRole(identity.RoleDriver)) // триггер оплаты
 
 	// Payments
-	protected.POST("/payments/process", handler.ProcessPayment)
-	protected.POST("/payments/refund", handler.RefundPayment)
 	protected.GET("/payments/history", handler.GetPaymentHistory)
+	protected.GET("/payments/updates", handler.PaymentUpdates)
+	protected.GET("/payments/:id/receipt", handler.GetReceipt)
+	protected.PUT("/payments/receipt-contact", handler.SetReceiptContact)
+	protected.GET("/payments/methods", handler.ListPaymentMethods)
+	protected.POST("/payments/methods", handler.AddPaymentMethod)
+	protected.DELETE("/payments/methods/:id", handler.RemovePaymentMethod)
+	protected.PUT("/payments/methods/:id/default", handler.SetDefaultPaymentMethod)
+	protected.POST("/statements", handler.RequestStatement)
+	protected.GET("/statements/:id", handler.GetStatement)
+	protected.GET("/statements/:id/download", handler.DownloadStatement)
+	protected.GET("/wallet", handler.GetWallet)
+	protected.GET("/driver/earnings", handler.GetDriverEarnings, middlewares.RequireRole(identity.RoleDriver))
+
+	// Поддержка
+	admin := protected.Group("/admin", middlewares.RequireRole(identity.RoleAdmin), middlewares.Audit(userService))
+	admin.GET("/users", handler.SearchUsers)
+	admin.PUT("/users/:id/role", handler.SetUserRole)
+	admin.POST("/users/:id/block", handler.BlockUser)
+	admin.GET("/rooms", handler.SearchRooms)
+	admin.POST("/rooms/:id/cancel", handler.CancelRoom)
+	admin.GET("/rooms/:id/timeline", handler.GetRoomTimeline)
+	admin.GET("/payments", handler.SearchPayments)
+	admin.POST("/payments/process", handler.ProcessPayment)
+	admin.POST("/payments/retry", handler.RetryFailedPayments)
+	admin.POST("/payments/refund", handler.RefundPayment)
+	admin.GET("/promo-codes", handler.ListPromoCodes)
+	admin.POST("/promo-codes", handler.CreatePromoCode)
+	admin.GET("/audit", handler.ListAuditEvents)
 }
@@ -0,0 +1,113 @@
+package identity
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey — ключ gRPC-метаданных, в котором gateway передаёт access token
+const MetadataKey = "authorization"
+
+type key string
+
+const (
+	identityKey = key("identity")
+	tokenKey    = key("access_token")
+)
+
+// Роли пользователей, хранятся в user_service и приходят клеймом role
+const (
+	RoleRider  = "rider"
+	RoleDriver = "driver"
+	RoleAdmin  = "admin"
+)
+
+// ValidRole сообщает, известна ли роль
+func ValidRole(role string) bool {
+	switch role {
+	case RoleRider, RoleDriver, RoleAdmin:
+		return true
+	}
+	return false
+}
+
+// Identity — проверенный вызывающий пользователь
+type Identity struct {
+	UserID        string
+	Email         string
+	EmailVerified bool
+	Role          string
+}
+
+// HasRole сообщает, есть ли у пользователя одна из ролей.
+// Токены без клейма role (выпущенные до появления ролей) считаются токенами пассажира.
+func (id Identity) HasRole(roles ...string) bool {
+	role := id.Role
+	if role == "" {
+		role = RoleRider
+	}
+	for _, r := range roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// WithIdentity кладёт вызывающего пользователя в контекст
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey, id)
+}
+
+// FromContext достаёт вызывающего пользователя, положенного интерцептором
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey).(Identity)
+	return id, ok && id.UserID != ""
+}
+
+// WithToken сохраняет исходный токен, чтобы переслать его в следующий сервис
+func WithToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, tokenKey, token)
+}
+
+// TokenFromContext возвращает токен, сохранённый WithToken
+func TokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(tokenKey).(string)
+	return token, ok && token != ""
+}
+
+// Caller возвращает вызывающего пользователя или ошибку Unauthenticated
+func Caller(ctx context.Context) (Identity, error) {
+	id, ok := FromContext(ctx)
+	if !ok {
+		return Identity{}, status.Error(codes.Unauthenticated, "caller identity is missing")
+	}
+	return id, nil
+}
+
+// RequireRole возвращает вызывающего пользователя, если у него одна из ролей, иначе PermissionDenied
+func RequireRole(ctx context.Context, roles ...string) (Identity, error) {
+	id, err := Caller(ctx)
+	if err != nil {
+		return Identity{}, err
+	}
+	if !id.HasRole(roles...) {
+		return Identity{}, status.Error(codes.PermissionDenied, "insufficient role")
+	}
+	return id, nil
+}
+
+// Authorize сверяет user_id из тела запроса с вызывающим пользователем.
+// Пустой claimed означает «от имени вызывающего». Возвращает итоговый user_id.
+func Authorize(ctx context.Context, claimed string) (string, error) {
+	id, err := Caller(ctx)
+	if err != nil {
+		return "", err
+	}
+	if claimed != "" && claimed != id.UserID {
+		return "", status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+	return id.UserID, nil
+}
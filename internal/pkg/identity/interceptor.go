@@ -0,0 +1,110 @@
+package identity
+
+import (
+	"context"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Verifier проверяет access token и возвращает владельца
+type Verifier interface {
+	Verify(ctx context.Context, token string) (Identity, error)
+}
+
+// UnaryServerInterceptor проверяет токен из метаданных и кладёт Identity в контекст.
+// Методы из public (полные имена, например "/auth.Auth/Login") пропускаются без токена.
+func UnaryServerInterceptor(v Verifier, public ...string) grpc.UnaryServerInterceptor {
+	skip := toSet(public)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := authenticate(ctx, v, skip[info.FullMethod])
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor — то же для стриминговых методов
+func StreamServerInterceptor(v Verifier, public ...string) grpc.StreamServerInterceptor {
+	skip := toSet(public)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := authenticate(ss.Context(), v, skip[info.FullMethod])
+		if err != nil {
+			return err
+		}
+		return handler(srv, &wrappedStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// UnaryClientInterceptor пересылает токен из контекста в исходящие метаданные,
+// поэтому вызовы между сервисами выполняются от имени исходного пользователя.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if token, ok := TokenFromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, "Bearer "+token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor — то же для стриминговых вызовов
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if token, ok := TokenFromContext(ctx); ok {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, "Bearer "+token)
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+func authenticate(ctx context.Context, v Verifier, public bool) (context.Context, error) {
+	token := tokenFromMetadata(ctx)
+	if token == "" {
+		if public {
+			return ctx, nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "access token is missing")
+	}
+
+	id, err := v.Verify(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid access token: %v", err)
+	}
+
+	ctx = WithIdentity(ctx, id)
+	ctx = WithToken(ctx, token)
+	return ctx, nil
+}
+
+func tokenFromMetadata(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(values[0], "Bearer ")
+}
+
+func toSet(methods []string) map[string]bool {
+	set := make(map[string]bool, len(methods))
+	for _, m := range methods {
+		set[m] = true
+	}
+	return set
+}
+
+type wrappedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (w *wrappedStream) Context() context.Context {
+	return w.ctx
+}
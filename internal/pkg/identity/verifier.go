@@ -0,0 +1,53 @@
+package identity
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"we_ride/internal/pkg/jwks"
+)
+
+// claims — поля токена user_service, нужные сервисам
+type claims struct {
+	UserID        string `json:"uid"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role"`
+	jwt.RegisteredClaims
+}
+
+// JWKSVerifier проверяет токены по опубликованным ключам user_service
+type JWKSVerifier struct {
+	keys *jwks.Cache
+}
+
+func NewJWKSVerifier(keys *jwks.Cache) *JWKSVerifier {
+	return &JWKSVerifier{keys: keys}
+}
+
+func (v *JWKSVerifier) Verify(ctx context.Context, token string) (Identity, error) {
+	var c claims
+	_, err := jwt.ParseWithClaims(token, &c, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		alg, key, err := v.keys.Lookup(ctx, kid)
+		if err != nil {
+			return nil, err
+		}
+		if t.Method.Alg() != alg {
+			return nil, errors.New("unexpected signing method")
+		}
+		return key, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Identity{}, err
+	}
+	if c.UserID == "" {
+		return Identity{}, errors.New("uid claim is missing")
+	}
+	return Identity{UserID: c.UserID, Email: c.Email, EmailVerified: c.EmailVerified, Role: c.Role}, nil
+}
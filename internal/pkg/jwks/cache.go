@@ -0,0 +1,139 @@
+package jwks
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrUnknownKey — kid отсутствует в опубликованном наборе ключей
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// minRefetchInterval ограничивает внеплановые запросы при неизвестном kid,
+// чтобы токены с мусорным kid не превращались в DoS на user_service
+const minRefetchInterval = 10 * time.Second
+
+type cachedKey struct {
+	alg string
+	pub crypto.PublicKey
+}
+
+// Cache хранит опубликованные ключи и периодически их обновляет
+type Cache struct {
+	url        string
+	refresh    time.Duration
+	httpClient *http.Client
+
+	mu        sync.RWMutex
+	set       Set
+	keys      map[string]cachedKey
+	fetchedAt time.Time
+}
+
+func NewCache(url string, refresh time.Duration) *Cache {
+	return &Cache{
+		url:        url,
+		refresh:    refresh,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		keys:       map[string]cachedKey{},
+	}
+}
+
+// Start загружает ключи и обновляет их в фоне до отмены ctx.
+// Ошибка первой загрузки не фатальна: ключи подтянутся при первом запросе.
+func (c *Cache) Start(ctx context.Context) error {
+	err := c.Refresh(ctx)
+
+	go func() {
+		ticker := time.NewTicker(c.refresh)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_ = c.Refresh(ctx)
+			}
+		}
+	}()
+
+	return err
+}
+
+// Refresh скачивает актуальный набор ключей
+func (c *Cache) Refresh(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetch jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetch jwks: unexpected status %d", resp.StatusCode)
+	}
+
+	var set Set
+	if err := json.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return fmt.Errorf("decode jwks: %w", err)
+	}
+
+	keys := make(map[string]cachedKey, len(set.Keys))
+	for _, k := range set.Keys {
+		pub, err := k.PublicKey()
+		if err != nil {
+			return fmt.Errorf("key %s: %w", k.Kid, err)
+		}
+		keys[k.Kid] = cachedKey{alg: k.Alg, pub: pub}
+	}
+
+	c.mu.Lock()
+	c.set = set
+	c.keys = keys
+	c.fetchedAt = time.Now()
+	c.mu.Unlock()
+	return nil
+}
+
+// Lookup возвращает алгоритм и публичный ключ по kid.
+// Если kid неизвестен (ключ только что выпущен), набор перезагружается.
+func (c *Cache) Lookup(ctx context.Context, kid string) (string, crypto.PublicKey, error) {
+	c.mu.RLock()
+	k, ok := c.keys[kid]
+	stale := time.Since(c.fetchedAt) > minRefetchInterval
+	c.mu.RUnlock()
+	if ok {
+		return k.alg, k.pub, nil
+	}
+
+	if stale {
+		if err := c.Refresh(ctx); err != nil {
+			return "", nil, err
+		}
+		c.mu.RLock()
+		k, ok = c.keys[kid]
+		c.mu.RUnlock()
+		if ok {
+			return k.alg, k.pub, nil
+		}
+	}
+	return "", nil, ErrUnknownKey
+}
+
+// Set возвращает последний загруженный набор ключей
+func (c *Cache) Set() Set {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if c.set.Keys == nil {
+		return Set{Keys: []Key{}}
+	}
+	return c.set
+}
@@ -2,9 +2,11 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -12,9 +14,14 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/jwks"
 	"we_ride/internal/pkg/logger"
 	"we_ride/internal/services/payment_service/config"
 	"we_ride/internal/services/payment_service/database"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/provider/fake"
 	"we_ride/internal/services/payment_service/internal/repository"
 	"we_ride/internal/services/payment_service/internal/service"
 	"we_ride/internal/services/payment_service/internal/yookassa"
@@ -46,16 +53,102 @@ func main() {
 	}
 	defer pool.Close()
 
-	var ykClient *yookassa.Client
-	if cfg.YookassaShopID != "" && cfg.YookassaSecretKey != "" {
-		ykClient = yookassa.NewClient(cfg.YookassaShopID, cfg.YookassaSecretKey)
-	} else {
-		l.Info(ctx, "YOOKASSA credentials are not set; payment service started in mock mode")
-	}
 	repo := repository.NewRepository(pool)
-	svc := service.New(repo, ykClient)
+	// Уведомления провайдера приходят без токена: подлинность проверяется повторным запросом объекта
+	mux := http.NewServeMux()
+
+	if cfg.CommissionRate < 0 || cfg.CommissionRate >= 1 {
+		l.Fatal(ctx, "commission rate must be in [0, 1)", zap.Float64("commission_rate", cfg.CommissionRate))
+	}
+
+	var paymentProvider provider.PaymentProvider
+	var fakeProvider *fake.Provider
+	switch {
+	case cfg.Provider == "yookassa" || cfg.Provider == "" && cfg.YookassaShopID != "" && cfg.YookassaSecretKey != "":
+		paymentProvider = yookassa.NewProvider(yookassa.NewClient(cfg.YookassaShopID, cfg.YookassaSecretKey))
+	case cfg.Provider == "fake" || cfg.Provider == "":
+		fakeProvider = fake.New(fake.Options{
+			SettleDelay: cfg.Fake.SettleDelay,
+			WebhookURL:  cfg.Fake.PublicURL + service.WebhookPath,
+			BaseURL:     cfg.Fake.PublicURL,
+		})
+		mux.Handle("/fake/", fakeProvider.Handler())
+		paymentProvider = fakeProvider
+		l.Info(ctx, "payment service uses fake provider, no real money is charged")
+	default:
+		l.Fatal(ctx, "unknown payment provider", zap.String("provider", cfg.Provider))
+	}
+
+	var payoutProvider provider.PayoutProvider
+	switch {
+	case cfg.Payouts.Provider == "fake" && fakeProvider == nil:
+		payoutProvider = fake.New(fake.Options{})
+	case cfg.Payouts.Provider == "fake" || cfg.Payouts.Provider == "" && fakeProvider != nil:
+		payoutProvider = fakeProvider
+	case cfg.Payouts.Provider == "":
+		l.Info(ctx, "payout provider is not configured, driver payouts are disabled")
+	default:
+		l.Fatal(ctx, "unknown payout provider", zap.String("provider", cfg.Payouts.Provider))
+	}
+	// Лимит запросов общий для всех запросов сервиса, включая сверку и уведомления
+	paymentProvider = provider.Limited(paymentProvider, provider.Limits{
+		RPS:        cfg.Limits.RPS,
+		Burst:      cfg.Limits.Burst,
+		MaxRetries: cfg.Limits.MaxRetries,
+		BaseDelay:  cfg.Limits.RetryBaseDelay,
+		MaxDelay:   cfg.Limits.RetryMaxDelay,
+	})
+	currencies := append([]string{cfg.Currency.Default}, cfg.Currency.Accepted...)
+	if cfg.Currency.Report != "" {
+		currencies = append(currencies, cfg.Currency.Report)
+	}
+	for _, code := range currencies {
+		// Суммы в БД хранятся с двумя знаками после запятой
+		if err := money.Validate(code); err != nil || money.MinorUnits(code) > 2 {
+			l.Fatal(ctx, "unsupported currency", zap.String("currency", code))
+		}
+	}
+	var rates money.Rates
+	if cfg.Currency.RatesFile != "" {
+		static, err := money.LoadStaticRates(cfg.Currency.RatesFile)
+		if err != nil {
+			l.Fatal(ctx, "failed to load exchange rates", zap.Error(err))
+		}
+		rates = static
+	}
+	svc := service.New(repo, paymentProvider, service.Options{
+		CommissionRate: cfg.CommissionRate,
+		Payouts:        payoutProvider,
+		Concurrency:    cfg.Limits.Concurrency,
+		Receipts: service.ReceiptOptions{
+			Enabled:       cfg.Receipts.Enabled,
+			VatCode:       cfg.Receipts.VatCode,
+			TaxSystemCode: cfg.Receipts.TaxSystemCode,
+			SellerName:    cfg.Receipts.SellerName,
+			SellerINN:     cfg.Receipts.SellerINN,
+		},
+		Currency:        cfg.Currency.Default,
+		Currencies:      cfg.Currency.Accepted,
+		ReportCurrency:  cfg.Currency.Report,
+		Rates:           rates,
+		StatementsDir:   cfg.StatementsDir,
+		UserServiceAddr: cfg.UserServiceAddr,
+		RoomServiceAddr: cfg.RoomServiceAddr,
+	})
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(logger.Interceptor(ctx, l)))
+	keys := jwks.NewCache(cfg.JWKSURL, cfg.JWKSRefresh)
+	if err := keys.Start(ctx); err != nil {
+		l.Error(ctx, "failed to load JWKS, will retry on demand", zap.Error(err))
+	}
+
+	verifier := identity.NewJWKSVerifier(keys)
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(
+			logger.Interceptor(ctx, l),
+			identity.UnaryServerInterceptor(verifier),
+		),
+		grpc.StreamInterceptor(identity.StreamServerInterceptor(verifier)),
+	)
 	pb.RegisterPaymentServiceServer(grpcServer, svc)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.GRPCHost, cfg.GRPCPort))
@@ -71,8 +164,40 @@ func main() {
 		}
 	}()
 
+	go svc.RunReconciler(ctx, service.ReconcileOptions{
+		Interval:  cfg.Reconcile.Interval,
+		Threshold: cfg.Reconcile.Threshold,
+		BatchSize: cfg.Reconcile.BatchSize,
+	})
+
+	go svc.RunReminders(ctx, service.ReminderOptions{
+		Interval:  cfg.Reminders.Interval,
+		After:     cfg.Reminders.After,
+		Max:       cfg.Reminders.Max,
+		BatchSize: cfg.Reminders.BatchSize,
+	})
+
+	if payoutProvider != nil {
+		go svc.RunPayouts(ctx, service.PayoutOptions{
+			Interval: cfg.Payouts.Interval,
+			Period:   cfg.Payouts.Period,
+		})
+	}
+
+	mux.Handle(service.WebhookPath, svc.WebhookHandler(l))
+	httpServer := &http.Server{Addr: fmt.Sprintf("%s:%s", cfg.GRPCHost, cfg.WebhookPort), Handler: mux}
+
+	l.Info(ctx, "payment service webhook server started", zap.String("port", cfg.WebhookPort))
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			l.Fatal(ctx, "failed to serve HTTP", zap.Error(err))
+		}
+	}()
+
 	<-ctx.Done()
 	l.Info(ctx, "shutting down gracefully...")
+	_ = httpServer.Shutdown(context.Background())
 	grpcServer.GracefulStop()
 	l.Info(ctx, "payment service stopped")
 }
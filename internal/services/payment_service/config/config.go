@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"we_ride/internal/services/payment_service/database"
@@ -13,8 +14,106 @@ type Config struct {
 	GRPCPort string `env:"GRPC_PORT" env-default:"50053" yaml:"GRPC_PORT"`
 	GRPCHost string `env:"GRPC_HOST" env-default:"0.0.0.0" yaml:"GRPC_HOST"`
 
+	// Порт HTTP-сервера для уведомлений провайдера (и управления фейковым провайдером)
+	WebhookPort string `env:"WEBHOOK_PORT" env-default:"8083" yaml:"WEBHOOK_PORT"`
+
+	// Провайдер платежей: yookassa или fake; пусто — yookassa, если заданы credentials, иначе fake
+	Provider string `env:"PAYMENT_PROVIDER" yaml:"PAYMENT_PROVIDER"`
+
 	YookassaShopID    string `env:"YOOKASSA_SHOP_ID"    yaml:"YOOKASSA_SHOP_ID"`
 	YookassaSecretKey string `env:"YOOKASSA_SECRET_KEY" yaml:"YOOKASSA_SECRET_KEY"`
+
+	Fake FakeProvider `yaml:"FAKE_PROVIDER"`
+
+	Limits ProviderLimits `yaml:"PROVIDER_LIMITS"`
+
+	Reconcile Reconcile `yaml:"RECONCILE"`
+
+	// Доля платформы в оплате поездки, остальное начисляется водителю
+	CommissionRate float64 `env:"COMMISSION_RATE" env-default:"0.15" yaml:"COMMISSION_RATE"`
+
+	Payouts Payouts `yaml:"PAYOUTS"`
+
+	Reminders Reminders `yaml:"REMINDERS"`
+
+	Receipts Receipts `yaml:"RECEIPTS"`
+
+	Currency Currency `yaml:"CURRENCY"`
+
+	// Каталог локального хранилища файлов выписок
+	StatementsDir string `env:"STATEMENTS_DIR" env-default:"data/statements" yaml:"STATEMENTS_DIR"`
+	// Адрес user_service: из него берутся маршруты поездок для выписок
+	UserServiceAddr string `env:"USER_SERVICE_ADDR" env-default:"localhost:50052" yaml:"USER_SERVICE_ADDR"`
+	// Адрес room_service: по нему проверяются создатель, участники и доля поездки перед списанием
+	RoomServiceAddr string `env:"ROOM_SERVICE_ADDR" env-default:"localhost:50051" yaml:"ROOM_SERVICE_ADDR"`
+
+	JWKSURL     string        `env:"JWKS_URL"              env-default:"http://localhost:8082/.well-known/jwks.json" yaml:"JWKS_URL"`
+	JWKSRefresh time.Duration `env:"JWKS_REFRESH_INTERVAL" env-default:"5m"                                          yaml:"JWKS_REFRESH_INTERVAL"`
+}
+
+// ProviderLimits — параллельность, частота запросов к провайдеру и повторы временных ошибок (429, 5xx)
+type ProviderLimits struct {
+	// Сколько пассажиров одного запроса обрабатывается параллельно
+	Concurrency int     `yaml:"PROVIDER_CONCURRENCY" env:"PROVIDER_CONCURRENCY" env-default:"4"`
+	RPS         float64 `yaml:"PROVIDER_RPS"         env:"PROVIDER_RPS"         env-default:"10"`
+	Burst       int     `yaml:"PROVIDER_BURST"       env:"PROVIDER_BURST"       env-default:"10"`
+
+	MaxRetries     int           `yaml:"PROVIDER_MAX_RETRIES"      env:"PROVIDER_MAX_RETRIES"      env-default:"3"`
+	RetryBaseDelay time.Duration `yaml:"PROVIDER_RETRY_BASE_DELAY" env:"PROVIDER_RETRY_BASE_DELAY" env-default:"200ms"`
+	RetryMaxDelay  time.Duration `yaml:"PROVIDER_RETRY_MAX_DELAY"  env:"PROVIDER_RETRY_MAX_DELAY"  env-default:"5s"`
+}
+
+// Reconcile — сверка зависших платежей с ЮKassa на случай потерянных уведомлений
+type Reconcile struct {
+	Interval  time.Duration `yaml:"RECONCILE_INTERVAL"  env:"RECONCILE_INTERVAL"  env-default:"5m"`
+	Threshold time.Duration `yaml:"RECONCILE_THRESHOLD" env:"RECONCILE_THRESHOLD" env-default:"15m"`
+	BatchSize int           `yaml:"RECONCILE_BATCH"     env:"RECONCILE_BATCH"     env-default:"100"`
+}
+
+// Payouts — выплаты заработка водителям
+type Payouts struct {
+	// Провайдер выплат: fake; пусто — fake при фейковом провайдере платежей, иначе выплаты выключены
+	Provider string        `yaml:"PAYOUT_PROVIDER" env:"PAYOUT_PROVIDER"`
+	Interval time.Duration `yaml:"PAYOUT_INTERVAL" env:"PAYOUT_INTERVAL" env-default:"1h"`
+	Period   time.Duration `yaml:"PAYOUT_PERIOD"   env:"PAYOUT_PERIOD"   env-default:"24h"`
+}
+
+// Reminders — напоминания пассажирам, не подтвердившим свою долю по ссылке
+type Reminders struct {
+	Interval  time.Duration `yaml:"REMINDER_INTERVAL" env:"REMINDER_INTERVAL" env-default:"10m"`
+	After     time.Duration `yaml:"REMINDER_AFTER"    env:"REMINDER_AFTER"    env-default:"1h"`
+	Max       int           `yaml:"REMINDER_MAX"      env:"REMINDER_MAX"      env-default:"3"`
+	BatchSize int           `yaml:"REMINDER_BATCH"    env:"REMINDER_BATCH"    env-default:"100"`
+}
+
+// Receipts — чеки по 54-ФЗ, которые ЮKassa регистрирует в ОФД; включаются, если они подключены в магазине
+type Receipts struct {
+	Enabled bool `yaml:"RECEIPTS_ENABLED" env:"RECEIPTS_ENABLED" env-default:"false"`
+	// Ставка НДС по справочнику ЮKassa: 1 — без НДС
+	VatCode int `yaml:"RECEIPT_VAT_CODE" env:"RECEIPT_VAT_CODE" env-default:"1"`
+	// Система налогообложения; 0 — не передавать (у магазина одна система)
+	TaxSystemCode int    `yaml:"RECEIPT_TAX_SYSTEM_CODE" env:"RECEIPT_TAX_SYSTEM_CODE" env-default:"0"`
+	SellerName    string `yaml:"RECEIPT_SELLER_NAME"     env:"RECEIPT_SELLER_NAME"     env-default:"WeRide"`
+	SellerINN     string `yaml:"RECEIPT_SELLER_INN"      env:"RECEIPT_SELLER_INN"`
+}
+
+// Currency — валюты поездок по ISO 4217 и курсы для сводных отчётов
+type Currency struct {
+	// Валюта поездок, для которых room_service её не передал
+	Default string `yaml:"CURRENCY" env:"CURRENCY" env-default:"RUB"`
+	// Принимаемые валюты, через запятую
+	Accepted []string `yaml:"CURRENCIES" env:"CURRENCIES" env-default:"RUB" env-separator:","`
+	// Валюта отчётов о заработке; пусто — CURRENCY
+	Report string `yaml:"REPORT_CURRENCY" env:"REPORT_CURRENCY"`
+	// JSON-файл с курсами; пусто — отчёты только по поездкам в валюте отчёта
+	RatesFile string `yaml:"EXCHANGE_RATES_FILE" env:"EXCHANGE_RATES_FILE"`
+}
+
+// FakeProvider — фейковый провайдер для локального запуска и тестов без ЮKassa
+type FakeProvider struct {
+	SettleDelay time.Duration `yaml:"FAKE_SETTLE_DELAY" env:"FAKE_SETTLE_DELAY" env-default:"2s"`
+	// Адрес webhook-сервера, каким его видит сам сервис: туда уходят уведомления и confirmation_url
+	PublicURL string `yaml:"FAKE_PUBLIC_URL" env:"FAKE_PUBLIC_URL" env-default:"http://localhost:8083"`
 }
 
 func New() (*Config, error) {
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS refunds (
+    refund_id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id         UUID          NOT NULL REFERENCES payments(payment_id),
+    amount             NUMERIC(10,2) NOT NULL CHECK (amount > 0),
+    status             VARCHAR(30)   NOT NULL DEFAULT 'pending',
+    yookassa_refund_id VARCHAR(255),
+    reason             TEXT,
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS refunds_payment_idx ON refunds(payment_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS payments_active_hold_idx;
+ALTER TABLE payments DROP COLUMN IF EXISTS authorized_amount;
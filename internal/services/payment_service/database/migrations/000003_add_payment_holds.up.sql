@@ -0,0 +1,5 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS authorized_amount NUMERIC(10,2);
+
+-- Не больше одного действующего холда пассажира в комнате
+CREATE UNIQUE INDEX IF NOT EXISTS payments_active_hold_idx ON payments(room_id, user_id)
+    WHERE authorized_amount IS NOT NULL AND status IN ('pending', 'waiting_for_capture');
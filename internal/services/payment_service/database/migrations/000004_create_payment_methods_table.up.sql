@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS payment_methods (
+    method_id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id            UUID         NOT NULL,
+    status             VARCHAR(30)  NOT NULL DEFAULT 'pending',
+    provider_method_id VARCHAR(255),
+    binding_payment_id VARCHAR(255) NOT NULL UNIQUE,
+    type               VARCHAR(50)  NOT NULL DEFAULT '',
+    title              VARCHAR(255) NOT NULL DEFAULT '',
+    is_default         BOOLEAN      NOT NULL DEFAULT false,
+    created_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS payment_methods_user_idx ON payment_methods(user_id);
+CREATE UNIQUE INDEX IF NOT EXISTS payment_methods_default_idx ON payment_methods(user_id) WHERE is_default;
@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS ledger_entries_balanced ON ledger_entries;
+DROP FUNCTION IF EXISTS ledger_check_balance();
+DROP TABLE IF EXISTS ledger_entries;
+DROP TABLE IF EXISTS ledger_transactions;
+DROP TABLE IF EXISTS ledger_accounts;
@@ -0,0 +1,45 @@
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    account_id   VARCHAR(100) PRIMARY KEY,
+    account_type VARCHAR(20)  NOT NULL,
+    owner_id     VARCHAR(255),
+    currency     VARCHAR(10)  NOT NULL DEFAULT 'RUB',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS ledger_accounts_owner_idx ON ledger_accounts(owner_id);
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    tx_id       UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    kind        VARCHAR(20)  NOT NULL,
+    reference   VARCHAR(255) NOT NULL,
+    description TEXT,
+    created_at  TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    UNIQUE (kind, reference)
+);
+
+-- amount > 0 — дебет, < 0 — кредит; сумма проводок транзакции равна нулю
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    entry_id   BIGSERIAL PRIMARY KEY,
+    tx_id      UUID          NOT NULL REFERENCES ledger_transactions(tx_id),
+    account_id VARCHAR(100)  NOT NULL REFERENCES ledger_accounts(account_id),
+    amount     NUMERIC(12,2) NOT NULL CHECK (amount <> 0),
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS ledger_entries_account_idx ON ledger_entries(account_id, entry_id DESC);
+CREATE INDEX IF NOT EXISTS ledger_entries_tx_idx ON ledger_entries(tx_id);
+
+-- Несбалансированная транзакция откатывается при коммите
+CREATE OR REPLACE FUNCTION ledger_check_balance() RETURNS trigger AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger_entries WHERE tx_id = NEW.tx_id) <> 0 THEN
+        RAISE EXCEPTION 'ledger transaction % is not balanced', NEW.tx_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER ledger_entries_balanced
+    AFTER INSERT ON ledger_entries
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger_check_balance();
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS driver_earnings;
+DROP TABLE IF EXISTS payouts;
+ALTER TABLE payments DROP COLUMN IF EXISTS driver_id;
@@ -0,0 +1,34 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS driver_id UUID;
+
+CREATE TABLE IF NOT EXISTS payouts (
+    payout_id          UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    driver_id          UUID          NOT NULL,
+    amount             NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    currency           VARCHAR(10)   NOT NULL DEFAULT 'RUB',
+    period_end         TIMESTAMPTZ   NOT NULL,
+    status             VARCHAR(30)   NOT NULL DEFAULT 'pending',
+    provider_payout_id VARCHAR(255),
+    error              TEXT,
+    created_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW(),
+    updated_at         TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS payouts_driver_idx ON payouts(driver_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS payouts_pending_idx ON payouts(status) WHERE status = 'pending';
+
+-- Заработок водителя по оплаченной доле пассажира; payout_id — выплата, в которую он вошёл
+CREATE TABLE IF NOT EXISTS driver_earnings (
+    earning_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    driver_id  UUID          NOT NULL,
+    room_id    UUID          NOT NULL,
+    payment_id UUID          NOT NULL UNIQUE REFERENCES payments(payment_id),
+    gross      NUMERIC(12,2) NOT NULL,
+    commission NUMERIC(12,2) NOT NULL,
+    net        NUMERIC(12,2) NOT NULL,
+    currency   VARCHAR(10)   NOT NULL DEFAULT 'RUB',
+    payout_id  UUID REFERENCES payouts(payout_id),
+    created_at TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS driver_earnings_driver_idx ON driver_earnings(driver_id, created_at);
+CREATE INDEX IF NOT EXISTS driver_earnings_unpaid_idx ON driver_earnings(driver_id) WHERE payout_id IS NULL;
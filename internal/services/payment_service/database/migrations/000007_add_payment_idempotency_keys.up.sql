@@ -0,0 +1,4 @@
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS idempotency_key VARCHAR(255);
+
+-- Повтор ProcessPayment с тем же ключом возвращает сохранённый платёж, а не создаёт новый
+CREATE UNIQUE INDEX IF NOT EXISTS payments_idempotency_key_idx ON payments(idempotency_key);
@@ -0,0 +1,3 @@
+ALTER TABLE payments DROP COLUMN IF EXISTS reminded_at;
+ALTER TABLE payments DROP COLUMN IF EXISTS reminders;
+ALTER TABLE payments DROP COLUMN IF EXISTS confirmation_url;
@@ -0,0 +1,4 @@
+-- Ссылка, по которой пассажир подтверждает платёж, и напоминания о неоплаченной доле
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS confirmation_url TEXT;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reminders INT NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;
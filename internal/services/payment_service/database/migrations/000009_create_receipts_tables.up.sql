@@ -0,0 +1,30 @@
+-- Контакт для чеков по 54-ФЗ: туда ОФД отправляет чек
+CREATE TABLE IF NOT EXISTS receipt_contacts (
+    user_id    UUID PRIMARY KEY,
+    email      VARCHAR(255) NOT NULL DEFAULT '',
+    phone      VARCHAR(20)  NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+-- Чеки, переданные провайдеру: приход по платежу и возврат прихода по возврату
+CREATE TABLE IF NOT EXISTS receipts (
+    receipt_id      UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    payment_id      UUID          NOT NULL REFERENCES payments(payment_id),
+    refund_id       UUID REFERENCES refunds(refund_id),
+    type            VARCHAR(20)   NOT NULL,
+    user_id         UUID          NOT NULL,
+    email           VARCHAR(255)  NOT NULL DEFAULT '',
+    phone           VARCHAR(20)   NOT NULL DEFAULT '',
+    description     VARCHAR(128)  NOT NULL,
+    amount          NUMERIC(12,2) NOT NULL CHECK (amount > 0),
+    currency        VARCHAR(10)   NOT NULL DEFAULT 'RUB',
+    vat_code        INT           NOT NULL,
+    tax_system_code INT           NOT NULL DEFAULT 0,
+    payment_subject VARCHAR(30)   NOT NULL,
+    payment_mode    VARCHAR(30)   NOT NULL,
+    created_at      TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS receipts_payment_idx ON receipts(payment_id) WHERE refund_id IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS receipts_refund_idx ON receipts(refund_id) WHERE refund_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS receipts_user_idx ON receipts(user_id);
@@ -0,0 +1,7 @@
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_id_fkey;
+
+UPDATE ledger_entries SET account_id = regexp_replace(account_id, ':[A-Z]{3}$', '');
+UPDATE ledger_accounts SET account_id = regexp_replace(account_id, ':[A-Z]{3}$', '');
+
+ALTER TABLE ledger_entries
+    ADD CONSTRAINT ledger_entries_account_id_fkey FOREIGN KEY (account_id) REFERENCES ledger_accounts(account_id);
@@ -0,0 +1,11 @@
+-- Счета ведутся отдельно по валютам: к идентификатору счёта добавляется код валюты (rider:<id>:RUB)
+ALTER TABLE ledger_entries DROP CONSTRAINT IF EXISTS ledger_entries_account_id_fkey;
+
+UPDATE ledger_entries e SET account_id = e.account_id || ':' || a.currency
+FROM ledger_accounts a
+WHERE a.account_id = e.account_id;
+
+UPDATE ledger_accounts SET account_id = account_id || ':' || currency;
+
+ALTER TABLE ledger_entries
+    ADD CONSTRAINT ledger_entries_account_id_fkey FOREIGN KEY (account_id) REFERENCES ledger_accounts(account_id);
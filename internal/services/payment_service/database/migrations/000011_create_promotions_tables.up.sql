@@ -0,0 +1,32 @@
+-- Промокоды: скидка в процентах от доли пассажира или фиксированной суммой в валюте кода
+CREATE TABLE IF NOT EXISTS promo_codes (
+    code              VARCHAR(50)   PRIMARY KEY,
+    type              VARCHAR(20)   NOT NULL CHECK (type IN ('percent', 'fixed')),
+    value             NUMERIC(12,2) NOT NULL CHECK (value > 0),
+    currency          VARCHAR(10)   NOT NULL DEFAULT '',
+    max_uses          INT           NOT NULL DEFAULT 0,
+    max_uses_per_user INT           NOT NULL DEFAULT 1,
+    first_ride_only   BOOLEAN       NOT NULL DEFAULT false,
+    valid_from        TIMESTAMPTZ,
+    valid_until       TIMESTAMPTZ,
+    active            BOOLEAN       NOT NULL DEFAULT true,
+    created_by        UUID,
+    created_at        TIMESTAMPTZ   NOT NULL DEFAULT NOW()
+);
+
+-- Применение кода к участию пассажира в поездке; redeemed — скидка учтена в платеже
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    redemption_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code          VARCHAR(50) NOT NULL REFERENCES promo_codes(code),
+    room_id       UUID        NOT NULL,
+    user_id       UUID        NOT NULL,
+    status        VARCHAR(20) NOT NULL DEFAULT 'applied',
+    payment_id    UUID REFERENCES payments(payment_id),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (room_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_code_idx ON promo_redemptions(code, user_id);
+
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS discount NUMERIC(12,2) NOT NULL DEFAULT 0;
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS promo_code VARCHAR(50);
@@ -0,0 +1,3 @@
+-- Назначение платежа: оплата доли в поездке или штраф за позднюю отмену / неявку
+ALTER TABLE payments ADD COLUMN IF NOT EXISTS kind VARCHAR(20) NOT NULL DEFAULT 'ride'
+    CHECK (kind IN ('ride', 'cancellation_fee', 'no_show_fee'));
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS payments_user_history_idx;
+CREATE INDEX IF NOT EXISTS payments_user_idx ON payments(user_id);
@@ -0,0 +1,3 @@
+-- История платежей пользователя постранично: ORDER BY created_at DESC, payment_id DESC
+DROP INDEX IF EXISTS payments_user_idx;
+CREATE INDEX IF NOT EXISTS payments_user_history_idx ON payments(user_id, created_at DESC, payment_id DESC);
@@ -0,0 +1,15 @@
+-- Выписки за месяц: файл рендерится в фоне и хранится в локальном хранилище сервиса
+CREATE TABLE IF NOT EXISTS statements (
+    statement_id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id      UUID         NOT NULL,
+    role         VARCHAR(10)  NOT NULL,
+    month        DATE         NOT NULL,
+    format       VARCHAR(10)  NOT NULL,
+    status       VARCHAR(20)  NOT NULL DEFAULT 'pending',
+    file_name    VARCHAR(255) NOT NULL DEFAULT '',
+    error        TEXT         NOT NULL DEFAULT '',
+    created_at   TIMESTAMPTZ  NOT NULL DEFAULT NOW(),
+    finished_at  TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS statements_user_idx ON statements(user_id, created_at DESC);
@@ -0,0 +1,62 @@
+package broker
+
+import (
+	"sync"
+
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// bufferSize — сколько обновлений ждёт медленного подписчика, дальше они отбрасываются
+const bufferSize = 16
+
+// Broker раздаёт обновления платежей подписчикам внутри процесса.
+// Подписки живут, пока открыт стрим StreamPaymentUpdates; пропущенное клиент
+// дочитывает через GetPaymentHistory.
+type Broker struct {
+	mu     sync.Mutex
+	nextID int
+	subs   map[string]map[int]chan *pb.PaymentUpdate
+}
+
+func New() *Broker {
+	return &Broker{subs: make(map[string]map[int]chan *pb.PaymentUpdate)}
+}
+
+// Subscribe подписывает на обновления платежей пользователя; cancel закрывает канал
+func (b *Broker) Subscribe(userID string) (<-chan *pb.PaymentUpdate, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	id := b.nextID
+	ch := make(chan *pb.PaymentUpdate, bufferSize)
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[int]chan *pb.PaymentUpdate)
+	}
+	b.subs[userID][id] = ch
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subs[userID], id)
+			if len(b.subs[userID]) == 0 {
+				delete(b.subs, userID)
+			}
+			close(ch)
+		})
+	}
+}
+
+// Publish отправляет обновление подписчикам владельца платежа, не блокируясь
+func (b *Broker) Publish(u *pb.PaymentUpdate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, ch := range b.subs[u.UserId] {
+		select {
+		case ch <- u:
+		default:
+		}
+	}
+}
@@ -0,0 +1,198 @@
+// Package ledger — внутренняя двойная запись: каждое движение денег — транзакция из проводок,
+// сумма которых равна нулю. Проводка с положительной суммой — дебет счёта, с отрицательной — кредит.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"we_ride/internal/services/payment_service/internal/money"
+)
+
+// Типы счетов
+const (
+	AccountRider      = "rider"      // средства пассажира на платформе
+	AccountDriver     = "driver"     // заработок водителя к выплате
+	AccountCommission = "commission" // комиссия платформы
+	AccountClearing   = "clearing"   // деньги у платёжного провайдера
+	AccountPromo      = "promo"      // скидки по промокодам за счёт платформы
+)
+
+// Владельцы счетов платформы: счета одни на всех, по одному на валюту
+const (
+	platformOwner = "platform"
+	providerOwner = "provider"
+)
+
+// Виды транзакций. Пара (вид, основание) уникальна: повторная проводка того же платежа не записывается.
+const (
+	KindCharge     = "charge"
+	KindRide       = "ride"
+	KindRefund     = "refund"
+	KindCommission = "commission"
+	KindPayout     = "payout"
+	KindDiscount   = "discount"
+)
+
+var (
+	ErrUnbalanced   = errors.New("ledger transaction is not balanced")
+	ErrEmptyEntries = errors.New("ledger transaction needs at least two non-zero entries")
+)
+
+// Entry — проводка по счёту: > 0 — дебет, < 0 — кредит
+type Entry struct {
+	AccountID string
+	Amount    float64
+}
+
+// Transaction — проводки одного движения денег. Reference — основание: id платежа, возврата или выплаты.
+type Transaction struct {
+	Kind        string
+	Reference   string
+	Description string
+	Currency    string
+	Entries     []Entry
+}
+
+// Validate проверяет, что проводки сбалансированы с точностью до минимальной единицы валюты
+func (t *Transaction) Validate() error {
+	if len(t.Entries) < 2 {
+		return ErrEmptyEntries
+	}
+	var sum int64
+	for _, e := range t.Entries {
+		c := money.ToMinor(e.Amount, t.Currency)
+		if c == 0 {
+			return ErrEmptyEntries
+		}
+		sum += c
+	}
+	if sum != 0 {
+		return fmt.Errorf("%w: %s %s is off by %d minor units of %s", ErrUnbalanced, t.Kind, t.Reference, sum, t.Currency)
+	}
+	return nil
+}
+
+// Счёт — <тип>:<владелец>:<валюта>: у пользователя отдельный счёт в каждой валюте
+func RiderAccount(userID, currency string) string  { return account(AccountRider, userID, currency) }
+func DriverAccount(userID, currency string) string { return account(AccountDriver, userID, currency) }
+func CommissionAccount(currency string) string {
+	return account(AccountCommission, platformOwner, currency)
+}
+func ClearingAccount(currency string) string {
+	return account(AccountClearing, providerOwner, currency)
+}
+func PromoAccount(currency string) string {
+	return account(AccountPromo, platformOwner, currency)
+}
+
+func account(accountType, ownerID, currency string) string {
+	return accountType + ":" + ownerID + ":" + currency
+}
+
+// ParseAccount возвращает тип счёта, владельца и валюту; у счетов платформы владельца нет
+func ParseAccount(accountID string) (accountType, ownerID, currency string) {
+	accountType, rest, _ := strings.Cut(accountID, ":")
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		ownerID, currency = rest[:i], rest[i+1:]
+	}
+	if accountType == AccountCommission || accountType == AccountClearing || accountType == AccountPromo {
+		ownerID = ""
+	}
+	return accountType, ownerID, currency
+}
+
+// Sign — знак, с которым проводки входят в баланс счёта. Счета пассажиров, водителей и комиссии —
+// обязательства и доходы платформы, они растут по кредиту; clearing — актив и promo — расходы,
+// они растут по дебету.
+func Sign(accountType string) float64 {
+	if accountType == AccountClearing || accountType == AccountPromo {
+		return 1
+	}
+	return -1
+}
+
+// Charge — пассажир оплатил поездку: деньги пришли к провайдеру и числятся за пассажиром
+func Charge(userID, paymentID string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindCharge,
+		Reference:   paymentID,
+		Description: "Оплата поездки",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: ClearingAccount(currency), Amount: amount},
+			{AccountID: RiderAccount(userID, currency), Amount: -amount},
+		},
+	}
+}
+
+// Discount — платформа оплачивает за пассажира скидку по промокоду
+func Discount(userID, paymentID string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindDiscount,
+		Reference:   paymentID,
+		Description: "Скидка по промокоду",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: PromoAccount(currency), Amount: amount},
+			{AccountID: RiderAccount(userID, currency), Amount: -amount},
+		},
+	}
+}
+
+// Ride — оплаченная доля пассажира переходит в заработок водителя поездки
+func Ride(userID, driverID, paymentID string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindRide,
+		Reference:   paymentID,
+		Description: "Заработок за поездку",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: RiderAccount(userID, currency), Amount: amount},
+			{AccountID: DriverAccount(driverID, currency), Amount: -amount},
+		},
+	}
+}
+
+// Refund — возврат пассажиру через провайдера
+func Refund(userID, refundID string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindRefund,
+		Reference:   refundID,
+		Description: "Возврат",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: RiderAccount(userID, currency), Amount: amount},
+			{AccountID: ClearingAccount(currency), Amount: -amount},
+		},
+	}
+}
+
+// Commission — удержание комиссии платформы из заработка водителя
+func Commission(driverID, reference string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindCommission,
+		Reference:   reference,
+		Description: "Комиссия платформы",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: DriverAccount(driverID, currency), Amount: amount},
+			{AccountID: CommissionAccount(currency), Amount: -amount},
+		},
+	}
+}
+
+// Payout — выплата водителю: деньги уходят от провайдера на его счёт
+func Payout(driverID, payoutID string, amount float64, currency string) Transaction {
+	return Transaction{
+		Kind:        KindPayout,
+		Reference:   payoutID,
+		Description: "Выплата водителю",
+		Currency:    currency,
+		Entries: []Entry{
+			{AccountID: DriverAccount(driverID, currency), Amount: amount},
+			{AccountID: ClearingAccount(currency), Amount: -amount},
+		},
+	}
+}
@@ -0,0 +1,87 @@
+package money
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrNoRate — курс для пары валют неизвестен
+var ErrNoRate = errors.New("exchange rate is not available")
+
+// Rates — источник курсов валют для отчётов, сводящих суммы в разных валютах.
+// Платежи всегда проводятся в валюте поездки, курсы к ним не применяются.
+type Rates interface {
+	// Rate — сколько единиц to стоит одна единица from
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// Convert переводит сумму из from в to и округляет до минимальной единицы to.
+// Для одной валюты курс не нужен, rates может быть nil.
+func Convert(ctx context.Context, rates Rates, amount float64, from, to string) (float64, error) {
+	if from == to {
+		return amount, nil
+	}
+	if rates == nil {
+		return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+	rate, err := rates.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return Round(amount*rate, to), nil
+}
+
+// StaticRates — курсы из файла для локального запуска и тестов. Файл — JSON вида
+// {"base": "RUB", "rates": {"USD": 92.5, "EUR": 100.2}}: стоимость единицы валюты в базовой.
+type StaticRates struct {
+	Base  string             `json:"base"`
+	Rates map[string]float64 `json:"rates"`
+}
+
+// LoadStaticRates читает и проверяет файл курсов
+func LoadStaticRates(path string) (*StaticRates, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("LoadStaticRates: %w", err)
+	}
+	var r StaticRates
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("LoadStaticRates %s: %w", path, err)
+	}
+	if err := Validate(r.Base); err != nil {
+		return nil, fmt.Errorf("LoadStaticRates base: %w", err)
+	}
+	for code, rate := range r.Rates {
+		if err := Validate(code); err != nil {
+			return nil, fmt.Errorf("LoadStaticRates: %w", err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("LoadStaticRates: rate for %s must be positive", code)
+		}
+	}
+	return &r, nil
+}
+
+func (r *StaticRates) Rate(_ context.Context, from, to string) (float64, error) {
+	fromBase, ok := r.inBase(from)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+	toBase, ok := r.inBase(to)
+	if !ok {
+		return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+	}
+	return fromBase / toBase, nil
+}
+
+// inBase — стоимость единицы валюты в базовой
+func (r *StaticRates) inBase(code string) (float64, bool) {
+	if code == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[code]
+	return rate, ok
+}
@@ -0,0 +1,356 @@
+// Package fake — платёжный провайдер в памяти для локального запуска и end-to-end тестов без ЮKassa.
+// Повторяет поведение ЮKassa: платёж создаётся в pending и через SettleDelay переходит в succeeded,
+// waiting_for_capture (холд) или canceled (отказ), о каждом переходе уходит HTTP-уведомление.
+// Сохранённый способ оплаты списывается сразу, без подтверждения. Provider реализует и
+// provider.PayoutProvider: выплаты проходят сразу, кроме выплат пользователям с отказом.
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"we_ride/internal/services/payment_service/internal/provider"
+)
+
+// Options — поведение фейкового провайдера
+type Options struct {
+	// SettleDelay — через сколько pending-платёж получает итоговый статус; 0 — сразу при создании
+	SettleDelay time.Duration
+	// WebhookURL — куда отправлять уведомления; пусто — не отправлять
+	WebhookURL string
+	// BaseURL — адрес, на котором смонтирован Handler; из него строится confirmation_url
+	BaseURL string
+	// DeclineUsers — пользователи (metadata user_id), чьи платежи отклоняются
+	DeclineUsers []string
+}
+
+// webhookAttempts — сколько раз уведомление отправляется, пока сервис не ответит 200
+const webhookAttempts = 3
+
+type payment struct {
+	provider.Payment
+	capture    bool
+	saveMethod bool
+	refunded   float64
+}
+
+// Provider — фейковый provider.PaymentProvider
+type Provider struct {
+	opts       Options
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	payments map[string]*payment
+	refunds  map[string]*provider.Refund
+	keys     map[string]string // idempotencyKey → id созданного объекта
+	declined map[string]bool
+	methods  map[string]*provider.PaymentMethod
+	payouts  map[string]*provider.Payout
+}
+
+func New(opts Options) *Provider {
+	p := &Provider{
+		opts:       opts,
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		payments:   make(map[string]*payment),
+		refunds:    make(map[string]*provider.Refund),
+		keys:       make(map[string]string),
+		declined:   make(map[string]bool),
+		methods:    make(map[string]*provider.PaymentMethod),
+		payouts:    make(map[string]*provider.Payout),
+	}
+	for _, id := range opts.DeclineUsers {
+		p.declined[id] = true
+	}
+	return p
+}
+
+func (p *Provider) Name() string { return "fake" }
+
+// SetDecline включает или выключает отказ по платежам пользователя
+func (p *Provider) SetDecline(userID string, decline bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if decline {
+		p.declined[userID] = true
+	} else {
+		delete(p.declined, userID)
+	}
+}
+
+func (p *Provider) CreatePayment(_ context.Context, idempotencyKey string, req provider.CreatePaymentRequest) (*provider.Payment, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("fake provider: amount must be greater than 0")
+	}
+
+	p.mu.Lock()
+	if id, ok := p.keys[idempotencyKey]; ok {
+		defer p.mu.Unlock()
+		return p.snapshot(p.payments[id]), nil
+	}
+	pay := &payment{
+		Payment: provider.Payment{
+			ID:          "fake-" + uuid.New().String(),
+			Status:      provider.StatusPending,
+			Amount:      req.Amount,
+			Currency:    req.Currency,
+			Description: req.Description,
+			Metadata:    req.Metadata,
+		},
+		capture:    req.Capture,
+		saveMethod: req.SavePaymentMethod,
+	}
+	// Сохранённым способом платёж проходит без подтверждения пользователем
+	if req.PaymentMethodID != "" {
+		method, ok := p.methods[req.PaymentMethodID]
+		if !ok {
+			p.mu.Unlock()
+			return nil, fmt.Errorf("fake provider: unknown payment method %s", req.PaymentMethodID)
+		}
+		pay.PaymentMethod = method
+	} else if p.opts.SettleDelay > 0 {
+		pay.ConfirmationURL = fmt.Sprintf("%s/fake/payments/%s/confirm", p.opts.BaseURL, pay.ID)
+	}
+	p.payments[pay.ID] = pay
+	p.keys[idempotencyKey] = pay.ID
+	p.mu.Unlock()
+
+	if p.opts.SettleDelay == 0 || req.PaymentMethodID != "" {
+		_ = p.Settle(pay.ID)
+	} else {
+		time.AfterFunc(p.opts.SettleDelay, func() { _ = p.Settle(pay.ID) })
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.snapshot(pay), nil
+}
+
+// Settle переводит pending-платёж в итоговый статус, как если бы пользователь его подтвердил.
+// Платёж в другом статусе не меняется.
+func (p *Provider) Settle(paymentID string) error {
+	p.mu.Lock()
+	pay, ok := p.payments[paymentID]
+	if !ok {
+		p.mu.Unlock()
+		return provider.ErrNotFound
+	}
+	if pay.Status != provider.StatusPending {
+		p.mu.Unlock()
+		return nil
+	}
+	var event string
+	switch {
+	case p.declined[pay.Metadata["user_id"]]:
+		pay.Status, event = provider.StatusCanceled, provider.EventPaymentCanceled
+	case pay.capture:
+		pay.Status, event = provider.StatusSucceeded, provider.EventPaymentSucceeded
+	default:
+		pay.Status, event = provider.StatusWaitingForCapture, provider.EventPaymentWaitingForCapture
+	}
+	if pay.saveMethod && pay.Status != provider.StatusCanceled {
+		pay.PaymentMethod = &provider.PaymentMethod{
+			ID:    "fake-pm-" + uuid.New().String(),
+			Type:  "bank_card",
+			Title: "Bank card *4242",
+			Saved: true,
+		}
+		p.methods[pay.PaymentMethod.ID] = pay.PaymentMethod
+	}
+	p.mu.Unlock()
+
+	p.notify(event, paymentID)
+	return nil
+}
+
+func (p *Provider) GetPayment(_ context.Context, paymentID string) (*provider.Payment, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	pay, ok := p.payments[paymentID]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return p.snapshot(pay), nil
+}
+
+func (p *Provider) CapturePayment(_ context.Context, idempotencyKey, paymentID string, amount float64, _ string, _ *provider.Receipt) (*provider.Payment, error) {
+	p.mu.Lock()
+	pay, ok := p.payments[paymentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, provider.ErrNotFound
+	}
+	if id, ok := p.keys[idempotencyKey]; ok && id == paymentID {
+		defer p.mu.Unlock()
+		return p.snapshot(pay), nil
+	}
+	if pay.Status != provider.StatusWaitingForCapture {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("fake provider: cannot capture payment in status %s", pay.Status)
+	}
+	if amount <= 0 || cents(amount) > cents(pay.Amount) {
+		p.mu.Unlock()
+		return nil, errors.New("fake provider: capture amount exceeds authorized amount")
+	}
+	pay.Status, pay.Amount = provider.StatusSucceeded, amount
+	p.keys[idempotencyKey] = paymentID
+	result := p.snapshot(pay)
+	p.mu.Unlock()
+
+	p.notify(provider.EventPaymentSucceeded, paymentID)
+	return result, nil
+}
+
+func (p *Provider) CancelPayment(_ context.Context, _, paymentID string) (*provider.Payment, error) {
+	p.mu.Lock()
+	pay, ok := p.payments[paymentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, provider.ErrNotFound
+	}
+	switch pay.Status {
+	case provider.StatusCanceled:
+		defer p.mu.Unlock()
+		return p.snapshot(pay), nil
+	case provider.StatusWaitingForCapture:
+	default:
+		p.mu.Unlock()
+		return nil, fmt.Errorf("fake provider: cannot cancel payment in status %s", pay.Status)
+	}
+	pay.Status = provider.StatusCanceled
+	result := p.snapshot(pay)
+	p.mu.Unlock()
+
+	p.notify(provider.EventPaymentCanceled, paymentID)
+	return result, nil
+}
+
+func (p *Provider) CreateRefund(_ context.Context, idempotencyKey string, req provider.RefundRequest) (*provider.Refund, error) {
+	p.mu.Lock()
+	if id, ok := p.keys[idempotencyKey]; ok {
+		defer p.mu.Unlock()
+		r := *p.refunds[id]
+		return &r, nil
+	}
+	pay, ok := p.payments[req.PaymentID]
+	if !ok {
+		p.mu.Unlock()
+		return nil, provider.ErrNotFound
+	}
+	if pay.Status != provider.StatusSucceeded {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("fake provider: cannot refund payment in status %s", pay.Status)
+	}
+	if req.Amount <= 0 || cents(pay.refunded+req.Amount) > cents(pay.Amount) {
+		p.mu.Unlock()
+		return nil, errors.New("fake provider: refund amount exceeds payment amount")
+	}
+	pay.refunded += req.Amount
+	refund := &provider.Refund{
+		ID:        "fake-" + uuid.New().String(),
+		PaymentID: pay.ID,
+		Status:    provider.StatusSucceeded,
+		Amount:    req.Amount,
+		Currency:  pay.Currency,
+	}
+	p.refunds[refund.ID] = refund
+	p.keys[idempotencyKey] = refund.ID
+	result := *refund
+	p.mu.Unlock()
+
+	p.notify(provider.EventRefundSucceeded, refund.ID)
+	return &result, nil
+}
+
+func (p *Provider) GetRefund(_ context.Context, refundID string) (*provider.Refund, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r, ok := p.refunds[refundID]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	result := *r
+	return &result, nil
+}
+
+func (p *Provider) CreatePayout(_ context.Context, idempotencyKey string, req provider.PayoutRequest) (*provider.Payout, error) {
+	if req.Amount <= 0 {
+		return nil, errors.New("fake provider: payout amount must be greater than 0")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if id, ok := p.keys[idempotencyKey]; ok {
+		result := *p.payouts[id]
+		return &result, nil
+	}
+	payout := &provider.Payout{
+		ID:       "fake-" + uuid.New().String(),
+		Status:   provider.StatusSucceeded,
+		Amount:   req.Amount,
+		Currency: req.Currency,
+	}
+	if p.declined[req.RecipientID] {
+		payout.Status = provider.StatusCanceled
+	}
+	p.payouts[payout.ID] = payout
+	p.keys[idempotencyKey] = payout.ID
+	result := *payout
+	return &result, nil
+}
+
+// notification — тело уведомления фейкового провайдера
+type notification struct {
+	Event    string `json:"event"`
+	ObjectID string `json:"object_id"`
+}
+
+func (p *Provider) ParseNotification(body []byte) (*provider.Notification, error) {
+	var n notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("unmarshal notification: %w", err)
+	}
+	if n.Event == "" || n.ObjectID == "" {
+		return nil, errors.New("malformed notification")
+	}
+	return &provider.Notification{Event: n.Event, ObjectID: n.ObjectID}, nil
+}
+
+// notify отправляет уведомление в фоне. Как и ЮKassa, повторяет отправку, пока сервис не ответит 200.
+func (p *Provider) notify(event, objectID string) {
+	if p.opts.WebhookURL == "" {
+		return
+	}
+	body, _ := json.Marshal(notification{Event: event, ObjectID: objectID})
+	go func() {
+		for attempt := 1; attempt <= webhookAttempts; attempt++ {
+			resp, err := p.httpClient.Post(p.opts.WebhookURL, "application/json", bytes.NewReader(body))
+			if err == nil {
+				resp.Body.Close()
+				if resp.StatusCode == http.StatusOK {
+					return
+				}
+			}
+			time.Sleep(time.Duration(attempt) * time.Second)
+		}
+	}()
+}
+
+// snapshot копирует платёж, чтобы вызывающий не видел последующих изменений; вызывается под p.mu
+func (p *Provider) snapshot(pay *payment) *provider.Payment {
+	result := pay.Payment
+	return &result
+}
+
+func cents(v float64) int64 {
+	return int64(math.Round(v * 100))
+}
@@ -0,0 +1,52 @@
+package fake
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"we_ride/internal/services/payment_service/internal/provider"
+)
+
+// Handler — HTTP-управление фейковым провайдером, монтируется на /fake/:
+//
+//	GET    /fake/payments/{id}               — платёж в текущем состоянии
+//	POST   /fake/payments/{id}/confirm       — подтвердить pending-платёж (confirmation_url)
+//	POST   /fake/users/{user_id}/decline     — отклонять платежи пользователя
+//	DELETE /fake/users/{user_id}/decline     — снять отказ
+func (p *Provider) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /fake/payments/{id}", func(w http.ResponseWriter, r *http.Request) {
+		pay, err := p.GetPayment(r.Context(), r.PathValue("id"))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(pay)
+	})
+	mux.HandleFunc("POST /fake/payments/{id}/confirm", func(w http.ResponseWriter, r *http.Request) {
+		if err := p.Settle(r.PathValue("id")); err != nil {
+			writeError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /fake/users/{user_id}/decline", func(w http.ResponseWriter, r *http.Request) {
+		p.SetDecline(r.PathValue("user_id"), true)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /fake/users/{user_id}/decline", func(w http.ResponseWriter, r *http.Request) {
+		p.SetDecline(r.PathValue("user_id"), false)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, provider.ErrNotFound) {
+		code = http.StatusNotFound
+	}
+	http.Error(w, err.Error(), code)
+}
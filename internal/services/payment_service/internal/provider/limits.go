@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Limits — ограничения обращений к провайдеру, общие для всех запросов сервиса
+type Limits struct {
+	RPS        float64       // запросов в секунду; 0 — без ограничения
+	Burst      int           // сколько запросов можно отправить подряд сверх RPS
+	MaxRetries int           // повторов временной ошибки (429, 5xx); 0 — без повторов
+	BaseDelay  time.Duration // задержка перед первым повтором, дальше удваивается
+	MaxDelay   time.Duration // предел задержки
+}
+
+// Temporary сообщает, что запрос можно повторить: провайдер ограничивает частоту запросов или недоступен
+func Temporary(err error) bool {
+	var t interface{ Temporary() bool }
+	return errors.As(err, &t) && t.Temporary()
+}
+
+// Limited оборачивает провайдера: каждый запрос ждёт токен общего token bucket, временные ошибки
+// повторяются с экспоненциальной задержкой и джиттером. Ожидание прерывается отменой ctx.
+func Limited(p PaymentProvider, l Limits) PaymentProvider {
+	lp := &limited{PaymentProvider: p, limits: l}
+	if l.RPS > 0 {
+		lp.bucket = newTokenBucket(l.RPS, max(l.Burst, 1))
+	}
+	return lp
+}
+
+type limited struct {
+	PaymentProvider
+	limits Limits
+	bucket *tokenBucket
+}
+
+func (p *limited) CreatePayment(ctx context.Context, idempotencyKey string, req CreatePaymentRequest) (*Payment, error) {
+	return call(ctx, p, func() (*Payment, error) { return p.PaymentProvider.CreatePayment(ctx, idempotencyKey, req) })
+}
+
+func (p *limited) GetPayment(ctx context.Context, paymentID string) (*Payment, error) {
+	return call(ctx, p, func() (*Payment, error) { return p.PaymentProvider.GetPayment(ctx, paymentID) })
+}
+
+func (p *limited) CapturePayment(ctx context.Context, idempotencyKey, paymentID string, amount float64, currency string, receipt *Receipt) (*Payment, error) {
+	return call(ctx, p, func() (*Payment, error) {
+		return p.PaymentProvider.CapturePayment(ctx, idempotencyKey, paymentID, amount, currency, receipt)
+	})
+}
+
+func (p *limited) CancelPayment(ctx context.Context, idempotencyKey, paymentID string) (*Payment, error) {
+	return call(ctx, p, func() (*Payment, error) { return p.PaymentProvider.CancelPayment(ctx, idempotencyKey, paymentID) })
+}
+
+func (p *limited) CreateRefund(ctx context.Context, idempotencyKey string, req RefundRequest) (*Refund, error) {
+	return call(ctx, p, func() (*Refund, error) { return p.PaymentProvider.CreateRefund(ctx, idempotencyKey, req) })
+}
+
+func (p *limited) GetRefund(ctx context.Context, refundID string) (*Refund, error) {
+	return call(ctx, p, func() (*Refund, error) { return p.PaymentProvider.GetRefund(ctx, refundID) })
+}
+
+func call[T any](ctx context.Context, p *limited, fn func() (T, error)) (T, error) {
+	var zero T
+	for attempt := 0; ; attempt++ {
+		if p.bucket != nil {
+			if err := p.bucket.wait(ctx); err != nil {
+				return zero, err
+			}
+		}
+		resp, err := fn()
+		if err == nil || !Temporary(err) || attempt >= p.limits.MaxRetries {
+			return resp, err
+		}
+		if err := sleep(ctx, p.limits.backoff(attempt)); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// backoff — задержка перед повтором attempt+1: случайная в [0, min(MaxDelay, BaseDelay·2^attempt)],
+// чтобы повторы одновременных запросов не приходили к провайдеру разом
+func (l Limits) backoff(attempt int) time.Duration {
+	d := l.BaseDelay << min(attempt, 30)
+	if l.MaxDelay > 0 && (d > l.MaxDelay || d <= 0) {
+		d = l.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d + 1)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// tokenBucket — rate seconds⁻¹ токенов, не больше burst про запас
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// wait забирает токен, дожидаясь его при необходимости
+func (b *tokenBucket) wait(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+		if b.tokens >= 1 {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		d := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		b.mu.Unlock()
+
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
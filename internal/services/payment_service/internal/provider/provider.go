@@ -0,0 +1,146 @@
+package provider
+
+import (
+	"context"
+	"errors"
+)
+
+// Статусы платежей и возвратов. Значения совпадают со статусами ЮKassa и хранятся в БД как есть,
+// другие провайдеры переводят свои статусы в эти.
+const (
+	StatusPending           = "pending"
+	StatusWaitingForCapture = "waiting_for_capture"
+	StatusSucceeded         = "succeeded"
+	StatusCanceled          = "canceled"
+)
+
+// События уведомлений провайдера, которые обрабатывает сервис
+const (
+	EventPaymentSucceeded         = "payment.succeeded"
+	EventPaymentWaitingForCapture = "payment.waiting_for_capture"
+	EventPaymentCanceled          = "payment.canceled"
+	EventRefundSucceeded          = "refund.succeeded"
+)
+
+// ErrNotFound — провайдер не знает платёж или возврат с таким id
+var ErrNotFound = errors.New("not found at provider")
+
+// PaymentProvider — платёжный провайдер: ЮKassa или фейковый провайдер для локального запуска и тестов.
+// Повторный вызов с тем же idempotencyKey возвращает уже созданный объект.
+type PaymentProvider interface {
+	Name() string
+	CreatePayment(ctx context.Context, idempotencyKey string, req CreatePaymentRequest) (*Payment, error)
+	GetPayment(ctx context.Context, paymentID string) (*Payment, error)
+	// CapturePayment списывает холд; сумма может быть меньше авторизованной, тогда чек передаётся заново
+	CapturePayment(ctx context.Context, idempotencyKey, paymentID string, amount float64, currency string, receipt *Receipt) (*Payment, error)
+	// CancelPayment снимает холд в статусе waiting_for_capture
+	CancelPayment(ctx context.Context, idempotencyKey, paymentID string) (*Payment, error)
+	CreateRefund(ctx context.Context, idempotencyKey string, req RefundRequest) (*Refund, error)
+	GetRefund(ctx context.Context, refundID string) (*Refund, error)
+	// ParseNotification разбирает тело HTTP-уведомления провайдера
+	ParseNotification(body []byte) (*Notification, error)
+}
+
+// CreatePaymentRequest — новый платёж. Capture=false ставит холд, который списывается CapturePayment.
+type CreatePaymentRequest struct {
+	Amount      float64
+	Currency    string
+	Description string
+	ReturnURL   string // куда провайдер вернёт пользователя после подтверждения
+	Capture     bool
+	Metadata    map[string]string
+	// SavePaymentMethod просит сохранить способ оплаты; PaymentMethodID списывает сохранённым
+	// способом без подтверждения пользователем
+	SavePaymentMethod bool
+	PaymentMethodID   string
+	// Receipt — чек по 54-ФЗ; nil — платёж без чека
+	Receipt *Receipt
+}
+
+// Receipt — данные чека по 54-ФЗ: контакт, на который ОФД отправит чек, и позиции
+type Receipt struct {
+	Email         string
+	Phone         string // только цифры, с кодом страны
+	TaxSystemCode int    // система налогообложения; 0 — не передавать, если у магазина одна
+	Items         []ReceiptItem
+}
+
+// ReceiptItem — позиция чека
+type ReceiptItem struct {
+	Description    string
+	Quantity       float64
+	Amount         float64 // цена за единицу
+	Currency       string
+	VatCode        int    // ставка НДС по справочнику ЮKassa: 1 — без НДС
+	PaymentSubject string // признак предмета расчёта, например service
+	PaymentMode    string // признак способа расчёта, например full_payment
+}
+
+// Payment — платёж на стороне провайдера
+type Payment struct {
+	ID              string
+	Status          string
+	Amount          float64
+	Currency        string
+	Description     string
+	Metadata        map[string]string
+	ConfirmationURL string // пусто, если подтверждение не требуется
+	PaymentMethod   *PaymentMethod
+}
+
+// PaymentMethod — способ оплаты платежа; Saved — сохранён и доступен по ID
+type PaymentMethod struct {
+	ID    string
+	Type  string
+	Title string
+	Saved bool
+}
+
+// RefundRequest — возврат по платежу провайдера
+type RefundRequest struct {
+	PaymentID   string
+	Amount      float64
+	Currency    string
+	Description string
+	Receipt     *Receipt // чек возврата прихода; nil — без чека
+}
+
+// Refund — возврат на стороне провайдера
+type Refund struct {
+	ID        string
+	PaymentID string
+	Status    string
+	Amount    float64
+	Currency  string
+}
+
+// PayoutProvider — провайдер выплат водителям. Повторный вызов с тем же idempotencyKey
+// возвращает уже созданную выплату.
+type PayoutProvider interface {
+	Name() string
+	CreatePayout(ctx context.Context, idempotencyKey string, req PayoutRequest) (*Payout, error)
+}
+
+// PayoutRequest — выплата получателю RecipientID (id водителя; реквизиты хранит провайдер)
+type PayoutRequest struct {
+	RecipientID string
+	Amount      float64
+	Currency    string
+	Description string
+	Metadata    map[string]string
+}
+
+// Payout — выплата на стороне провайдера: succeeded, canceled или pending
+type Payout struct {
+	ID       string
+	Status   string
+	Amount   float64
+	Currency string
+}
+
+// Notification — уведомление провайдера. Ему нельзя доверять как есть:
+// объект нужно перезапросить по ObjectID.
+type Notification struct {
+	Event    string
+	ObjectID string
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"we_ride/internal/services/payment_service/internal/ledger"
+)
+
+// AccountBalance — счёт журнала с балансом в его нормальном знаке (см. ledger.Sign)
+type AccountBalance struct {
+	AccountID   string
+	AccountType string
+	Currency    string
+	Balance     float64
+}
+
+// LedgerEntryRecord — проводка по счёту вместе с транзакцией, к которой она относится
+type LedgerEntryRecord struct {
+	EntryID     int64
+	TxID        string
+	Kind        string
+	Reference   string
+	Description string
+	AccountID   string
+	Amount      float64 // > 0 — дебет, < 0 — кредит
+	CreatedAt   time.Time
+}
+
+// PostTransaction записывает сбалансированную транзакцию. false — транзакция с тем же видом
+// и основанием уже записана. Недостающие счета создаются.
+func (r *repository) PostTransaction(ctx context.Context, t *ledger.Transaction) (bool, error) {
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("PostTransaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var txID string
+	err = tx.QueryRow(ctx, `
+		INSERT INTO ledger_transactions (kind, reference, description)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (kind, reference) DO NOTHING
+		RETURNING tx_id
+	`, t.Kind, t.Reference, t.Description).Scan(&txID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("PostTransaction: %w", err)
+	}
+
+	for _, e := range t.Entries {
+		accountType, ownerID, _ := ledger.ParseAccount(e.AccountID)
+		_, err := tx.Exec(ctx, `
+			INSERT INTO ledger_accounts (account_id, account_type, owner_id, currency)
+			VALUES ($1, $2, NULLIF($3::text, ''), $4)
+			ON CONFLICT (account_id) DO NOTHING
+		`, e.AccountID, accountType, ownerID, t.Currency)
+		if err != nil {
+			return false, fmt.Errorf("PostTransaction account: %w", err)
+		}
+		if _, err := tx.Exec(ctx, `INSERT INTO ledger_entries (tx_id, account_id, amount) VALUES ($1, $2, $3)`,
+			txID, e.AccountID, e.Amount); err != nil {
+			return false, fmt.Errorf("PostTransaction entry: %w", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("PostTransaction: %w", err)
+	}
+	return true, nil
+}
+
+// GetAccountBalances возвращает счета владельца с балансами
+func (r *repository) GetAccountBalances(ctx context.Context, ownerID string) ([]*AccountBalance, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT a.account_id, a.account_type, a.currency, COALESCE(SUM(e.amount), 0)::float8
+		FROM ledger_accounts a
+		LEFT JOIN ledger_entries e ON e.account_id = a.account_id
+		WHERE a.owner_id = $1
+		GROUP BY a.account_id
+		ORDER BY a.account_type
+	`, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountBalances: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*AccountBalance
+	for rows.Next() {
+		b := &AccountBalance{}
+		if err := rows.Scan(&b.AccountID, &b.AccountType, &b.Currency, &b.Balance); err != nil {
+			return nil, fmt.Errorf("GetAccountBalances scan: %w", err)
+		}
+		b.Balance *= ledger.Sign(b.AccountType)
+		result = append(result, b)
+	}
+	return result, rows.Err()
+}
+
+// ListAccountEntries возвращает последние проводки по счетам, новые первыми
+func (r *repository) ListAccountEntries(ctx context.Context, accountIDs []string, limit int) ([]*LedgerEntryRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT e.entry_id, t.tx_id, t.kind, t.reference, COALESCE(t.description, ''),
+		       e.account_id, e.amount::float8, e.created_at
+		FROM ledger_entries e
+		JOIN ledger_transactions t ON t.tx_id = e.tx_id
+		WHERE e.account_id = ANY($1)
+		ORDER BY e.entry_id DESC
+		LIMIT $2
+	`, accountIDs, limit)
+	if err != nil {
+		return nil, fmt.Errorf("ListAccountEntries: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*LedgerEntryRecord
+	for rows.Next() {
+		e := &LedgerEntryRecord{}
+		if err := rows.Scan(&e.EntryID, &e.TxID, &e.Kind, &e.Reference, &e.Description,
+			&e.AccountID, &e.Amount, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListAccountEntries scan: %w", err)
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
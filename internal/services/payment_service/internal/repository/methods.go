@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// PaymentMethodRecord — сохранённый у провайдера способ оплаты пользователя.
+// Привязка подтверждается платежом BindingPaymentID, до уведомления о нём способ в статусе pending.
+type PaymentMethodRecord struct {
+	MethodID         string
+	UserID           string
+	Status           string // pending, active, failed
+	ProviderMethodID string
+	BindingPaymentID string
+	Type             string
+	Title            string // например, «Bank card *4444»
+	IsDefault        bool
+	CreatedAt        time.Time
+}
+
+// ErrMethodNotActive — способ оплаты ещё не подтверждён или привязка не удалась
+var ErrMethodNotActive = errors.New("payment method is not active")
+
+const methodColumns = `method_id, user_id, status, COALESCE(provider_method_id, ''), binding_payment_id,
+		       type, title, is_default, created_at`
+
+func scanMethod(row pgx.Row) (*PaymentMethodRecord, error) {
+	m := &PaymentMethodRecord{}
+	err := row.Scan(&m.MethodID, &m.UserID, &m.Status, &m.ProviderMethodID, &m.BindingPaymentID,
+		&m.Type, &m.Title, &m.IsDefault, &m.CreatedAt)
+	return m, err
+}
+
+func (r *repository) CreatePaymentMethod(ctx context.Context, m *PaymentMethodRecord) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO payment_methods (method_id, user_id, status, binding_payment_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`, m.MethodID, m.UserID, m.Status, m.BindingPaymentID).Scan(&m.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("CreatePaymentMethod: %w", err)
+	}
+	return nil
+}
+
+// ListPaymentMethods возвращает способы оплаты пользователя, кроме неудавшихся привязок
+func (r *repository) ListPaymentMethods(ctx context.Context, userID string) ([]*PaymentMethodRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+methodColumns+`
+		FROM payment_methods WHERE user_id = $1 AND status <> 'failed'
+		ORDER BY is_default DESC, created_at DESC
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("ListPaymentMethods: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*PaymentMethodRecord
+	for rows.Next() {
+		m, err := scanMethod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListPaymentMethods scan: %w", err)
+		}
+		result = append(result, m)
+	}
+	return result, rows.Err()
+}
+
+func (r *repository) GetPaymentMethodByBinding(ctx context.Context, bindingPaymentID string) (*PaymentMethodRecord, error) {
+	m, err := scanMethod(r.db.QueryRow(ctx,
+		`SELECT `+methodColumns+` FROM payment_methods WHERE binding_payment_id = $1`, bindingPaymentID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetPaymentMethodByBinding: %w", err)
+	}
+	return m, nil
+}
+
+// GetDefaultPaymentMethod возвращает активный способ оплаты по умолчанию или ErrNotFound
+func (r *repository) GetDefaultPaymentMethod(ctx context.Context, userID string) (*PaymentMethodRecord, error) {
+	m, err := scanMethod(r.db.QueryRow(ctx, `
+		SELECT `+methodColumns+` FROM payment_methods
+		WHERE user_id = $1 AND is_default AND status = 'active'
+	`, userID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetDefaultPaymentMethod: %w", err)
+	}
+	return m, nil
+}
+
+// ActivatePaymentMethod завершает привязку. Первый активный способ пользователя становится способом
+// по умолчанию. false — привязка уже завершена (повторное уведомление).
+func (r *repository) ActivatePaymentMethod(ctx context.Context, methodID, providerMethodID, methodType, title string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE payment_methods SET
+			status = 'active', provider_method_id = $2, type = $3, title = $4, updated_at = NOW(),
+			is_default = NOT EXISTS (
+				SELECT 1 FROM payment_methods d WHERE d.user_id = payment_methods.user_id AND d.is_default
+			)
+		WHERE method_id = $1 AND status = 'pending'
+	`, methodID, providerMethodID, methodType, title)
+	if err != nil {
+		return false, fmt.Errorf("ActivatePaymentMethod: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FailPaymentMethod отмечает неудавшуюся привязку; false — привязка уже завершена
+func (r *repository) FailPaymentMethod(ctx context.Context, methodID string) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE payment_methods SET status = 'failed', updated_at = NOW()
+		WHERE method_id = $1 AND status = 'pending'
+	`, methodID)
+	if err != nil {
+		return false, fmt.Errorf("FailPaymentMethod: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// SetDefaultPaymentMethod делает активный способ оплаты способом по умолчанию
+func (r *repository) SetDefaultPaymentMethod(ctx context.Context, userID, methodID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("SetDefaultPaymentMethod: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var status string
+	err = tx.QueryRow(ctx, `SELECT status FROM payment_methods WHERE method_id = $1 AND user_id = $2 FOR UPDATE`,
+		methodID, userID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("SetDefaultPaymentMethod: %w", err)
+	}
+	if status != "active" {
+		return ErrMethodNotActive
+	}
+	if _, err := tx.Exec(ctx, `UPDATE payment_methods SET is_default = false WHERE user_id = $1 AND is_default`, userID); err != nil {
+		return fmt.Errorf("SetDefaultPaymentMethod: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE payment_methods SET is_default = true, updated_at = NOW() WHERE method_id = $1`, methodID); err != nil {
+		return fmt.Errorf("SetDefaultPaymentMethod: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// DeletePaymentMethod удаляет способ оплаты. Если он был по умолчанию, им становится
+// последний добавленный активный способ.
+func (r *repository) DeletePaymentMethod(ctx context.Context, userID, methodID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("DeletePaymentMethod: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var wasDefault bool
+	err = tx.QueryRow(ctx, `DELETE FROM payment_methods WHERE method_id = $1 AND user_id = $2 RETURNING is_default`,
+		methodID, userID).Scan(&wasDefault)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("DeletePaymentMethod: %w", err)
+	}
+	if wasDefault {
+		_, err = tx.Exec(ctx, `
+			UPDATE payment_methods SET is_default = true, updated_at = NOW()
+			WHERE method_id = (
+				SELECT method_id FROM payment_methods
+				WHERE user_id = $1 AND status = 'active'
+				ORDER BY created_at DESC LIMIT 1
+			)
+		`, userID)
+		if err != nil {
+			return fmt.Errorf("DeletePaymentMethod: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
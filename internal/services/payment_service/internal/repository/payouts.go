@@ -0,0 +1,239 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"we_ride/internal/services/payment_service/internal/money"
+)
+
+// EarningRecord — заработок водителя по одной оплаченной доле пассажира
+type EarningRecord struct {
+	EarningID  string
+	DriverID   string
+	RoomID     string
+	PaymentID  string
+	Gross      float64 // оплачено пассажиром
+	Commission float64 // комиссия платформы
+	Net        float64 // к выплате водителю
+	Currency   string
+	PayoutID   string // пусто — ещё не выплачен
+	CreatedAt  time.Time
+}
+
+// PayoutRecord — выплата водителю заработка, накопленного до PeriodEnd
+type PayoutRecord struct {
+	PayoutID         string
+	DriverID         string
+	Amount           float64
+	Currency         string
+	PeriodEnd        time.Time
+	Status           string // pending, succeeded, failed
+	ProviderPayoutID string
+	Error            string
+	CreatedAt        time.Time
+}
+
+// UnpaidDriver — водитель с невыплаченным заработком в валюте: выплаты делаются по каждой валюте отдельно
+type UnpaidDriver struct {
+	DriverID string
+	Currency string
+}
+
+const earningColumns = `earning_id, driver_id, room_id, payment_id, gross::float8, commission::float8, net::float8,
+		       currency, COALESCE(payout_id::text, ''), created_at`
+
+const payoutColumns = `payout_id, driver_id, amount::float8, currency, period_end, status,
+		       COALESCE(provider_payout_id, ''), COALESCE(error, ''), created_at`
+
+func scanPayout(row pgx.Row) (*PayoutRecord, error) {
+	p := &PayoutRecord{}
+	err := row.Scan(&p.PayoutID, &p.DriverID, &p.Amount, &p.Currency, &p.PeriodEnd, &p.Status,
+		&p.ProviderPayoutID, &p.Error, &p.CreatedAt)
+	return p, err
+}
+
+// CreateEarning начисляет заработок по платежу; false — по этому платежу уже начислено
+func (r *repository) CreateEarning(ctx context.Context, e *EarningRecord) (bool, error) {
+	tag, err := r.db.Exec(ctx, `
+		INSERT INTO driver_earnings (driver_id, room_id, payment_id, gross, commission, net, currency)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (payment_id) DO NOTHING
+	`, e.DriverID, e.RoomID, e.PaymentID, e.Gross, e.Commission, e.Net, e.Currency)
+	if err != nil {
+		return false, fmt.Errorf("CreateEarning: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListDriverEarnings возвращает заработок водителя за [from, to); нулевые границы не учитываются
+func (r *repository) ListDriverEarnings(ctx context.Context, driverID string, from, to time.Time) ([]*EarningRecord, error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conds = append(conds, "driver_id = "+arg(driverID))
+	if !from.IsZero() {
+		conds = append(conds, "created_at >= "+arg(from))
+	}
+	if !to.IsZero() {
+		conds = append(conds, "created_at < "+arg(to))
+	}
+	rows, err := r.db.Query(ctx, `
+		SELECT `+earningColumns+` FROM driver_earnings
+		WHERE `+strings.Join(conds, " AND ")+`
+		ORDER BY created_at DESC
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListDriverEarnings: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*EarningRecord
+	for rows.Next() {
+		e := &EarningRecord{}
+		if err := rows.Scan(&e.EarningID, &e.DriverID, &e.RoomID, &e.PaymentID, &e.Gross, &e.Commission, &e.Net,
+			&e.Currency, &e.PayoutID, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListDriverEarnings scan: %w", err)
+		}
+		result = append(result, e)
+	}
+	return result, rows.Err()
+}
+
+// ListDriversToPay возвращает водителей и валюты с невыплаченным заработком, начисленным до before
+func (r *repository) ListDriversToPay(ctx context.Context, before time.Time) ([]UnpaidDriver, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT DISTINCT driver_id::text, currency FROM driver_earnings
+		WHERE payout_id IS NULL AND created_at < $1
+	`, before)
+	if err != nil {
+		return nil, fmt.Errorf("ListDriversToPay: %w", err)
+	}
+	defer rows.Close()
+
+	var result []UnpaidDriver
+	for rows.Next() {
+		var d UnpaidDriver
+		if err := rows.Scan(&d.DriverID, &d.Currency); err != nil {
+			return nil, fmt.Errorf("ListDriversToPay scan: %w", err)
+		}
+		result = append(result, d)
+	}
+	return result, rows.Err()
+}
+
+// CreatePayout собирает в выплату невыплаченный заработок водителя, начисленный до periodEnd.
+// ErrNotFound — выплачивать нечего.
+func (r *repository) CreatePayout(ctx context.Context, driverID, currency string, periodEnd time.Time) (*PayoutRecord, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("CreatePayout: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var amount float64
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(net), 0)::float8 FROM (
+			SELECT net FROM driver_earnings
+			WHERE driver_id = $1 AND currency = $2 AND payout_id IS NULL AND created_at < $3
+			FOR UPDATE
+		) e
+	`, driverID, currency, periodEnd).Scan(&amount)
+	if err != nil {
+		return nil, fmt.Errorf("CreatePayout: %w", err)
+	}
+	if money.ToMinor(amount, currency) <= 0 {
+		return nil, ErrNotFound
+	}
+
+	p, err := scanPayout(tx.QueryRow(ctx, `
+		INSERT INTO payouts (driver_id, amount, currency, period_end)
+		VALUES ($1, $2, $3, $4)
+		RETURNING `+payoutColumns, driverID, amount, currency, periodEnd))
+	if err != nil {
+		return nil, fmt.Errorf("CreatePayout insert: %w", err)
+	}
+	_, err = tx.Exec(ctx, `
+		UPDATE driver_earnings SET payout_id = $1
+		WHERE driver_id = $2 AND currency = $3 AND payout_id IS NULL AND created_at < $4
+	`, p.PayoutID, driverID, currency, periodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("CreatePayout earnings: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("CreatePayout: %w", err)
+	}
+	return p, nil
+}
+
+// FinishPayout сохраняет результат выплаты. Заработок из неудавшейся выплаты освобождается
+// и попадёт в следующую.
+func (r *repository) FinishPayout(ctx context.Context, payoutID, status, providerPayoutID, errMsg string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("FinishPayout: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `
+		UPDATE payouts SET status = $2, provider_payout_id = NULLIF($3, ''), error = NULLIF($4, ''), updated_at = NOW()
+		WHERE payout_id = $1
+	`, payoutID, status, providerPayoutID, errMsg)
+	if err != nil {
+		return fmt.Errorf("FinishPayout: %w", err)
+	}
+	if status == "failed" {
+		if _, err := tx.Exec(ctx, `UPDATE driver_earnings SET payout_id = NULL WHERE payout_id = $1`, payoutID); err != nil {
+			return fmt.Errorf("FinishPayout release: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// ListPayouts возвращает выплаты, новые первыми; пустые driverID и status не учитываются
+func (r *repository) ListPayouts(ctx context.Context, driverID, status string, limit int) ([]*PayoutRecord, error) {
+	var (
+		conds []string
+		args  []interface{}
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if driverID != "" {
+		conds = append(conds, "driver_id = "+arg(driverID))
+	}
+	if status != "" {
+		conds = append(conds, "status = "+arg(status))
+	}
+	query := `SELECT ` + payoutColumns + ` FROM payouts`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY created_at DESC LIMIT " + arg(limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListPayouts: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*PayoutRecord
+	for rows.Next() {
+		p, err := scanPayout(rows)
+		if err != nil {
+			return nil, fmt.Errorf("ListPayouts scan: %w", err)
+		}
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Типы скидок промокода
+const (
+	PromoPercent = "percent" // процент от доли пассажира
+	PromoFixed   = "fixed"   // фиксированная сумма в валюте кода
+)
+
+// Статусы применения промокода
+const (
+	RedemptionApplied  = "applied"  // код применён, поездка ещё не оплачена
+	RedemptionRedeemed = "redeemed" // скидка учтена в платеже
+)
+
+// PromoCodeRecord — промокод. Нулевые ValidFrom, ValidUntil и MaxUses — без ограничения.
+type PromoCodeRecord struct {
+	Code           string
+	Type           string // percent, fixed
+	Value          float64
+	Currency       string // только у fixed
+	MaxUses        int
+	MaxUsesPerUser int
+	FirstRideOnly  bool
+	ValidFrom      time.Time
+	ValidUntil     time.Time
+	Active         bool
+	Uses           int // сколько раз применён, только при чтении
+	CreatedBy      string
+	CreatedAt      time.Time
+}
+
+// PromoRedemption — промокод, применённый пассажиром к поездке
+type PromoRedemption struct {
+	RoomID    string
+	UserID    string
+	Status    string // applied, redeemed
+	PaymentID string // платёж, в котором учтена скидка
+	Promo     PromoCodeRecord
+	CreatedAt time.Time
+}
+
+// ErrPromoExists — промокод с таким кодом уже создан
+var ErrPromoExists = errors.New("promo code already exists")
+
+// ErrPromoExhausted — исчерпан общий лимит применений или лимит на пассажира
+var ErrPromoExhausted = errors.New("promo code usage limit reached")
+
+// ErrPromoRedeemed — к поездке уже применён код, скидка по которому учтена в платеже
+var ErrPromoRedeemed = errors.New("promo code is already redeemed for this ride")
+
+const promoColumns = `p.code, p.type, p.value::float8, p.currency, p.max_uses, p.max_uses_per_user, p.first_ride_only,
+		       p.valid_from, p.valid_until, p.active, COALESCE(p.created_by::text, ''), p.created_at`
+
+func scanPromo(row pgx.Row, extra ...any) (*PromoCodeRecord, error) {
+	p := &PromoCodeRecord{}
+	var from, until *time.Time
+	dest := []any{&p.Code, &p.Type, &p.Value, &p.Currency, &p.MaxUses, &p.MaxUsesPerUser, &p.FirstRideOnly,
+		&from, &until, &p.Active, &p.CreatedBy, &p.CreatedAt}
+	if err := row.Scan(append(dest, extra...)...); err != nil {
+		return nil, err
+	}
+	if from != nil {
+		p.ValidFrom = *from
+	}
+	if until != nil {
+		p.ValidUntil = *until
+	}
+	return p, nil
+}
+
+// nullTime — NULL вместо нулевого времени
+func nullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
+
+// CreatePromoCode сохраняет промокод; ErrPromoExists — код занят
+func (r *repository) CreatePromoCode(ctx context.Context, p *PromoCodeRecord) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO promo_codes (code, type, value, currency, max_uses, max_uses_per_user, first_ride_only,
+		                         valid_from, valid_until, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NULLIF($10::text, '')::uuid)
+		ON CONFLICT (code) DO NOTHING
+		RETURNING active, created_at
+	`, p.Code, p.Type, p.Value, p.Currency, p.MaxUses, p.MaxUsesPerUser, p.FirstRideOnly,
+		nullTime(p.ValidFrom), nullTime(p.ValidUntil), p.CreatedBy).Scan(&p.Active, &p.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrPromoExists
+	}
+	if err != nil {
+		return fmt.Errorf("CreatePromoCode: %w", err)
+	}
+	return nil
+}
+
+// GetPromoCode возвращает промокод; ErrNotFound — такого кода нет
+func (r *repository) GetPromoCode(ctx context.Context, code string) (*PromoCodeRecord, error) {
+	p, err := scanPromo(r.db.QueryRow(ctx, `SELECT `+promoColumns+` FROM promo_codes p WHERE p.code = $1`, code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetPromoCode: %w", err)
+	}
+	return p, nil
+}
+
+// ListPromoCodes возвращает промокоды с числом применений, новые первыми
+func (r *repository) ListPromoCodes(ctx context.Context) ([]*PromoCodeRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+promoColumns+`, (SELECT COUNT(*) FROM promo_redemptions WHERE code = p.code)
+		FROM promo_codes p
+		ORDER BY p.created_at DESC
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("ListPromoCodes: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*PromoCodeRecord
+	for rows.Next() {
+		var uses int
+		p, err := scanPromo(rows, &uses)
+		if err != nil {
+			return nil, fmt.Errorf("ListPromoCodes scan: %w", err)
+		}
+		p.Uses = uses
+		result = append(result, p)
+	}
+	return result, rows.Err()
+}
+
+// ApplyPromoCode применяет код к поездке пассажира, заменяя ранее применённый. Лимиты применений
+// проверяются под блокировкой кода, поэтому одновременные применения их не превысят. Применённый,
+// но не использованный код занимает место в лимитах, пока его не снимет ReleasePromoCodes.
+func (r *repository) ApplyPromoCode(ctx context.Context, code, roomID, userID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("ApplyPromoCode: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var maxUses, maxPerUser int
+	err = tx.QueryRow(ctx, `SELECT max_uses, max_uses_per_user FROM promo_codes WHERE code = $1 FOR UPDATE`, code).
+		Scan(&maxUses, &maxPerUser)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("ApplyPromoCode: %w", err)
+	}
+
+	// Повторное применение того же кода к той же поездке не считается
+	var total, byUser int
+	err = tx.QueryRow(ctx, `
+		SELECT COUNT(*), COUNT(*) FILTER (WHERE user_id = $3)
+		FROM promo_redemptions
+		WHERE code = $1 AND NOT (room_id = $2 AND user_id = $3)
+	`, code, roomID, userID).Scan(&total, &byUser)
+	if err != nil {
+		return fmt.Errorf("ApplyPromoCode count: %w", err)
+	}
+	if (maxUses > 0 && total >= maxUses) || (maxPerUser > 0 && byUser >= maxPerUser) {
+		return ErrPromoExhausted
+	}
+
+	tag, err := tx.Exec(ctx, `
+		INSERT INTO promo_redemptions (code, room_id, user_id) VALUES ($1, $2, $3)
+		ON CONFLICT (room_id, user_id) DO UPDATE SET code = EXCLUDED.code, created_at = NOW()
+		WHERE promo_redemptions.status = 'applied'
+	`, code, roomID, userID)
+	if err != nil {
+		return fmt.Errorf("ApplyPromoCode: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrPromoRedeemed
+	}
+	return tx.Commit(ctx)
+}
+
+// GetPromoRedemption возвращает код, применённый к поездке пассажира; ErrNotFound — кода нет
+func (r *repository) GetPromoRedemption(ctx context.Context, roomID, userID string) (*PromoRedemption, error) {
+	red := &PromoRedemption{RoomID: roomID, UserID: userID}
+	promo, err := scanPromo(r.db.QueryRow(ctx, `
+		SELECT `+promoColumns+`, d.status, COALESCE(d.payment_id::text, ''), d.created_at
+		FROM promo_redemptions d
+		JOIN promo_codes p ON p.code = d.code
+		WHERE d.room_id = $1 AND d.user_id = $2
+	`, roomID, userID), &red.Status, &red.PaymentID, &red.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetPromoRedemption: %w", err)
+	}
+	red.Promo = *promo
+	return red, nil
+}
+
+// RedeemPromoCode записывает скидку в платёж и отмечает код поездки использованным. Повторный вызов
+// для того же платежа ничего не меняет.
+func (r *repository) RedeemPromoCode(ctx context.Context, roomID, userID, paymentID, code string, discount float64) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("RedeemPromoCode: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE payments SET discount = $2, promo_code = $3, updated_at = NOW() WHERE payment_id = $1
+	`, paymentID, discount, code); err != nil {
+		return fmt.Errorf("RedeemPromoCode payment: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE promo_redemptions SET status = 'redeemed', payment_id = $3
+		WHERE room_id = $1 AND user_id = $2 AND status = 'applied'
+	`, roomID, userID, paymentID); err != nil {
+		return fmt.Errorf("RedeemPromoCode: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ReleasePromoCodes снимает коды, применённые к поездке пассажира (пустой userID — всех пассажиров комнаты),
+// но не использованные в платеже, и освобождает их места в лимитах
+func (r *repository) ReleasePromoCodes(ctx context.Context, roomID, userID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM promo_redemptions
+		WHERE room_id = $1 AND ($2::text = '' OR user_id::text = $2) AND status = 'applied'
+	`, roomID, userID)
+	if err != nil {
+		return 0, fmt.Errorf("ReleasePromoCodes: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
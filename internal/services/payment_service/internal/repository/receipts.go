@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Типы чеков
+const (
+	ReceiptPayment = "payment" // приход
+	ReceiptRefund  = "refund"  // возврат прихода
+)
+
+// ReceiptContact — email и телефон пользователя для чеков; достаточно одного из них
+type ReceiptContact struct {
+	UserID    string
+	Email     string
+	Phone     string
+	UpdatedAt time.Time
+}
+
+// ReceiptRecord — чек по 54-ФЗ, переданный провайдеру вместе с платежом или возвратом.
+// Чек с одной позицией: доля пассажира в поездке.
+type ReceiptRecord struct {
+	ReceiptID      string
+	PaymentID      string
+	RefundID       string // пусто у чека прихода
+	Type           string // payment, refund
+	UserID         string
+	Email          string
+	Phone          string
+	Description    string
+	Amount         float64
+	Currency       string
+	VatCode        int
+	TaxSystemCode  int
+	PaymentSubject string
+	PaymentMode    string
+	RefundStatus   string // статус возврата, только при чтении
+	CreatedAt      time.Time
+}
+
+// GetReceiptContact возвращает контакт для чеков; ErrNotFound — пользователь его не оставлял
+func (r *repository) GetReceiptContact(ctx context.Context, userID string) (*ReceiptContact, error) {
+	c := &ReceiptContact{UserID: userID}
+	err := r.db.QueryRow(ctx, `
+		SELECT email, phone, updated_at FROM receipt_contacts WHERE user_id = $1
+	`, userID).Scan(&c.Email, &c.Phone, &c.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetReceiptContact: %w", err)
+	}
+	return c, nil
+}
+
+// SetReceiptContact сохраняет контакт, указанный пользователем, поверх прежнего
+func (r *repository) SetReceiptContact(ctx context.Context, c *ReceiptContact) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO receipt_contacts (user_id, email, phone)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET email = EXCLUDED.email, phone = EXCLUDED.phone, updated_at = NOW()
+		RETURNING updated_at
+	`, c.UserID, c.Email, c.Phone).Scan(&c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("SetReceiptContact: %w", err)
+	}
+	return nil
+}
+
+// AddReceiptEmail сохраняет email из токена, только если пользователь ещё не оставлял контакт
+func (r *repository) AddReceiptEmail(ctx context.Context, userID, email string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO receipt_contacts (user_id, email) VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`, userID, email)
+	if err != nil {
+		return fmt.Errorf("AddReceiptEmail: %w", err)
+	}
+	return nil
+}
+
+// CreateReceipt сохраняет чек; повторный чек того же платежа или возврата не записывается
+func (r *repository) CreateReceipt(ctx context.Context, rec *ReceiptRecord) error {
+	var refundID *string
+	if rec.RefundID != "" {
+		refundID = &rec.RefundID
+	}
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO receipts (receipt_id, payment_id, refund_id, type, user_id, email, phone, description,
+		                      amount, currency, vat_code, tax_system_code, payment_subject, payment_mode)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		ON CONFLICT DO NOTHING
+	`, rec.ReceiptID, rec.PaymentID, refundID, rec.Type, rec.UserID, rec.Email, rec.Phone, rec.Description,
+		rec.Amount, rec.Currency, rec.VatCode, rec.TaxSystemCode, rec.PaymentSubject, rec.PaymentMode)
+	if err != nil {
+		return fmt.Errorf("CreateReceipt: %w", err)
+	}
+	return nil
+}
+
+// ListReceipts возвращает чеки платежа: сначала чек прихода, затем чеки возвратов по порядку
+func (r *repository) ListReceipts(ctx context.Context, paymentID string) ([]*ReceiptRecord, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT c.receipt_id, c.payment_id, COALESCE(c.refund_id::text, ''), c.type, c.user_id, c.email, c.phone,
+		       c.description, c.amount::float8, c.currency, c.vat_code, c.tax_system_code,
+		       c.payment_subject, c.payment_mode, COALESCE(f.status, ''), c.created_at
+		FROM receipts c
+		LEFT JOIN refunds f ON f.refund_id = c.refund_id
+		WHERE c.payment_id = $1
+		ORDER BY c.refund_id IS NOT NULL, c.created_at
+	`, paymentID)
+	if err != nil {
+		return nil, fmt.Errorf("ListReceipts: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*ReceiptRecord
+	for rows.Next() {
+		rec := &ReceiptRecord{}
+		if err := rows.Scan(&rec.ReceiptID, &rec.PaymentID, &rec.RefundID, &rec.Type, &rec.UserID, &rec.Email,
+			&rec.Phone, &rec.Description, &rec.Amount, &rec.Currency, &rec.VatCode, &rec.TaxSystemCode,
+			&rec.PaymentSubject, &rec.PaymentMode, &rec.RefundStatus, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListReceipts scan: %w", err)
+		}
+		result = append(result, rec)
+	}
+	return result, rows.Err()
+}
@@ -2,10 +2,16 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/money"
 )
 
 // PaymentRecord — модель платежа в БД
@@ -18,16 +24,125 @@ type PaymentRecord struct {
 	Status            string
 	YookassaPaymentID string
 	Description       string
+	AuthorizedAmount  float64 // сумма холда; 0 — платёж без предавторизации
+	DriverID          string  // водитель поездки, которому начисляется заработок; пусто — без начисления
+	IdempotencyKey    string  // ключ списания у провайдера; пусто — платёж без ключа (холды, привязка карт)
+	ConfirmationURL   string  // где пассажир подтверждает платёж; пусто — подтверждение не нужно
+	Reminders         int     // сколько раз пассажиру напоминали подтвердить платёж
+	Discount          float64 // скидка по промокоду PromoCode; Amount — уже за вычетом скидки
+	PromoCode         string
+	Kind              string // PaymentRide или штраф; пусто при сохранении — PaymentRide
 	CreatedAt         time.Time
 	UpdatedAt         time.Time
 }
 
+// Назначение платежа
+const (
+	PaymentRide            = "ride"
+	PaymentCancellationFee = "cancellation_fee"
+	PaymentNoShowFee       = "no_show_fee"
+)
+
+// RefundRecord — возврат по платежу; у одного платежа может быть несколько частичных возвратов
+type RefundRecord struct {
+	RefundID         string
+	PaymentID        string
+	Amount           float64
+	Status           string // pending, succeeded, failed
+	YookassaRefundID string
+	Reason           string
+	CreatedAt        time.Time
+}
+
+// ErrNotFound — платёж или возврат с таким id ЮKassa не создавался через сервис
+var ErrNotFound = errors.New("not found")
+
+// ErrRefundExceedsBalance — сумма возвратов превысила бы сумму платежа
+var ErrRefundExceedsBalance = errors.New("refund exceeds refundable balance")
+
+// ErrRefundFinished — возврат уже завершён (повторное уведомление или гонка)
+var ErrRefundFinished = errors.New("refund is already finished")
+
+// ErrNotRefundable — платёж не прошёл или уже возвращён полностью
+var ErrNotRefundable = errors.New("payment is not refundable")
+
 type Repository interface {
 	CreatePayment(ctx context.Context, p *PaymentRecord) error
-	UpdatePaymentStatus(ctx context.Context, paymentID, status, yookassaID string) error
+	ReservePayment(ctx context.Context, p *PaymentRecord) (*PaymentRecord, bool, error)
+	UpdatePaymentStatus(ctx context.Context, paymentID, status, yookassaID, confirmationURL string) error
 	GetPaymentsByRoom(ctx context.Context, roomID string) ([]*PaymentRecord, error)
-	GetPaymentsByUser(ctx context.Context, userID string) ([]*PaymentRecord, error)
 	GetPaymentByID(ctx context.Context, paymentID string) (*PaymentRecord, error)
+	GetPaymentByYookassaID(ctx context.Context, yookassaID string) (*PaymentRecord, error)
+	TransitionPaymentStatus(ctx context.Context, paymentID, status string, from ...string) (bool, error)
+	ListStalePayments(ctx context.Context, before time.Time, limit int, statuses ...string) ([]*PaymentRecord, error)
+	ListActiveHolds(ctx context.Context, roomID, userID string) ([]*PaymentRecord, error)
+	ListUnconfirmedPayments(ctx context.Context, remindBefore time.Time, maxReminders, limit int) ([]*PaymentRecord, error)
+	MarkReminded(ctx context.Context, paymentID string) error
+	CaptureHold(ctx context.Context, paymentID, driverID string, amount float64, status string) (bool, error)
+	AnonymizeUserPayments(ctx context.Context, userID string) (int64, error)
+	SearchPayments(ctx context.Context, f PaymentFilter) ([]*PaymentRecord, error)
+	PaymentTotals(ctx context.Context, f PaymentFilter) ([]PaymentTotal, error)
+	GetRefundedAmount(ctx context.Context, paymentID string) (float64, error)
+	CreateRefund(ctx context.Context, r *RefundRecord) error
+	FinishRefund(ctx context.Context, refundID, status, yookassaRefundID string) (string, error)
+	GetRefundByYookassaID(ctx context.Context, yookassaRefundID string) (*RefundRecord, error)
+	CreatePaymentMethod(ctx context.Context, m *PaymentMethodRecord) error
+	ListPaymentMethods(ctx context.Context, userID string) ([]*PaymentMethodRecord, error)
+	GetPaymentMethodByBinding(ctx context.Context, bindingPaymentID string) (*PaymentMethodRecord, error)
+	GetDefaultPaymentMethod(ctx context.Context, userID string) (*PaymentMethodRecord, error)
+	ActivatePaymentMethod(ctx context.Context, methodID, providerMethodID, methodType, title string) (bool, error)
+	FailPaymentMethod(ctx context.Context, methodID string) (bool, error)
+	SetDefaultPaymentMethod(ctx context.Context, userID, methodID string) error
+	DeletePaymentMethod(ctx context.Context, userID, methodID string) error
+	PostTransaction(ctx context.Context, t *ledger.Transaction) (bool, error)
+	GetAccountBalances(ctx context.Context, ownerID string) ([]*AccountBalance, error)
+	ListAccountEntries(ctx context.Context, accountIDs []string, limit int) ([]*LedgerEntryRecord, error)
+	CreateEarning(ctx context.Context, e *EarningRecord) (bool, error)
+	ListDriverEarnings(ctx context.Context, driverID string, from, to time.Time) ([]*EarningRecord, error)
+	ListDriversToPay(ctx context.Context, before time.Time) ([]UnpaidDriver, error)
+	CreatePayout(ctx context.Context, driverID, currency string, periodEnd time.Time) (*PayoutRecord, error)
+	FinishPayout(ctx context.Context, payoutID, status, providerPayoutID, errMsg string) error
+	ListPayouts(ctx context.Context, driverID, status string, limit int) ([]*PayoutRecord, error)
+	GetReceiptContact(ctx context.Context, userID string) (*ReceiptContact, error)
+	SetReceiptContact(ctx context.Context, c *ReceiptContact) error
+	AddReceiptEmail(ctx context.Context, userID, email string) error
+	CreateReceipt(ctx context.Context, rec *ReceiptRecord) error
+	ListReceipts(ctx context.Context, paymentID string) ([]*ReceiptRecord, error)
+	CreatePromoCode(ctx context.Context, p *PromoCodeRecord) error
+	GetPromoCode(ctx context.Context, code string) (*PromoCodeRecord, error)
+	ListPromoCodes(ctx context.Context) ([]*PromoCodeRecord, error)
+	ApplyPromoCode(ctx context.Context, code, roomID, userID string) error
+	GetPromoRedemption(ctx context.Context, roomID, userID string) (*PromoRedemption, error)
+	RedeemPromoCode(ctx context.Context, roomID, userID, paymentID, code string, discount float64) error
+	ReleasePromoCodes(ctx context.Context, roomID, userID string) (int64, error)
+	CreateStatement(ctx context.Context, st *StatementRecord) error
+	GetStatement(ctx context.Context, statementID string) (*StatementRecord, error)
+	FinishStatement(ctx context.Context, statementID, status, fileName, errMsg string) error
+	DeleteStatements(ctx context.Context, userID string) ([]string, error)
+}
+
+// PaymentFilter — условия поиска платежей для поддержки и истории пользователя, пустые поля не учитываются
+type PaymentFilter struct {
+	PaymentID   string
+	RoomID      string
+	UserIDs     []string
+	Status      string
+	Kind        string
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	// Курсор страницы: платежи после (BeforeTime, BeforeID) в порядке created_at DESC, payment_id DESC
+	BeforeTime time.Time
+	BeforeID   string
+	Limit      int
+}
+
+// PaymentTotal — итоги платежей в одной валюте: Spent — списано, Refunded — успешно возвращено
+type PaymentTotal struct {
+	Currency string
+	Count    int
+	Paid     int // сколько платежей списано
+	Spent    float64
+	Refunded float64
 }
 
 type repository struct {
@@ -40,13 +155,15 @@ func NewRepository(db *pgxpool.Pool) Repository {
 
 func (r *repository) CreatePayment(ctx context.Context, p *PaymentRecord) error {
 	query := `
-		INSERT INTO payments (payment_id, room_id, user_id, amount, currency, status, yookassa_payment_id, description)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		INSERT INTO payments (payment_id, room_id, user_id, amount, currency, status, yookassa_payment_id, description,
+		                      authorized_amount, driver_id, idempotency_key, confirmation_url)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9::numeric, 0), NULLIF($10::text, '')::uuid, NULLIF($11, ''),
+		        NULLIF($12, ''))
 	`
 	_, err := r.db.Exec(ctx, query,
 		p.PaymentID, p.RoomID, p.UserID,
 		p.Amount, p.Currency, p.Status,
-		p.YookassaPaymentID, p.Description,
+		p.YookassaPaymentID, p.Description, p.AuthorizedAmount, p.DriverID, p.IdempotencyKey, p.ConfirmationURL,
 	)
 	if err != nil {
 		return fmt.Errorf("CreatePayment: %w", err)
@@ -54,13 +171,44 @@ func (r *repository) CreatePayment(ctx context.Context, p *PaymentRecord) error
 	return nil
 }
 
-func (r *repository) UpdatePaymentStatus(ctx context.Context, paymentID, status, yookassaID string) error {
+// ReservePayment сохраняет платёж под ключом p.IdempotencyKey до обращения к провайдеру.
+// Если ключ уже занят, возвращает ранее сохранённый платёж и false.
+func (r *repository) ReservePayment(ctx context.Context, p *PaymentRecord) (*PaymentRecord, bool, error) {
+	query := `
+		INSERT INTO payments (payment_id, room_id, user_id, amount, currency, status, yookassa_payment_id, description,
+		                      driver_id, idempotency_key, discount, promo_code, kind, authorized_amount)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, NULLIF($9::text, '')::uuid, $10, $11, NULLIF($12, ''),
+		        COALESCE(NULLIF($13, ''), 'ride'), NULLIF($14::numeric, 0))
+		ON CONFLICT (idempotency_key) DO NOTHING
+	`
+	tag, err := r.db.Exec(ctx, query,
+		p.PaymentID, p.RoomID, p.UserID,
+		p.Amount, p.Currency, p.Status,
+		p.YookassaPaymentID, p.Description, p.DriverID, p.IdempotencyKey, p.Discount, p.PromoCode, p.Kind,
+		p.AuthorizedAmount,
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("ReservePayment: %w", err)
+	}
+	if tag.RowsAffected() > 0 {
+		return p, true, nil
+	}
+
+	row := r.db.QueryRow(ctx, `SELECT `+paymentColumns+` FROM payments WHERE idempotency_key = $1`, p.IdempotencyKey)
+	existing, err := scanPayment(row)
+	if err != nil {
+		return nil, false, fmt.Errorf("ReservePayment existing: %w", err)
+	}
+	return existing, false, nil
+}
+
+func (r *repository) UpdatePaymentStatus(ctx context.Context, paymentID, status, yookassaID, confirmationURL string) error {
 	query := `
 		UPDATE payments
-		SET status = $1, yookassa_payment_id = $2, updated_at = NOW()
+		SET status = $1, yookassa_payment_id = $2, confirmation_url = NULLIF($4, ''), updated_at = NOW()
 		WHERE payment_id = $3
 	`
-	_, err := r.db.Exec(ctx, query, status, yookassaID, paymentID)
+	_, err := r.db.Exec(ctx, query, status, yookassaID, paymentID, confirmationURL)
 	if err != nil {
 		return fmt.Errorf("UpdatePaymentStatus: %w", err)
 	}
@@ -69,46 +217,377 @@ func (r *repository) UpdatePaymentStatus(ctx context.Context, paymentID, status,
 
 func (r *repository) GetPaymentsByRoom(ctx context.Context, roomID string) ([]*PaymentRecord, error) {
 	query := `
-		SELECT payment_id, room_id, user_id, amount, currency, status,
-		       yookassa_payment_id, description, created_at, updated_at
+		SELECT ` + paymentColumns + `
 		FROM payments WHERE room_id = $1
 		ORDER BY created_at DESC
 	`
 	return r.scanPayments(ctx, query, roomID)
 }
 
-func (r *repository) GetPaymentsByUser(ctx context.Context, userID string) ([]*PaymentRecord, error) {
+func (r *repository) GetPaymentByID(ctx context.Context, paymentID string) (*PaymentRecord, error) {
 	query := `
-		SELECT payment_id, room_id, user_id, amount, currency, status,
-		       yookassa_payment_id, description, created_at, updated_at
-		FROM payments WHERE user_id = $1
-		ORDER BY created_at DESC
+		SELECT ` + paymentColumns + `
+		FROM payments WHERE payment_id = $1
 	`
-	return r.scanPayments(ctx, query, userID)
+	row := r.db.QueryRow(ctx, query, paymentID)
+	p, err := scanPayment(row)
+	if err != nil {
+		return nil, fmt.Errorf("GetPaymentByID: %w", err)
+	}
+	return p, nil
 }
 
-func (r *repository) GetPaymentByID(ctx context.Context, paymentID string) (*PaymentRecord, error) {
+func (r *repository) GetPaymentByYookassaID(ctx context.Context, yookassaID string) (*PaymentRecord, error) {
 	query := `
-		SELECT payment_id, room_id, user_id, amount, currency, status,
-		       yookassa_payment_id, description, created_at, updated_at
-		FROM payments WHERE payment_id = $1
+		SELECT ` + paymentColumns + `
+		FROM payments WHERE yookassa_payment_id = $1
 	`
-	row := r.db.QueryRow(ctx, query, paymentID)
+	row := r.db.QueryRow(ctx, query, yookassaID)
+	p, err := scanPayment(row)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetPaymentByYookassaID: %w", err)
+	}
+	return p, nil
+}
+
+// TransitionPaymentStatus меняет статус, только если текущий входит в from.
+// false — платёж уже в другом статусе: повторное уведомление ничего не меняет.
+func (r *repository) TransitionPaymentStatus(ctx context.Context, paymentID, status string, from ...string) (bool, error) {
+	query := `
+		UPDATE payments
+		SET status = $1, updated_at = NOW()
+		WHERE payment_id = $2 AND status = ANY($3)
+	`
+	tag, err := r.db.Exec(ctx, query, status, paymentID, from)
+	if err != nil {
+		return false, fmt.Errorf("TransitionPaymentStatus: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// ListStalePayments — платежи в статусах statuses, не менявшиеся с before, самые старые первыми
+func (r *repository) ListStalePayments(ctx context.Context, before time.Time, limit int, statuses ...string) ([]*PaymentRecord, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = ANY($1) AND updated_at < $2 AND yookassa_payment_id <> ''
+		ORDER BY updated_at
+		LIMIT $3
+	`
+	return r.scanPayments(ctx, query, statuses, before, limit)
+}
+
+// ListActiveHolds — неподтверждённые и ожидающие списания холды комнаты; с userID — только его
+func (r *repository) ListActiveHolds(ctx context.Context, roomID, userID string) ([]*PaymentRecord, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE room_id = $1 AND ($2 = '' OR user_id::text = $2)
+		  AND authorized_amount IS NOT NULL AND status IN ('pending', 'waiting_for_capture')
+		ORDER BY created_at
+	`
+	return r.scanPayments(ctx, query, roomID, userID)
+}
+
+// ListUnconfirmedPayments — списания, которые пассажир так и не подтвердил по ссылке: напоминаний меньше
+// maxReminders, последнее (или сам платёж) — раньше remindBefore. Холды не входят: их снимают при завершении.
+func (r *repository) ListUnconfirmedPayments(ctx context.Context, remindBefore time.Time, maxReminders, limit int) ([]*PaymentRecord, error) {
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments
+		WHERE status = 'pending' AND confirmation_url IS NOT NULL AND authorized_amount IS NULL
+		  AND reminders < $2 AND COALESCE(reminded_at, created_at) < $1
+		ORDER BY created_at
+		LIMIT $3
+	`
+	return r.scanPayments(ctx, query, remindBefore, maxReminders, limit)
+}
+
+// MarkReminded отмечает отправленное напоминание
+func (r *repository) MarkReminded(ctx context.Context, paymentID string) error {
+	_, err := r.db.Exec(ctx, `UPDATE payments SET reminders = reminders + 1, reminded_at = NOW() WHERE payment_id = $1`, paymentID)
+	if err != nil {
+		return fmt.Errorf("MarkReminded: %w", err)
+	}
+	return nil
+}
+
+// CaptureHold фиксирует списанную сумму холда и водителя поездки. false — холд уже списан или отменён.
+func (r *repository) CaptureHold(ctx context.Context, paymentID, driverID string, amount float64, status string) (bool, error) {
+	query := `
+		UPDATE payments
+		SET amount = $1, status = $2, driver_id = COALESCE(NULLIF($4::text, '')::uuid, driver_id), updated_at = NOW()
+		WHERE payment_id = $3 AND status = 'waiting_for_capture'
+	`
+	tag, err := r.db.Exec(ctx, query, amount, status, paymentID, driverID)
+	if err != nil {
+		return false, fmt.Errorf("CaptureHold: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// AnonymizeUserPayments очищает описания платежей пользователя (в них адреса поездок) и контакты для чеков
+func (r *repository) AnonymizeUserPayments(ctx context.Context, userID string) (int64, error) {
+	query := `
+		UPDATE payments
+		SET description = '', updated_at = NOW()
+		WHERE user_id = $1 AND description <> ''
+	`
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("AnonymizeUserPayments: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, userID)
+	if err != nil {
+		return 0, fmt.Errorf("AnonymizeUserPayments: %w", err)
+	}
+	// Чеки уже переданы в ОФД, локально контакты не нужны
+	if _, err := tx.Exec(ctx, `DELETE FROM receipt_contacts WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("AnonymizeUserPayments contacts: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE receipts SET email = '', phone = '' WHERE user_id = $1`, userID); err != nil {
+		return 0, fmt.Errorf("AnonymizeUserPayments receipts: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("AnonymizeUserPayments commit: %w", err)
+	}
+	return tag.RowsAffected(), nil
+}
+
+// SearchPayments ищет платежи по id, комнате, пользователям, статусу и дате создания
+func (r *repository) SearchPayments(ctx context.Context, f PaymentFilter) ([]*PaymentRecord, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conds := f.conditions(arg)
+	if !f.BeforeTime.IsZero() {
+		conds = append(conds, "(p.created_at, p.payment_id) < ("+arg(f.BeforeTime)+", "+arg(f.BeforeID)+"::uuid)")
+	}
+
+	query := `
+		SELECT ` + paymentColumns + `
+		FROM payments p`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY p.created_at DESC, p.payment_id DESC LIMIT " + arg(f.Limit)
+	return r.scanPayments(ctx, query, args...)
+}
+
+// PaymentTotals — итоги по валютам всех платежей под фильтром; курсор и лимит не учитываются
+func (r *repository) PaymentTotals(ctx context.Context, f PaymentFilter) ([]PaymentTotal, error) {
+	var args []interface{}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	conds := f.conditions(arg)
+
+	query := `
+		SELECT p.currency, COUNT(*),
+		       COUNT(*) FILTER (WHERE p.status IN ('succeeded', 'partially_refunded', 'refunded')),
+		       COALESCE(SUM(p.amount) FILTER (WHERE p.status IN ('succeeded', 'partially_refunded', 'refunded')), 0)::float8,
+		       COALESCE(SUM(r.amount), 0)::float8
+		FROM payments p
+		LEFT JOIN (
+			SELECT payment_id, SUM(amount) AS amount FROM refunds
+			WHERE status = 'succeeded' GROUP BY payment_id
+		) r ON r.payment_id = p.payment_id`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " GROUP BY p.currency ORDER BY p.currency"
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("PaymentTotals: %w", err)
+	}
+	defer rows.Close()
+
+	var totals []PaymentTotal
+	for rows.Next() {
+		var t PaymentTotal
+		if err := rows.Scan(&t.Currency, &t.Count, &t.Paid, &t.Spent, &t.Refunded); err != nil {
+			return nil, fmt.Errorf("PaymentTotals scan: %w", err)
+		}
+		totals = append(totals, t)
+	}
+	return totals, rows.Err()
+}
+
+// conditions — условия WHERE по полям фильтра, кроме курсора; arg добавляет параметр запроса
+func (f PaymentFilter) conditions(arg func(v interface{}) string) []string {
+	var conds []string
+	if f.PaymentID != "" {
+		conds = append(conds, "p.payment_id = "+arg(f.PaymentID))
+	}
+	if f.RoomID != "" {
+		conds = append(conds, "p.room_id = "+arg(f.RoomID))
+	}
+	if len(f.UserIDs) > 0 {
+		conds = append(conds, "p.user_id = ANY("+arg(f.UserIDs)+"::uuid[])")
+	}
+	if f.Status != "" {
+		conds = append(conds, "p.status = "+arg(f.Status))
+	}
+	if f.Kind != "" {
+		conds = append(conds, "p.kind = "+arg(f.Kind))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "p.created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "p.created_at < "+arg(f.CreatedTo))
+	}
+	return conds
+}
+
+// GetRefundedAmount — сумма возвратов платежа, которые прошли или ещё обрабатываются
+func (r *repository) GetRefundedAmount(ctx context.Context, paymentID string) (float64, error) {
+	var sum float64
+	err := r.db.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::float8 FROM refunds
+		WHERE payment_id = $1 AND status <> 'failed'
+	`, paymentID).Scan(&sum)
+	if err != nil {
+		return 0, fmt.Errorf("GetRefundedAmount: %w", err)
+	}
+	return sum, nil
+}
+
+// CreateRefund резервирует возврат до запроса в ЮKassa. Строка платежа блокируется,
+// поэтому параллельные возвраты не превысят сумму платежа.
+func (r *repository) CreateRefund(ctx context.Context, ref *RefundRecord) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("CreateRefund: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var (
+		amount, refunded float64
+		status, currency string
+	)
+	err = tx.QueryRow(ctx, `SELECT amount::float8, status, currency FROM payments WHERE payment_id = $1 FOR UPDATE`, ref.PaymentID).
+		Scan(&amount, &status, &currency)
+	if err != nil {
+		return fmt.Errorf("CreateRefund: %w", err)
+	}
+	if status != "succeeded" && status != "partially_refunded" {
+		return ErrNotRefundable
+	}
+	err = tx.QueryRow(ctx, `
+		SELECT COALESCE(SUM(amount), 0)::float8 FROM refunds
+		WHERE payment_id = $1 AND status <> 'failed'
+	`, ref.PaymentID).Scan(&refunded)
+	if err != nil {
+		return fmt.Errorf("CreateRefund: %w", err)
+	}
+	if money.ToMinor(refunded, currency)+money.ToMinor(ref.Amount, currency) > money.ToMinor(amount, currency) {
+		return ErrRefundExceedsBalance
+	}
+
+	err = tx.QueryRow(ctx, `
+		INSERT INTO refunds (refund_id, payment_id, amount, status, reason)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`, ref.RefundID, ref.PaymentID, ref.Amount, ref.Status, ref.Reason).Scan(&ref.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("CreateRefund: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// FinishRefund сохраняет результат ожидающего возврата и пересчитывает статус платежа:
+// refunded — возвращена вся сумма, partially_refunded — часть. Возвращает статус платежа.
+func (r *repository) FinishRefund(ctx context.Context, refundID, status, yookassaRefundID string) (string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("FinishRefund: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var paymentID string
+	err = tx.QueryRow(ctx, `
+		UPDATE refunds SET status = $1, yookassa_refund_id = $2, updated_at = NOW()
+		WHERE refund_id = $3 AND status = 'pending'
+		RETURNING payment_id
+	`, status, yookassaRefundID, refundID).Scan(&paymentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", ErrRefundFinished
+	}
+	if err != nil {
+		return "", fmt.Errorf("FinishRefund: %w", err)
+	}
+
+	var paymentStatus string
+	err = tx.QueryRow(ctx, `
+		UPDATE payments p SET
+			status = CASE
+				WHEN s.refunded >= p.amount THEN 'refunded'
+				WHEN s.refunded > 0 THEN 'partially_refunded'
+				ELSE p.status
+			END,
+			updated_at = NOW()
+		FROM (
+			SELECT COALESCE(SUM(amount), 0) AS refunded FROM refunds
+			WHERE payment_id = $1 AND status = 'succeeded'
+		) s
+		WHERE p.payment_id = $1
+		RETURNING p.status
+	`, paymentID).Scan(&paymentStatus)
+	if err != nil {
+		return "", fmt.Errorf("FinishRefund: %w", err)
+	}
+	return paymentStatus, tx.Commit(ctx)
+}
+
+func (r *repository) GetRefundByYookassaID(ctx context.Context, yookassaRefundID string) (*RefundRecord, error) {
+	query := `
+		SELECT refund_id, payment_id, amount::float8, status, yookassa_refund_id, COALESCE(reason, ''), created_at
+		FROM refunds WHERE yookassa_refund_id = $1
+	`
+	ref := &RefundRecord{}
+	err := r.db.QueryRow(ctx, query, yookassaRefundID).Scan(
+		&ref.RefundID, &ref.PaymentID, &ref.Amount, &ref.Status,
+		&ref.YookassaRefundID, &ref.Reason, &ref.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetRefundByYookassaID: %w", err)
+	}
+	return ref, nil
+}
+
+// paymentColumns — колонки платежа в порядке scanPayment
+const paymentColumns = `payment_id, room_id, user_id, amount, currency, status,
+		       yookassa_payment_id, description, COALESCE(authorized_amount, 0), COALESCE(driver_id::text, ''),
+		       COALESCE(idempotency_key, ''), COALESCE(confirmation_url, ''), reminders, discount::float8,
+		       COALESCE(promo_code, ''), kind, created_at, updated_at`
+
+func scanPayment(row pgx.Row) (*PaymentRecord, error) {
 	p := &PaymentRecord{}
 	err := row.Scan(
 		&p.PaymentID, &p.RoomID, &p.UserID,
 		&p.Amount, &p.Currency, &p.Status,
 		&p.YookassaPaymentID, &p.Description,
-		&p.CreatedAt, &p.UpdatedAt,
+		&p.AuthorizedAmount, &p.DriverID, &p.IdempotencyKey, &p.ConfirmationURL, &p.Reminders,
+		&p.Discount, &p.PromoCode, &p.Kind, &p.CreatedAt, &p.UpdatedAt,
 	)
-	if err != nil {
-		return nil, fmt.Errorf("GetPaymentByID: %w", err)
-	}
-	return p, nil
+	return p, err
 }
 
-func (r *repository) scanPayments(ctx context.Context, query string, arg interface{}) ([]*PaymentRecord, error) {
-	rows, err := r.db.Query(ctx, query, arg)
+func (r *repository) scanPayments(ctx context.Context, query string, args ...interface{}) ([]*PaymentRecord, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query: %w", err)
 	}
@@ -116,13 +595,7 @@ func (r *repository) scanPayments(ctx context.Context, query string, arg interfa
 
 	var result []*PaymentRecord
 	for rows.Next() {
-		p := &PaymentRecord{}
-		err := rows.Scan(
-			&p.PaymentID, &p.RoomID, &p.UserID,
-			&p.Amount, &p.Currency, &p.Status,
-			&p.YookassaPaymentID, &p.Description,
-			&p.CreatedAt, &p.UpdatedAt,
-		)
+		p, err := scanPayment(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Состояния выписки
+const (
+	StatementPending = "pending"
+	StatementReady   = "ready"
+	StatementFailed  = "failed"
+)
+
+// StatementRecord — выписка пользователя за месяц; файл рендерится в фоне
+type StatementRecord struct {
+	StatementID string
+	UserID      string
+	Role        string    // rider — оплаченные поездки, driver — заработок
+	Month       time.Time // первое число месяца, UTC
+	Format      string    // csv, pdf
+	Status      string
+	FileName    string // имя файла в хранилище выписок; пусто, пока выписка не готова
+	Error       string // причина ошибки рендера
+	CreatedAt   time.Time
+	FinishedAt  time.Time // нулевое — выписка ещё рендерится
+}
+
+const statementColumns = `
+	statement_id, user_id, role, month, format, status, file_name, error, created_at, finished_at`
+
+// CreateStatement сохраняет запрос выписки в статусе pending
+func (r *repository) CreateStatement(ctx context.Context, st *StatementRecord) error {
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO statements (user_id, role, month, format)
+		VALUES ($1, $2, $3, $4)
+		RETURNING statement_id, status, created_at
+	`, st.UserID, st.Role, st.Month, st.Format).Scan(&st.StatementID, &st.Status, &st.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("CreateStatement: %w", err)
+	}
+	return nil
+}
+
+// GetStatement возвращает выписку по id или ErrNotFound
+func (r *repository) GetStatement(ctx context.Context, statementID string) (*StatementRecord, error) {
+	st := &StatementRecord{}
+	var finishedAt *time.Time
+	err := r.db.QueryRow(ctx, `SELECT `+statementColumns+` FROM statements WHERE statement_id = $1`, statementID).Scan(
+		&st.StatementID, &st.UserID, &st.Role, &st.Month, &st.Format, &st.Status, &st.FileName, &st.Error,
+		&st.CreatedAt, &finishedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("GetStatement: %w", err)
+	}
+	if finishedAt != nil {
+		st.FinishedAt = *finishedAt
+	}
+	return st, nil
+}
+
+// FinishStatement переводит выписку в ready с файлом fileName или в failed с ошибкой errMsg
+func (r *repository) FinishStatement(ctx context.Context, statementID, status, fileName, errMsg string) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE statements
+		SET status = $2, file_name = $3, error = $4, finished_at = NOW()
+		WHERE statement_id = $1
+	`, statementID, status, fileName, errMsg)
+	if err != nil {
+		return fmt.Errorf("FinishStatement: %w", err)
+	}
+	return nil
+}
+
+// DeleteStatements удаляет выписки пользователя и возвращает имена их файлов
+func (r *repository) DeleteStatements(ctx context.Context, userID string) ([]string, error) {
+	rows, err := r.db.Query(ctx, `DELETE FROM statements WHERE user_id = $1 RETURNING file_name`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("DeleteStatements: %w", err)
+	}
+	defer rows.Close()
+
+	var files []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("DeleteStatements scan: %w", err)
+		}
+		if name != "" {
+			files = append(files, name)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("DeleteStatements rows: %w", err)
+	}
+	return files, nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+	roompb "we_ride/internal/services/room_service/pb"
+)
+
+// feeKinds — виды штрафов по правилам отмены комнаты
+var feeKinds = map[string]string{
+	"cancellation": repository.PaymentCancellationFee,
+	"no_show":      repository.PaymentNoShowFee,
+}
+
+var feeDescriptions = map[string]string{
+	repository.PaymentCancellationFee: "поздняя отмена поездки",
+	repository.PaymentNoShowFee:       "неявка на поездку",
+}
+
+// ChargeFee списывает штраф за позднюю отмену или неявку и начисляет его создателю комнаты.
+// Вызывается room_service от имени пассажира при выходе из комнаты или водителя при начале поездки;
+// пассажир может оплатить только свой штраф. Сумма берётся из штрафа, записанного в комнате,
+// штраф списывается один раз на комнату и вид.
+func (s *PaymentService) ChargeFee(ctx context.Context, req *pb.ChargeFeeRequest) (*pb.ChargeFeeResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" || req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id and user_id are required")
+	}
+	kind, ok := feeKinds[req.Type]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "type must be cancellation or no_show")
+	}
+
+	billing, err := s.roomBilling(ctx, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	room := billing.GetRoom()
+	driverID := room.GetCreatorId()
+	if req.UserId != caller.UserID && caller.UserID != driverID && !caller.HasRole(identity.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+	i := slices.IndexFunc(billing.GetFees(), func(f *roompb.RoomFee) bool {
+		return f.UserId == req.UserId && f.Type == req.Type
+	})
+	if i < 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "no %s fee for user %s in room", req.Type, req.UserId)
+	}
+	amount := billing.GetFees()[i].Amount
+	if amount <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "fee amount must be greater than 0")
+	}
+	cur, err := s.currency(room.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("Штраф: %s (комната %s)", feeDescriptions[kind], req.RoomId)
+	}
+	payment, err := s.charge(ctx, kind+"-"+req.RoomId, req.RoomId, req.UserId, driverID, kind, amount, cur, description, discount{})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.ChargeFeeResponse{Payment: payment}, nil
+}
+
+// isFee — платёж является штрафом, а не оплатой доли в поездке
+func isFee(p *repository.PaymentRecord) bool {
+	return p.Kind == repository.PaymentCancellationFee || p.Kind == repository.PaymentNoShowFee
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+const (
+	defaultHistoryLimit = 20
+	maxHistoryLimit     = 100
+)
+
+// GetPaymentHistory — история платежей пользователя постранично, новые первыми, с фильтрами по статусу,
+// комнате, виду платежа и периоду. Итоги по валютам считаются по всем платежам под фильтрами, а не по странице.
+func (s *PaymentService) GetPaymentHistory(ctx context.Context, req *pb.GetPaymentHistoryRequest) (*pb.GetPaymentHistoryResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	f := repository.PaymentFilter{
+		UserIDs: []string{userID},
+		RoomID:  req.RoomId,
+		Status:  req.Status,
+		Kind:    req.Kind,
+		Limit:   defaultHistoryLimit,
+	}
+	if req.Limit < 0 {
+		return nil, status.Error(codes.InvalidArgument, "limit must not be negative")
+	}
+	if req.Limit > 0 {
+		f.Limit = min(int(req.Limit), maxHistoryLimit)
+	}
+	if f.CreatedFrom, err = filterTime(req.From, "from"); err != nil {
+		return nil, err
+	}
+	if f.CreatedTo, err = filterTime(req.To, "to"); err != nil {
+		return nil, err
+	}
+	if req.Cursor != "" {
+		if f.BeforeTime, f.BeforeID, err = decodeCursor(req.Cursor); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid cursor")
+		}
+	}
+
+	// Лишняя запись показывает, есть ли следующая страница
+	page := f
+	page.Limit++
+	records, err := s.repo.SearchPayments(ctx, page)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payment history: %v", err)
+	}
+	totals, err := s.repo.PaymentTotals(ctx, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payment totals: %v", err)
+	}
+
+	resp := &pb.GetPaymentHistoryResponse{Payments: make([]*pb.Payment, 0, min(len(records), f.Limit))}
+	if len(records) > f.Limit {
+		records = records[:f.Limit]
+		resp.NextCursor = encodeCursor(records[len(records)-1])
+	}
+	for _, p := range records {
+		resp.Payments = append(resp.Payments, toPBPayment(p))
+	}
+	for _, t := range totals {
+		resp.Totals = append(resp.Totals, &pb.PaymentTotals{
+			Currency: t.Currency,
+			Count:    int32(t.Count),
+			Spent:    float32(money.Round(t.Spent, t.Currency)),
+			Refunded: float32(money.Round(t.Refunded, t.Currency)),
+		})
+	}
+	return resp, nil
+}
+
+// filterTime — граница периода из запроса; nil — без границы
+func filterTime(ts *timestamppb.Timestamp, name string) (time.Time, error) {
+	if ts == nil {
+		return time.Time{}, nil
+	}
+	if err := ts.CheckValid(); err != nil {
+		return time.Time{}, status.Errorf(codes.InvalidArgument, "%s is not a valid timestamp", name)
+	}
+	return ts.AsTime(), nil
+}
+
+// encodeCursor — курсор страницы после платежа p: его время создания и id
+func encodeCursor(p *repository.PaymentRecord) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(p.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + p.PaymentID))
+}
+
+func decodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	at, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return time.Time{}, "", errors.New("malformed cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, at)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return time.Time{}, "", err
+	}
+	return t, id, nil
+}
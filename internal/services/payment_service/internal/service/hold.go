@@ -0,0 +1,302 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// AuthorizePayment ставит холд на оценочную долю пассажира при вступлении в комнату.
+// С сохранённым способом оплаты холд ставится сразу, иначе нужен redirect; неподтверждённый холд
+// остаётся pending, и room_service не пускает пассажира в комнату, пока холд не подтверждён.
+// Холд резервируется под ключом комнаты и пассажира до обращения к провайдеру, поэтому параллельные
+// вступления и повтор после таймаута получают один и тот же холд.
+func (s *PaymentService) AuthorizePayment(ctx context.Context, req *pb.AuthorizePaymentRequest) (*pb.AuthorizePaymentResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	s.rememberEmail(ctx)
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	if req.Amount <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be greater than 0")
+	}
+	currency, err := s.currency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	description := req.Description
+	if description == "" {
+		description = fmt.Sprintf("Предавторизация поездки (комната %s)", req.RoomId)
+	}
+	amount := money.Round(float64(req.Amount), currency)
+
+	// Снятый, отклонённый или списанный холд не переиспользуется: следующий получает ключ с номером попытки
+	var record *repository.PaymentRecord
+	for attempt := 1; record == nil; attempt++ {
+		reserved := &repository.PaymentRecord{
+			PaymentID:        uuid.New().String(),
+			RoomID:           req.RoomId,
+			UserID:           userID,
+			Amount:           amount,
+			AuthorizedAmount: amount,
+			Currency:         currency,
+			Status:           provider.StatusPending,
+			Description:      description,
+			IdempotencyKey:   holdKey(req.RoomId, userID, attempt),
+			CreatedAt:        time.Now(),
+		}
+		existing, created, err := s.repo.ReservePayment(ctx, reserved)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to save payment: %v", err)
+		}
+		switch {
+		case created:
+			record = reserved
+		case existing.Status == provider.StatusCanceled || existing.Status == provider.StatusSucceeded:
+			continue
+		case existing.YookassaPaymentID != "":
+			// Неподтверждённый холд можно подтвердить по той же ссылке
+			return &pb.AuthorizePaymentResponse{Payment: toPBPayment(existing), ConfirmationUrl: existing.ConfirmationURL}, nil
+		default:
+			// Провайдер не создал холд: отправляем его заново с тем же ключом
+			record = existing
+		}
+	}
+
+	methodID, err := s.defaultMethodID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, provErr := s.provider.CreatePayment(ctx, record.IdempotencyKey, provider.CreatePaymentRequest{
+		Amount:          record.Amount,
+		Currency:        record.Currency,
+		Description:     record.Description,
+		ReturnURL:       returnURL,
+		Capture:         false,
+		PaymentMethodID: methodID,
+		// Чек на сумму холда; при списании передаётся чек на итоговую долю
+		Receipt: s.receipt(ctx, userID, record.Amount, record.Currency),
+		Metadata: map[string]string{
+			"room_id":    req.RoomId,
+			"user_id":    userID,
+			"payment_id": record.PaymentID,
+		},
+	})
+	if provErr != nil {
+		record.Status = "failed"
+	} else {
+		record.Status, record.YookassaPaymentID, record.ConfirmationURL = resp.Status, resp.ID, resp.ConfirmationURL
+	}
+
+	// Отказ тоже сохраняется — для аудита
+	if err := s.repo.UpdatePaymentStatus(ctx, record.PaymentID, record.Status, record.YookassaPaymentID, record.ConfirmationURL); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save payment: %v", err)
+	}
+	if provErr != nil {
+		return nil, status.Errorf(codes.FailedPrecondition, "payment authorization failed: %v", provErr)
+	}
+	if record.Status == provider.StatusCanceled {
+		return nil, status.Error(codes.FailedPrecondition, "payment authorization declined")
+	}
+	return &pb.AuthorizePaymentResponse{Payment: toPBPayment(record), ConfirmationUrl: record.ConfirmationURL}, nil
+}
+
+// holdKey — ключ идемпотентности холда пассажира в комнате; attempt растёт после снятого холда
+func holdKey(roomID, userID string, attempt int) string {
+	if attempt == 1 {
+		return fmt.Sprintf("hold-%s-%s", roomID, userID)
+	}
+	return fmt.Sprintf("hold-%s-%s-%d", roomID, userID, attempt)
+}
+
+// CapturePayment списывает итоговую долю при завершении поездки, только для создателя комнаты и admin
+// (проверяется в room_service, как в ProcessPayment).
+// Холд списывается на сумму доли за вычетом скидки по промокоду (остаток холда снимается с карты),
+// недостающее и пассажиры без подтверждённого холда оплачиваются обычным платежом.
+// Ошибка по одному пассажиру не прерывает остальных — итог по каждому в results, как в ProcessPayment.
+func (s *PaymentService) CapturePayment(ctx context.Context, req *pb.CapturePaymentRequest) (*pb.CapturePaymentResponse, error) {
+	caller, err := identity.RequireRole(ctx, identity.RoleDriver, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	if len(req.UserIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_ids must not be empty")
+	}
+	if req.AmountPerUser <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount_per_user must be greater than 0")
+	}
+	currency, err := s.currency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	driverID, err := s.rideCharge(ctx, caller, req.RoomId, req.UserIds, req.AmountPerUser, currency, req.DriverId)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([][]*pb.Payment, len(req.UserIds))
+	results := make([]*pb.PaymentResult, len(req.UserIds))
+	s.fanOut(ctx, len(req.UserIds), func(i int) {
+		userID := req.UserIds[i]
+		paid, err := s.captureShare(ctx, req, userID, driverID, currency)
+		payments[i], results[i] = paid, shareResult(userID, paid, err)
+	})
+
+	overall := overallStatus(results)
+	return &pb.CapturePaymentResponse{
+		Payments: slices.Concat(payments...),
+		Success:  overall == resultSucceeded || overall == resultPending,
+		Status:   overall,
+		Results:  results,
+	}, nil
+}
+
+// captureShare списывает долю пассажира с его холдов и доплачивает недостающее.
+// Холд, который не удалось списать, снимается, и его часть доли уходит в обычный платёж: отказ по нему
+// повторяется через RetryFailedPayments. Если холд не удалось и снять, доплата не создаётся — иначе
+// пассажир может заплатить дважды; итог сверит Reconcile.
+func (s *PaymentService) captureShare(ctx context.Context, req *pb.CapturePaymentRequest, userID, driverID, currency string) ([]*pb.Payment, error) {
+	share := money.Round(float64(req.AmountPerUser), currency)
+	// Скидка записывается в первый платёж пассажира: списание холда или обычный платёж
+	d := s.discountFor(ctx, req.RoomId, userID, share, currency)
+	remaining := money.Round(share-d.Amount, currency)
+
+	holds, err := s.repo.ListActiveHolds(ctx, req.RoomId, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get holds: %v", err)
+	}
+	var paid []*pb.Payment
+	for _, hold := range holds {
+		if hold.Status != provider.StatusWaitingForCapture || hold.Currency != currency || remaining <= 0 {
+			// Пассажир не подтвердил холд, холд в другой валюте или долю покрыла скидка — он больше не нужен
+			s.void(ctx, hold)
+			continue
+		}
+		hold.DriverID = driverID
+		hold.Discount, hold.PromoCode = d.Amount, d.Code
+		captured, err := s.captureHold(ctx, hold, min(remaining, hold.AuthorizedAmount))
+		if err != nil {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to capture hold, charging the share without it",
+				zap.String("payment_id", hold.PaymentID), zap.Error(err))
+			hold.Discount, hold.PromoCode = 0, ""
+			if !s.void(ctx, hold) {
+				return paid, err
+			}
+			continue
+		}
+		s.redeem(ctx, hold)
+		d = discount{}
+		paid = append(paid, captured)
+		remaining = money.Round(remaining-float64(captured.Amount), currency)
+	}
+
+	if remaining > 0 || d.Code != "" {
+		payment, err := s.charge(ctx, req.RoomId, req.RoomId, userID, driverID, repository.PaymentRide, float32(remaining), currency, req.Description, d)
+		if payment != nil {
+			paid = append(paid, payment)
+		}
+		if err != nil {
+			return paid, err
+		}
+	}
+	return paid, nil
+}
+
+func (s *PaymentService) captureHold(ctx context.Context, hold *repository.PaymentRecord, amount float64) (*pb.Payment, error) {
+	receipt := s.receipt(ctx, hold.UserID, amount, hold.Currency)
+	resp, err := s.provider.CapturePayment(ctx, "capture-"+hold.PaymentID, hold.YookassaPaymentID, amount, hold.Currency, receipt)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s capture error for user %s: %v", s.provider.Name(), hold.UserID, err)
+	}
+	newStatus := resp.Status
+	if _, err := s.repo.CaptureHold(ctx, hold.PaymentID, hold.DriverID, amount, newStatus); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save capture: %v", err)
+	}
+	hold.Amount, hold.Status = amount, newStatus
+	s.saveReceipt(ctx, receipt, hold.PaymentID, "", hold.UserID)
+	if newStatus == provider.StatusSucceeded {
+		if err := s.recordCharge(ctx, hold, amount); err != nil {
+			s.ledgerFailed(ctx, hold.PaymentID, err)
+		}
+	}
+	return toPBPayment(hold), nil
+}
+
+// VoidPayment снимает холды и не использованные промокоды: пассажира при выходе из комнаты или неявке,
+// всей комнаты при её отмене (только admin). Снятый промокод больше не занимает место в лимитах.
+func (s *PaymentService) VoidPayment(ctx context.Context, req *pb.VoidPaymentRequest) (*pb.VoidPaymentResponse, error) {
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	userID := req.UserId
+	if userID == "" {
+		if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+			return nil, err
+		}
+	} else if caller, err := identity.Caller(ctx); err != nil {
+		return nil, err
+	} else if caller.UserID != userID && !caller.HasRole(identity.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+
+	if _, err := s.repo.ReleasePromoCodes(ctx, req.RoomId, userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to release promo codes: %v", err)
+	}
+	holds, err := s.repo.ListActiveHolds(ctx, req.RoomId, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get holds: %v", err)
+	}
+	resp := &pb.VoidPaymentResponse{}
+	for _, hold := range holds {
+		if s.void(ctx, hold) {
+			resp.Payments = append(resp.Payments, toPBPayment(hold))
+		}
+	}
+	return resp, nil
+}
+
+// void отменяет холд у провайдера и в БД. Неподтверждённый (pending) холд провайдер отменить
+// не даёт — он истечёт сам, а пришедшее позже уведомление снимет его (см. webhook).
+func (s *PaymentService) void(ctx context.Context, hold *repository.PaymentRecord) bool {
+	if hold.Status == provider.StatusWaitingForCapture {
+		if _, err := s.provider.CancelPayment(ctx, "void-"+hold.PaymentID, hold.YookassaPaymentID); err != nil {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to cancel hold at provider",
+				zap.String("provider", s.provider.Name()),
+				zap.String("payment_id", hold.PaymentID), zap.Error(err))
+			return false
+		}
+	}
+	changed, err := s.repo.TransitionPaymentStatus(ctx, hold.PaymentID, provider.StatusCanceled,
+		provider.StatusPending, provider.StatusWaitingForCapture)
+	if err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to save canceled hold",
+			zap.String("payment_id", hold.PaymentID), zap.Error(err))
+		return false
+	}
+	if changed {
+		hold.Status = provider.StatusCanceled
+		s.publish(hold, provider.EventPaymentCanceled, "", hold.AuthorizedAmount)
+	}
+	return changed
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// bindingAmount — сумма проверочного платежа при привязке способа оплаты; холд сразу снимается
+const bindingAmount = 1.00
+
+// ListPaymentMethods — сохранённые способы оплаты пользователя, способ по умолчанию первым
+func (s *PaymentService) ListPaymentMethods(ctx context.Context, req *pb.ListPaymentMethodsRequest) (*pb.ListPaymentMethodsResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	methods, err := s.repo.ListPaymentMethods(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list payment methods: %v", err)
+	}
+	result := make([]*pb.PaymentMethod, 0, len(methods))
+	for _, m := range methods {
+		result = append(result, toPBMethod(m))
+	}
+	return &pb.ListPaymentMethodsResponse{Methods: result}, nil
+}
+
+// AddPaymentMethod начинает привязку способа оплаты: у провайдера создаётся проверочный холд
+// с сохранением способа. Пользователь подтверждает его по confirmation_url, после уведомления
+// провайдера способ становится активным, а холд снимается.
+func (s *PaymentService) AddPaymentMethod(ctx context.Context, req *pb.AddPaymentMethodRequest) (*pb.AddPaymentMethodResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	s.rememberEmail(ctx)
+	back := req.ReturnUrl
+	if back == "" {
+		back = returnURL
+	}
+
+	methodID := uuid.New().String()
+	resp, err := s.provider.CreatePayment(ctx, "bind-"+methodID, provider.CreatePaymentRequest{
+		Amount:            bindingAmount,
+		Currency:          s.opts.Currency,
+		Description:       "Привязка способа оплаты",
+		ReturnURL:         back,
+		Capture:           false,
+		SavePaymentMethod: true,
+		Receipt:           s.receipt(ctx, userID, bindingAmount, s.opts.Currency),
+		Metadata: map[string]string{
+			"user_id":   userID,
+			"method_id": methodID,
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "%s error: %v", s.provider.Name(), err)
+	}
+
+	m := &repository.PaymentMethodRecord{
+		MethodID:         methodID,
+		UserID:           userID,
+		Status:           "pending",
+		BindingPaymentID: resp.ID,
+	}
+	if err := s.repo.CreatePaymentMethod(ctx, m); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save payment method: %v", err)
+	}
+	return &pb.AddPaymentMethodResponse{Method: toPBMethod(m), ConfirmationUrl: resp.ConfirmationURL}, nil
+}
+
+// RemovePaymentMethod удаляет способ оплаты; способом по умолчанию становится последний добавленный
+func (s *PaymentService) RemovePaymentMethod(ctx context.Context, req *pb.RemovePaymentMethodRequest) (*pb.RemovePaymentMethodResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.MethodId == "" {
+		return nil, status.Error(codes.InvalidArgument, "method_id is required")
+	}
+	if err := s.repo.DeletePaymentMethod(ctx, userID, req.MethodId); err != nil {
+		return nil, methodError(err)
+	}
+	return &pb.RemovePaymentMethodResponse{Success: true}, nil
+}
+
+// SetDefaultPaymentMethod выбирает способ, которым списываются поездки
+func (s *PaymentService) SetDefaultPaymentMethod(ctx context.Context, req *pb.SetDefaultPaymentMethodRequest) (*pb.SetDefaultPaymentMethodResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.MethodId == "" {
+		return nil, status.Error(codes.InvalidArgument, "method_id is required")
+	}
+	if err := s.repo.SetDefaultPaymentMethod(ctx, userID, req.MethodId); err != nil {
+		return nil, methodError(err)
+	}
+	m, err := s.repo.GetDefaultPaymentMethod(ctx, userID)
+	if err != nil {
+		return nil, methodError(err)
+	}
+	return &pb.SetDefaultPaymentMethodResponse{Method: toPBMethod(m)}, nil
+}
+
+// defaultMethodID — сохранённый у провайдера способ по умолчанию; пусто — платёж подтверждается redirect
+func (s *PaymentService) defaultMethodID(ctx context.Context, userID string) (string, error) {
+	m, err := s.repo.GetDefaultPaymentMethod(ctx, userID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return "", nil
+	}
+	if err != nil {
+		return "", status.Errorf(codes.Internal, "failed to get payment method: %v", err)
+	}
+	return m.ProviderMethodID, nil
+}
+
+// applyBindingNotification завершает привязку способа оплаты по уведомлению о проверочном платеже
+func (s *PaymentService) applyBindingNotification(ctx context.Context, yk *provider.Payment) error {
+	l := logger.GetLoggerFromCtx(ctx)
+
+	m, err := s.repo.GetPaymentMethodByBinding(ctx, yk.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		l.Info(ctx, "provider notification for unknown payment method", zap.String("yookassa_id", yk.ID))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case yk.Status == provider.StatusCanceled:
+		_, err := s.repo.FailPaymentMethod(ctx, m.MethodID)
+		return err
+	case yk.PaymentMethod == nil || !yk.PaymentMethod.Saved:
+		// Платёж прошёл, но провайдер не сохранил способ (например, карта это не поддерживает)
+		l.Info(ctx, "payment method was not saved by provider", zap.String("method_id", m.MethodID))
+		if _, err := s.repo.FailPaymentMethod(ctx, m.MethodID); err != nil {
+			return err
+		}
+	default:
+		if _, err := s.repo.ActivatePaymentMethod(ctx, m.MethodID,
+			yk.PaymentMethod.ID, yk.PaymentMethod.Type, yk.PaymentMethod.Title); err != nil {
+			return err
+		}
+	}
+
+	// Проверочный холд не списывается
+	if yk.Status == provider.StatusWaitingForCapture {
+		if _, err := s.provider.CancelPayment(ctx, "unbind-"+m.MethodID, yk.ID); err != nil {
+			return fmt.Errorf("cancel binding payment: %w", err)
+		}
+	}
+	return nil
+}
+
+func methodError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		return status.Error(codes.NotFound, "payment method not found")
+	case errors.Is(err, repository.ErrMethodNotActive):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Errorf(codes.Internal, "payment method error: %v", err)
+	}
+}
+
+func toPBMethod(m *repository.PaymentMethodRecord) *pb.PaymentMethod {
+	return &pb.PaymentMethod{
+		MethodId:  m.MethodID,
+		UserId:    m.UserID,
+		Status:    m.Status,
+		Type:      m.Type,
+		Title:     m.Title,
+		IsDefault: m.IsDefault,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,271 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// PayoutOptions — параметры выплат водителям
+type PayoutOptions struct {
+	Interval time.Duration // как часто проверять, не пора ли выплачивать
+	Period   time.Duration // период выплат: заработок выплачивается после окончания периода
+}
+
+// PayoutReport — итог одного запуска выплат
+type PayoutReport struct {
+	PeriodEnd time.Time
+	Succeeded int
+	Failed    int
+	Pending   int
+	Amounts   map[string]float64 // выплачено по валютам
+}
+
+// payoutRetryBatch — сколько незавершённых выплат повторяется за запуск
+const payoutRetryBatch = 100
+
+// rideDriver — водитель, которому начисляется заработок. Водитель списывает оплату сам при завершении
+// поездки, поэтому driver_id может не указывать; admin указывает его явно.
+func rideDriver(caller identity.Identity, claimed string) (string, error) {
+	if caller.Role != identity.RoleDriver {
+		return claimed, nil
+	}
+	if claimed != "" && claimed != caller.UserID {
+		return "", status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+	return caller.UserID, nil
+}
+
+// GetDriverEarnings — заработок водителя по поездкам за период и выплаты, для driver и admin.
+// Итоги — в валюте отчёта: заработок в других валютах пересчитывается по курсам.
+func (s *PaymentService) GetDriverEarnings(ctx context.Context, req *pb.GetDriverEarningsRequest) (*pb.GetDriverEarningsResponse, error) {
+	caller, err := identity.RequireRole(ctx, identity.RoleDriver, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	driverID, err := rideDriver(caller, req.DriverId)
+	if err != nil {
+		return nil, err
+	}
+	if driverID == "" {
+		return nil, status.Error(codes.InvalidArgument, "driver_id is required")
+	}
+	reportCurrency := s.opts.ReportCurrency
+	if req.Currency != "" {
+		reportCurrency = strings.ToUpper(req.Currency)
+		if err := money.Validate(reportCurrency); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "currency must be an ISO 4217 code: %v", err)
+		}
+	}
+	var from, to time.Time
+	if req.From != "" {
+		if from, err = time.Parse(time.RFC3339, req.From); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "from must be RFC3339")
+		}
+	}
+	if req.To != "" {
+		if to, err = time.Parse(time.RFC3339, req.To); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "to must be RFC3339")
+		}
+	}
+
+	earnings, err := s.repo.ListDriverEarnings(ctx, driverID, from, to)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get earnings: %v", err)
+	}
+	payouts, err := s.repo.ListPayouts(ctx, driverID, "", defaultSearchLimit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payouts: %v", err)
+	}
+
+	resp := &pb.GetDriverEarningsResponse{DriverId: driverID, Currency: reportCurrency}
+	var gross, commission, net, paidOut float64
+	rides := map[string]*pb.RideEarning{}
+	for _, e := range earnings {
+		converted, err := s.convertEarning(ctx, e, reportCurrency)
+		if err != nil {
+			return nil, err
+		}
+		gross += converted.Gross
+		commission += converted.Commission
+		net += converted.Net
+		if e.PayoutID != "" {
+			paidOut += converted.Net
+		}
+
+		ride, ok := rides[e.RoomID]
+		if !ok {
+			// Заработок отсортирован от новых к старым: поездки в том же порядке
+			ride = &pb.RideEarning{RoomId: e.RoomID, PaidOut: true, EarnedAt: e.CreatedAt.Format(time.RFC3339), Currency: e.Currency}
+			rides[e.RoomID] = ride
+			resp.Rides = append(resp.Rides, ride)
+		}
+		ride.Gross += float32(e.Gross)
+		ride.Commission += float32(e.Commission)
+		ride.Net += float32(e.Net)
+		ride.Payments++
+		ride.PaidOut = ride.PaidOut && e.PayoutID != ""
+	}
+	resp.Gross = float32(money.Round(gross, reportCurrency))
+	resp.Commission = float32(money.Round(commission, reportCurrency))
+	resp.Net = float32(money.Round(net, reportCurrency))
+	resp.PaidOut = float32(money.Round(paidOut, reportCurrency))
+	resp.Unpaid = float32(money.Round(net-paidOut, reportCurrency))
+
+	for _, p := range payouts {
+		resp.Payouts = append(resp.Payouts, toPBPayout(p))
+	}
+	return resp, nil
+}
+
+// convertEarning пересчитывает заработок в валюту отчёта по курсам Options.Rates
+func (s *PaymentService) convertEarning(ctx context.Context, e *repository.EarningRecord, currency string) (*repository.EarningRecord, error) {
+	converted := *e
+	converted.Currency = currency
+	for _, v := range []*float64{&converted.Gross, &converted.Commission, &converted.Net} {
+		amount, err := money.Convert(ctx, s.opts.Rates, *v, e.Currency, currency)
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "cannot convert earnings to %s: %v", currency, err)
+		}
+		*v = amount
+	}
+	return &converted, nil
+}
+
+// RunPayouts запускает выплаты каждые opts.Interval до отмены ctx. ctx должен содержать логгер.
+func (s *PaymentService) RunPayouts(ctx context.Context, opts PayoutOptions) {
+	l := logger.GetLoggerFromCtx(ctx)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.PayoutBatch(ctx, time.Now().Truncate(opts.Period))
+			if err != nil {
+				l.Error(ctx, "driver payouts failed", zap.Error(err))
+				continue
+			}
+			l.Info(ctx, "driver payouts report",
+				zap.Time("period_end", report.PeriodEnd),
+				zap.Int("succeeded", report.Succeeded),
+				zap.Int("failed", report.Failed),
+				zap.Int("pending", report.Pending),
+				zap.Any("amounts", report.Amounts),
+			)
+		}
+	}
+}
+
+// PayoutBatch выплачивает каждому водителю заработок, начисленный до periodEnd. Сначала повторяются
+// выплаты, не завершённые на прошлых запусках, — с тем же ключом идемпотентности.
+func (s *PaymentService) PayoutBatch(ctx context.Context, periodEnd time.Time) (*PayoutReport, error) {
+	if s.opts.Payouts == nil {
+		return nil, errors.New("payout provider is not configured")
+	}
+	report := &PayoutReport{PeriodEnd: periodEnd, Amounts: map[string]float64{}}
+
+	pending, err := s.repo.ListPayouts(ctx, "", "pending", payoutRetryBatch)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range pending {
+		s.payout(ctx, p, report)
+	}
+
+	drivers, err := s.repo.ListDriversToPay(ctx, periodEnd)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range drivers {
+		p, err := s.repo.CreatePayout(ctx, d.DriverID, d.Currency, periodEnd)
+		if errors.Is(err, repository.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			report.Failed++
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to create payout",
+				zap.String("driver_id", d.DriverID), zap.String("currency", d.Currency), zap.Error(err))
+			continue
+		}
+		s.payout(ctx, p, report)
+	}
+	return report, nil
+}
+
+// payout отправляет выплату провайдеру и сохраняет результат. Неудавшаяся выплата освобождает
+// заработок для следующего запуска; pending остаётся и повторяется.
+func (s *PaymentService) payout(ctx context.Context, p *repository.PayoutRecord, report *PayoutReport) {
+	l := logger.GetLoggerFromCtx(ctx)
+
+	resp, err := s.opts.Payouts.CreatePayout(ctx, "payout-"+p.PayoutID, provider.PayoutRequest{
+		RecipientID: p.DriverID,
+		Amount:      p.Amount,
+		Currency:    p.Currency,
+		Description: fmt.Sprintf("Выплата за поездки до %s", p.PeriodEnd.Format(time.DateOnly)),
+		Metadata:    map[string]string{"payout_id": p.PayoutID, "driver_id": p.DriverID},
+	})
+
+	newStatus, providerID, errMsg := "failed", "", ""
+	switch {
+	case err != nil:
+		errMsg = fmt.Sprintf("%s payout error: %v", s.opts.Payouts.Name(), err)
+	case resp.Status == provider.StatusCanceled:
+		providerID, errMsg = resp.ID, "payout canceled by "+s.opts.Payouts.Name()
+	case resp.Status == provider.StatusSucceeded:
+		newStatus, providerID = "succeeded", resp.ID
+		// Без проводки выплата остаётся pending и повторится: провайдер вернёт ту же выплату
+		if err := s.post(ctx, ledger.Payout(p.DriverID, p.PayoutID, p.Amount, p.Currency)); err != nil {
+			s.ledgerFailed(ctx, p.PayoutID, err)
+			report.Pending++
+			return
+		}
+	default:
+		newStatus, providerID = "pending", resp.ID
+	}
+
+	if err := s.repo.FinishPayout(ctx, p.PayoutID, newStatus, providerID, errMsg); err != nil {
+		l.Error(ctx, "failed to save payout result", zap.String("payout_id", p.PayoutID), zap.Error(err))
+		report.Pending++
+		return
+	}
+	switch newStatus {
+	case "succeeded":
+		report.Succeeded++
+		report.Amounts[p.Currency] = money.Round(report.Amounts[p.Currency]+p.Amount, p.Currency)
+	case "failed":
+		report.Failed++
+		l.Error(ctx, "driver payout failed", zap.String("payout_id", p.PayoutID),
+			zap.String("driver_id", p.DriverID), zap.String("error", errMsg))
+	default:
+		report.Pending++
+	}
+}
+
+func toPBPayout(p *repository.PayoutRecord) *pb.Payout {
+	return &pb.Payout{
+		PayoutId:  p.PayoutID,
+		DriverId:  p.DriverID,
+		Amount:    float32(p.Amount),
+		Currency:  p.Currency,
+		Status:    p.Status,
+		PeriodEnd: p.PeriodEnd.Format(time.RFC3339),
+		Error:     p.Error,
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+	}
+}
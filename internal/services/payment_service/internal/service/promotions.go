@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+var promoCodeRe = regexp.MustCompile(`^[A-Z0-9_-]{3,50}$`)
+
+// discount — скидка по промокоду на долю пассажира; нулевая — скидки нет
+type discount struct {
+	Code   string
+	Amount float64
+}
+
+// CreatePromoCode создаёт промокод, только для admin
+func (s *PaymentService) CreatePromoCode(ctx context.Context, req *pb.CreatePromoCodeRequest) (*pb.CreatePromoCodeResponse, error) {
+	caller, err := identity.RequireRole(ctx, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	p := &repository.PromoCodeRecord{
+		Code:           strings.ToUpper(strings.TrimSpace(req.Code)),
+		Type:           req.Type,
+		Value:          float64(req.Value),
+		MaxUses:        int(req.MaxUses),
+		MaxUsesPerUser: int(req.MaxUsesPerUser),
+		FirstRideOnly:  req.FirstRideOnly,
+		CreatedBy:      caller.UserID,
+	}
+	if !promoCodeRe.MatchString(p.Code) {
+		return nil, status.Error(codes.InvalidArgument, "code must be 3 to 50 letters, digits, '-' or '_'")
+	}
+	switch p.Type {
+	case repository.PromoPercent:
+		if p.Value <= 0 || p.Value > 100 {
+			return nil, status.Error(codes.InvalidArgument, "percent value must be in (0, 100]")
+		}
+	case repository.PromoFixed:
+		if p.Currency, err = s.currency(req.Currency); err != nil {
+			return nil, err
+		}
+		p.Value = money.Round(p.Value, p.Currency)
+		if p.Value <= 0 {
+			return nil, status.Error(codes.InvalidArgument, "fixed value must be greater than 0")
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "type must be percent or fixed")
+	}
+	if p.MaxUses < 0 || p.MaxUsesPerUser < 0 {
+		return nil, status.Error(codes.InvalidArgument, "usage limits must not be negative")
+	}
+	if p.MaxUsesPerUser == 0 {
+		p.MaxUsesPerUser = 1
+	}
+	if req.ValidFrom != "" {
+		if p.ValidFrom, err = time.Parse(time.RFC3339, req.ValidFrom); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "valid_from must be RFC3339")
+		}
+	}
+	if req.ValidUntil != "" {
+		if p.ValidUntil, err = time.Parse(time.RFC3339, req.ValidUntil); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "valid_until must be RFC3339")
+		}
+		if !p.ValidUntil.After(p.ValidFrom) {
+			return nil, status.Error(codes.InvalidArgument, "valid_until must be after valid_from")
+		}
+	}
+
+	if err := s.repo.CreatePromoCode(ctx, p); err != nil {
+		if errors.Is(err, repository.ErrPromoExists) {
+			return nil, status.Errorf(codes.AlreadyExists, "promo code %s already exists", p.Code)
+		}
+		return nil, status.Errorf(codes.Internal, "failed to save promo code: %v", err)
+	}
+	return &pb.CreatePromoCodeResponse{PromoCode: toPBPromoCode(p)}, nil
+}
+
+// ListPromoCodes — промокоды с числом применений, только для admin
+func (s *PaymentService) ListPromoCodes(ctx context.Context, _ *pb.ListPromoCodesRequest) (*pb.ListPromoCodesResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+	promos, err := s.repo.ListPromoCodes(ctx)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get promo codes: %v", err)
+	}
+	resp := &pb.ListPromoCodesResponse{}
+	for _, p := range promos {
+		resp.PromoCodes = append(resp.PromoCodes, toPBPromoCode(p))
+	}
+	return resp, nil
+}
+
+// ApplyPromoCode применяет промокод к участию пассажира в поездке, заменяя ранее применённый.
+// Скидка вычитается из доли при оплате поездки. Участие в комнате проверяет room_service.
+func (s *PaymentService) ApplyPromoCode(ctx context.Context, req *pb.ApplyPromoCodeRequest) (*pb.ApplyPromoCodeResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id and code are required")
+	}
+	currency, err := s.currency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.repo.GetPromoCode(ctx, strings.ToUpper(strings.TrimSpace(req.Code)))
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get promo code: %v", err)
+	}
+	now := time.Now()
+	switch {
+	case !p.Active:
+		return nil, status.Error(codes.FailedPrecondition, "promo code is not active")
+	case !p.ValidFrom.IsZero() && now.Before(p.ValidFrom):
+		return nil, status.Error(codes.FailedPrecondition, "promo code is not valid yet")
+	case !p.ValidUntil.IsZero() && !now.Before(p.ValidUntil):
+		return nil, status.Error(codes.FailedPrecondition, "promo code has expired")
+	case p.Type == repository.PromoFixed && p.Currency != currency:
+		return nil, status.Errorf(codes.FailedPrecondition, "promo code applies to rides in %s", p.Currency)
+	}
+	if p.FirstRideOnly {
+		paid, err := s.hasPaidRides(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if paid {
+			return nil, status.Error(codes.FailedPrecondition, "promo code is only valid for the first ride")
+		}
+	}
+
+	switch err := s.repo.ApplyPromoCode(ctx, p.Code, req.RoomId, userID); {
+	case errors.Is(err, repository.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	case errors.Is(err, repository.ErrPromoExhausted), errors.Is(err, repository.ErrPromoRedeemed):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to apply promo code: %v", err)
+	}
+	return &pb.ApplyPromoCodeResponse{RoomId: req.RoomId, PromoCode: toPBPromoCode(p)}, nil
+}
+
+// hasPaidRides — есть ли у пассажира оплаченные поездки
+func (s *PaymentService) hasPaidRides(ctx context.Context, userID string) (bool, error) {
+	totals, err := s.repo.PaymentTotals(ctx, repository.PaymentFilter{UserIDs: []string{userID}, Kind: repository.PaymentRide})
+	if err != nil {
+		return false, status.Errorf(codes.Internal, "failed to get payments: %v", err)
+	}
+	for _, t := range totals {
+		if t.Paid > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// discountFor считает скидку по коду, применённому пассажиром к поездке. Скидка не больше доли;
+// фиксированная скидка в другой валюте не применяется.
+func (s *PaymentService) discountFor(ctx context.Context, roomID, userID string, share float64, currency string) discount {
+	red, err := s.repo.GetPromoRedemption(ctx, roomID, userID)
+	if err != nil {
+		if !errors.Is(err, repository.ErrNotFound) {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to get promo code, charging without discount",
+				zap.String("room_id", roomID), zap.String("user_id", userID), zap.Error(err))
+		}
+		return discount{}
+	}
+	p := red.Promo
+	var amount float64
+	switch p.Type {
+	case repository.PromoPercent:
+		amount = share * p.Value / 100
+	case repository.PromoFixed:
+		if p.Currency != currency {
+			logger.GetLoggerFromCtx(ctx).Info(ctx, "promo code currency differs from ride currency",
+				zap.String("code", p.Code), zap.String("room_id", roomID), zap.String("currency", currency))
+			return discount{}
+		}
+		amount = p.Value
+	}
+	return discount{Code: p.Code, Amount: money.Round(min(amount, share), currency)}
+}
+
+// redeem отмечает код использованным в платеже. Ошибка только логируется: скидка уже сохранена в платеже.
+func (s *PaymentService) redeem(ctx context.Context, p *repository.PaymentRecord) {
+	if p.PromoCode == "" {
+		return
+	}
+	if err := s.repo.RedeemPromoCode(ctx, p.RoomID, p.UserID, p.PaymentID, p.PromoCode, p.Discount); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to redeem promo code",
+			zap.String("payment_id", p.PaymentID), zap.String("code", p.PromoCode), zap.Error(err))
+	}
+}
+
+func toPBPromoCode(p *repository.PromoCodeRecord) *pb.PromoCode {
+	promo := &pb.PromoCode{
+		Code:           p.Code,
+		Type:           p.Type,
+		Value:          float32(p.Value),
+		Currency:       p.Currency,
+		MaxUses:        int32(p.MaxUses),
+		MaxUsesPerUser: int32(p.MaxUsesPerUser),
+		FirstRideOnly:  p.FirstRideOnly,
+		Active:         p.Active,
+		Uses:           int32(p.Uses),
+		CreatedAt:      p.CreatedAt.Format(time.RFC3339),
+	}
+	if !p.ValidFrom.IsZero() {
+		promo.ValidFrom = p.ValidFrom.Format(time.RFC3339)
+	}
+	if !p.ValidUntil.IsZero() {
+		promo.ValidUntil = p.ValidUntil.Format(time.RFC3339)
+	}
+	return promo
+}
@@ -0,0 +1,250 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"html/template"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// ReceiptOptions — чеки по 54-ФЗ. Провайдер регистрирует чек в ОФД и отправляет его на контакт пассажира.
+type ReceiptOptions struct {
+	Enabled       bool
+	VatCode       int // ставка НДС по справочнику ЮKassa: 1 — без НДС
+	TaxSystemCode int // система налогообложения; 0 — не передавать
+	SellerName    string
+	SellerINN     string
+}
+
+// Позиция чека: доля пассажира в поездке. Адреса в чек не попадают.
+const (
+	receiptDescription    = "Услуга совместной поездки"
+	receiptPaymentSubject = "service"
+	receiptPaymentMode    = "full_payment"
+)
+
+var phoneRe = regexp.MustCompile(`^[0-9]{10,15}$`)
+
+// SetReceiptContact сохраняет email и/или телефон, на которые приходят чеки
+func (s *PaymentService) SetReceiptContact(ctx context.Context, req *pb.SetReceiptContactRequest) (*pb.SetReceiptContactResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	email := strings.TrimSpace(req.Email)
+	phone := strings.NewReplacer("+", "", " ", "", "-", "", "(", "", ")", "").Replace(req.Phone)
+	if email == "" && phone == "" {
+		return nil, status.Error(codes.InvalidArgument, "email or phone is required")
+	}
+	if email != "" {
+		if addr, err := mail.ParseAddress(email); err != nil || addr.Address != email {
+			return nil, status.Error(codes.InvalidArgument, "invalid email")
+		}
+	}
+	if phone != "" && !phoneRe.MatchString(phone) {
+		return nil, status.Error(codes.InvalidArgument, "phone must contain 10 to 15 digits with country code")
+	}
+
+	c := &repository.ReceiptContact{UserID: userID, Email: email, Phone: phone}
+	if err := s.repo.SetReceiptContact(ctx, c); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save receipt contact: %v", err)
+	}
+	return &pb.SetReceiptContactResponse{Email: c.Email, Phone: c.Phone}, nil
+}
+
+// GetReceipt — чеки платежа: приход и возвраты прихода, для владельца платежа и admin.
+// Показываются только чеки прошедших платежей и возвратов.
+func (s *PaymentService) GetReceipt(ctx context.Context, req *pb.GetReceiptRequest) (*pb.GetReceiptResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.PaymentId == "" {
+		return nil, status.Error(codes.InvalidArgument, "payment_id is required")
+	}
+	p, err := s.repo.GetPaymentByID(ctx, req.PaymentId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "payment %s not found", req.PaymentId)
+	}
+	if p.UserID != caller.UserID && !caller.HasRole(identity.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+	if !refundable(p.Status) && p.Status != "refunded" {
+		return nil, status.Error(codes.FailedPrecondition, "payment is not paid")
+	}
+
+	records, err := s.repo.ListReceipts(ctx, p.PaymentID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get receipts: %v", err)
+	}
+	resp := &pb.GetReceiptResponse{PaymentId: p.PaymentID}
+	for _, r := range records {
+		if r.Type == repository.ReceiptRefund && r.RefundStatus != provider.StatusSucceeded {
+			continue
+		}
+		resp.Receipts = append(resp.Receipts, toPBReceipt(r))
+	}
+	if len(resp.Receipts) == 0 {
+		return nil, status.Error(codes.NotFound, "no receipts for this payment")
+	}
+
+	var buf bytes.Buffer
+	if err := receiptTemplate.Execute(&buf, receiptPage{Seller: s.opts.Receipts.SellerName, INN: s.opts.Receipts.SellerINN, Receipts: resp.Receipts}); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to render receipt: %v", err)
+	}
+	resp.Html = buf.String()
+	return resp, nil
+}
+
+// receipt собирает чек на сумму доли пассажира. nil — чеки выключены или пассажир не оставил контакт:
+// платёж уходит без чека, а провайдер, которому чек обязателен, его отклонит.
+func (s *PaymentService) receipt(ctx context.Context, userID string, amount float64, currency string) *provider.Receipt {
+	if !s.opts.Receipts.Enabled {
+		return nil
+	}
+	c, err := s.repo.GetReceiptContact(ctx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			logger.GetLoggerFromCtx(ctx).Info(ctx, "no receipt contact, sending payment without receipt", zap.String("user_id", userID))
+		} else {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to get receipt contact", zap.String("user_id", userID), zap.Error(err))
+		}
+		return nil
+	}
+	return &provider.Receipt{
+		Email:         c.Email,
+		Phone:         c.Phone,
+		TaxSystemCode: s.opts.Receipts.TaxSystemCode,
+		Items: []provider.ReceiptItem{{
+			Description:    receiptDescription,
+			Quantity:       1,
+			Amount:         money.Round(amount, currency),
+			Currency:       currency,
+			VatCode:        s.opts.Receipts.VatCode,
+			PaymentSubject: receiptPaymentSubject,
+			PaymentMode:    receiptPaymentMode,
+		}},
+	}
+}
+
+// saveReceipt сохраняет чек, принятый провайдером вместе с платежом или возвратом. Ошибка только
+// логируется: деньги уже движутся, а чек в ОФД провайдер отправит и без локальной копии.
+func (s *PaymentService) saveReceipt(ctx context.Context, r *provider.Receipt, paymentID, refundID, userID string) {
+	if r == nil {
+		return
+	}
+	item := r.Items[0]
+	rec := &repository.ReceiptRecord{
+		ReceiptID:      uuid.New().String(),
+		PaymentID:      paymentID,
+		RefundID:       refundID,
+		Type:           repository.ReceiptPayment,
+		UserID:         userID,
+		Email:          r.Email,
+		Phone:          r.Phone,
+		Description:    item.Description,
+		Amount:         item.Amount,
+		Currency:       item.Currency,
+		VatCode:        item.VatCode,
+		TaxSystemCode:  r.TaxSystemCode,
+		PaymentSubject: item.PaymentSubject,
+		PaymentMode:    item.PaymentMode,
+	}
+	if refundID != "" {
+		rec.Type = repository.ReceiptRefund
+	}
+	if err := s.repo.CreateReceipt(ctx, rec); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to save receipt",
+			zap.String("payment_id", paymentID), zap.String("refund_id", refundID), zap.Error(err))
+	}
+}
+
+// rememberEmail сохраняет email из токена контактом для чеков, если пассажир не указал свой
+func (s *PaymentService) rememberEmail(ctx context.Context) {
+	caller, ok := identity.FromContext(ctx)
+	if !s.opts.Receipts.Enabled || !ok || caller.Email == "" {
+		return
+	}
+	if err := s.repo.AddReceiptEmail(ctx, caller.UserID, caller.Email); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to save receipt email", zap.String("user_id", caller.UserID), zap.Error(err))
+	}
+}
+
+func toPBReceipt(r *repository.ReceiptRecord) *pb.Receipt {
+	return &pb.Receipt{
+		ReceiptId:      r.ReceiptID,
+		PaymentId:      r.PaymentID,
+		RefundId:       r.RefundID,
+		Type:           r.Type,
+		Email:          r.Email,
+		Phone:          r.Phone,
+		Description:    r.Description,
+		Amount:         float32(r.Amount),
+		Currency:       r.Currency,
+		VatCode:        int32(r.VatCode),
+		TaxSystemCode:  int32(r.TaxSystemCode),
+		PaymentSubject: r.PaymentSubject,
+		PaymentMode:    r.PaymentMode,
+		CreatedAt:      r.CreatedAt.Format(time.RFC3339),
+	}
+}
+
+type receiptPage struct {
+	Seller   string
+	INN      string
+	Receipts []*pb.Receipt
+}
+
+// vatTitles — ставки НДС по справочнику ЮKassa
+var vatTitles = map[int32]string{1: "Без НДС", 2: "НДС 0%", 3: "НДС 10%", 4: "НДС 20%", 5: "НДС 10/110", 6: "НДС 20/120"}
+
+var receiptTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"vat": func(code int32) string { return vatTitles[code] },
+	"money": func(v float32, code string) string {
+		return strings.Replace(money.Format(float64(v), code), ".", ",", 1)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ru">
+<head>
+<meta charset="utf-8">
+<title>Кассовый чек</title>
+<style>
+body { font-family: monospace; max-width: 360px; margin: 24px auto; }
+.receipt { border: 1px dashed #888; padding: 16px; margin-bottom: 24px; page-break-inside: avoid; }
+.row { display: flex; justify-content: space-between; }
+h1 { font-size: 16px; text-align: center; margin: 0 0 12px; }
+</style>
+</head>
+<body>
+{{range .Receipts}}<div class="receipt">
+<h1>Кассовый чек. {{if eq .Type "refund"}}Возврат прихода{{else}}Приход{{end}}</h1>
+{{with $.Seller}}<div>{{.}}</div>{{end}}{{with $.INN}}<div>ИНН {{.}}</div>{{end}}
+<div>{{.CreatedAt}}</div>
+<hr>
+<div class="row"><span>{{.Description}}</span><span>1 × {{money .Amount .Currency}}</span></div>
+<div class="row"><span>{{vat .VatCode}}</span></div>
+<div class="row"><span>Признак расчёта</span><span>{{if eq .PaymentMode "full_payment"}}Полный расчёт{{else}}{{.PaymentMode}}{{end}}</span></div>
+<hr>
+<div class="row"><strong>ИТОГО</strong><strong>{{money .Amount .Currency}} {{.Currency}}</strong></div>
+{{with .Email}}<div>Чек отправлен на {{.}}</div>{{else}}{{with .Phone}}<div>Чек отправлен на +{{.}}</div>{{end}}{{end}}
+<div>Платёж {{.PaymentId}}{{with .RefundId}}, возврат {{.}}{{end}}</div>
+</div>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/provider"
+)
+
+// ReconcileOptions — параметры сверки зависших платежей
+type ReconcileOptions struct {
+	Interval  time.Duration // период запуска
+	Threshold time.Duration // сколько платёж может оставаться без изменений
+	BatchSize int           // сколько платежей проверяется за запуск
+}
+
+// ReconcileMismatch — расхождение статуса в БД со статусом у провайдера
+type ReconcileMismatch struct {
+	PaymentID        string
+	YookassaID       string
+	DBStatus         string
+	ProviderStatus   string
+	Applied          bool // false — статус успел измениться (например, уведомлением)
+	ApplyError       string
+	StaleForDuration time.Duration
+}
+
+// ReconcileReport — итог одного запуска сверки
+type ReconcileReport struct {
+	StartedAt   time.Time
+	Checked     int
+	FetchErrors int
+	Mismatches  []ReconcileMismatch
+}
+
+// reconcileStatuses — статусы, из которых платёж должен выйти сам по уведомлению провайдера
+var reconcileStatuses = []string{provider.StatusPending, provider.StatusWaitingForCapture}
+
+// RunReconciler запускает сверку каждые opts.Interval до отмены ctx. ctx должен содержать логгер.
+func (s *PaymentService) RunReconciler(ctx context.Context, opts ReconcileOptions) {
+	l := logger.GetLoggerFromCtx(ctx)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Reconcile(ctx, opts)
+			if err != nil {
+				l.Error(ctx, "payment reconciliation failed", zap.Error(err))
+				continue
+			}
+			s.logReport(ctx, report)
+		}
+	}
+}
+
+// Reconcile перезапрашивает у провайдера платежи, зависшие дольше opts.Threshold, и применяет
+// статус провайдера. Статус меняется, только если в БД он остался тем же, что был прочитан:
+// уведомление, пришедшее во время сверки, не перезаписывается.
+func (s *PaymentService) Reconcile(ctx context.Context, opts ReconcileOptions) (*ReconcileReport, error) {
+	report := &ReconcileReport{StartedAt: time.Now()}
+	payments, err := s.repo.ListStalePayments(ctx, report.StartedAt.Add(-opts.Threshold), opts.BatchSize, reconcileStatuses...)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range payments {
+		report.Checked++
+		yk, err := s.provider.GetPayment(ctx, p.YookassaPaymentID)
+		if err != nil {
+			report.FetchErrors++
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "reconcile: failed to fetch payment",
+				zap.String("payment_id", p.PaymentID), zap.String("yookassa_id", p.YookassaPaymentID), zap.Error(err))
+			continue
+		}
+		if yk.Status == p.Status {
+			continue
+		}
+
+		m := ReconcileMismatch{
+			PaymentID:        p.PaymentID,
+			YookassaID:       p.YookassaPaymentID,
+			DBStatus:         p.Status,
+			ProviderStatus:   yk.Status,
+			StaleForDuration: report.StartedAt.Sub(p.UpdatedAt),
+		}
+		if yk.Status == provider.StatusSucceeded {
+			if err := s.recordCharge(ctx, p, yk.Amount); err != nil {
+				m.ApplyError = err.Error()
+				report.Mismatches = append(report.Mismatches, m)
+				continue
+			}
+		}
+		changed, err := s.repo.TransitionPaymentStatus(ctx, p.PaymentID, yk.Status, p.Status)
+		switch {
+		case err != nil:
+			m.ApplyError = err.Error()
+		case changed:
+			m.Applied = true
+			p.Status = yk.Status
+			s.publish(p, "payment."+yk.Status, "", p.Amount)
+		}
+		report.Mismatches = append(report.Mismatches, m)
+	}
+	return report, nil
+}
+
+// logReport пишет отчёт сверки: сводку и по строке на каждое расхождение
+func (s *PaymentService) logReport(ctx context.Context, r *ReconcileReport) {
+	l := logger.GetLoggerFromCtx(ctx)
+	for _, m := range r.Mismatches {
+		l.Error(ctx, "reconcile: payment status mismatch",
+			zap.String("payment_id", m.PaymentID),
+			zap.String("yookassa_id", m.YookassaID),
+			zap.String("db_status", m.DBStatus),
+			zap.String("provider_status", m.ProviderStatus),
+			zap.Bool("applied", m.Applied),
+			zap.String("apply_error", m.ApplyError),
+			zap.Duration("stale_for", m.StaleForDuration),
+		)
+	}
+	l.Info(ctx, "payment reconciliation report",
+		zap.Time("started_at", r.StartedAt),
+		zap.Duration("took", time.Since(r.StartedAt)),
+		zap.Int("checked", r.Checked),
+		zap.Int("mismatches", len(r.Mismatches)),
+		zap.Int("fetch_errors", r.FetchErrors),
+	)
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// EventPaymentReminder — напоминание пассажиру подтвердить платёж по ссылке
+const EventPaymentReminder = "payment.reminder"
+
+// Состояние доли пассажира в комнате
+const (
+	shareAuthorized = "authorized"
+	sharePaid       = "paid"
+	sharePending    = "pending"
+	shareFailed     = "failed"
+)
+
+// ReminderOptions — параметры напоминаний о неоплаченных долях
+type ReminderOptions struct {
+	Interval  time.Duration // период запуска
+	After     time.Duration // сколько ждать подтверждения перед первым и между напоминаниями
+	Max       int           // сколько напоминаний отправить по одному платежу
+	BatchSize int           // сколько платежей обрабатывается за запуск
+}
+
+// GetRoomPayments — кто из пассажиров комнаты оплатил свою долю. Водитель поездки и admin видят
+// всех пассажиров, пассажир — только свою долю со ссылкой на оплату.
+func (s *PaymentService) GetRoomPayments(ctx context.Context, req *pb.GetRoomPaymentsRequest) (*pb.GetRoomPaymentsResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	payments, err := s.repo.GetPaymentsByRoom(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payments: %v", err)
+	}
+
+	isDriver := false
+	for _, p := range payments {
+		isDriver = isDriver || (p.DriverID != "" && p.DriverID == caller.UserID)
+	}
+	if !caller.HasRole(identity.RoleAdmin) && !isDriver {
+		var own []*repository.PaymentRecord
+		for _, p := range payments {
+			if p.UserID == caller.UserID {
+				own = append(own, p)
+			}
+		}
+		payments = own
+	}
+	if len(payments) == 0 {
+		return nil, status.Error(codes.NotFound, "no payments for this room")
+	}
+
+	resp := &pb.GetRoomPaymentsResponse{RoomId: req.RoomId, Riders: riderShares(payments)}
+	for _, r := range resp.Riders {
+		if r.Status == sharePaid {
+			resp.Paid++
+		} else {
+			resp.Unpaid++
+		}
+	}
+	return resp, nil
+}
+
+// riderShares сводит платежи комнаты (новые первыми) в доли пассажиров: ожидающее подтверждения
+// списание важнее оплаченного, оплаченное — подтверждённого холда, иначе доля не оплачена.
+// Штрафы за отмену и неявку в доли не входят.
+func riderShares(payments []*repository.PaymentRecord) []*pb.RiderPayment {
+	var result []*pb.RiderPayment
+	byUser := map[string]*pb.RiderPayment{}
+	for _, p := range payments {
+		if isFee(p) {
+			continue
+		}
+		r, ok := byUser[p.UserID]
+		if !ok {
+			r = &pb.RiderPayment{UserId: p.UserID, Status: shareFailed, PaymentId: p.PaymentID, Currency: p.Currency}
+			byUser[p.UserID] = r
+			result = append(result, r)
+		}
+		hold := p.AuthorizedAmount > 0
+		switch {
+		case p.Status == provider.StatusPending && !hold:
+			if r.Status != sharePending {
+				r.Status, r.ConfirmationUrl, r.Reminders = sharePending, p.ConfirmationURL, int32(p.Reminders)
+			}
+		case refundable(p.Status) || p.Status == "refunded":
+			r.PaidAmount = float32(money.Round(float64(r.PaidAmount)+p.Amount, p.Currency))
+			if r.Status != sharePending {
+				r.Status = sharePaid
+			}
+		case p.Status == provider.StatusWaitingForCapture || p.Status == provider.StatusPending:
+			if r.Status == shareFailed {
+				r.Status, r.ConfirmationUrl = shareAuthorized, p.ConfirmationURL
+			}
+		}
+	}
+	return result
+}
+
+// RunReminders рассылает напоминания каждые opts.Interval до отмены ctx. ctx должен содержать логгер.
+func (s *PaymentService) RunReminders(ctx context.Context, opts ReminderOptions) {
+	l := logger.GetLoggerFromCtx(ctx)
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sent, err := s.SendReminders(ctx, opts)
+			if err != nil {
+				l.Error(ctx, "payment reminders failed", zap.Error(err))
+				continue
+			}
+			if sent > 0 {
+				l.Info(ctx, "payment reminders sent", zap.Int("sent", sent))
+			}
+		}
+	}
+}
+
+// SendReminders публикует в стрим обновлений пассажира напоминание со ссылкой на оплату по каждому
+// списанию, которое он не подтвердил дольше opts.After. Возвращает число отправленных напоминаний.
+func (s *PaymentService) SendReminders(ctx context.Context, opts ReminderOptions) (int, error) {
+	payments, err := s.repo.ListUnconfirmedPayments(ctx, time.Now().Add(-opts.After), opts.Max, opts.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for _, p := range payments {
+		if err := s.repo.MarkReminded(ctx, p.PaymentID); err != nil {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to mark payment reminded",
+				zap.String("payment_id", p.PaymentID), zap.Error(err))
+			continue
+		}
+		s.updates.Publish(&pb.PaymentUpdate{
+			PaymentId:       p.PaymentID,
+			RoomId:          p.RoomID,
+			UserId:          p.UserID,
+			Event:           EventPaymentReminder,
+			Status:          p.Status,
+			Amount:          float32(p.Amount),
+			Currency:        p.Currency,
+			UpdatedAt:       time.Now().Format(time.RFC3339),
+			ConfirmationUrl: p.ConfirmationURL,
+		})
+		sent++
+	}
+	return sent, nil
+}
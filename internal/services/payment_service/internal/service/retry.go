@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// Итог списания по пассажиру и по запросу в целом
+const (
+	resultSucceeded = "succeeded"
+	resultPending   = "pending"
+	resultFailed    = "failed"
+	resultPartial   = "partial" // часть пассажиров оплатила, часть — нет
+)
+
+// RetryFailedPayments повторяет неудавшиеся платежи комнаты новым платежом на ту же сумму,
+// для создателя комнаты и admin.
+// Ключ повтора выводится из неудавшегося платежа, поэтому повторный вызов возвращает ту же попытку.
+func (s *PaymentService) RetryFailedPayments(ctx context.Context, req *pb.RetryFailedPaymentsRequest) (*pb.RetryFailedPaymentsResponse, error) {
+	caller, err := identity.RequireRole(ctx, identity.RoleDriver, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+
+	billing, err := s.roomBilling(ctx, req.RoomId)
+	if err != nil {
+		return nil, err
+	}
+	if caller.UserID != billing.GetRoom().GetCreatorId() && !caller.HasRole(identity.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "cannot retry payments of another driver's ride")
+	}
+
+	failed, err := s.failedCharges(ctx, req.RoomId, req.UserIds)
+	if err != nil {
+		return nil, err
+	}
+
+	payments := make([]*pb.Payment, len(failed))
+	results := make([]*pb.PaymentResult, len(failed))
+	s.fanOut(ctx, len(failed), func(i int) {
+		p := failed[i]
+		payment, err := s.charge(ctx, retryKey(p), p.RoomID, p.UserID, p.DriverID, p.Kind, float32(p.Amount), p.Currency, p.Description,
+			discount{Code: p.PromoCode, Amount: p.Discount})
+		payments[i], results[i] = payment, chargeResult(p.UserID, payment, err)
+	})
+
+	overall := overallStatus(results)
+	return &pb.RetryFailedPaymentsResponse{
+		Payments: savedPayments(payments),
+		Success:  overall == resultSucceeded || overall == resultPending,
+		Status:   overall,
+		Results:  results,
+	}, nil
+}
+
+// failedCharges — неудавшиеся списания комнаты, которые ещё не повторялись. Холды не повторяются:
+// доля по холду, который не удалось списать, уходит в обычный платёж (см. captureShare), повторяется он.
+func (s *PaymentService) failedCharges(ctx context.Context, roomID string, userIDs []string) ([]*repository.PaymentRecord, error) {
+	payments, err := s.repo.GetPaymentsByRoom(ctx, roomID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payments: %v", err)
+	}
+	var users map[string]bool
+	if len(userIDs) > 0 {
+		users = make(map[string]bool, len(userIDs))
+		for _, id := range userIDs {
+			users[id] = true
+		}
+	}
+	keys := make(map[string]bool, len(payments))
+	for _, p := range payments {
+		keys[p.IdempotencyKey] = true
+	}
+
+	var result []*repository.PaymentRecord
+	for _, p := range payments {
+		if p.AuthorizedAmount > 0 || (p.Status != "failed" && p.Status != provider.StatusCanceled) {
+			continue
+		}
+		if users != nil && !users[p.UserID] {
+			continue
+		}
+		if keys[retryKey(p)+"-"+p.UserID] {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// retryKey — ключ запроса для повтора неудавшегося платежа
+func retryKey(p *repository.PaymentRecord) string {
+	return "retry-" + p.PaymentID
+}
+
+// chargeResult — итог списания по пассажиру: ошибка или отказ провайдера — failed
+func chargeResult(userID string, payment *pb.Payment, err error) *pb.PaymentResult {
+	result := &pb.PaymentResult{UserId: userID, Payment: payment}
+	switch {
+	case err != nil:
+		result.Status, result.Error = resultFailed, status.Convert(err).Message()
+	case payment.Status == provider.StatusSucceeded:
+		result.Status = resultSucceeded
+	case payment.Status == provider.StatusCanceled || payment.Status == "failed":
+		result.Status, result.Error = resultFailed, "payment declined"
+	default:
+		result.Status = resultPending
+	}
+	return result
+}
+
+// shareResult — итог по пассажиру, доля которого списана несколькими платежами (холд и доплата):
+// ошибка или отказ по любому из них — failed, иначе pending, если какой-то ещё не завершён
+func shareResult(userID string, payments []*pb.Payment, err error) *pb.PaymentResult {
+	if err != nil || len(payments) == 0 {
+		var last *pb.Payment
+		if len(payments) > 0 {
+			last = payments[len(payments)-1]
+		}
+		if err == nil {
+			err = status.Error(codes.Internal, "no payments for share")
+		}
+		return chargeResult(userID, last, err)
+	}
+	var result *pb.PaymentResult
+	for _, p := range payments {
+		r := chargeResult(userID, p, nil)
+		if result == nil || r.Status == resultFailed || (r.Status == resultPending && result.Status == resultSucceeded) {
+			result = r
+		}
+	}
+	return result
+}
+
+// overallStatus — итог по всем пассажирам: succeeded, pending (ни одного отказа), partial или failed
+func overallStatus(results []*pb.PaymentResult) string {
+	var failed, pending int
+	for _, r := range results {
+		switch r.Status {
+		case resultFailed:
+			failed++
+		case resultPending:
+			pending++
+		}
+	}
+	switch {
+	case failed > 0 && failed == len(results):
+		return resultFailed
+	case failed > 0:
+		return resultPartial
+	case pending > 0:
+		return resultPending
+	default:
+		return resultSucceeded
+	}
+}
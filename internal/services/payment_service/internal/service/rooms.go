@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/payment_service/internal/money"
+	roompb "we_ride/internal/services/room_service/pb"
+)
+
+// rideCharge сверяет списание доли с room_service: вызывающий — создатель комнаты или admin,
+// поездка завершена, пассажиры — текущие участники, кроме самого водителя, доля — cost_per_member
+// комнаты в её валюте.
+// Возвращает водителя, которому начисляется заработок, — создателя комнаты.
+func (s *PaymentService) rideCharge(ctx context.Context, caller identity.Identity, roomID string, userIDs []string,
+	amountPerUser float32, currency, claimedDriver string) (string, error) {
+	billing, err := s.roomBilling(ctx, roomID)
+	if err != nil {
+		return "", err
+	}
+	room := billing.GetRoom()
+	driverID := room.GetCreatorId()
+	if caller.UserID != driverID && !caller.HasRole(identity.RoleAdmin) {
+		return "", status.Error(codes.PermissionDenied, "only the room creator can charge for the ride")
+	}
+	if claimedDriver != "" && claimedDriver != driverID {
+		return "", status.Error(codes.InvalidArgument, "driver_id must be the room creator")
+	}
+	if room.GetStatus() != roompb.RoomStatus_ROOM_STATUS_COMPLETED {
+		return "", status.Error(codes.FailedPrecondition, "ride is not completed")
+	}
+	for _, userID := range userIDs {
+		if userID == driverID {
+			return "", status.Error(codes.InvalidArgument, "the driver does not pay for their own ride")
+		}
+		if !slices.Contains(billing.GetMembers(), userID) {
+			return "", status.Errorf(codes.InvalidArgument, "user %s is not a member of room", userID)
+		}
+	}
+	roomCurrency := room.GetCurrency()
+	if roomCurrency == "" {
+		roomCurrency = s.opts.Currency
+	}
+	if roomCurrency != currency {
+		return "", status.Errorf(codes.InvalidArgument, "currency must be the room currency %s", roomCurrency)
+	}
+	if money.ToMinor(float64(amountPerUser), currency) != money.ToMinor(float64(room.GetCostPerMember()), currency) {
+		return "", status.Errorf(codes.InvalidArgument, "amount_per_user must be the room cost per member %s",
+			money.Format(float64(room.GetCostPerMember()), currency))
+	}
+	return driverID, nil
+}
+
+// roomBilling запрашивает комнату и её участников от имени вызывающего
+func (s *PaymentService) roomBilling(ctx context.Context, roomID string) (*roompb.GetRoomBillingResponse, error) {
+	client, closeConn, err := s.rooms()
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to check room: %v", err)
+	}
+	defer closeConn()
+
+	resp, err := client.GetRoomBilling(ctx, &roompb.GetRoomBillingRequest{RoomId: roomID})
+	if err != nil {
+		switch status.Code(err) {
+		case codes.NotFound, codes.PermissionDenied:
+			return nil, err
+		default:
+			return nil, status.Errorf(codes.Unavailable, "failed to check room: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// rooms открывает соединение с room_service от имени вызывающего; closeConn закрывает его
+func (s *PaymentService) rooms() (client roompb.RoomServiceClient, closeConn func(), err error) {
+	if s.roomClient != nil {
+		return s.roomClient, func() {}, nil
+	}
+	if s.opts.RoomServiceAddr == "" {
+		return nil, nil, errors.New("room service address is not configured")
+	}
+
+	conn, err := grpc.NewClient(s.opts.RoomServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to room service: %w", err)
+	}
+	return roompb.NewRoomServiceClient(conn), func() { _ = conn.Close() }, nil
+}
@@ -2,37 +2,116 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/payment_service/internal/broker"
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
 	"we_ride/internal/services/payment_service/internal/repository"
-	"we_ride/internal/services/payment_service/internal/yookassa"
 	pb "we_ride/internal/services/payment_service/pb"
+	roompb "we_ride/internal/services/room_service/pb"
+	authpb "we_ride/internal/services/user_service/protoc/gen/go"
 )
 
-const currency = "RUB"
+// defaultCurrency — валюта поездок, если она не настроена
+const defaultCurrency = "RUB"
 
-type yookassaClient interface {
-	CreatePayment(ctx context.Context, idempotencyKey string, req yookassa.CreatePaymentRequest) (*yookassa.PaymentResponse, error)
-	CreateRefund(ctx context.Context, idempotencyKey string, req yookassa.CreateRefundRequest) (*yookassa.RefundResponse, error)
+// returnURL — куда провайдер возвращает пользователя после подтверждения платежа
+const returnURL = "https://weride.app/payment/success"
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// Options — настройки сервиса
+type Options struct {
+	// CommissionRate — доля платформы в оплате поездки, от 0 до 1
+	CommissionRate float64
+	// Payouts — провайдер выплат водителям; nil — выплаты не проводятся
+	Payouts provider.PayoutProvider
+	// Concurrency — сколько пассажиров одного запроса обрабатывается параллельно; 0 — по одному
+	Concurrency int
+	// Receipts — чеки по 54-ФЗ к платежам и возвратам
+	Receipts ReceiptOptions
+	// Currency — валюта поездок, для которых валюта не указана; Currencies — какие валюты принимаются
+	Currency   string
+	Currencies []string
+	// ReportCurrency — валюта сводных отчётов; суммы в других валютах пересчитываются по курсам Rates
+	ReportCurrency string
+	Rates          money.Rates
+	// StatementsDir — каталог, в котором хранятся файлы выписок
+	StatementsDir string
+	// UserServiceAddr — user_service, из которого берутся маршруты поездок для выписок
+	UserServiceAddr string
+	// RoomServiceAddr — room_service, по которому проверяются создатель комнаты, участники и доля
+	RoomServiceAddr string
 }
 
 type PaymentService struct {
 	pb.UnimplementedPaymentServiceServer
 	repo     repository.Repository
-	yookassa yookassaClient
+	provider provider.PaymentProvider
+	opts     Options
+	updates  *broker.Broker
+
+	userClient authpb.AuthClient        // подменяется в тестах
+	roomClient roompb.RoomServiceClient // подменяется в тестах
+}
+
+func New(repo repository.Repository, p provider.PaymentProvider, opts Options) *PaymentService {
+	if opts.Currency == "" {
+		opts.Currency = defaultCurrency
+	}
+	if !slices.Contains(opts.Currencies, opts.Currency) {
+		opts.Currencies = append(opts.Currencies, opts.Currency)
+	}
+	if opts.ReportCurrency == "" {
+		opts.ReportCurrency = opts.Currency
+	}
+	return &PaymentService{repo: repo, provider: p, opts: opts, updates: broker.New()}
 }
 
-func New(repo repository.Repository, ykClient yookassaClient) *PaymentService {
-	return &PaymentService{repo: repo, yookassa: ykClient}
+// currency проверяет валюту из запроса; пустая — валюта по умолчанию
+func (s *PaymentService) currency(code string) (string, error) {
+	if code == "" {
+		return s.opts.Currency, nil
+	}
+	code = strings.ToUpper(code)
+	if err := money.Validate(code); err != nil {
+		return "", status.Errorf(codes.InvalidArgument, "currency must be an ISO 4217 code: %v", err)
+	}
+	if !slices.Contains(s.opts.Currencies, code) {
+		return "", status.Errorf(codes.InvalidArgument, "currency %s is not accepted", code)
+	}
+	return code, nil
 }
 
-// ProcessPayment — создаёт платёж в ЮKassa для каждого пассажира комнаты
+// ProcessPayment — создаёт платёж у провайдера для каждого пассажира комнаты.
+// Вызывается room_service от имени водителя при завершении поездки или поддержкой.
+// Пассажиры, доля и водитель сверяются с комнатой в room_service (см. rideCharge).
+// Оплаченные доли начисляются водителю поездки за вычетом комиссии. Скидка по промокоду, применённому
+// пассажиром к поездке, вычитается из его доли, а водитель получает заработок с полной доли.
+// Ошибка по одному пассажиру не прерывает остальных — итог по каждому в results.
+// Запрос идемпотентен: повтор с тем же ключом (по умолчанию room_id) возвращает сохранённые платежи,
+// тот же ключ с другой суммой — AlreadyExists.
 func (s *PaymentService) ProcessPayment(ctx context.Context, req *pb.ProcessPaymentRequest) (*pb.ProcessPaymentResponse, error) {
+	caller, err := identity.RequireRole(ctx, identity.RoleDriver, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
 	if req.RoomId == "" {
 		return nil, status.Error(codes.InvalidArgument, "room_id is required")
 	}
@@ -42,187 +121,424 @@ func (s *PaymentService) ProcessPayment(ctx context.Context, req *pb.ProcessPaym
 	if req.AmountPerUser <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "amount_per_user must be greater than 0")
 	}
+	cur, err := s.currency(req.Currency)
+	if err != nil {
+		return nil, err
+	}
+	driverID, err := s.rideCharge(ctx, caller, req.RoomId, req.UserIds, req.AmountPerUser, cur, req.DriverId)
+	if err != nil {
+		return nil, err
+	}
 
-	var results []*pb.Payment
-	amountStr := fmt.Sprintf("%.2f", req.AmountPerUser)
+	requestKey := req.IdempotencyKey
+	if requestKey == "" {
+		requestKey = req.RoomId
+	}
 
-	for _, userID := range req.UserIds {
-		paymentID := uuid.New().String()
-		idempotencyKey := fmt.Sprintf("%s-%s", req.RoomId, userID)
+	payments := make([]*pb.Payment, len(req.UserIds))
+	results := make([]*pb.PaymentResult, len(req.UserIds))
+	s.fanOut(ctx, len(req.UserIds), func(i int) {
+		userID := req.UserIds[i]
+		share := money.Round(float64(req.AmountPerUser), cur)
+		d := s.discountFor(ctx, req.RoomId, userID, share, cur)
+		payment, err := s.charge(ctx, requestKey, req.RoomId, userID, driverID, repository.PaymentRide, float32(share-d.Amount), cur, req.Description, d)
+		payments[i], results[i] = payment, chargeResult(userID, payment, err)
+	})
+
+	overall := overallStatus(results)
+	return &pb.ProcessPaymentResponse{
+		Payments: savedPayments(payments),
+		Success:  overall == resultSucceeded || overall == resultPending,
+		Status:   overall,
+		Results:  results,
+	}, nil
+}
 
-		description := req.Description
-		if description == "" {
-			description = fmt.Sprintf("Оплата поездки (комната %s)", req.RoomId)
+// charge создаёт платёж с немедленным списанием, способом оплаты по умолчанию, если он привязан.
+// При ошибке провайдера возвращает и сохранённый платёж в статусе failed.
+// Платёж сохраняется под ключом requestKey-userID до обращения к провайдеру: повтор с тем же ключом
+// возвращает сохранённый платёж, а если прошлая попытка не дошла до провайдера — повторяет её.
+// kind — назначение платежа (оплата доли или штраф).
+// amount — сумма к списанию за вычетом скидки d; если скидка покрыла всю долю, провайдер не вызывается.
+func (s *PaymentService) charge(ctx context.Context, requestKey, roomID, userID, driverID, kind string, amount float32, currency, description string, d discount) (*pb.Payment, error) {
+	if description == "" {
+		description = fmt.Sprintf("Оплата поездки (комната %s)", roomID)
+	}
+	record := &repository.PaymentRecord{
+		PaymentID:      uuid.New().String(),
+		RoomID:         roomID,
+		UserID:         userID,
+		Amount:         money.Round(float64(amount), currency),
+		Currency:       currency,
+		Status:         provider.StatusPending,
+		Description:    description,
+		DriverID:       driverID,
+		IdempotencyKey: fmt.Sprintf("%s-%s", requestKey, userID),
+		Discount:       d.Amount,
+		PromoCode:      d.Code,
+		Kind:           kind,
+		CreatedAt:      time.Now(),
+	}
+	if record.Amount == 0 {
+		record.Status = provider.StatusSucceeded
+	}
+	existing, created, err := s.repo.ReservePayment(ctx, record)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save payment: %v", err)
+	}
+	if !created {
+		if existing.RoomID != roomID || existing.Currency != currency ||
+			money.ToMinor(existing.Amount, currency) != money.ToMinor(record.Amount, currency) {
+			return nil, status.Errorf(codes.AlreadyExists,
+				"idempotency key %s was already used for room %s with amount %s %s",
+				record.IdempotencyKey, existing.RoomID, money.Format(existing.Amount, existing.Currency), existing.Currency)
 		}
-
-		// Создаём платёж в ЮKassa (или мок-режим, если клиент не настроен)
-		paymentStatus := "succeeded"
-		yookassaID := ""
-		var err error
-		if s.yookassa != nil {
-			ykResp, ykErr := s.yookassa.CreatePayment(ctx, idempotencyKey, yookassa.CreatePaymentRequest{
-				Amount: yookassa.Amount{
-					Value:    amountStr,
-					Currency: currency,
-				},
-				Confirmation: yookassa.Confirmation{
-					Type:      "redirect",
-					ReturnURL: "https://weride.app/payment/success",
-				},
-				Description: description,
-				Capture:     true,
-				Metadata: map[string]string{
-					"room_id":    req.RoomId,
-					"user_id":    userID,
-					"payment_id": paymentID,
-				},
-			})
-			err = ykErr
-			if ykErr != nil {
-				paymentStatus = "failed"
-			} else {
-				yookassaID = ykResp.ID
-				paymentStatus = ykResp.Status
-			}
-		} else {
-			yookassaID = "mock-" + paymentID
+		if existing.YookassaPaymentID != "" || existing.Status == provider.StatusSucceeded {
+			return toPBPayment(existing), nil
 		}
-
-		// Сохраняем в БД в любом случае (даже при ошибке — для аудита)
-		record := &repository.PaymentRecord{
-			PaymentID:         paymentID,
-			RoomID:            req.RoomId,
-			UserID:            userID,
-			Amount:            float64(req.AmountPerUser),
-			Currency:          currency,
-			Status:            paymentStatus,
-			YookassaPaymentID: yookassaID,
-			Description:       description,
+		record = existing
+	}
+	s.redeem(ctx, record)
+	if record.Amount == 0 {
+		// Скидка покрыла всю долю: списывать нечего, поездка оплачена платформой
+		if err := s.recordCharge(ctx, record, 0); err != nil {
+			s.ledgerFailed(ctx, record.PaymentID, err)
 		}
+		return toPBPayment(record), nil
+	}
+
+	// Сохранённым способом по умолчанию списываем без подтверждения пользователем
+	methodID, err := s.defaultMethodID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
 
-		if saveErr := s.repo.CreatePayment(ctx, record); saveErr != nil {
+	receipt := s.receipt(ctx, userID, record.Amount, currency)
+	resp, err := s.provider.CreatePayment(ctx, record.IdempotencyKey, provider.CreatePaymentRequest{
+		Amount:          record.Amount,
+		Currency:        currency,
+		Description:     description,
+		ReturnURL:       returnURL,
+		Capture:         true,
+		PaymentMethodID: methodID,
+		Receipt:         receipt,
+		Metadata: map[string]string{
+			"room_id":    roomID,
+			"user_id":    userID,
+			"payment_id": record.PaymentID,
+		},
+	})
+	if err != nil {
+		// Запись остаётся failed для аудита; повтор с тем же ключом отправит платёж заново
+		record.Status = "failed"
+		if saveErr := s.repo.UpdatePaymentStatus(ctx, record.PaymentID, record.Status, "", ""); saveErr != nil {
 			return nil, status.Errorf(codes.Internal, "failed to save payment: %v", saveErr)
 		}
+		return toPBPayment(record), status.Errorf(codes.Internal, "%s error for user %s: %v", s.provider.Name(), userID, err)
+	}
 
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "yookassa error for user %s: %v", userID, err)
+	record.Status, record.YookassaPaymentID, record.ConfirmationURL = resp.Status, resp.ID, resp.ConfirmationURL
+	if err := s.repo.UpdatePaymentStatus(ctx, record.PaymentID, record.Status, record.YookassaPaymentID, record.ConfirmationURL); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save payment: %v", err)
+	}
+	if record.Status != provider.StatusCanceled {
+		s.saveReceipt(ctx, receipt, record.PaymentID, "", userID)
+	}
+	if record.Status == provider.StatusSucceeded {
+		if err := s.recordCharge(ctx, record, record.Amount); err != nil {
+			s.ledgerFailed(ctx, record.PaymentID, err)
 		}
+	}
+	return toPBPayment(record), nil
+}
 
-		results = append(results, &pb.Payment{
-			PaymentId:         paymentID,
-			RoomId:            req.RoomId,
-			UserId:            userID,
-			Amount:            req.AmountPerUser,
-			Currency:          currency,
-			Status:            paymentStatus,
-			YookassaPaymentId: yookassaID,
-			CreatedAt:         time.Now().Format(time.RFC3339),
-			Description:       description,
-		})
+// fanOut вызывает fn(i) для каждого i из [0, n), не больше opts.Concurrency одновременно.
+// fn пишет результат по своему индексу, поэтому порядок ответа совпадает с порядком запроса.
+// После отмены ctx оставшиеся вызовы не ждут очереди: они сразу завершаются ошибкой отмены.
+func (s *PaymentService) fanOut(ctx context.Context, n int, fn func(i int)) {
+	sem := make(chan struct{}, max(s.opts.Concurrency, 1))
+	var wg sync.WaitGroup
+	for i := range n {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			fn(i)
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}()
 	}
+	wg.Wait()
+}
 
-	return &pb.ProcessPaymentResponse{
-		Payments: results,
-		Success:  true,
-	}, nil
+// savedPayments — платежи, которые удалось сохранить
+func savedPayments(payments []*pb.Payment) []*pb.Payment {
+	return slices.DeleteFunc(payments, func(p *pb.Payment) bool { return p == nil })
 }
 
-// RefundPayment — возвращает деньги всем пассажирам комнаты
+// RefundPayment — полный или частичный возврат по платежам комнаты, только для admin.
+// Каждый платёж обрабатывается отдельно: ошибка по одному не останавливает остальные.
 func (s *PaymentService) RefundPayment(ctx context.Context, req *pb.RefundPaymentRequest) (*pb.RefundPaymentResponse, error) {
-	if req.RoomId == "" {
-		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" && len(req.PaymentIds) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "room_id or payment_ids is required")
+	}
+	if req.Amount < 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must not be negative")
 	}
 
-	// Получаем все успешные платежи по комнате
-	payments, err := s.repo.GetPaymentsByRoom(ctx, req.RoomId)
+	payments, err := s.refundTargets(ctx, req)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get payments: %v", err)
+		return nil, err
 	}
 
-	var refunds []*pb.Payment
+	reason := req.Reason
+	if reason == "" {
+		reason = "Отмена поездки"
+	}
 
-	for _, p := range payments {
-		// Возвращаем только успешные платежи
-		if p.Status != "succeeded" {
+	items := make([]*pb.Refund, len(payments))
+	s.fanOut(ctx, len(payments), func(i int) {
+		items[i] = s.refundOne(ctx, payments[i], refundAmount(req, payments[i]), reason)
+	})
+
+	resp := &pb.RefundPaymentResponse{Success: true, Items: items}
+	for i, p := range payments {
+		item := items[i]
+		if item.Status == "failed" {
+			resp.Success = false
 			continue
 		}
+		payment := toPBPayment(p)
+		payment.Description = reason
+		resp.Refunds = append(resp.Refunds, payment)
+	}
+	return resp, nil
+}
 
-		idempotencyKey := fmt.Sprintf("refund-%s", p.PaymentID)
-		amountStr := fmt.Sprintf("%.2f", p.Amount)
-		reason := req.Reason
-		if reason == "" {
-			reason = "Отмена поездки"
+// refundTargets выбирает платежи для возврата. Явно указанные payment_ids возвращаются
+// в любом статусе (отказ попадёт в ответ), из комнаты берутся только успешные платежи.
+func (s *PaymentService) refundTargets(ctx context.Context, req *pb.RefundPaymentRequest) ([]*repository.PaymentRecord, error) {
+	var users map[string]bool
+	if len(req.UserIds) > 0 {
+		users = make(map[string]bool, len(req.UserIds))
+		for _, id := range req.UserIds {
+			users[id] = true
 		}
+	}
 
-		var ykErr error
-		if s.yookassa != nil {
-			_, ykErr = s.yookassa.CreateRefund(ctx, idempotencyKey, yookassa.CreateRefundRequest{
-				PaymentID: p.YookassaPaymentID,
-				Amount: yookassa.Amount{
-					Value:    amountStr,
-					Currency: p.Currency,
-				},
-				Description: reason,
-			})
+	var result []*repository.PaymentRecord
+	if len(req.PaymentIds) > 0 {
+		for _, id := range req.PaymentIds {
+			p, err := s.repo.GetPaymentByID(ctx, id)
+			if err != nil {
+				return nil, status.Errorf(codes.NotFound, "payment %s not found", id)
+			}
+			if req.RoomId != "" && p.RoomID != req.RoomId {
+				return nil, status.Errorf(codes.InvalidArgument, "payment %s belongs to another room", id)
+			}
+			if users == nil || users[p.UserID] {
+				result = append(result, p)
+			}
 		}
+		return result, nil
+	}
 
-		newStatus := "refunded"
-		if ykErr != nil {
-			newStatus = "refund_failed"
+	payments, err := s.repo.GetPaymentsByRoom(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get payments: %v", err)
+	}
+	for _, p := range payments {
+		if !refundable(p.Status) || (users != nil && !users[p.UserID]) {
+			continue
 		}
+		result = append(result, p)
+	}
+	return result, nil
+}
 
-		if updateErr := s.repo.UpdatePaymentStatus(ctx, p.PaymentID, newStatus, p.YookassaPaymentID); updateErr != nil {
-			return nil, status.Errorf(codes.Internal, "failed to update payment status: %v", updateErr)
+// refundOne резервирует возврат в БД, отправляет его провайдеру и сохраняет результат.
+// p.Status обновляется итоговым статусом платежа.
+func (s *PaymentService) refundOne(ctx context.Context, p *repository.PaymentRecord, amount float64, reason string) *pb.Refund {
+	item := &pb.Refund{
+		RefundId:  uuid.New().String(),
+		PaymentId: p.PaymentID,
+		UserId:    p.UserID,
+		Currency:  p.Currency,
+		Status:    "failed",
+	}
+	fail := func(msg string) *pb.Refund {
+		item.Error = msg
+		return item
+	}
+
+	if !refundable(p.Status) {
+		return fail(repository.ErrNotRefundable.Error())
+	}
+	refunded, err := s.repo.GetRefundedAmount(ctx, p.PaymentID)
+	if err != nil {
+		return fail("failed to get refunded amount")
+	}
+	remaining := money.Round(p.Amount-refunded, p.Currency)
+	if amount == 0 {
+		amount = remaining
+	}
+	item.Amount = float32(amount)
+	if amount <= 0 || money.ToMinor(amount, p.Currency) > money.ToMinor(remaining, p.Currency) {
+		return fail(repository.ErrRefundExceedsBalance.Error())
+	}
+
+	record := &repository.RefundRecord{
+		RefundID:  item.RefundId,
+		PaymentID: p.PaymentID,
+		Amount:    amount,
+		Status:    "pending",
+		Reason:    reason,
+	}
+	if err := s.repo.CreateRefund(ctx, record); err != nil {
+		if errors.Is(err, repository.ErrRefundExceedsBalance) || errors.Is(err, repository.ErrNotRefundable) {
+			return fail(err.Error())
 		}
+		return fail("failed to save refund")
+	}
+	item.CreatedAt = record.CreatedAt.Format(time.RFC3339)
+
+	// pending от провайдера остаётся в резерве до уведомления
+	refundStatus := "succeeded"
+	yookassaRefundID := ""
+	receipt := s.receipt(ctx, p.UserID, amount, p.Currency)
+	ref, provErr := s.provider.CreateRefund(ctx, "refund-"+record.RefundID, provider.RefundRequest{
+		PaymentID:   p.YookassaPaymentID,
+		Amount:      amount,
+		Currency:    p.Currency,
+		Description: reason,
+		Receipt:     receipt,
+	})
+	switch {
+	case provErr != nil:
+		refundStatus = "failed"
+	case ref.Status == provider.StatusCanceled:
+		refundStatus, yookassaRefundID = "failed", ref.ID
+	case ref.Status == provider.StatusPending:
+		refundStatus, yookassaRefundID = "pending", ref.ID
+	default:
+		yookassaRefundID = ref.ID
+	}
 
-		if ykErr != nil {
-			return nil, status.Errorf(codes.Internal, "yookassa refund error for payment %s: %v", p.PaymentID, ykErr)
+	paymentStatus, err := s.repo.FinishRefund(ctx, record.RefundID, refundStatus, yookassaRefundID)
+	if err != nil {
+		return fail("failed to save refund result")
+	}
+	p.Status = paymentStatus
+	item.Status = refundStatus
+	if refundStatus != "failed" {
+		s.saveReceipt(ctx, receipt, p.PaymentID, record.RefundID, p.UserID)
+	}
+	if refundStatus == "succeeded" {
+		if err := s.post(ctx, ledger.Refund(p.UserID, record.RefundID, amount, p.Currency)); err != nil {
+			s.ledgerFailed(ctx, record.RefundID, err)
 		}
+	}
+	item.YookassaRefundId = yookassaRefundID
+	switch {
+	case provErr != nil:
+		item.Error = fmt.Sprintf("%s refund error: %v", s.provider.Name(), provErr)
+	case refundStatus == "failed":
+		item.Error = "refund canceled by " + s.provider.Name()
+	}
+	return item
+}
 
-		refunds = append(refunds, &pb.Payment{
-			PaymentId:         p.PaymentID,
-			RoomId:            p.RoomID,
-			UserId:            p.UserID,
-			Amount:            float32(p.Amount),
-			Currency:          p.Currency,
-			Status:            newStatus,
-			YookassaPaymentId: p.YookassaPaymentID,
-			CreatedAt:         p.CreatedAt.Format(time.RFC3339),
-			Description:       reason,
-		})
-	}
-
-	return &pb.RefundPaymentResponse{
-		Refunds: refunds,
-		Success: true,
-	}, nil
+// refundAmount — сумма возврата по платежу из запроса в валюте платежа, 0 — весь остаток
+func refundAmount(req *pb.RefundPaymentRequest, p *repository.PaymentRecord) float64 {
+	if v, ok := req.Amounts[p.PaymentID]; ok {
+		return money.Round(float64(v), p.Currency)
+	}
+	return money.Round(float64(req.Amount), p.Currency)
+}
+
+func refundable(paymentStatus string) bool {
+	return paymentStatus == "succeeded" || paymentStatus == "partially_refunded"
 }
 
-// GetPaymentHistory — история транзакций пользователя
-func (s *PaymentService) GetPaymentHistory(ctx context.Context, req *pb.GetPaymentHistoryRequest) (*pb.GetPaymentHistoryResponse, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+// SearchPayments — поиск платежей для поддержки, только для admin
+func (s *PaymentService) SearchPayments(ctx context.Context, req *pb.SearchPaymentsRequest) (*pb.SearchPaymentsResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
 	}
 
-	records, err := s.repo.GetPaymentsByUser(ctx, req.UserId)
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to get payment history: %v", err)
+	f := repository.PaymentFilter{
+		PaymentID: req.PaymentId,
+		RoomID:    req.RoomId,
+		UserIDs:   req.UserIds,
+		Status:    req.Status,
+		Limit:     defaultSearchLimit,
+	}
+	if req.Limit > 0 {
+		f.Limit = min(int(req.Limit), maxSearchLimit)
+	}
+	var err error
+	if req.CreatedFrom != "" {
+		if f.CreatedFrom, err = time.Parse(time.RFC3339, req.CreatedFrom); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "created_from must be RFC3339")
+		}
+	}
+	if req.CreatedTo != "" {
+		if f.CreatedTo, err = time.Parse(time.RFC3339, req.CreatedTo); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "created_to must be RFC3339")
+		}
 	}
 
-	var payments []*pb.Payment
+	records, err := s.repo.SearchPayments(ctx, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search payments: %v", err)
+	}
+	resp := &pb.SearchPaymentsResponse{Payments: make([]*pb.Payment, 0, len(records))}
 	for _, p := range records {
-		payments = append(payments, &pb.Payment{
-			PaymentId:         p.PaymentID,
-			RoomId:            p.RoomID,
-			UserId:            p.UserID,
-			Amount:            float32(p.Amount),
-			Currency:          p.Currency,
-			Status:            p.Status,
-			YookassaPaymentId: p.YookassaPaymentID,
-			CreatedAt:         p.CreatedAt.Format(time.RFC3339),
-			Description:       p.Description,
-		})
-	}
-
-	return &pb.GetPaymentHistoryResponse{Payments: payments}, nil
+		resp.Payments = append(resp.Payments, toPBPayment(p))
+	}
+	return resp, nil
+}
+
+func toPBPayment(p *repository.PaymentRecord) *pb.Payment {
+	return &pb.Payment{
+		PaymentId:         p.PaymentID,
+		RoomId:            p.RoomID,
+		UserId:            p.UserID,
+		Amount:            float32(p.Amount),
+		Currency:          p.Currency,
+		Status:            p.Status,
+		YookassaPaymentId: p.YookassaPaymentID,
+		CreatedAt:         timestamppb.New(p.CreatedAt),
+		Description:       p.Description,
+		AuthorizedAmount:  float32(p.AuthorizedAmount),
+		ConfirmationUrl:   p.ConfirmationURL,
+		Discount:          float32(p.Discount),
+		PromoCode:         p.PromoCode,
+		Kind:              p.Kind,
+	}
+}
+
+// AnonymizeUserPayments — вызывается user_service при удалении аккаунта; выписки пользователя удаляются
+func (s *PaymentService) AnonymizeUserPayments(ctx context.Context, req *pb.AnonymizeUserPaymentsRequest) (*pb.AnonymizeUserPaymentsResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.repo.AnonymizeUserPayments(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to anonymize payments: %v", err)
+	}
+	if err := s.deleteStatements(ctx, userID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to delete statements: %v", err)
+	}
+	return &pb.AnonymizeUserPaymentsResponse{Anonymized: int32(n)}, nil
 }
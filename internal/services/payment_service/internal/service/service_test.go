@@ -3,26 +3,74 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/provider/fake"
 	"we_ride/internal/services/payment_service/internal/repository"
-	"we_ride/internal/services/payment_service/internal/yookassa"
 	pb "we_ride/internal/services/payment_service/pb"
+	roompb "we_ride/internal/services/room_service/pb"
+	authpb "we_ride/internal/services/user_service/protoc/gen/go"
 )
 
+// fakePaymentRepo — методы, которые вызываются при параллельной обработке пассажиров, берут mu
 type fakePaymentRepo struct {
-	created []*repository.PaymentRecord
-	byRoom  []*repository.PaymentRecord
-	byUser  []*repository.PaymentRecord
-	updated map[string]string
+	mu       sync.Mutex
+	created  []*repository.PaymentRecord
+	byRoom   []*repository.PaymentRecord
+	byUser   []*repository.PaymentRecord
+	updated  map[string]string
+	filter   repository.PaymentFilter
+	refunds  []*repository.RefundRecord
+	methods  []*repository.PaymentMethodRecord
+	ledger   []*ledger.Transaction
+	earnings []*repository.EarningRecord
+	payouts  []*repository.PayoutRecord
+	contacts map[string]*repository.ReceiptContact
+	receipts []*repository.ReceiptRecord
+	promos   map[string]*repository.PromoCodeRecord
+	redeemed []*repository.PromoRedemption
+
+	statements map[string]*repository.StatementRecord
 }
 
 func (f *fakePaymentRepo) CreatePayment(_ context.Context, p *repository.PaymentRecord) error {
 	f.created = append(f.created, p)
 	return nil
 }
-func (f *fakePaymentRepo) UpdatePaymentStatus(_ context.Context, paymentID, status, _ string) error {
+func (f *fakePaymentRepo) ReservePayment(_ context.Context, p *repository.PaymentRecord) (*repository.PaymentRecord, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, existing := range f.created {
+		if existing.IdempotencyKey == p.IdempotencyKey {
+			return existing, false, nil
+		}
+	}
+	f.created = append(f.created, p)
+	return p, true, nil
+}
+func (f *fakePaymentRepo) UpdatePaymentStatus(_ context.Context, paymentID, status, _, _ string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
 	if f.updated == nil {
 		f.updated = map[string]string{}
 	}
@@ -32,46 +80,708 @@ func (f *fakePaymentRepo) UpdatePaymentStatus(_ context.Context, paymentID, stat
 func (f *fakePaymentRepo) GetPaymentsByRoom(_ context.Context, _ string) ([]*repository.PaymentRecord, error) {
 	return f.byRoom, nil
 }
-func (f *fakePaymentRepo) GetPaymentsByUser(_ context.Context, _ string) ([]*repository.PaymentRecord, error) {
-	return f.byUser, nil
+func (f *fakePaymentRepo) GetPaymentByID(_ context.Context, paymentID string) (*repository.PaymentRecord, error) {
+	for _, p := range f.byRoom {
+		if p.PaymentID == paymentID {
+			return p, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+func (f *fakePaymentRepo) AnonymizeUserPayments(_ context.Context, userID string) (int64, error) {
+	var n int64
+	for _, p := range f.byUser {
+		if p.UserID == userID && p.Description != "" {
+			p.Description = ""
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakePaymentRepo) SearchPayments(_ context.Context, filter repository.PaymentFilter) ([]*repository.PaymentRecord, error) {
+	f.filter = filter
+	return f.byRoom, nil
+}
+func (f *fakePaymentRepo) PaymentTotals(_ context.Context, filter repository.PaymentFilter) ([]repository.PaymentTotal, error) {
+	byCurrency := map[string]*repository.PaymentTotal{}
+	var currencies []string
+	for _, p := range f.byRoom {
+		if (len(filter.UserIDs) > 0 && !slices.Contains(filter.UserIDs, p.UserID)) || (filter.Kind != "" && p.Kind != filter.Kind) {
+			continue
+		}
+		t, ok := byCurrency[p.Currency]
+		if !ok {
+			t = &repository.PaymentTotal{Currency: p.Currency}
+			byCurrency[p.Currency] = t
+			currencies = append(currencies, p.Currency)
+		}
+		t.Count++
+		if refundable(p.Status) || p.Status == "refunded" {
+			t.Paid++
+			t.Spent += p.Amount
+		}
+		for _, ref := range f.refunds {
+			if ref.PaymentID == p.PaymentID && ref.Status == "succeeded" {
+				t.Refunded += ref.Amount
+			}
+		}
+	}
+	slices.Sort(currencies)
+	totals := make([]repository.PaymentTotal, 0, len(currencies))
+	for _, c := range currencies {
+		totals = append(totals, *byCurrency[c])
+	}
+	return totals, nil
+}
+
+func (f *fakePaymentRepo) GetRefundedAmount(_ context.Context, paymentID string) (float64, error) {
+	var sum float64
+	for _, r := range f.refunds {
+		if r.PaymentID == paymentID && r.Status != "failed" {
+			sum += r.Amount
+		}
+	}
+	return sum, nil
+}
+func (f *fakePaymentRepo) CreateRefund(ctx context.Context, r *repository.RefundRecord) error {
+	p, err := f.GetPaymentByID(ctx, r.PaymentID)
+	if err != nil {
+		return err
+	}
+	refunded, _ := f.GetRefundedAmount(ctx, r.PaymentID)
+	if refunded+r.Amount > p.Amount {
+		return repository.ErrRefundExceedsBalance
+	}
+	f.refunds = append(f.refunds, r)
+	return nil
+}
+func (f *fakePaymentRepo) FinishRefund(_ context.Context, refundID, status, yookassaRefundID string) (string, error) {
+	var ref *repository.RefundRecord
+	for _, r := range f.refunds {
+		if r.RefundID == refundID {
+			ref = r
+		}
+	}
+	if ref == nil || ref.Status != "pending" {
+		return "", repository.ErrRefundFinished
+	}
+	ref.Status, ref.YookassaRefundID = status, yookassaRefundID
+	var succeeded float64
+	for _, r := range f.refunds {
+		if r.PaymentID == ref.PaymentID && r.Status == "succeeded" {
+			succeeded += r.Amount
+		}
+	}
+	p, _ := f.GetPaymentByID(context.Background(), ref.PaymentID)
+	switch {
+	case succeeded >= p.Amount:
+		p.Status = "refunded"
+	case succeeded > 0:
+		p.Status = "partially_refunded"
+	}
+	if f.updated == nil {
+		f.updated = map[string]string{}
+	}
+	f.updated[p.PaymentID] = p.Status
+	return p.Status, nil
+}
+
+func (f *fakePaymentRepo) GetPaymentByYookassaID(_ context.Context, yookassaID string) (*repository.PaymentRecord, error) {
+	for _, p := range append(f.byRoom, f.created...) {
+		if p.YookassaPaymentID == yookassaID {
+			return p, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) TransitionPaymentStatus(_ context.Context, paymentID, status string, from ...string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range append(f.byRoom, f.created...) {
+		if p.PaymentID == paymentID && slices.Contains(from, p.Status) {
+			p.Status = status
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakePaymentRepo) ListStalePayments(_ context.Context, before time.Time, _ int, statuses ...string) ([]*repository.PaymentRecord, error) {
+	var result []*repository.PaymentRecord
+	for _, p := range f.byRoom {
+		if slices.Contains(statuses, p.Status) && p.UpdatedAt.Before(before) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) ListActiveHolds(_ context.Context, roomID, userID string) ([]*repository.PaymentRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var result []*repository.PaymentRecord
+	for _, p := range append(f.byRoom, f.created...) {
+		if p.RoomID == roomID && (userID == "" || p.UserID == userID) && p.AuthorizedAmount > 0 &&
+			(p.Status == "pending" || p.Status == "waiting_for_capture") {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) ListUnconfirmedPayments(_ context.Context, before time.Time, maxReminders, _ int) ([]*repository.PaymentRecord, error) {
+	var result []*repository.PaymentRecord
+	for _, p := range f.byRoom {
+		if p.Status == "pending" && p.ConfirmationURL != "" && p.AuthorizedAmount == 0 &&
+			p.Reminders < maxReminders && p.CreatedAt.Before(before) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) MarkReminded(_ context.Context, paymentID string) error {
+	for _, p := range f.byRoom {
+		if p.PaymentID == paymentID {
+			p.Reminders++
+		}
+	}
+	return nil
+}
+func (f *fakePaymentRepo) CaptureHold(_ context.Context, paymentID, driverID string, amount float64, status string) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, p := range append(f.byRoom, f.created...) {
+		if p.PaymentID == paymentID && p.Status == "waiting_for_capture" {
+			p.Amount, p.Status = amount, status
+			if driverID != "" {
+				p.DriverID = driverID
+			}
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakePaymentRepo) GetRefundByYookassaID(_ context.Context, yookassaRefundID string) (*repository.RefundRecord, error) {
+	for _, r := range f.refunds {
+		if r.YookassaRefundID == yookassaRefundID {
+			return r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+
+func (f *fakePaymentRepo) CreatePaymentMethod(_ context.Context, m *repository.PaymentMethodRecord) error {
+	m.CreatedAt = time.Now()
+	f.methods = append(f.methods, m)
+	return nil
+}
+func (f *fakePaymentRepo) ListPaymentMethods(_ context.Context, userID string) ([]*repository.PaymentMethodRecord, error) {
+	var result []*repository.PaymentMethodRecord
+	for _, m := range f.methods {
+		if m.UserID == userID && m.Status != "failed" {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) GetPaymentMethodByBinding(_ context.Context, bindingPaymentID string) (*repository.PaymentMethodRecord, error) {
+	for _, m := range f.methods {
+		if m.BindingPaymentID == bindingPaymentID {
+			return m, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) GetDefaultPaymentMethod(_ context.Context, userID string) (*repository.PaymentMethodRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, m := range f.methods {
+		if m.UserID == userID && m.IsDefault && m.Status == "active" {
+			return m, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) ActivatePaymentMethod(ctx context.Context, methodID, providerMethodID, methodType, title string) (bool, error) {
+	for _, m := range f.methods {
+		if m.MethodID == methodID && m.Status == "pending" {
+			_, err := f.GetDefaultPaymentMethod(ctx, m.UserID)
+			m.Status, m.ProviderMethodID, m.Type, m.Title = "active", providerMethodID, methodType, title
+			m.IsDefault = errors.Is(err, repository.ErrNotFound)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakePaymentRepo) FailPaymentMethod(_ context.Context, methodID string) (bool, error) {
+	for _, m := range f.methods {
+		if m.MethodID == methodID && m.Status == "pending" {
+			m.Status = "failed"
+			return true, nil
+		}
+	}
+	return false, nil
+}
+func (f *fakePaymentRepo) SetDefaultPaymentMethod(_ context.Context, userID, methodID string) error {
+	i := slices.IndexFunc(f.methods, func(m *repository.PaymentMethodRecord) bool {
+		return m.MethodID == methodID && m.UserID == userID
+	})
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+	if f.methods[i].Status != "active" {
+		return repository.ErrMethodNotActive
+	}
+	for _, m := range f.methods {
+		m.IsDefault = m.UserID == userID && m.MethodID == methodID
+	}
+	return nil
+}
+func (f *fakePaymentRepo) DeletePaymentMethod(_ context.Context, userID, methodID string) error {
+	i := slices.IndexFunc(f.methods, func(m *repository.PaymentMethodRecord) bool {
+		return m.MethodID == methodID && m.UserID == userID
+	})
+	if i < 0 {
+		return repository.ErrNotFound
+	}
+	deleted := f.methods[i]
+	f.methods = slices.Delete(f.methods, i, i+1)
+	if deleted.IsDefault {
+		for j := len(f.methods) - 1; j >= 0; j-- {
+			if m := f.methods[j]; m.UserID == userID && m.Status == "active" {
+				m.IsDefault = true
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func (f *fakePaymentRepo) PostTransaction(_ context.Context, t *ledger.Transaction) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := t.Validate(); err != nil {
+		return false, err
+	}
+	for _, posted := range f.ledger {
+		if posted.Kind == t.Kind && posted.Reference == t.Reference {
+			return false, nil
+		}
+	}
+	f.ledger = append(f.ledger, t)
+	return true, nil
+}
+func (f *fakePaymentRepo) GetAccountBalances(_ context.Context, ownerID string) ([]*repository.AccountBalance, error) {
+	var result []*repository.AccountBalance
+	byID := map[string]*repository.AccountBalance{}
+	for _, t := range f.ledger {
+		for _, e := range t.Entries {
+			accountType, owner, _ := ledger.ParseAccount(e.AccountID)
+			if owner != ownerID {
+				continue
+			}
+			b, ok := byID[e.AccountID]
+			if !ok {
+				b = &repository.AccountBalance{AccountID: e.AccountID, AccountType: accountType, Currency: t.Currency}
+				byID[e.AccountID] = b
+				result = append(result, b)
+			}
+			b.Balance += e.Amount * ledger.Sign(accountType)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) ListAccountEntries(_ context.Context, accountIDs []string, limit int) ([]*repository.LedgerEntryRecord, error) {
+	var result []*repository.LedgerEntryRecord
+	for i := len(f.ledger) - 1; i >= 0 && len(result) < limit; i-- {
+		t := f.ledger[i]
+		for _, e := range t.Entries {
+			if slices.Contains(accountIDs, e.AccountID) {
+				result = append(result, &repository.LedgerEntryRecord{
+					TxID: t.Reference, Kind: t.Kind, Reference: t.Reference, Description: t.Description,
+					AccountID: e.AccountID, Amount: e.Amount, CreatedAt: time.Now(),
+				})
+			}
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) CreateEarning(_ context.Context, e *repository.EarningRecord) (bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, existing := range f.earnings {
+		if existing.PaymentID == e.PaymentID {
+			return false, nil
+		}
+	}
+	e.EarningID = fmt.Sprintf("earning-%d", len(f.earnings)+1)
+	e.CreatedAt = time.Now()
+	f.earnings = append(f.earnings, e)
+	return true, nil
+}
+func (f *fakePaymentRepo) ListDriverEarnings(_ context.Context, driverID string, _, _ time.Time) ([]*repository.EarningRecord, error) {
+	var result []*repository.EarningRecord
+	for i := len(f.earnings) - 1; i >= 0; i-- {
+		if f.earnings[i].DriverID == driverID {
+			result = append(result, f.earnings[i])
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) ListDriversToPay(_ context.Context, before time.Time) ([]repository.UnpaidDriver, error) {
+	var result []repository.UnpaidDriver
+	for _, e := range f.earnings {
+		d := repository.UnpaidDriver{DriverID: e.DriverID, Currency: e.Currency}
+		if e.PayoutID == "" && e.CreatedAt.Before(before) && !slices.Contains(result, d) {
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) CreatePayout(_ context.Context, driverID, currency string, periodEnd time.Time) (*repository.PayoutRecord, error) {
+	p := &repository.PayoutRecord{
+		PayoutID:  fmt.Sprintf("payout-%d", len(f.payouts)+1),
+		DriverID:  driverID,
+		Currency:  currency,
+		PeriodEnd: periodEnd,
+		Status:    "pending",
+		CreatedAt: time.Now(),
+	}
+	for _, e := range f.earnings {
+		if e.DriverID == driverID && e.Currency == currency && e.PayoutID == "" && e.CreatedAt.Before(periodEnd) {
+			e.PayoutID = p.PayoutID
+			p.Amount = money.Round(p.Amount+e.Net, currency)
+		}
+	}
+	if p.Amount == 0 {
+		return nil, repository.ErrNotFound
+	}
+	f.payouts = append(f.payouts, p)
+	return p, nil
+}
+func (f *fakePaymentRepo) FinishPayout(_ context.Context, payoutID, status, providerPayoutID, errMsg string) error {
+	for _, p := range f.payouts {
+		if p.PayoutID == payoutID {
+			p.Status, p.ProviderPayoutID, p.Error = status, providerPayoutID, errMsg
+		}
+	}
+	if status == "failed" {
+		for _, e := range f.earnings {
+			if e.PayoutID == payoutID {
+				e.PayoutID = ""
+			}
+		}
+	}
+	return nil
+}
+func (f *fakePaymentRepo) ListPayouts(_ context.Context, driverID, status string, limit int) ([]*repository.PayoutRecord, error) {
+	var result []*repository.PayoutRecord
+	for i := len(f.payouts) - 1; i >= 0 && len(result) < limit; i-- {
+		p := f.payouts[i]
+		if (driverID == "" || p.DriverID == driverID) && (status == "" || p.Status == status) {
+			result = append(result, p)
+		}
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) GetReceiptContact(_ context.Context, userID string) (*repository.ReceiptContact, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if c, ok := f.contacts[userID]; ok {
+		return c, nil
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) SetReceiptContact(_ context.Context, c *repository.ReceiptContact) error {
+	if f.contacts == nil {
+		f.contacts = map[string]*repository.ReceiptContact{}
+	}
+	f.contacts[c.UserID] = c
+	return nil
+}
+func (f *fakePaymentRepo) AddReceiptEmail(ctx context.Context, userID, email string) error {
+	if _, ok := f.contacts[userID]; ok {
+		return nil
+	}
+	return f.SetReceiptContact(ctx, &repository.ReceiptContact{UserID: userID, Email: email})
+}
+func (f *fakePaymentRepo) CreateReceipt(_ context.Context, rec *repository.ReceiptRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.receipts {
+		if r.PaymentID == rec.PaymentID && r.RefundID == rec.RefundID {
+			return nil
+		}
+	}
+	f.receipts = append(f.receipts, rec)
+	return nil
+}
+func (f *fakePaymentRepo) ListReceipts(_ context.Context, paymentID string) ([]*repository.ReceiptRecord, error) {
+	var result []*repository.ReceiptRecord
+	for _, r := range f.receipts {
+		if r.PaymentID != paymentID {
+			continue
+		}
+		for _, ref := range f.refunds {
+			if ref.RefundID == r.RefundID {
+				r.RefundStatus = ref.Status
+			}
+		}
+		result = append(result, r)
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) CreatePromoCode(_ context.Context, p *repository.PromoCodeRecord) error {
+	if f.promos == nil {
+		f.promos = map[string]*repository.PromoCodeRecord{}
+	}
+	if _, ok := f.promos[p.Code]; ok {
+		return repository.ErrPromoExists
+	}
+	p.Active, p.CreatedAt = true, time.Now()
+	f.promos[p.Code] = p
+	return nil
+}
+func (f *fakePaymentRepo) GetPromoCode(_ context.Context, code string) (*repository.PromoCodeRecord, error) {
+	if p, ok := f.promos[code]; ok {
+		return p, nil
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) ListPromoCodes(_ context.Context) ([]*repository.PromoCodeRecord, error) {
+	var result []*repository.PromoCodeRecord
+	for _, p := range f.promos {
+		p.Uses = 0
+		for _, r := range f.redeemed {
+			if r.Promo.Code == p.Code {
+				p.Uses++
+			}
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+func (f *fakePaymentRepo) ApplyPromoCode(_ context.Context, code, roomID, userID string) error {
+	p, ok := f.promos[code]
+	if !ok {
+		return repository.ErrNotFound
+	}
+	var current *repository.PromoRedemption
+	total, byUser := 0, 0
+	for _, r := range f.redeemed {
+		if r.RoomID == roomID && r.UserID == userID {
+			current = r
+			continue
+		}
+		if r.Promo.Code == code {
+			total++
+			if r.UserID == userID {
+				byUser++
+			}
+		}
+	}
+	if (p.MaxUses > 0 && total >= p.MaxUses) || (p.MaxUsesPerUser > 0 && byUser >= p.MaxUsesPerUser) {
+		return repository.ErrPromoExhausted
+	}
+	if current == nil {
+		f.redeemed = append(f.redeemed, &repository.PromoRedemption{RoomID: roomID, UserID: userID, Status: repository.RedemptionApplied, Promo: *p})
+		return nil
+	}
+	if current.Status != repository.RedemptionApplied {
+		return repository.ErrPromoRedeemed
+	}
+	current.Promo = *p
+	return nil
+}
+func (f *fakePaymentRepo) GetPromoRedemption(_ context.Context, roomID, userID string) (*repository.PromoRedemption, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.redeemed {
+		if r.RoomID == roomID && r.UserID == userID {
+			return r, nil
+		}
+	}
+	return nil, repository.ErrNotFound
+}
+func (f *fakePaymentRepo) RedeemPromoCode(_ context.Context, roomID, userID, paymentID, _ string, _ float64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, r := range f.redeemed {
+		if r.RoomID == roomID && r.UserID == userID && r.Status == repository.RedemptionApplied {
+			r.Status, r.PaymentID = repository.RedemptionRedeemed, paymentID
+		}
+	}
+	return nil
+}
+
+func (f *fakePaymentRepo) ReleasePromoCodes(_ context.Context, roomID, userID string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	before := len(f.redeemed)
+	f.redeemed = slices.DeleteFunc(f.redeemed, func(r *repository.PromoRedemption) bool {
+		return r.RoomID == roomID && (userID == "" || r.UserID == userID) && r.Status == repository.RedemptionApplied
+	})
+	return int64(before - len(f.redeemed)), nil
+}
+
+func (f *fakePaymentRepo) CreateStatement(_ context.Context, st *repository.StatementRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.statements == nil {
+		f.statements = map[string]*repository.StatementRecord{}
+	}
+	st.StatementID, st.Status, st.CreatedAt = uuid.NewString(), repository.StatementPending, time.Now()
+	saved := *st
+	f.statements[st.StatementID] = &saved
+	return nil
+}
+func (f *fakePaymentRepo) GetStatement(_ context.Context, statementID string) (*repository.StatementRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st, ok := f.statements[statementID]
+	if !ok {
+		return nil, repository.ErrNotFound
+	}
+	saved := *st
+	return &saved, nil
+}
+func (f *fakePaymentRepo) FinishStatement(_ context.Context, statementID, status, fileName, errMsg string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	st := f.statements[statementID]
+	st.Status, st.FileName, st.Error, st.FinishedAt = status, fileName, errMsg, time.Now()
+	return nil
+}
+func (f *fakePaymentRepo) DeleteStatements(_ context.Context, userID string) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var files []string
+	for id, st := range f.statements {
+		if st.UserID == userID {
+			if st.FileName != "" {
+				files = append(files, st.FileName)
+			}
+			delete(f.statements, id)
+		}
+	}
+	return files, nil
+}
+
+func loggerCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, err := logger.New(context.Background())
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+	return ctx
 }
-func (f *fakePaymentRepo) GetPaymentByID(_ context.Context, _ string) (*repository.PaymentRecord, error) {
-	return nil, errors.New("not implemented")
+
+func asRole(userID, role string) context.Context {
+	return identity.WithIdentity(context.Background(), identity.Identity{UserID: userID, Role: role})
 }
 
-type fakeYookassa struct {
+type stubProvider struct {
 	createErr error
 	refundErr error
+	payments  map[string]*provider.Payment
 }
 
-func (f *fakeYookassa) CreatePayment(_ context.Context, _ string, _ yookassa.CreatePaymentRequest) (*yookassa.PaymentResponse, error) {
+func (f *stubProvider) Name() string { return "stub" }
+
+func (f *stubProvider) CreatePayment(_ context.Context, _ string, _ provider.CreatePaymentRequest) (*provider.Payment, error) {
 	if f.createErr != nil {
 		return nil, f.createErr
 	}
-	return &yookassa.PaymentResponse{ID: "yk-payment-id", Status: "succeeded"}, nil
+	return &provider.Payment{ID: "yk-payment-id", Status: "succeeded"}, nil
 }
 
-func (f *fakeYookassa) CreateRefund(_ context.Context, _ string, _ yookassa.CreateRefundRequest) (*yookassa.RefundResponse, error) {
+func (f *stubProvider) CreateRefund(_ context.Context, _ string, _ provider.RefundRequest) (*provider.Refund, error) {
 	if f.refundErr != nil {
 		return nil, f.refundErr
 	}
-	return &yookassa.RefundResponse{ID: "yk-refund-id", Status: "succeeded"}, nil
+	return &provider.Refund{ID: "yk-refund-id", Status: "succeeded"}, nil
+}
+
+func (f *stubProvider) GetPayment(_ context.Context, paymentID string) (*provider.Payment, error) {
+	if p, ok := f.payments[paymentID]; ok {
+		return p, nil
+	}
+	return nil, provider.ErrNotFound
+}
+
+func (f *stubProvider) GetRefund(_ context.Context, refundID string) (*provider.Refund, error) {
+	return &provider.Refund{ID: refundID, Status: "succeeded"}, nil
+}
+
+func (f *stubProvider) CapturePayment(_ context.Context, _, paymentID string, amount float64, _ string, _ *provider.Receipt) (*provider.Payment, error) {
+	return &provider.Payment{ID: paymentID, Status: "succeeded", Amount: amount}, nil
+}
+
+func (f *stubProvider) CancelPayment(_ context.Context, _, paymentID string) (*provider.Payment, error) {
+	return &provider.Payment{ID: paymentID, Status: "canceled"}, nil
+}
+
+func (f *stubProvider) ParseNotification(_ []byte) (*provider.Notification, error) {
+	return nil, errors.New("not supported")
+}
+
+// fakeRooms — room_service с завершёнными комнатами
+type fakeRooms struct {
+	roompb.RoomServiceClient
+	rooms map[string]*roompb.GetRoomBillingResponse
+}
+
+// withRooms подставляет в сервис fakeRooms
+func withRooms(svc *PaymentService) *fakeRooms {
+	rooms := &fakeRooms{rooms: map[string]*roompb.GetRoomBillingResponse{}}
+	svc.roomClient = rooms
+	return rooms
+}
+
+// complete добавляет завершённую поездку с долей costPerMember; создатель тоже участник
+func (f *fakeRooms) complete(roomID, creatorID string, costPerMember float32, currency string, members ...string) *roompb.Room {
+	room := &roompb.Room{
+		RoomId:        roomID,
+		CreatorId:     creatorID,
+		Status:        roompb.RoomStatus_ROOM_STATUS_COMPLETED,
+		CostPerMember: costPerMember,
+		Currency:      currency,
+	}
+	f.rooms[roomID] = &roompb.GetRoomBillingResponse{Room: room, Members: append([]string{creatorID}, members...)}
+	return room
+}
+
+// fee записывает в комнату штраф пассажиру; комната создаётся, если её ещё нет
+func (f *fakeRooms) fee(roomID, creatorID, userID, feeType string, amount float32) {
+	billing, ok := f.rooms[roomID]
+	if !ok {
+		billing = &roompb.GetRoomBillingResponse{Room: &roompb.Room{RoomId: roomID, CreatorId: creatorID}}
+		f.rooms[roomID] = billing
+	}
+	billing.Fees = append(billing.Fees, &roompb.RoomFee{UserId: userID, Type: feeType, Amount: amount})
+}
+
+func (f *fakeRooms) GetRoomBilling(_ context.Context, req *roompb.GetRoomBillingRequest, _ ...grpc.CallOption) (*roompb.GetRoomBillingResponse, error) {
+	billing, ok := f.rooms[req.RoomId]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "room not found")
+	}
+	return billing, nil
 }
 
 func TestProcessPaymentValidation(t *testing.T) {
-	svc := New(&fakePaymentRepo{}, nil)
+	svc := New(&fakePaymentRepo{}, nil, Options{})
 
-	_, err := svc.ProcessPayment(context.Background(), &pb.ProcessPaymentRequest{})
+	_, err := svc.ProcessPayment(asRole("driver-1", identity.RoleDriver), &pb.ProcessPaymentRequest{})
 	if err == nil {
 		t.Fatal("expected validation error")
 	}
 }
 
-func TestProcessPaymentMockSuccess(t *testing.T) {
+func TestProcessPaymentFakeProviderSuccess(t *testing.T) {
 	repo := &fakePaymentRepo{}
-	svc := New(repo, nil)
+	svc := New(repo, fake.New(fake.Options{}), Options{})
+	withRooms(svc).complete("room-1", "driver-1", 100, "RUB", "u1", "u2")
 
-	resp, err := svc.ProcessPayment(context.Background(), &pb.ProcessPaymentRequest{
+	resp, err := svc.ProcessPayment(asRole("driver-1", identity.RoleDriver), &pb.ProcessPaymentRequest{
 		RoomId:        "room-1",
 		UserIds:       []string{"u1", "u2"},
 		AmountPerUser: 100,
@@ -86,21 +796,25 @@ func TestProcessPaymentMockSuccess(t *testing.T) {
 		t.Fatalf("expected 2 persisted payments, got %d", len(repo.created))
 	}
 	if repo.created[0].Status != "succeeded" {
-		t.Fatalf("expected succeeded status in mock mode, got %s", repo.created[0].Status)
+		t.Fatalf("expected succeeded status from fake provider, got %s", repo.created[0].Status)
 	}
 }
 
 func TestProcessPaymentYookassaErrorPersistsAudit(t *testing.T) {
 	repo := &fakePaymentRepo{}
-	svc := New(repo, &fakeYookassa{createErr: errors.New("gateway down")})
+	svc := New(repo, &stubProvider{createErr: errors.New("gateway down")}, Options{})
+	withRooms(svc).complete("room-1", "driver-1", 10, "RUB", "u1")
 
-	_, err := svc.ProcessPayment(context.Background(), &pb.ProcessPaymentRequest{
+	resp, err := svc.ProcessPayment(asRole("driver-1", identity.RoleDriver), &pb.ProcessPaymentRequest{
 		RoomId:        "room-1",
 		UserIds:       []string{"u1"},
 		AmountPerUser: 10,
 	})
-	if err == nil {
-		t.Fatal("expected yookassa error")
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if resp.Success || resp.Status != "failed" || len(resp.Results) != 1 || !strings.Contains(resp.Results[0].Error, "gateway down") {
+		t.Fatalf("expected yookassa error in results, got %+v", resp)
 	}
 	if len(repo.created) != 1 {
 		t.Fatalf("expected one persisted failed payment, got %d", len(repo.created))
@@ -115,9 +829,9 @@ func TestRefundPaymentScenarios(t *testing.T) {
 		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Currency: "RUB", Status: "succeeded", YookassaPaymentID: "yk1", CreatedAt: time.Now()},
 		{PaymentID: "p2", RoomID: "room-1", UserID: "u2", Amount: 100, Currency: "RUB", Status: "failed", YookassaPaymentID: "yk2", CreatedAt: time.Now()},
 	}}
-	svc := New(repo, &fakeYookassa{})
+	svc := New(repo, &stubProvider{}, Options{})
 
-	resp, err := svc.RefundPayment(context.Background(), &pb.RefundPaymentRequest{RoomId: "room-1"})
+	resp, err := svc.RefundPayment(asRole("admin-1", identity.RoleAdmin), &pb.RefundPaymentRequest{RoomId: "room-1"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -129,17 +843,1458 @@ func TestRefundPaymentScenarios(t *testing.T) {
 	}
 }
 
+func TestPartialRefundsEnforceBalance(t *testing.T) {
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Currency: "RUB", Status: "succeeded", YookassaPaymentID: "yk1"},
+		{PaymentID: "p2", RoomID: "room-1", UserID: "u2", Amount: 100, Currency: "RUB", Status: "succeeded", YookassaPaymentID: "yk2"},
+	}}
+	svc := New(repo, &stubProvider{}, Options{})
+	ctx := asRole("admin-1", identity.RoleAdmin)
+
+	resp, err := svc.RefundPayment(ctx, &pb.RefundPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, Amount: 30})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Items) != 1 || resp.Items[0].Status != "succeeded" || repo.updated["p1"] != "partially_refunded" {
+		t.Fatalf("expected partial refund of p1 only, got %+v, statuses %v", resp.Items, repo.updated)
+	}
+
+	resp, err = svc.RefundPayment(ctx, &pb.RefundPaymentRequest{PaymentIds: []string{"p1"}, Amount: 80})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || resp.Items[0].Error == "" {
+		t.Fatalf("expected refund over remaining balance to fail, got %+v", resp.Items)
+	}
+
+	resp, err = svc.RefundPayment(ctx, &pb.RefundPaymentRequest{PaymentIds: []string{"p1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Items[0].Amount != 70 || repo.updated["p1"] != "refunded" {
+		t.Fatalf("expected remaining 70 to be refunded, got %+v, status %s", resp.Items[0], repo.updated["p1"])
+	}
+}
+
+func TestRefundContinuesAfterYookassaError(t *testing.T) {
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Currency: "RUB", Status: "succeeded", YookassaPaymentID: "yk1"},
+		{PaymentID: "p2", RoomID: "room-1", UserID: "u2", Amount: 100, Currency: "RUB", Status: "succeeded", YookassaPaymentID: "yk2"},
+	}}
+	svc := New(repo, &stubProvider{refundErr: errors.New("yookassa unavailable")}, Options{})
+
+	resp, err := svc.RefundPayment(asRole("admin-1", identity.RoleAdmin), &pb.RefundPaymentRequest{RoomId: "room-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Success || len(resp.Items) != 2 {
+		t.Fatalf("expected both payments to be attempted, got %+v", resp.Items)
+	}
+	for _, r := range repo.refunds {
+		if r.Status != "failed" {
+			t.Fatalf("expected failed refund to release the balance, got %s", r.Status)
+		}
+	}
+}
+
+func TestPaymentCallsRequireRole(t *testing.T) {
+	svc := New(&fakePaymentRepo{}, nil, Options{})
+
+	_, err := svc.ProcessPayment(asRole("u1", identity.RoleRider), &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u2"}, AmountPerUser: 100})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for rider charging users, got %v", err)
+	}
+	_, err = svc.RefundPayment(asRole("driver-1", identity.RoleDriver), &pb.RefundPaymentRequest{RoomId: "room-1"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-admin refund, got %v", err)
+	}
+}
+
+func TestRideChargeIsVerifiedWithRoom(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	svc := New(repo, fake.New(fake.Options{}), Options{})
+	rooms := withRooms(svc)
+	rooms.complete("room-1", "d1", 100, "RUB", "u1", "u2")
+	rooms.complete("room-2", "d1", 100, "RUB", "u1").Status = roompb.RoomStatus_ROOM_STATUS_ON_RIDE
+	driver := asRole("d1", identity.RoleDriver)
+
+	for name, tc := range map[string]struct {
+		ctx  context.Context
+		req  *pb.ProcessPaymentRequest
+		code codes.Code
+	}{
+		"another driver":  {asRole("d2", identity.RoleDriver), &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100}, codes.PermissionDenied},
+		"unknown room":    {driver, &pb.ProcessPaymentRequest{RoomId: "room-x", UserIds: []string{"u1"}, AmountPerUser: 100}, codes.NotFound},
+		"ride in process": {driver, &pb.ProcessPaymentRequest{RoomId: "room-2", UserIds: []string{"u1"}, AmountPerUser: 100}, codes.FailedPrecondition},
+		"not a member":    {driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u3"}, AmountPerUser: 100}, codes.InvalidArgument},
+		"driver share":    {driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "d1"}, AmountPerUser: 100}, codes.InvalidArgument},
+		"other amount":    {driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 1000}, codes.InvalidArgument},
+		"other currency":  {driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100, Currency: "USD"}, codes.InvalidArgument},
+		"other driver_id": {asRole("a1", identity.RoleAdmin), &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100, DriverId: "d2"}, codes.InvalidArgument},
+	} {
+		if _, err := svc.ProcessPayment(tc.ctx, tc.req); status.Code(err) != tc.code {
+			t.Fatalf("%s: expected %v, got %v", name, tc.code, err)
+		}
+	}
+	if _, err := svc.CapturePayment(asRole("d2", identity.RoleDriver),
+		&pb.CapturePaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for capture by another driver, got %v", err)
+	}
+	if len(repo.created) != 0 {
+		t.Fatalf("rejected charges must not create payments, got %d", len(repo.created))
+	}
+
+	// Заработок начисляется создателю комнаты, даже если admin его не указал
+	resp, err := svc.ProcessPayment(asRole("a1", identity.RoleAdmin), &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100})
+	if err != nil || !resp.Success || repo.created[0].DriverID != "d1" {
+		t.Fatalf("expected payment with the room creator as driver, got %+v, %v", resp, err)
+	}
+}
+
 func TestGetPaymentHistory(t *testing.T) {
+	now := time.Now()
+	repo := &fakePaymentRepo{
+		byRoom: []*repository.PaymentRecord{
+			{PaymentID: "00000000-0000-0000-0000-000000000003", RoomID: "room-2", UserID: "u1", Amount: 100, Currency: "RUB", Status: "partially_refunded", Kind: repository.PaymentRide, CreatedAt: now},
+			{PaymentID: "00000000-0000-0000-0000-000000000002", RoomID: "room-1", UserID: "u1", Amount: 50, Currency: "RUB", Status: "failed", Kind: repository.PaymentRide, CreatedAt: now.Add(-time.Hour)},
+			{PaymentID: "00000000-0000-0000-0000-000000000001", RoomID: "room-1", UserID: "u1", Amount: 10, Currency: "USD", Status: "succeeded", Kind: repository.PaymentRide, CreatedAt: now.Add(-2 * time.Hour)},
+		},
+		refunds: []*repository.RefundRecord{
+			{RefundID: "r1", PaymentID: "00000000-0000-0000-0000-000000000003", Amount: 30, Status: "succeeded"},
+			{RefundID: "r2", PaymentID: "00000000-0000-0000-0000-000000000003", Amount: 20, Status: "failed"},
+		},
+	}
+	svc := New(repo, nil, Options{})
+	ctx := identity.WithIdentity(context.Background(), identity.Identity{UserID: "u1"})
+
+	from := timestamppb.New(now.Add(-24 * time.Hour))
+	resp, err := svc.GetPaymentHistory(ctx, &pb.GetPaymentHistoryRequest{Limit: 2, Status: "succeeded", RoomId: "room-1", Kind: "ride", From: from})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Payments) != 2 || resp.NextCursor == "" || !resp.Payments[0].CreatedAt.AsTime().Equal(now) {
+		t.Fatalf("expected first page of 2 with a cursor, got %+v", resp)
+	}
+	if f := repo.filter; f.Limit != 3 || f.UserIDs[0] != "u1" || f.Status != "succeeded" || f.RoomID != "room-1" ||
+		f.Kind != "ride" || !f.CreatedFrom.Equal(from.AsTime()) || !f.CreatedTo.IsZero() || !f.BeforeTime.IsZero() {
+		t.Fatalf("unexpected filter %+v", f)
+	}
+	if len(resp.Totals) != 2 || resp.Totals[0].Currency != "RUB" || resp.Totals[0].Count != 2 ||
+		resp.Totals[0].Spent != 100 || resp.Totals[0].Refunded != 30 || resp.Totals[1].Spent != 10 {
+		t.Fatalf("unexpected totals %+v", resp.Totals)
+	}
+
+	// Следующая страница начинается после последнего платежа предыдущей
+	repo.byRoom = repo.byRoom[2:]
+	next, err := svc.GetPaymentHistory(ctx, &pb.GetPaymentHistoryRequest{Limit: 2, Cursor: resp.NextCursor})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(next.Payments) != 1 || next.NextCursor != "" {
+		t.Fatalf("expected last page, got %+v", next)
+	}
+	if repo.filter.BeforeID != "00000000-0000-0000-0000-000000000002" || !repo.filter.BeforeTime.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected cursor in filter %+v", repo.filter)
+	}
+	if _, err := svc.GetPaymentHistory(ctx, &pb.GetPaymentHistoryRequest{Limit: 1000}); err != nil || repo.filter.Limit != maxHistoryLimit+1 {
+		t.Fatalf("expected limit capped at %d, got %d, %v", maxHistoryLimit, repo.filter.Limit, err)
+	}
+
+	for _, req := range []*pb.GetPaymentHistoryRequest{
+		{Cursor: "not-a-cursor"},
+		{Limit: -1},
+		{To: &timestamppb.Timestamp{Seconds: -1 << 40}},
+	} {
+		if _, err := svc.GetPaymentHistory(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %+v, got %v", req, err)
+		}
+	}
+	_, err = svc.GetPaymentHistory(ctx, &pb.GetPaymentHistoryRequest{UserId: "u2"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user's history, got %v", err)
+	}
+}
+
+func TestAnonymizeUserPayments(t *testing.T) {
 	repo := &fakePaymentRepo{byUser: []*repository.PaymentRecord{
-		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Currency: "RUB", Status: "succeeded", Description: "trip", CreatedAt: time.Now()},
+		{PaymentID: "p1", UserID: "u1", Description: "Поездка A → B"},
+	}}
+	svc := New(repo, nil, Options{})
+
+	if _, err := svc.AnonymizeUserPayments(identity.WithIdentity(context.Background(), identity.Identity{UserID: "u2"}), &pb.AnonymizeUserPaymentsRequest{UserId: "u1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user, got %v", err)
+	}
+	resp, err := svc.AnonymizeUserPayments(identity.WithIdentity(context.Background(), identity.Identity{UserID: "u1"}), &pb.AnonymizeUserPaymentsRequest{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Anonymized != 1 || repo.byUser[0].Description != "" {
+		t.Fatalf("expected description to be cleared, got %+v", repo.byUser[0])
+	}
+}
+
+func TestSearchPayments(t *testing.T) {
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Status: "succeeded", CreatedAt: time.Now()},
 	}}
-	svc := New(repo, nil)
+	svc := New(repo, nil, Options{})
+
+	if _, err := svc.SearchPayments(asRole("u1", identity.RoleDriver), &pb.SearchPaymentsRequest{}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-admin, got %v", err)
+	}
+	if _, err := svc.SearchPayments(asRole("a1", identity.RoleAdmin), &pb.SearchPaymentsRequest{CreatedFrom: "yesterday"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for bad created_from, got %v", err)
+	}
 
-	resp, err := svc.GetPaymentHistory(context.Background(), &pb.GetPaymentHistoryRequest{UserId: "u1"})
+	resp, err := svc.SearchPayments(asRole("a1", identity.RoleAdmin), &pb.SearchPaymentsRequest{RoomId: "room-1", Limit: 1000})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if len(resp.Payments) != 1 {
-		t.Fatalf("expected 1 payment in history, got %d", len(resp.Payments))
+	if len(resp.Payments) != 1 || resp.Payments[0].PaymentId != "p1" {
+		t.Fatalf("unexpected payments %+v", resp.Payments)
+	}
+	if repo.filter.RoomID != "room-1" || repo.filter.Limit != maxSearchLimit {
+		t.Fatalf("unexpected filter %+v", repo.filter)
+	}
+}
+
+func TestWebhookPaymentSucceededIsIdempotent(t *testing.T) {
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 100, Currency: "RUB", Status: "pending", YookassaPaymentID: "yk1"},
+	}}
+	yk := &stubProvider{payments: map[string]*provider.Payment{
+		"yk1": {ID: "yk1", Status: "succeeded", Amount: 100, Metadata: map[string]string{"payment_id": "p1"}},
+	}}
+	svc := New(repo, yk, Options{})
+	updates, cancel := svc.updates.Subscribe("u1")
+	defer cancel()
+
+	n := &provider.Notification{Event: provider.EventPaymentSucceeded, ObjectID: "yk1"}
+	for i := 0; i < 2; i++ {
+		if err := svc.HandleNotification(loggerCtx(t), n); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if repo.byRoom[0].Status != "succeeded" {
+		t.Fatalf("expected payment to be succeeded, got %s", repo.byRoom[0].Status)
+	}
+	if len(updates) != 1 {
+		t.Fatalf("expected exactly one published update, got %d", len(updates))
+	}
+	if u := <-updates; u.PaymentId != "p1" || u.Status != "succeeded" {
+		t.Fatalf("unexpected update %+v", u)
+	}
+}
+
+func TestWebhookTrustsFetchedStatusOnly(t *testing.T) {
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", UserID: "u1", Status: "pending", YookassaPaymentID: "yk1"},
+	}}
+	yk := &stubProvider{payments: map[string]*provider.Payment{"yk1": {ID: "yk1", Status: "pending"}}}
+	svc := New(repo, yk, Options{})
+
+	n := &provider.Notification{Event: provider.EventPaymentSucceeded, ObjectID: "yk1"}
+	if err := svc.HandleNotification(loggerCtx(t), n); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if repo.byRoom[0].Status != "pending" {
+		t.Fatalf("forged notification must not change status, got %s", repo.byRoom[0].Status)
+	}
+}
+
+func TestReconcileAppliesProviderStatus(t *testing.T) {
+	old := time.Now().Add(-time.Hour)
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", UserID: "u1", Status: "pending", YookassaPaymentID: "yk1", UpdatedAt: old},
+		{PaymentID: "p2", UserID: "u2", Status: "pending", YookassaPaymentID: "yk2", UpdatedAt: old},
+		{PaymentID: "p3", UserID: "u3", Status: "pending", YookassaPaymentID: "yk3", UpdatedAt: time.Now()},
+		{PaymentID: "p4", UserID: "u4", Status: "waiting_for_capture", YookassaPaymentID: "yk4", UpdatedAt: old},
+	}}
+	yk := &stubProvider{payments: map[string]*provider.Payment{
+		"yk1": {ID: "yk1", Status: "succeeded", Amount: 100},
+		"yk2": {ID: "yk2", Status: "pending"},
+		"yk3": {ID: "yk3", Status: "succeeded"},
+	}}
+	svc := New(repo, yk, Options{})
+
+	report, err := svc.Reconcile(loggerCtx(t), ReconcileOptions{Threshold: 15 * time.Minute, BatchSize: 100})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if report.Checked != 3 || report.FetchErrors != 1 || len(report.Mismatches) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	if m := report.Mismatches[0]; m.PaymentID != "p1" || !m.Applied || m.DBStatus != "pending" || m.ProviderStatus != "succeeded" {
+		t.Fatalf("unexpected mismatch %+v", m)
+	}
+	if repo.byRoom[0].Status != "succeeded" || repo.byRoom[2].Status != "pending" {
+		t.Fatalf("only stale payments must be reconciled: %s, %s", repo.byRoom[0].Status, repo.byRoom[2].Status)
+	}
+}
+
+func TestHoldCaptureAndVoid(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	svc := New(repo, fake.New(fake.Options{}), Options{})
+	withRooms(svc).complete("room-1", "d1", 350, "RUB", "u1", "u2")
+	ctx := loggerCtx(t)
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Role: identity.RoleRider})
+
+	hold, err := svc.AuthorizePayment(rider, &pb.AuthorizePaymentRequest{RoomId: "room-1", Amount: 300})
+	if err != nil {
+		t.Fatalf("authorize: %v", err)
+	}
+	again, err := svc.AuthorizePayment(rider, &pb.AuthorizePaymentRequest{RoomId: "room-1", Amount: 300})
+	if err != nil || again.Payment.PaymentId != hold.Payment.PaymentId {
+		t.Fatalf("repeated authorize must return the active hold, got %v, %v", again, err)
+	}
+	if _, err := svc.AuthorizePayment(rider, &pb.AuthorizePaymentRequest{RoomId: "room-1", UserId: "u2", Amount: 300}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for hold on behalf of another user, got %v", err)
+	}
+	if _, err := svc.AuthorizePayment(identity.WithIdentity(ctx, identity.Identity{UserID: "u2"}), &pb.AuthorizePaymentRequest{RoomId: "room-1", Amount: 300}); err != nil {
+		t.Fatalf("authorize u2: %v", err)
+	}
+
+	// Итоговая доля 350 больше холда 300: холд списывается полностью, 50 — отдельным платежом
+	resp, err := svc.CapturePayment(identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver}),
+		&pb.CapturePaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 350})
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if len(resp.Payments) != 2 || resp.Payments[0].Amount != 300 || resp.Payments[0].Status != "succeeded" || resp.Payments[1].Amount != 50 {
+		t.Fatalf("unexpected capture result %+v", resp.Payments)
+	}
+
+	if _, err := svc.VoidPayment(rider, &pb.VoidPaymentRequest{RoomId: "room-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for rider voiding the whole room, got %v", err)
+	}
+	voided, err := svc.VoidPayment(identity.WithIdentity(ctx, identity.Identity{UserID: "u2"}), &pb.VoidPaymentRequest{RoomId: "room-1", UserId: "u2"})
+	if err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	if len(voided.Payments) != 1 || voided.Payments[0].Status != "canceled" {
+		t.Fatalf("unexpected void result %+v", voided.Payments)
+	}
+}
+
+// flakyCreate — фейковый провайдер, у которого первые fails созданий платежа падают по таймауту
+type flakyCreate struct {
+	*fake.Provider
+	fails int
+	keys  []string
+}
+
+func (p *flakyCreate) CreatePayment(ctx context.Context, key string, req provider.CreatePaymentRequest) (*provider.Payment, error) {
+	p.keys = append(p.keys, key)
+	if p.fails > 0 {
+		p.fails--
+		return nil, errors.New("timeout")
+	}
+	return p.Provider.CreatePayment(ctx, key, req)
+}
+
+func TestAuthorizePaymentIsReserved(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	prov := &flakyCreate{Provider: fake.New(fake.Options{}), fails: 1}
+	svc := New(repo, prov, Options{})
+	rider := identity.WithIdentity(loggerCtx(t), identity.Identity{UserID: "u1", Role: identity.RoleRider})
+	req := &pb.AuthorizePaymentRequest{RoomId: "room-1", Amount: 300}
+
+	if _, err := svc.AuthorizePayment(rider, req); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition on provider timeout, got %v", err)
+	}
+	// Повтор после таймаута отправляет тот же холд с тем же ключом, а не открывает второй
+	hold, err := svc.AuthorizePayment(rider, req)
+	if err != nil || len(repo.created) != 1 || hold.Payment.PaymentId != repo.created[0].PaymentID {
+		t.Fatalf("expected the reserved hold to be retried, got %+v, %v (%d payments)", hold, err, len(repo.created))
+	}
+	if again, err := svc.AuthorizePayment(rider, req); err != nil || again.Payment.PaymentId != hold.Payment.PaymentId {
+		t.Fatalf("expected the same hold, got %+v, %v", again, err)
+	}
+	if !slices.Equal(prov.keys, []string{"hold-room-1-u1", "hold-room-1-u1"}) {
+		t.Fatalf("unexpected provider keys %v", prov.keys)
+	}
+
+	// После снятого холда повторное вступление ставит новый
+	if _, err := svc.VoidPayment(rider, &pb.VoidPaymentRequest{RoomId: "room-1", UserId: "u1"}); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	rejoin, err := svc.AuthorizePayment(rider, req)
+	if err != nil || rejoin.Payment.PaymentId == hold.Payment.PaymentId || prov.keys[len(prov.keys)-1] != "hold-room-1-u1-2" {
+		t.Fatalf("expected a new hold after void, got %+v, %v, keys %v", rejoin, err, prov.keys)
+	}
+}
+
+// failingCapture — фейковый провайдер, у которого не списываются холды failHolds
+type failingCapture struct {
+	*fake.Provider
+	failHolds []string
+}
+
+func (p *failingCapture) CapturePayment(ctx context.Context, key, paymentID string, amount float64, currency string, receipt *provider.Receipt) (*provider.Payment, error) {
+	if slices.Contains(p.failHolds, paymentID) {
+		return nil, errors.New("hold expired")
+	}
+	return p.Provider.CapturePayment(ctx, key, paymentID, amount, currency, receipt)
+}
+
+func TestCapturePaymentPartialSuccessAndRetry(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	prov := &failingCapture{Provider: fake.New(fake.Options{DeclineUsers: []string{"u3"}})}
+	svc := New(repo, prov, Options{})
+	withRooms(svc).complete("room-1", "d1", 350, "RUB", "u1", "u2", "u3")
+	ctx := loggerCtx(t)
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+
+	var holds []*pb.Payment
+	for _, userID := range []string{"u1", "u2"} {
+		hold, err := svc.AuthorizePayment(identity.WithIdentity(ctx, identity.Identity{UserID: userID}), &pb.AuthorizePaymentRequest{RoomId: "room-1", Amount: 300})
+		if err != nil {
+			t.Fatalf("authorize %s: %v", userID, err)
+		}
+		holds = append(holds, hold.Payment)
+	}
+	prov.failHolds = []string{holds[1].YookassaPaymentId}
+
+	// Холд u2 не списался — он снимается, доля уходит в обычный платёж; отказ по u3 не мешает остальным
+	resp, err := svc.CapturePayment(driver, &pb.CapturePaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u2", "u3"}, AmountPerUser: 350})
+	if err != nil {
+		t.Fatalf("capture: %v", err)
+	}
+	if resp.Success || resp.Status != "partial" || len(resp.Results) != 3 {
+		t.Fatalf("expected partial capture, got %+v", resp)
+	}
+	if resp.Results[0].Status != "succeeded" || resp.Results[1].Status != "succeeded" || resp.Results[2].Status != "failed" {
+		t.Fatalf("unexpected results %+v", resp.Results)
+	}
+	if p := resp.Results[1].Payment; p.Amount != 350 || p.AuthorizedAmount != 0 {
+		t.Fatalf("expected u2 share to be charged without the hold, got %+v", p)
+	}
+	for _, p := range repo.created {
+		if p.PaymentID == holds[1].PaymentId && p.Status != "canceled" {
+			t.Fatalf("expected the uncaptured hold to be voided, got %s", p.Status)
+		}
+	}
+
+	repo.byRoom = repo.created
+	retry, err := svc.RetryFailedPayments(driver, &pb.RetryFailedPaymentsRequest{RoomId: "room-1"})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(retry.Results) != 1 || retry.Results[0].UserId != "u3" {
+		t.Fatalf("expected only u3 to be retried, got %+v", retry.Results)
+	}
+}
+
+// Сквозной сценарий с фейковым провайдером: платёж висит в pending, пока его не подтвердят,
+// итоговый статус приходит HTTP-уведомлением в WebhookHandler
+func TestFakeProviderWebhookFlow(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	ctx := loggerCtx(t)
+
+	var svc *PaymentService
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		svc.WebhookHandler(logger.GetLoggerFromCtx(ctx)).ServeHTTP(w, r)
+	}))
+	defer webhook.Close()
+
+	fp := fake.New(fake.Options{SettleDelay: time.Hour, WebhookURL: webhook.URL + WebhookPath, DeclineUsers: []string{"u2"}})
+	svc = New(repo, fp, Options{})
+	withRooms(svc).complete("room-1", "driver-1", 150, "RUB", "u1", "u2")
+	u1, cancel1 := svc.updates.Subscribe("u1")
+	defer cancel1()
+	u2, cancel2 := svc.updates.Subscribe("u2")
+	defer cancel2()
+
+	resp, err := svc.ProcessPayment(asRole("driver-1", identity.RoleDriver), &pb.ProcessPaymentRequest{
+		RoomId:        "room-1",
+		UserIds:       []string{"u1", "u2"},
+		AmountPerUser: 150,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, p := range resp.Payments {
+		if p.Status != "pending" {
+			t.Fatalf("expected pending payment before confirmation, got %s", p.Status)
+		}
+		if err := fp.Settle(p.YookassaPaymentId); err != nil {
+			t.Fatalf("settle: %v", err)
+		}
+	}
+
+	for user, ch := range map[string]<-chan *pb.PaymentUpdate{"u1": u1, "u2": u2} {
+		want := map[string]string{"u1": "succeeded", "u2": "canceled"}[user]
+		select {
+		case u := <-ch:
+			if u.Status != want {
+				t.Fatalf("expected %s for %s, got %+v", want, user, u)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no webhook update for %s", user)
+		}
+	}
+}
+
+// Привязка способа оплаты: проверочный холд подтверждается, способ становится способом по умолчанию,
+// холд снимается, а поездки списываются сохранённым способом без подтверждения
+func TestSavedPaymentMethods(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	fp := fake.New(fake.Options{SettleDelay: time.Hour})
+	svc := New(repo, fp, Options{})
+	withRooms(svc).complete("room-1", "driver-1", 200, "RUB", "u1")
+	ctx := loggerCtx(t)
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Role: identity.RoleRider})
+
+	if _, err := svc.AddPaymentMethod(rider, &pb.AddPaymentMethodRequest{UserId: "u2"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user's method, got %v", err)
+	}
+
+	var methods []*pb.PaymentMethod
+	for range 2 {
+		added, err := svc.AddPaymentMethod(rider, &pb.AddPaymentMethodRequest{})
+		if err != nil {
+			t.Fatalf("add method: %v", err)
+		}
+		if added.ConfirmationUrl == "" || added.Method.Status != "pending" {
+			t.Fatalf("binding must wait for confirmation, got %+v", added)
+		}
+		binding := repo.methods[len(repo.methods)-1].BindingPaymentID
+		if err := fp.Settle(binding); err != nil {
+			t.Fatalf("settle: %v", err)
+		}
+		if err := svc.HandleNotification(ctx, &provider.Notification{Event: provider.EventPaymentWaitingForCapture, ObjectID: binding}); err != nil {
+			t.Fatalf("binding notification: %v", err)
+		}
+		if p, _ := fp.GetPayment(ctx, binding); p.Status != provider.StatusCanceled {
+			t.Fatalf("binding hold must be released, got %s", p.Status)
+		}
+		methods = append(methods, added.Method)
+	}
+
+	list, err := svc.ListPaymentMethods(rider, &pb.ListPaymentMethodsRequest{})
+	if err != nil || len(list.Methods) != 2 {
+		t.Fatalf("unexpected methods %v, %v", list, err)
+	}
+	first := repo.methods[0]
+	if first.Status != "active" || !first.IsDefault || repo.methods[1].IsDefault {
+		t.Fatalf("first bound method must become default: %+v, %+v", first, repo.methods[1])
+	}
+
+	resp, err := svc.ProcessPayment(asRole("driver-1", identity.RoleDriver), &pb.ProcessPaymentRequest{
+		RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 200,
+	})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	p, _ := fp.GetPayment(ctx, resp.Payments[0].YookassaPaymentId)
+	if resp.Payments[0].Status != "succeeded" || p.PaymentMethod == nil || p.PaymentMethod.ID != first.ProviderMethodID {
+		t.Fatalf("ride must be charged with the default method without confirmation, got %+v", resp.Payments[0])
+	}
+
+	set, err := svc.SetDefaultPaymentMethod(rider, &pb.SetDefaultPaymentMethodRequest{MethodId: methods[1].MethodId})
+	if err != nil || !set.Method.IsDefault {
+		t.Fatalf("set default: %v, %v", set, err)
+	}
+	if _, err := svc.RemovePaymentMethod(rider, &pb.RemovePaymentMethodRequest{MethodId: methods[1].MethodId}); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if !first.IsDefault {
+		t.Fatal("remaining method must become default after removing the default one")
+	}
+	if _, err := svc.RemovePaymentMethod(rider, &pb.RemovePaymentMethodRequest{MethodId: methods[1].MethodId}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for removed method, got %v", err)
+	}
+}
+
+// Журнал: платежи и возвраты проводятся один раз, даже если подтверждение пришло и из ответа
+// провайдера, и из уведомления; баланс кошелька — оплачено минус возвращено
+func TestWalletLedger(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	svc := New(repo, fake.New(fake.Options{}), Options{})
+	ctx := loggerCtx(t)
+	admin := identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin})
+	withRooms(svc).complete("room-1", "", 300, "RUB", "u1")
+
+	// Без водителя (у комнаты нет создателя) оплата остаётся на счёте пассажира
+	resp, err := svc.ProcessPayment(admin,
+		&pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 300})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	paid := resp.Payments[0]
+	if err := svc.HandleNotification(ctx, &provider.Notification{Event: provider.EventPaymentSucceeded, ObjectID: paid.YookassaPaymentId}); err != nil {
+		t.Fatalf("notification: %v", err)
+	}
+	repo.byRoom = repo.created
+	if _, err := svc.RefundPayment(admin, &pb.RefundPaymentRequest{PaymentIds: []string{paid.PaymentId}, Amount: 120}); err != nil {
+		t.Fatalf("refund: %v", err)
+	}
+
+	if len(repo.ledger) != 2 {
+		t.Fatalf("expected charge and refund transactions, got %d", len(repo.ledger))
+	}
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Role: identity.RoleRider})
+	wallet, err := svc.GetWallet(rider, &pb.GetWalletRequest{})
+	if err != nil {
+		t.Fatalf("wallet: %v", err)
+	}
+	if len(wallet.Accounts) != 1 || wallet.Accounts[0].Balance != 180 {
+		t.Fatalf("unexpected accounts %+v", wallet.Accounts)
+	}
+	if len(wallet.Entries) != 2 || wallet.Entries[0].Kind != ledger.KindRefund || wallet.Entries[0].Amount != -120 || wallet.Entries[1].Amount != 300 {
+		t.Fatalf("unexpected entries %+v", wallet.Entries)
+	}
+	if _, err := svc.GetWallet(rider, &pb.GetWalletRequest{UserId: "u2"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user's wallet, got %v", err)
+	}
+
+	bad := ledger.Charge("u1", "p-bad", 100, "RUB")
+	bad.Entries[0].Amount = 99
+	if _, err := repo.PostTransaction(ctx, &bad); !errors.Is(err, ledger.ErrUnbalanced) {
+		t.Fatalf("expected unbalanced transaction to be rejected, got %v", err)
+	}
+}
+
+func TestDriverEarningsAndPayouts(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	fp := fake.New(fake.Options{DeclineUsers: []string{"d2"}})
+	svc := New(repo, fp, Options{CommissionRate: 0.2, Payouts: fp})
+	rooms := withRooms(svc)
+	rooms.complete("room-1", "d1", 250, "RUB", "u1", "u2")
+	rooms.complete("room-2", "d2", 100, "RUB", "u3")
+	rooms.complete("room-3", "d2", 100, "RUB", "u1")
+	ctx := loggerCtx(t)
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	admin := identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin})
+
+	if _, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u2"}, AmountPerUser: 250}); err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if _, err := svc.ProcessPayment(admin, &pb.ProcessPaymentRequest{RoomId: "room-2", UserIds: []string{"u3"}, AmountPerUser: 100, DriverId: "d2"}); err != nil {
+		t.Fatalf("process as admin: %v", err)
+	}
+	if _, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-3", UserIds: []string{"u1"}, AmountPerUser: 100, DriverId: "d2"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another driver, got %v", err)
+	}
+	if len(repo.earnings) != 3 || repo.earnings[0].Commission != 50 || repo.earnings[0].Net != 200 {
+		t.Fatalf("unexpected earnings %+v", repo.earnings)
+	}
+
+	earnings, err := svc.GetDriverEarnings(driver, &pb.GetDriverEarningsRequest{})
+	if err != nil {
+		t.Fatalf("earnings: %v", err)
+	}
+	if earnings.Gross != 500 || earnings.Commission != 100 || earnings.Unpaid != 400 || len(earnings.Rides) != 1 || earnings.Rides[0].Payments != 2 {
+		t.Fatalf("unexpected earnings report %+v", earnings)
+	}
+	if _, err := svc.GetDriverEarnings(driver, &pb.GetDriverEarningsRequest{DriverId: "d2"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another driver's earnings, got %v", err)
+	}
+
+	report, err := svc.PayoutBatch(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("payout batch: %v", err)
+	}
+	if report.Succeeded != 1 || report.Failed != 1 || report.Amounts["RUB"] != 400 {
+		t.Fatalf("unexpected payout report %+v", report)
+	}
+	earnings, err = svc.GetDriverEarnings(driver, &pb.GetDriverEarningsRequest{})
+	if err != nil {
+		t.Fatalf("earnings after payout: %v", err)
+	}
+	if earnings.PaidOut != 400 || earnings.Unpaid != 0 || !earnings.Rides[0].PaidOut || len(earnings.Payouts) != 1 || earnings.Payouts[0].Status != "succeeded" {
+		t.Fatalf("unexpected earnings after payout %+v", earnings)
+	}
+
+	// Отклонённая выплата освобождает заработок для следующего запуска
+	declined, err := svc.GetDriverEarnings(admin, &pb.GetDriverEarningsRequest{DriverId: "d2"})
+	if err != nil {
+		t.Fatalf("declined driver earnings: %v", err)
+	}
+	if declined.Unpaid != 80 || len(declined.Payouts) != 1 || declined.Payouts[0].Status != "failed" {
+		t.Fatalf("unexpected declined driver earnings %+v", declined)
+	}
+
+	wallet, err := svc.GetWallet(driver, &pb.GetWalletRequest{})
+	if err != nil {
+		t.Fatalf("driver wallet: %v", err)
+	}
+	if len(wallet.Accounts) != 1 || wallet.Accounts[0].Balance != 0 {
+		t.Fatalf("expected driver account to be settled, got %+v", wallet.Accounts)
+	}
+}
+
+func TestCurrencies(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	fp := fake.New(fake.Options{})
+	svc := New(repo, fp, Options{
+		CommissionRate: 0.2,
+		Payouts:        fp,
+		Currencies:     []string{"USD", "JPY"},
+		Rates:          &money.StaticRates{Base: "RUB", Rates: map[string]float64{"USD": 90}},
+	})
+	rooms := withRooms(svc)
+	rooms.complete("room-1", "d1", 900, "RUB", "u1")
+	rooms.complete("room-2", "d1", 10, "USD", "u2")
+	rooms.complete("room-3", "d2", 1000.4, "JPY", "u3")
+	ctx := loggerCtx(t)
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	admin := identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin})
+
+	for _, code := range []string{"XYZ", "EUR"} {
+		req := &pb.ProcessPaymentRequest{RoomId: "room-x", UserIds: []string{"u1"}, AmountPerUser: 100, Currency: code}
+		if _, err := svc.ProcessPayment(driver, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %s, got %v", code, err)
+		}
+	}
+	for _, tc := range []struct {
+		code   string
+		amount float64
+		want   string
+	}{{"SGD", 12.5, "12.50"}, {"JPY", 1500, "1500"}, {"KWD", 1.25, "1.250"}} {
+		if err := money.Validate(tc.code); err != nil {
+			t.Fatalf("expected %s to be a valid ISO 4217 code: %v", tc.code, err)
+		}
+		if got := money.Format(tc.amount, tc.code); got != tc.want {
+			t.Fatalf("expected %s for %s, got %s", tc.want, tc.code, got)
+		}
+	}
+
+	if _, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 900}); err != nil {
+		t.Fatalf("process RUB: %v", err)
+	}
+	usd, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-2", UserIds: []string{"u2"}, AmountPerUser: 10, Currency: "usd"})
+	if err != nil {
+		t.Fatalf("process USD: %v", err)
+	}
+	if usd.Payments[0].Currency != "USD" {
+		t.Fatalf("expected USD payment, got %+v", usd.Payments[0])
+	}
+	jpy, err := svc.ProcessPayment(admin, &pb.ProcessPaymentRequest{RoomId: "room-3", UserIds: []string{"u3"}, AmountPerUser: 1000.4, Currency: "JPY", DriverId: "d2"})
+	if err != nil {
+		t.Fatalf("process JPY: %v", err)
+	}
+	if jpy.Payments[0].Amount != 1000 {
+		t.Fatalf("expected JPY amount rounded to whole yen, got %v", jpy.Payments[0].Amount)
+	}
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u3", Role: identity.RoleRider})
+	wallet, err := svc.GetWallet(rider, &pb.GetWalletRequest{})
+	if err != nil || len(wallet.Accounts) != 1 || wallet.Accounts[0].AccountId != ledger.RiderAccount("u3", "JPY") {
+		t.Fatalf("expected per-currency rider account, got %+v, %v", wallet, err)
+	}
+
+	// Заработок в рублях и долларах сводится в валюту отчёта по курсу
+	earnings, err := svc.GetDriverEarnings(driver, &pb.GetDriverEarningsRequest{})
+	if err != nil {
+		t.Fatalf("earnings: %v", err)
+	}
+	if earnings.Currency != "RUB" || earnings.Gross != 1800 || earnings.Net != 1440 || len(earnings.Rides) != 2 {
+		t.Fatalf("unexpected earnings %+v", earnings)
+	}
+	for _, ride := range earnings.Rides {
+		if ride.RoomId == "room-2" && (ride.Currency != "USD" || ride.Gross != 10) {
+			t.Fatalf("expected ride in its own currency, got %+v", ride)
+		}
+	}
+	inUSD, err := svc.GetDriverEarnings(driver, &pb.GetDriverEarningsRequest{Currency: "USD"})
+	if err != nil || inUSD.Gross != 20 {
+		t.Fatalf("expected earnings converted to USD, got %+v, %v", inUSD, err)
+	}
+	if _, err := svc.GetDriverEarnings(admin, &pb.GetDriverEarningsRequest{DriverId: "d2"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition without JPY rate, got %v", err)
+	}
+	if _, err := svc.GetDriverEarnings(admin, &pb.GetDriverEarningsRequest{DriverId: "d2", Currency: "ABC"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unknown report currency, got %v", err)
+	}
+
+	report, err := svc.PayoutBatch(ctx, time.Now().Add(time.Minute))
+	if err != nil {
+		t.Fatalf("payout batch: %v", err)
+	}
+	if report.Succeeded != 3 || report.Amounts["RUB"] != 720 || report.Amounts["USD"] != 8 || report.Amounts["JPY"] != 800 {
+		t.Fatalf("expected payouts per currency, got %+v", report)
+	}
+}
+
+func TestPromoCodes(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	svc := New(repo, fake.New(fake.Options{}), Options{CommissionRate: 0.1})
+	rooms := withRooms(svc)
+	rooms.complete("room-1", "d1", 300, "RUB", "u1", "u2", "u3")
+	rooms.complete("room-2", "d1", 300, "RUB", "u5")
+	ctx := loggerCtx(t)
+	admin := identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin})
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	rider := func(id string) context.Context {
+		return identity.WithIdentity(ctx, identity.Identity{UserID: id, Role: identity.RoleRider})
+	}
+
+	for _, req := range []*pb.CreatePromoCodeRequest{
+		{Code: "first50", Type: "percent", Value: 50, FirstRideOnly: true},
+		{Code: "FIX100", Type: "fixed", Value: 100, MaxUses: 1},
+		{Code: "FREE", Type: "fixed", Value: 500},
+	} {
+		if _, err := svc.CreatePromoCode(admin, req); err != nil {
+			t.Fatalf("create %s: %v", req.Code, err)
+		}
+	}
+	for _, req := range []*pb.CreatePromoCodeRequest{
+		{Code: "BAD", Type: "bonus", Value: 10},
+		{Code: "BIG", Type: "percent", Value: 150},
+		{Code: "x", Type: "percent", Value: 10},
+		{Code: "LATE", Type: "percent", Value: 10, ValidFrom: "2026-02-01T00:00:00Z", ValidUntil: "2026-01-01T00:00:00Z"},
+	} {
+		if _, err := svc.CreatePromoCode(admin, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %+v, got %v", req, err)
+		}
+	}
+	if _, err := svc.CreatePromoCode(admin, &pb.CreatePromoCodeRequest{Code: "FREE", Type: "percent", Value: 10}); status.Code(err) != codes.AlreadyExists {
+		t.Fatalf("expected AlreadyExists, got %v", err)
+	}
+	if _, err := svc.CreatePromoCode(rider("u1"), &pb.CreatePromoCodeRequest{Code: "MINE", Type: "percent", Value: 10}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for rider, got %v", err)
+	}
+
+	apply := func(user, room, code string) error {
+		_, err := svc.ApplyPromoCode(rider(user), &pb.ApplyPromoCodeRequest{RoomId: room, Code: code})
+		return err
+	}
+	if err := apply("u1", "room-1", "FIRST50"); err != nil {
+		t.Fatalf("apply percent: %v", err)
+	}
+	// Код u4 занимает единственное место, пока u4 не выйдет из комнаты
+	if err := apply("u4", "room-4", "FIX100"); err != nil {
+		t.Fatalf("apply fixed: %v", err)
+	}
+	if err := apply("u2", "room-1", "fix100"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition while the code is applied elsewhere, got %v", err)
+	}
+	if _, err := svc.VoidPayment(rider("u4"), &pb.VoidPaymentRequest{RoomId: "room-4", UserId: "u4"}); err != nil {
+		t.Fatalf("void: %v", err)
+	}
+	if err := apply("u2", "room-1", "fix100"); err != nil {
+		t.Fatalf("apply fixed: %v", err)
+	}
+	if err := apply("u3", "room-1", "FIX100"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for exhausted code, got %v", err)
+	}
+	if err := apply("u3", "room-1", "NOPE"); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for unknown code, got %v", err)
+	}
+	if err := apply("u5", "room-2", "FREE"); err != nil {
+		t.Fatalf("apply free ride: %v", err)
+	}
+
+	resp, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u2", "u3"}, AmountPerUser: 300})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	paid := map[string]*pb.Payment{}
+	for _, p := range resp.Payments {
+		paid[p.UserId] = p
+	}
+	if paid["u1"].Amount != 150 || paid["u1"].Discount != 150 || paid["u1"].PromoCode != "FIRST50" ||
+		paid["u2"].Amount != 200 || paid["u2"].Discount != 100 || paid["u3"].Amount != 300 || paid["u3"].Discount != 0 {
+		t.Fatalf("unexpected discounted payments %+v", resp.Payments)
+	}
+
+	free, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-2", UserIds: []string{"u5"}, AmountPerUser: 300})
+	if err != nil {
+		t.Fatalf("process free ride: %v", err)
+	}
+	if p := free.Payments[0]; p.Amount != 0 || p.Discount != 300 || p.Status != provider.StatusSucceeded || p.YookassaPaymentId != "" {
+		t.Fatalf("expected free ride without provider payment, got %+v", p)
+	}
+
+	// Водитель получает заработок с полной доли, скидку оплачивает платформа
+	for _, e := range repo.earnings {
+		if e.Gross != 300 || e.Commission != 30 {
+			t.Fatalf("expected earnings on full share, got %+v", e)
+		}
+	}
+	var discounts float64
+	for _, tx := range repo.ledger {
+		if tx.Kind == ledger.KindDiscount {
+			if tx.Entries[0].AccountID != ledger.PromoAccount("RUB") {
+				t.Fatalf("unexpected discount entries %+v", tx.Entries)
+			}
+			discounts += tx.Entries[0].Amount
+		}
+	}
+	if len(repo.earnings) != 4 || discounts != 550 {
+		t.Fatalf("expected 4 earnings and 550 of discounts, got %d and %v", len(repo.earnings), discounts)
+	}
+
+	if err := apply("u1", "room-1", "FREE"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition after redemption, got %v", err)
+	}
+	repo.byRoom = repo.created
+	if err := apply("u1", "room-3", "FIRST50"); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for first-ride code, got %v", err)
+	}
+	list, err := svc.ListPromoCodes(admin, &pb.ListPromoCodesRequest{})
+	if err != nil || len(list.PromoCodes) != 3 {
+		t.Fatalf("unexpected promo codes %+v, %v", list, err)
+	}
+}
+
+func TestChargeFee(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	svc := New(repo, fake.New(fake.Options{}), Options{CommissionRate: 0.1})
+	ctx := loggerCtx(t)
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Role: identity.RoleRider})
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	other := identity.WithIdentity(ctx, identity.Identity{UserID: "d2", Role: identity.RoleDriver})
+	rooms := withRooms(svc)
+	rooms.fee("room-1", "d1", "u1", "cancellation", 100)
+	rooms.fee("room-2", "d1", "u1", "no_show", 200)
+
+	if _, err := svc.ChargeFee(rider, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u2", Type: "cancellation"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another rider, got %v", err)
+	}
+	if _, err := svc.ChargeFee(other, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u1", Type: "cancellation"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for a driver of another room, got %v", err)
+	}
+	if _, err := svc.ChargeFee(rider, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u1", Type: "late"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for unknown type, got %v", err)
+	}
+	if _, err := svc.ChargeFee(rider, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u1", Type: "no_show"}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for a fee not recorded in room, got %v", err)
+	}
+
+	resp, err := svc.ChargeFee(rider, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u1", Type: "cancellation"})
+	if err != nil {
+		t.Fatalf("charge cancellation fee: %v", err)
+	}
+	if p := resp.Payment; p.Kind != repository.PaymentCancellationFee || p.Amount != 100 || p.Status != provider.StatusSucceeded {
+		t.Fatalf("unexpected fee payment %+v", p)
+	}
+	again, err := svc.ChargeFee(rider, &pb.ChargeFeeRequest{RoomId: "room-1", UserId: "u1", Type: "cancellation"})
+	if err != nil || again.Payment.PaymentId != resp.Payment.PaymentId {
+		t.Fatalf("expected the fee to be charged once, got %+v, %v", again, err)
+	}
+	noShow, err := svc.ChargeFee(driver, &pb.ChargeFeeRequest{RoomId: "room-2", UserId: "u1", Type: "no_show"})
+	if err != nil || noShow.Payment.Kind != repository.PaymentNoShowFee {
+		t.Fatalf("charge no-show fee: %+v, %v", noShow, err)
+	}
+	if len(repo.earnings) != 2 || repo.earnings[0].DriverID != "d1" || repo.earnings[1].Net != 180 {
+		t.Fatalf("expected fees credited to the driver, got %+v", repo.earnings)
+	}
+
+	// Штрафы видны в истории, но не считаются оплатой доли в комнате
+	repo.byRoom = repo.created
+	history, err := svc.GetPaymentHistory(rider, &pb.GetPaymentHistoryRequest{})
+	if err != nil || len(history.Payments) != 2 || history.Payments[1].Kind != repository.PaymentNoShowFee {
+		t.Fatalf("expected fees in payment history, got %+v, %v", history, err)
+	}
+	repo.byRoom = repo.created[:1]
+	shares, err := svc.GetRoomPayments(driver, &pb.GetRoomPaymentsRequest{RoomId: "room-1"})
+	if err != nil || len(shares.Riders) != 0 {
+		t.Fatalf("expected fees excluded from rider shares, got %+v, %v", shares, err)
+	}
+}
+
+func TestProcessPaymentIdempotent(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	yk := &stubProvider{createErr: errors.New("timeout")}
+	svc := New(repo, yk, Options{})
+	room := withRooms(svc).complete("room-1", "d1", 100, "RUB", "u1")
+	ctx := identity.WithIdentity(loggerCtx(t), identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	req := &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 100}
+
+	if resp, err := svc.ProcessPayment(ctx, req); err != nil || resp.Success {
+		t.Fatalf("expected provider error in results, got %+v, %v", resp, err)
+	}
+	if len(repo.created) != 1 || repo.created[0].Status != "failed" {
+		t.Fatalf("expected failed payment to be kept for audit, got %+v", repo.created)
+	}
+
+	// Повтор после ошибки отправляет тот же платёж заново, без новой записи
+	yk.createErr = nil
+	first, err := svc.ProcessPayment(ctx, req)
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if len(repo.created) != 1 || first.Payments[0].PaymentId != repo.created[0].PaymentID || first.Payments[0].Status != "succeeded" {
+		t.Fatalf("expected retry to reuse the payment, got %+v", first.Payments)
+	}
+
+	replay, err := svc.ProcessPayment(ctx, req)
+	if err != nil {
+		t.Fatalf("replay: %v", err)
+	}
+	if len(repo.created) != 1 || replay.Payments[0].PaymentId != first.Payments[0].PaymentId {
+		t.Fatalf("expected stored payment to be replayed, got %+v", replay.Payments)
+	}
+	if len(repo.earnings) != 1 {
+		t.Fatalf("expected one earning, got %d", len(repo.earnings))
+	}
+
+	// Доля, сверяемая с комнатой, изменилась, а ключ — прежний
+	room.CostPerMember = 150
+	changed := &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1"}, AmountPerUser: 150}
+	conflict, err := svc.ProcessPayment(ctx, changed)
+	if err != nil || conflict.Results[0].Status != "failed" || !strings.Contains(conflict.Results[0].Error, "already used") {
+		t.Fatalf("expected conflict for a different amount, got %+v, %v", conflict, err)
+	}
+	changed.IdempotencyKey = "room-1-extra"
+	if _, err := svc.ProcessPayment(ctx, changed); err != nil {
+		t.Fatalf("process with a new key: %v", err)
+	}
+	if len(repo.created) != 2 {
+		t.Fatalf("expected a new payment for a new key, got %d", len(repo.created))
+	}
+}
+
+func TestProcessPaymentPartialSuccessAndRetry(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	fp := fake.New(fake.Options{DeclineUsers: []string{"u2"}})
+	svc := New(repo, fp, Options{})
+	withRooms(svc).complete("room-1", "d1", 100, "RUB", "u1", "u2", "u3")
+	ctx := loggerCtx(t)
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+
+	resp, err := svc.ProcessPayment(driver, &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u2", "u3"}, AmountPerUser: 100})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if resp.Success || resp.Status != "partial" || len(resp.Payments) != 3 || len(resp.Results) != 3 {
+		t.Fatalf("expected partial success, got %+v", resp)
+	}
+	if resp.Results[0].Status != "succeeded" || resp.Results[1].Status != "failed" || resp.Results[1].Error == "" || resp.Results[2].Status != "succeeded" {
+		t.Fatalf("unexpected per-user results %+v", resp.Results)
+	}
+
+	repo.byRoom = repo.created
+	if _, err := svc.RetryFailedPayments(asRole("d2", identity.RoleDriver), &pb.RetryFailedPaymentsRequest{RoomId: "room-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another driver, got %v", err)
+	}
+	fp.SetDecline("u2", false)
+	retry, err := svc.RetryFailedPayments(driver, &pb.RetryFailedPaymentsRequest{RoomId: "room-1"})
+	if err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+	if !retry.Success || len(retry.Results) != 1 || retry.Results[0].UserId != "u2" || retry.Results[0].Status != "succeeded" {
+		t.Fatalf("expected only the failed user to be retried, got %+v", retry)
+	}
+
+	repo.byRoom = repo.created
+	again, err := svc.RetryFailedPayments(driver, &pb.RetryFailedPaymentsRequest{RoomId: "room-1"})
+	if err != nil {
+		t.Fatalf("second retry: %v", err)
+	}
+	if len(again.Results) != 0 || len(repo.created) != 4 {
+		t.Fatalf("expected nothing to retry, got %+v and %d payments", again.Results, len(repo.created))
+	}
+}
+
+// slowProvider держит каждый платёж delay и запоминает, сколько платежей создавалось одновременно
+type slowProvider struct {
+	stubProvider
+	delay       time.Duration
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+}
+
+func (p *slowProvider) CreatePayment(ctx context.Context, key string, req provider.CreatePaymentRequest) (*provider.Payment, error) {
+	p.mu.Lock()
+	p.inFlight++
+	p.maxInFlight = max(p.maxInFlight, p.inFlight)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.inFlight--
+		p.mu.Unlock()
+	}()
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(p.delay):
+	}
+	return &provider.Payment{ID: "yk-" + req.Metadata["user_id"], Status: provider.StatusSucceeded}, nil
+}
+
+func TestProcessPaymentFansOutWithBoundedConcurrency(t *testing.T) {
+	repo := &fakePaymentRepo{}
+	yk := &slowProvider{delay: 20 * time.Millisecond}
+	svc := New(repo, yk, Options{Concurrency: 2})
+	users := []string{"u1", "u2", "u3", "u4", "u5"}
+	rooms := withRooms(svc)
+	rooms.complete("room-1", "d1", 100, "RUB", users...)
+	rooms.complete("room-2", "d1", 100, "RUB", users...)
+
+	resp, err := svc.ProcessPayment(asRole("d1", identity.RoleDriver), &pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: users, AmountPerUser: 100})
+	if err != nil {
+		t.Fatalf("process: %v", err)
+	}
+	if yk.maxInFlight != 2 {
+		t.Fatalf("expected 2 concurrent provider calls, got %d", yk.maxInFlight)
+	}
+	for i, r := range resp.Results {
+		if r.UserId != users[i] || r.Status != "succeeded" || r.Payment.YookassaPaymentId != "yk-"+users[i] {
+			t.Fatalf("unexpected result %d: %+v", i, r)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(asRole("d1", identity.RoleDriver))
+	cancel()
+	canceled, err := svc.ProcessPayment(ctx, &pb.ProcessPaymentRequest{RoomId: "room-2", UserIds: users, AmountPerUser: 100, IdempotencyKey: "canceled"})
+	if err != nil {
+		t.Fatalf("process with canceled context: %v", err)
+	}
+	if canceled.Status != "failed" {
+		t.Fatalf("expected canceled request to fail, got %+v", canceled.Status)
+	}
+}
+
+// temporaryError — как ответ ЮKassa 429/5xx
+type temporaryError struct{}
+
+func (temporaryError) Error() string   { return "too many requests" }
+func (temporaryError) Temporary() bool { return true }
+
+// flakyProvider отвечает ошибкой errs[i] на i-й вызов, потом — успехом
+type flakyProvider struct {
+	stubProvider
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) CreatePayment(context.Context, string, provider.CreatePaymentRequest) (*provider.Payment, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &provider.Payment{ID: "yk1", Status: provider.StatusSucceeded}, nil
+}
+
+func TestLimitedProviderRetriesTemporaryErrors(t *testing.T) {
+	limits := provider.Limits{MaxRetries: 2, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	ctx := context.Background()
+
+	flaky := &flakyProvider{errs: []error{temporaryError{}, temporaryError{}}}
+	if _, err := provider.Limited(flaky, limits).CreatePayment(ctx, "k", provider.CreatePaymentRequest{}); err != nil || flaky.calls != 3 {
+		t.Fatalf("expected success on the third call, got %v after %d calls", err, flaky.calls)
+	}
+
+	exhausted := &flakyProvider{errs: []error{temporaryError{}, temporaryError{}, temporaryError{}}}
+	if _, err := provider.Limited(exhausted, limits).CreatePayment(ctx, "k", provider.CreatePaymentRequest{}); !provider.Temporary(err) || exhausted.calls != 3 {
+		t.Fatalf("expected retries to stop after MaxRetries, got %v after %d calls", err, exhausted.calls)
+	}
+
+	declined := &flakyProvider{errs: []error{errors.New("invalid card")}}
+	if _, err := provider.Limited(declined, limits).CreatePayment(ctx, "k", provider.CreatePaymentRequest{}); err == nil || declined.calls != 1 {
+		t.Fatalf("expected permanent error without retries, got %v after %d calls", err, declined.calls)
+	}
+
+	// Токены общие для всех запросов: 3 запроса при 50 RPS без запаса занимают не меньше 40ms
+	limited := provider.Limited(&flakyProvider{}, provider.Limits{RPS: 50, Burst: 1})
+	start := time.Now()
+	for range 3 {
+		if _, err := limited.CreatePayment(ctx, "k", provider.CreatePaymentRequest{}); err != nil {
+			t.Fatalf("limited call: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 35*time.Millisecond {
+		t.Fatalf("expected rate limit to delay requests, took %v", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	slow := provider.Limited(&flakyProvider{}, provider.Limits{RPS: 0.001, Burst: 1})
+	slow.CreatePayment(ctx, "k", provider.CreatePaymentRequest{})
+	if _, err := slow.CreatePayment(canceled, "k", provider.CreatePaymentRequest{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected waiting for a token to stop on cancel, got %v", err)
+	}
+}
+
+func TestRoomPaymentsAndReminders(t *testing.T) {
+	ctx := loggerCtx(t)
+	old := time.Now().Add(-2 * time.Hour)
+	repo := &fakePaymentRepo{byRoom: []*repository.PaymentRecord{
+		{PaymentID: "p1", RoomID: "room-1", UserID: "u1", DriverID: "d1", Amount: 150, Currency: "RUB", Status: "succeeded", CreatedAt: old},
+		{PaymentID: "p2", RoomID: "room-1", UserID: "u2", DriverID: "d1", Amount: 150, Currency: "RUB", Status: "pending",
+			ConfirmationURL: "https://pay.example/p2", CreatedAt: old},
+		{PaymentID: "p3", RoomID: "room-1", UserID: "u3", DriverID: "d1", Amount: 150, Currency: "RUB", Status: "canceled", CreatedAt: old},
+	}}
+	svc := New(repo, &stubProvider{}, Options{})
+
+	resp, err := svc.GetRoomPayments(identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver}),
+		&pb.GetRoomPaymentsRequest{RoomId: "room-1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Paid != 1 || resp.Unpaid != 2 || len(resp.Riders) != 3 {
+		t.Fatalf("expected 1 paid and 2 unpaid riders, got %+v", resp)
+	}
+	if r := resp.Riders[1]; r.Status != "pending" || r.ConfirmationUrl != "https://pay.example/p2" {
+		t.Fatalf("expected pending share with confirmation url, got %+v", r)
+	}
+	if resp.Riders[0].Status != "paid" || resp.Riders[0].PaidAmount != 150 || resp.Riders[2].Status != "failed" {
+		t.Fatalf("unexpected shares: %+v", resp.Riders)
+	}
+
+	// Пассажир видит только свою долю, чужой водитель — ничего
+	own, err := svc.GetRoomPayments(identity.WithIdentity(ctx, identity.Identity{UserID: "u2", Role: identity.RoleRider}),
+		&pb.GetRoomPaymentsRequest{RoomId: "room-1"})
+	if err != nil || len(own.Riders) != 1 || own.Riders[0].UserId != "u2" {
+		t.Fatalf("expected only own share, got %+v, %v", own, err)
+	}
+	if _, err := svc.GetRoomPayments(identity.WithIdentity(ctx, identity.Identity{UserID: "d2", Role: identity.RoleDriver}),
+		&pb.GetRoomPaymentsRequest{RoomId: "room-1"}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound for another driver, got %v", err)
+	}
+
+	updates, cancel := svc.updates.Subscribe("u2")
+	defer cancel()
+	opts := ReminderOptions{After: time.Hour, Max: 1, BatchSize: 10}
+	sent, err := svc.SendReminders(ctx, opts)
+	if err != nil || sent != 1 {
+		t.Fatalf("expected one reminder, got %d, %v", sent, err)
+	}
+	select {
+	case u := <-updates:
+		if u.Event != EventPaymentReminder || u.PaymentId != "p2" || u.ConfirmationUrl != "https://pay.example/p2" {
+			t.Fatalf("unexpected reminder: %+v", u)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reminder was not published")
+	}
+	// Лимит напоминаний исчерпан
+	if sent, _ := svc.SendReminders(ctx, opts); sent != 0 {
+		t.Fatalf("expected no reminders after the limit, got %d", sent)
+	}
+}
+
+// receiptProvider запоминает чеки, переданные с платежами и возвратами
+type receiptProvider struct {
+	stubProvider
+	receipts []*provider.Receipt
+}
+
+func (p *receiptProvider) CreatePayment(ctx context.Context, key string, req provider.CreatePaymentRequest) (*provider.Payment, error) {
+	p.receipts = append(p.receipts, req.Receipt)
+	return p.stubProvider.CreatePayment(ctx, key, req)
+}
+
+func (p *receiptProvider) CreateRefund(ctx context.Context, key string, req provider.RefundRequest) (*provider.Refund, error) {
+	p.receipts = append(p.receipts, req.Receipt)
+	return p.stubProvider.CreateRefund(ctx, key, req)
+}
+
+func TestReceipts(t *testing.T) {
+	ctx := loggerCtx(t)
+	repo := &fakePaymentRepo{}
+	prov := &receiptProvider{}
+	svc := New(repo, prov, Options{Receipts: ReceiptOptions{Enabled: true, VatCode: 1, SellerName: "WeRide", SellerINN: "7700000000"}})
+	withRooms(svc).complete("room-1", "d1", 150, "RUB", "u1", "u2")
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Email: "u1@example.com", Role: identity.RoleRider})
+
+	if _, err := svc.SetReceiptContact(rider, &pb.SetReceiptContactRequest{Phone: "123"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for short phone, got %v", err)
+	}
+	contact, err := svc.SetReceiptContact(rider, &pb.SetReceiptContactRequest{Email: "u1@example.com", Phone: "+7 (900) 123-45-67"})
+	if err != nil || contact.Phone != "79001234567" {
+		t.Fatalf("expected normalized phone, got %+v, %v", contact, err)
+	}
+
+	resp, err := svc.ProcessPayment(identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin}),
+		&pb.ProcessPaymentRequest{RoomId: "room-1", UserIds: []string{"u1", "u2"}, AmountPerUser: 150})
+	if err != nil || !resp.Success {
+		t.Fatalf("unexpected result: %+v, %v", resp, err)
+	}
+	// У u2 нет контакта: платёж уходит без чека
+	if len(prov.receipts) != 2 || prov.receipts[1] != nil {
+		t.Fatalf("expected receipt only for u1, got %+v", prov.receipts)
+	}
+	r := prov.receipts[0]
+	if r.Email != "u1@example.com" || r.Phone != "79001234567" || len(r.Items) != 1 || r.Items[0].Amount != 150 || r.Items[0].VatCode != 1 {
+		t.Fatalf("unexpected receipt: %+v", r)
+	}
+
+	paymentID := resp.Payments[0].PaymentId
+	repo.byRoom = repo.created
+	if _, err := svc.RefundPayment(identity.WithIdentity(ctx, identity.Identity{UserID: "a1", Role: identity.RoleAdmin}),
+		&pb.RefundPaymentRequest{PaymentIds: []string{paymentID}, Amount: 50}); err != nil {
+		t.Fatalf("unexpected refund error: %v", err)
+	}
+	if last := prov.receipts[len(prov.receipts)-1]; last == nil || last.Items[0].Amount != 50 {
+		t.Fatalf("expected refund receipt for 50, got %+v", last)
+	}
+
+	receipt, err := svc.GetReceipt(rider, &pb.GetReceiptRequest{PaymentId: paymentID})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(receipt.Receipts) != 2 || receipt.Receipts[0].Type != "payment" || receipt.Receipts[1].Type != "refund" {
+		t.Fatalf("expected payment and refund receipts, got %+v", receipt.Receipts)
+	}
+	for _, want := range []string{"Приход", "Возврат прихода", "ИНН 7700000000", "150,00", "50,00"} {
+		if !strings.Contains(receipt.Html, want) {
+			t.Fatalf("expected %q in rendered receipt", want)
+		}
+	}
+	if _, err := svc.GetReceipt(identity.WithIdentity(ctx, identity.Identity{UserID: "u2"}), &pb.GetReceiptRequest{PaymentId: paymentID}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another rider, got %v", err)
+	}
+}
+
+// fakeUsers — user_service с маршрутами поездок
+type fakeUsers struct {
+	authpb.AuthClient
+	routes []*authpb.Route
+	err    error
+}
+
+func (f *fakeUsers) HistoryOfRoutes(_ context.Context, req *authpb.HistoryOfRoutesRequest, _ ...grpc.CallOption) (*authpb.HistoryOfRoutesResponse, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	resp := &authpb.HistoryOfRoutesResponse{}
+	for _, r := range f.routes {
+		if slices.Contains(req.RoomIds, r.RoomId) {
+			resp.Routes = append(resp.Routes, r)
+		}
+	}
+	return resp, nil
+}
+
+// awaitStatement ждёт, пока фоновый рендер завершит выписку
+func awaitStatement(t *testing.T, svc *PaymentService, ctx context.Context, statementID string) *pb.Statement {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+		resp, err := svc.GetStatement(ctx, &pb.GetStatementRequest{StatementId: statementID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Statement.Status != repository.StatementPending {
+			return resp.Statement
+		}
+	}
+	t.Fatalf("statement %s is still pending", statementID)
+	return nil
+}
+
+func TestStatements(t *testing.T) {
+	ctx := loggerCtx(t)
+	at := time.Date(2026, 9, 14, 10, 0, 0, 0, time.UTC)
+	repo := &fakePaymentRepo{
+		byRoom: []*repository.PaymentRecord{
+			{PaymentID: "p3", RoomID: "room-3", UserID: "u1", Amount: 500, Currency: "RUB", Status: "failed", Kind: repository.PaymentRide, CreatedAt: at},
+			{PaymentID: "p2", RoomID: "room-2", UserID: "u1", Amount: 200, Currency: "RUB", Status: "succeeded", Kind: repository.PaymentNoShowFee, CreatedAt: at},
+			{PaymentID: "p1", RoomID: "room-1", UserID: "u1", Amount: 300, Discount: 50, PromoCode: "WELCOME", Currency: "RUB", Status: "partially_refunded", Kind: repository.PaymentRide, CreatedAt: at.AddDate(0, 0, -3)},
+		},
+		refunds: []*repository.RefundRecord{{RefundID: "r1", PaymentID: "p1", Amount: 100, Status: "succeeded"}},
+		earnings: []*repository.EarningRecord{
+			{EarningID: "e1", DriverID: "d1", RoomID: "room-1", PaymentID: "p1", Gross: 300, Commission: 45, Net: 255, Currency: "RUB", PayoutID: "po1", CreatedAt: at},
+			{EarningID: "e2", DriverID: "d1", RoomID: "room-1", PaymentID: "p4", Gross: 300, Commission: 45, Net: 255, Currency: "RUB", CreatedAt: at},
+		},
+	}
+	dir := t.TempDir()
+	svc := New(repo, nil, Options{StatementsDir: dir})
+	users := &fakeUsers{routes: []*authpb.Route{{RoomId: "room-1", StartPoint: "ул. Ленина, 1", EndPoint: "пр. Мира, 5"}}}
+	svc.userClient = users
+	rider := identity.WithIdentity(ctx, identity.Identity{UserID: "u1", Role: identity.RoleRider})
+
+	for _, req := range []*pb.RequestStatementRequest{
+		{Month: "2026-13"},
+		{Month: "2026/09"},
+		{Month: "2026-09", Format: "xlsx"},
+		{Month: "2026-09", Role: "admin"},
+		{Month: time.Now().AddDate(0, 2, 0).Format("2006-01")},
+	} {
+		if _, err := svc.RequestStatement(rider, req); status.Code(err) != codes.InvalidArgument {
+			t.Fatalf("expected InvalidArgument for %+v, got %v", req, err)
+		}
+	}
+	if _, err := svc.RequestStatement(rider, &pb.RequestStatementRequest{Month: "2026-09", Role: identity.RoleDriver}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for rider earnings statement, got %v", err)
+	}
+	if _, err := svc.RequestStatement(rider, &pb.RequestStatementRequest{UserId: "u2", Month: "2026-09"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user, got %v", err)
+	}
+
+	resp, err := svc.RequestStatement(rider, &pb.RequestStatementRequest{Month: "2026-09"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if st := resp.Statement; st.Status != repository.StatementPending || st.Format != "csv" || st.Role != identity.RoleRider || st.Month != "2026-09" {
+		t.Fatalf("unexpected statement: %+v", st)
+	}
+	if st := awaitStatement(t, svc, rider, resp.Statement.StatementId); st.Status != repository.StatementReady || st.FinishedAt == nil {
+		t.Fatalf("expected ready statement, got %+v", st)
+	}
+	if month := repo.filter.CreatedFrom; !month.Equal(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC)) || !repo.filter.CreatedTo.Equal(month.AddDate(0, 1, 0)) {
+		t.Fatalf("expected payments of September, got %+v", repo.filter)
+	}
+	file, err := svc.DownloadStatement(rider, &pb.DownloadStatementRequest{StatementId: resp.Statement.StatementId})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	csv := string(file.Content)
+	if file.FileName != "statement-rider-2026-09.csv" || !strings.HasPrefix(file.ContentType, "text/csv") {
+		t.Fatalf("unexpected file: %s, %s", file.FileName, file.ContentType)
+	}
+	// Платежи по порядку, неоплаченный не попадает; итоги — списано и возвращено
+	for _, want := range []string{
+		"2026-09-11,room-1,\"ул. Ленина, 1 — пр. Мира, 5\",Поездка,300.00,50.00,RUB,частично возвращён\n2026-09-14,room-2,,Штраф за неявку,200.00,,RUB,оплачен",
+		"Итого оплачено,,,,500.00,,RUB,",
+		"Возвращено,,,,100.00,,RUB,",
+	} {
+		if !strings.Contains(csv, want) {
+			t.Fatalf("expected %q in statement:\n%s", want, csv)
+		}
+	}
+	if strings.Contains(csv, "room-3") {
+		t.Fatalf("unpaid payment must not be in statement:\n%s", csv)
+	}
+
+	stranger := identity.WithIdentity(ctx, identity.Identity{UserID: "u2"})
+	if _, err := svc.DownloadStatement(stranger, &pb.DownloadStatementRequest{StatementId: resp.Statement.StatementId}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another user, got %v", err)
+	}
+	if _, err := svc.GetStatement(rider, &pb.GetStatementRequest{StatementId: uuid.NewString()}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+
+	// Без маршрутов выписка всё равно готова
+	users.err = errors.New("user service is down")
+	pdf, err := svc.RequestStatement(rider, &pb.RequestStatementRequest{Month: "2026-09", Format: "pdf"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	awaitStatement(t, svc, rider, pdf.Statement.StatementId)
+	file, err = svc.DownloadStatement(rider, &pb.DownloadStatementRequest{StatementId: pdf.Statement.StatementId})
+	if err != nil || file.ContentType != "application/pdf" || !strings.HasPrefix(string(file.Content), "%PDF-") ||
+		!strings.Contains(string(file.Content), "/FontFile2") {
+		t.Fatalf("expected pdf statement with an embedded font, got %+v, %v", file, err)
+	}
+	users.err = nil
+
+	driver := identity.WithIdentity(ctx, identity.Identity{UserID: "d1", Role: identity.RoleDriver})
+	earnings, err := svc.RequestStatement(driver, &pb.RequestStatementRequest{Month: "2026-09", Role: identity.RoleDriver})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	awaitStatement(t, svc, driver, earnings.Statement.StatementId)
+	file, err = svc.DownloadStatement(driver, &pb.DownloadStatementRequest{StatementId: earnings.Statement.StatementId})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Две оплаты одной поездки — одна строка; выплачена только одна из них
+	if want := "2026-09-14,room-1,\"ул. Ленина, 1 — пр. Мира, 5\",600.00,90.00,510.00,RUB,нет\n\nИтого,,,600.00,90.00,510.00,RUB,"; !strings.Contains(string(file.Content), want) {
+		t.Fatalf("expected %q in statement:\n%s", want, file.Content)
+	}
+
+	// Удаление аккаунта удаляет выписки вместе с файлами
+	if _, err := svc.AnonymizeUserPayments(rider, &pb.AnonymizeUserPaymentsRequest{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := svc.GetStatement(rider, &pb.GetStatementRequest{StatementId: resp.Statement.StatementId}); status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound after anonymization, got %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, resp.Statement.StatementId+".csv")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected statement file to be removed, got %v", err)
 	}
 }
@@ -0,0 +1,382 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/repository"
+	"we_ride/internal/services/payment_service/internal/statement"
+	pb "we_ride/internal/services/payment_service/pb"
+	authpb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+// statementMonth — формат месяца выписки
+const statementMonth = "2006-01"
+
+// maxStatementRows — сколько платежей пассажира попадает в выписку за месяц
+const maxStatementRows = 10000
+
+// Назначение платежа и статус в выписке пассажира
+var (
+	statementKinds = map[string]string{
+		repository.PaymentRide:            "Поездка",
+		repository.PaymentCancellationFee: "Штраф за отмену",
+		repository.PaymentNoShowFee:       "Штраф за неявку",
+	}
+	statementStatuses = map[string]string{
+		"succeeded":          "оплачен",
+		"partially_refunded": "частично возвращён",
+		"refunded":           "возвращён",
+	}
+)
+
+// RequestStatement ставит в очередь выписку вызывающего за месяц: пассажиру — оплаченные поездки
+// и штрафы, водителю — заработок по поездкам. Файл рендерится в фоне от имени вызывающего:
+// маршруты поездок запрашиваются у user_service с его токеном.
+func (s *PaymentService) RequestStatement(ctx context.Context, req *pb.RequestStatementRequest) (*pb.RequestStatementResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	st := &repository.StatementRecord{UserID: userID, Role: req.Role, Format: req.Format}
+	switch st.Role {
+	case "":
+		st.Role = identity.RoleRider
+	case identity.RoleRider:
+	case identity.RoleDriver:
+		if !caller.HasRole(identity.RoleDriver, identity.RoleAdmin) {
+			return nil, status.Error(codes.PermissionDenied, "insufficient role")
+		}
+	default:
+		return nil, status.Error(codes.InvalidArgument, "role must be rider or driver")
+	}
+	switch st.Format {
+	case "":
+		st.Format = statement.FormatCSV
+	case statement.FormatCSV, statement.FormatPDF:
+	default:
+		return nil, status.Error(codes.InvalidArgument, "format must be csv or pdf")
+	}
+	if st.Month, err = time.Parse(statementMonth, req.Month); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "month must be YYYY-MM")
+	}
+	if st.Month.After(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "month must not be in the future")
+	}
+
+	if err := s.repo.CreateStatement(ctx, st); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to save statement: %v", err)
+	}
+	// Рендерим после ответа, но от имени вызывающего: токен остаётся в контексте
+	go s.renderStatement(context.WithoutCancel(ctx), st)
+	return &pb.RequestStatementResponse{Statement: toPBStatement(st)}, nil
+}
+
+// GetStatement — состояние выписки; владельцу и admin
+func (s *PaymentService) GetStatement(ctx context.Context, req *pb.GetStatementRequest) (*pb.GetStatementResponse, error) {
+	st, err := s.ownStatement(ctx, req.StatementId)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetStatementResponse{Statement: toPBStatement(st)}, nil
+}
+
+// DownloadStatement отдаёт файл готовой выписки; владельцу и admin
+func (s *PaymentService) DownloadStatement(ctx context.Context, req *pb.DownloadStatementRequest) (*pb.DownloadStatementResponse, error) {
+	st, err := s.ownStatement(ctx, req.StatementId)
+	if err != nil {
+		return nil, err
+	}
+	switch st.Status {
+	case repository.StatementPending:
+		return nil, status.Error(codes.FailedPrecondition, "statement is not ready yet")
+	case repository.StatementFailed:
+		return nil, status.Errorf(codes.FailedPrecondition, "statement failed: %s", st.Error)
+	}
+	content, err := os.ReadFile(filepath.Join(s.opts.StatementsDir, st.FileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, status.Error(codes.NotFound, "statement file not found, request it again")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read statement: %v", err)
+	}
+	return &pb.DownloadStatementResponse{
+		FileName:    fmt.Sprintf("statement-%s-%s.%s", st.Role, st.Month.Format(statementMonth), st.Format),
+		ContentType: statement.ContentType(st.Format),
+		Content:     content,
+	}, nil
+}
+
+// ownStatement возвращает выписку, если вызывающий — её владелец или admin
+func (s *PaymentService) ownStatement(ctx context.Context, statementID string) (*repository.StatementRecord, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uuid.Parse(statementID); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid statement_id")
+	}
+	st, err := s.repo.GetStatement(ctx, statementID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return nil, status.Error(codes.NotFound, "statement not found")
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get statement: %v", err)
+	}
+	if st.UserID != caller.UserID && !caller.HasRole(identity.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "cannot act on behalf of another user")
+	}
+	return st, nil
+}
+
+// renderStatement собирает выписку, пишет файл в хранилище и отмечает её готовой или неудачной
+func (s *PaymentService) renderStatement(ctx context.Context, st *repository.StatementRecord) {
+	state, fileName, errMsg := repository.StatementReady, st.StatementID+"."+st.Format, ""
+	if err := s.writeStatement(ctx, st, fileName); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to render statement",
+			zap.String("statement_id", st.StatementID), zap.Error(err))
+		state, fileName, errMsg = repository.StatementFailed, "", err.Error()
+	}
+	if err := s.repo.FinishStatement(ctx, st.StatementID, state, fileName, errMsg); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to finish statement",
+			zap.String("statement_id", st.StatementID), zap.Error(err))
+	}
+}
+
+func (s *PaymentService) writeStatement(ctx context.Context, st *repository.StatementRecord, fileName string) error {
+	var table statement.Table
+	var err error
+	if st.Role == identity.RoleDriver {
+		table, err = s.driverStatement(ctx, st)
+	} else {
+		table, err = s.riderStatement(ctx, st)
+	}
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := statement.Write(&buf, st.Format, table); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.opts.StatementsDir, 0o750); err != nil {
+		return fmt.Errorf("create statements dir: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(s.opts.StatementsDir, fileName), buf.Bytes(), 0o640); err != nil {
+		return fmt.Errorf("write statement: %w", err)
+	}
+	return nil
+}
+
+// riderStatement — оплаченные платежи пассажира за месяц с маршрутами поездок и итоги по валютам
+func (s *PaymentService) riderStatement(ctx context.Context, st *repository.StatementRecord) (statement.Table, error) {
+	f := repository.PaymentFilter{
+		UserIDs:     []string{st.UserID},
+		CreatedFrom: st.Month,
+		CreatedTo:   st.Month.AddDate(0, 1, 0),
+		Limit:       maxStatementRows,
+	}
+	payments, err := s.repo.SearchPayments(ctx, f)
+	if err != nil {
+		return statement.Table{}, fmt.Errorf("get payments: %w", err)
+	}
+	totals, err := s.repo.PaymentTotals(ctx, f)
+	if err != nil {
+		return statement.Table{}, fmt.Errorf("get payment totals: %w", err)
+	}
+
+	var paid []*repository.PaymentRecord
+	var roomIDs []string
+	for _, p := range payments {
+		if refundable(p.Status) || p.Status == "refunded" {
+			paid = append(paid, p)
+			roomIDs = append(roomIDs, p.RoomID)
+		}
+	}
+	routes := s.statementRoutes(ctx, roomIDs)
+
+	t := statement.Table{
+		Title:  fmt.Sprintf("Выписка за %s: поездки пассажира %s", st.Month.Format(statementMonth), st.UserID),
+		Header: []string{"Дата", "Поездка", "Маршрут", "Назначение", "Сумма", "Скидка", "Валюта", "Статус"},
+	}
+	// Платежи отсортированы от новых к старым, в выписке — по порядку
+	for i := len(paid) - 1; i >= 0; i-- {
+		p := paid[i]
+		discount := ""
+		if p.Discount > 0 {
+			discount = money.Format(p.Discount, p.Currency)
+		}
+		t.Rows = append(t.Rows, []string{
+			p.CreatedAt.UTC().Format(time.DateOnly), p.RoomID, routes[p.RoomID], statementKinds[p.Kind],
+			money.Format(p.Amount, p.Currency), discount, p.Currency, statementStatuses[p.Status],
+		})
+	}
+	for _, total := range totals {
+		if total.Paid == 0 {
+			continue
+		}
+		t.Totals = append(t.Totals,
+			[]string{"Итого оплачено", "", "", "", money.Format(total.Spent, total.Currency), "", total.Currency, ""},
+			[]string{"Возвращено", "", "", "", money.Format(total.Refunded, total.Currency), "", total.Currency, ""},
+		)
+	}
+	return t, nil
+}
+
+// driverStatement — заработок водителя за месяц по поездкам с маршрутами и итоги по валютам
+func (s *PaymentService) driverStatement(ctx context.Context, st *repository.StatementRecord) (statement.Table, error) {
+	earnings, err := s.repo.ListDriverEarnings(ctx, st.UserID, st.Month, st.Month.AddDate(0, 1, 0))
+	if err != nil {
+		return statement.Table{}, fmt.Errorf("get earnings: %w", err)
+	}
+
+	type ride struct {
+		earnedAt               time.Time
+		roomID, currency       string
+		gross, commission, net float64
+		paidOut                bool
+	}
+	var rides []*ride
+	byRoom := map[string]*ride{}
+	totals := map[string]*ride{}
+	var currencies, roomIDs []string
+	for _, e := range earnings {
+		r, ok := byRoom[e.RoomID+e.Currency]
+		if !ok {
+			r = &ride{earnedAt: e.CreatedAt, roomID: e.RoomID, currency: e.Currency, paidOut: true}
+			byRoom[e.RoomID+e.Currency] = r
+			rides = append(rides, r)
+			roomIDs = append(roomIDs, e.RoomID)
+		}
+		total, ok := totals[e.Currency]
+		if !ok {
+			total = &ride{currency: e.Currency}
+			totals[e.Currency] = total
+			currencies = append(currencies, e.Currency)
+		}
+		for _, sum := range []*ride{r, total} {
+			sum.gross += e.Gross
+			sum.commission += e.Commission
+			sum.net += e.Net
+		}
+		r.paidOut = r.paidOut && e.PayoutID != ""
+	}
+	routes := s.statementRoutes(ctx, roomIDs)
+
+	t := statement.Table{
+		Title:  fmt.Sprintf("Выписка за %s: заработок водителя %s", st.Month.Format(statementMonth), st.UserID),
+		Header: []string{"Дата", "Поездка", "Маршрут", "Оплачено пассажирами", "Комиссия", "К выплате", "Валюта", "Выплачено"},
+	}
+	// Заработок отсортирован от новых к старым, в выписке — по порядку
+	for i := len(rides) - 1; i >= 0; i-- {
+		r := rides[i]
+		paidOut := "нет"
+		if r.paidOut {
+			paidOut = "да"
+		}
+		t.Rows = append(t.Rows, []string{
+			r.earnedAt.UTC().Format(time.DateOnly), r.roomID, routes[r.roomID],
+			money.Format(r.gross, r.currency), money.Format(r.commission, r.currency), money.Format(r.net, r.currency),
+			r.currency, paidOut,
+		})
+	}
+	for _, cur := range currencies {
+		total := totals[cur]
+		t.Totals = append(t.Totals, []string{
+			"Итого", "", "", money.Format(total.gross, cur), money.Format(total.commission, cur), money.Format(total.net, cur), cur, "",
+		})
+	}
+	return t, nil
+}
+
+// statementRoutes — маршруты поездок по room_id из user_service в виде «откуда — куда».
+// Без маршрутов выписка всё равно нужна, поэтому ошибка только логируется.
+func (s *PaymentService) statementRoutes(ctx context.Context, roomIDs []string) map[string]string {
+	routes := map[string]string{}
+	if len(roomIDs) == 0 {
+		return routes
+	}
+	client, closeConn, err := s.users()
+	if err == nil {
+		defer closeConn()
+		var resp *authpb.HistoryOfRoutesResponse
+		if resp, err = client.HistoryOfRoutes(ctx, &authpb.HistoryOfRoutesRequest{RoomIds: roomIDs}); err == nil {
+			for _, r := range resp.GetRoutes() {
+				routes[r.GetRoomId()] = r.GetStartPoint() + " — " + r.GetEndPoint()
+			}
+			return routes
+		}
+	}
+	logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to get routes for statement, rendering without them", zap.Error(err))
+	return routes
+}
+
+// users открывает соединение с user_service от имени вызывающего; closeConn закрывает его
+func (s *PaymentService) users() (client authpb.AuthClient, closeConn func(), err error) {
+	if s.userClient != nil {
+		return s.userClient, func() {}, nil
+	}
+	if s.opts.UserServiceAddr == "" {
+		return nil, nil, errors.New("user service address is not configured")
+	}
+
+	conn, err := grpc.NewClient(s.opts.UserServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to user service: %w", err)
+	}
+	return authpb.NewAuthClient(conn), func() { _ = conn.Close() }, nil
+}
+
+// deleteStatements удаляет выписки пользователя вместе с файлами: в них адреса поездок
+func (s *PaymentService) deleteStatements(ctx context.Context, userID string) error {
+	files, err := s.repo.DeleteStatements(ctx, userID)
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		if err := os.Remove(filepath.Join(s.opts.StatementsDir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to remove statement file", zap.String("file", name), zap.Error(err))
+		}
+	}
+	return nil
+}
+
+func toPBStatement(st *repository.StatementRecord) *pb.Statement {
+	out := &pb.Statement{
+		StatementId: st.StatementID,
+		UserId:      st.UserID,
+		Role:        st.Role,
+		Month:       st.Month.Format(statementMonth),
+		Format:      st.Format,
+		Status:      st.Status,
+		Error:       st.Error,
+		CreatedAt:   timestamppb.New(st.CreatedAt),
+	}
+	if !st.FinishedAt.IsZero() {
+		out.FinishedAt = timestamppb.New(st.FinishedAt)
+	}
+	return out
+}
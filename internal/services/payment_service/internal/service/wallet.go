@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// GetWallet — счета пользователя во внутреннем журнале с балансами и последние проводки по ним
+func (s *PaymentService) GetWallet(ctx context.Context, req *pb.GetWalletRequest) (*pb.GetWalletResponse, error) {
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	limit := defaultSearchLimit
+	if req.Limit > 0 {
+		limit = min(int(req.Limit), maxSearchLimit)
+	}
+
+	balances, err := s.repo.GetAccountBalances(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get balances: %v", err)
+	}
+	resp := &pb.GetWalletResponse{Accounts: make([]*pb.Account, 0, len(balances))}
+	if len(balances) == 0 {
+		return resp, nil
+	}
+
+	accountIDs := make([]string, 0, len(balances))
+	for _, b := range balances {
+		accountIDs = append(accountIDs, b.AccountID)
+		resp.Accounts = append(resp.Accounts, &pb.Account{
+			AccountId: b.AccountID,
+			Type:      b.AccountType,
+			Currency:  b.Currency,
+			Balance:   float32(b.Balance),
+		})
+	}
+	entries, err := s.repo.ListAccountEntries(ctx, accountIDs, limit)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get ledger entries: %v", err)
+	}
+	for _, e := range entries {
+		resp.Entries = append(resp.Entries, toPBEntry(e))
+	}
+	return resp, nil
+}
+
+// post записывает транзакцию в журнал. Повторная запись того же основания ничего не меняет,
+// поэтому вызывается при каждом подтверждении движения денег, в том числе из уведомлений.
+func (s *PaymentService) post(ctx context.Context, t ledger.Transaction) error {
+	_, err := s.repo.PostTransaction(ctx, &t)
+	return err
+}
+
+// recordCharge проводит подтверждённый провайдером платёж и скидку по промокоду за счёт платформы,
+// а если у поездки есть водитель — начисляет ему заработок с полной доли за вычетом комиссии.
+// Повторный вызов ничего не меняет.
+func (s *PaymentService) recordCharge(ctx context.Context, p *repository.PaymentRecord, amount float64) error {
+	if money.ToMinor(amount, p.Currency) > 0 {
+		if err := s.post(ctx, ledger.Charge(p.UserID, p.PaymentID, amount, p.Currency)); err != nil {
+			return err
+		}
+	}
+	if money.ToMinor(p.Discount, p.Currency) > 0 {
+		if err := s.post(ctx, ledger.Discount(p.UserID, p.PaymentID, p.Discount, p.Currency)); err != nil {
+			return err
+		}
+	}
+	if p.DriverID == "" {
+		return nil
+	}
+
+	gross := money.Round(amount+p.Discount, p.Currency)
+	commission := money.Round(gross*s.opts.CommissionRate, p.Currency)
+	earning := &repository.EarningRecord{
+		DriverID:   p.DriverID,
+		RoomID:     p.RoomID,
+		PaymentID:  p.PaymentID,
+		Gross:      gross,
+		Commission: commission,
+		Net:        money.Round(gross-commission, p.Currency),
+		Currency:   p.Currency,
+	}
+	if _, err := s.repo.CreateEarning(ctx, earning); err != nil {
+		return err
+	}
+	if err := s.post(ctx, ledger.Ride(p.UserID, p.DriverID, p.PaymentID, gross, p.Currency)); err != nil {
+		return err
+	}
+	if commission > 0 {
+		return s.post(ctx, ledger.Commission(p.DriverID, p.PaymentID, commission, p.Currency))
+	}
+	return nil
+}
+
+// ledgerFailed логирует ошибку проводки там, где деньги уже движутся и вернуть ошибку клиенту нельзя;
+// пропущенную проводку допишет уведомление провайдера
+func (s *PaymentService) ledgerFailed(ctx context.Context, reference string, err error) {
+	logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to post ledger transaction",
+		zap.String("reference", reference), zap.Error(err))
+}
+
+func toPBEntry(e *repository.LedgerEntryRecord) *pb.LedgerEntry {
+	accountType, _, _ := ledger.ParseAccount(e.AccountID)
+	return &pb.LedgerEntry{
+		TxId:        e.TxID,
+		Kind:        e.Kind,
+		Reference:   e.Reference,
+		AccountId:   e.AccountID,
+		Amount:      float32(e.Amount * ledger.Sign(accountType)),
+		Description: e.Description,
+		CreatedAt:   e.CreatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/payment_service/internal/ledger"
+	"we_ride/internal/services/payment_service/internal/provider"
+	"we_ride/internal/services/payment_service/internal/repository"
+	pb "we_ride/internal/services/payment_service/pb"
+)
+
+// WebhookPath — адрес для HTTP-уведомлений провайдера (в ЮKassa указывается в личном кабинете)
+const WebhookPath = "/webhooks/yookassa"
+
+// maxNotificationSize — уведомления провайдера занимают единицы килобайт
+const maxNotificationSize = 64 << 10
+
+// WebhookHandler принимает уведомления провайдера. 200 — уведомление обработано или
+// проигнорировано, 5xx — временная ошибка, провайдер пришлёт его повторно.
+func (s *PaymentService) WebhookHandler(l *logger.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		ctx := context.WithValue(r.Context(), logger.Key, l)
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxNotificationSize))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		n, err := s.provider.ParseNotification(body)
+		if err != nil {
+			l.Error(ctx, "invalid provider notification", zap.String("provider", s.provider.Name()), zap.Error(err))
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := s.HandleNotification(ctx, n); err != nil {
+			l.Error(ctx, "failed to handle provider notification",
+				zap.String("event", n.Event), zap.String("object_id", n.ObjectID), zap.Error(err))
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+// HandleNotification применяет уведомление провайдера. Телу уведомления не доверяем:
+// объект перезапрашивается через API, статус берётся из ответа. Повторное уведомление
+// ничего не меняет и не публикуется второй раз.
+func (s *PaymentService) HandleNotification(ctx context.Context, n *provider.Notification) error {
+	switch n.Event {
+	case provider.EventPaymentSucceeded, provider.EventPaymentCanceled, provider.EventPaymentWaitingForCapture:
+		return s.applyPaymentNotification(ctx, n)
+	case provider.EventRefundSucceeded:
+		return s.applyRefundNotification(ctx, n)
+	default:
+		logger.GetLoggerFromCtx(ctx).Info(ctx, "ignoring provider notification", zap.String("event", n.Event))
+		return nil
+	}
+}
+
+func (s *PaymentService) applyPaymentNotification(ctx context.Context, n *provider.Notification) error {
+	l := logger.GetLoggerFromCtx(ctx)
+
+	yk, err := s.provider.GetPayment(ctx, n.ObjectID)
+	if err != nil {
+		return fmt.Errorf("fetch payment: %w", err)
+	}
+	// Статус, который должен быть у платежа для этого события, и из каких статусов в него можно перейти
+	want, from := provider.StatusSucceeded, []string{provider.StatusPending, provider.StatusWaitingForCapture}
+	switch n.Event {
+	case provider.EventPaymentCanceled:
+		want = provider.StatusCanceled
+	case provider.EventPaymentWaitingForCapture:
+		want, from = provider.StatusWaitingForCapture, []string{provider.StatusPending}
+	}
+	if yk.Status != want {
+		l.Info(ctx, "provider notification does not match payment status",
+			zap.String("event", n.Event), zap.String("yookassa_id", yk.ID), zap.String("status", yk.Status))
+		return nil
+	}
+	if yk.Metadata["method_id"] != "" {
+		return s.applyBindingNotification(ctx, yk)
+	}
+
+	p, err := s.repo.GetPaymentByYookassaID(ctx, yk.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		l.Info(ctx, "provider notification for unknown payment", zap.String("yookassa_id", yk.ID))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if id := yk.Metadata["payment_id"]; id != "" && id != p.PaymentID {
+		l.Error(ctx, "provider payment metadata mismatch",
+			zap.String("yookassa_id", yk.ID), zap.String("payment_id", p.PaymentID), zap.String("metadata_payment_id", id))
+		return nil
+	}
+
+	// Холд отменили, пока пассажир его подтверждал: снимаем деньги с карты
+	if yk.Status == provider.StatusWaitingForCapture && p.Status == provider.StatusCanceled {
+		if _, err := s.provider.CancelPayment(ctx, "void-"+p.PaymentID, yk.ID); err != nil {
+			return fmt.Errorf("cancel voided hold: %w", err)
+		}
+		return nil
+	}
+
+	// Проводка идемпотентна и пишется до смены статуса: при ошибке провайдер пришлёт уведомление повторно
+	if yk.Status == provider.StatusSucceeded {
+		if err := s.recordCharge(ctx, p, yk.Amount); err != nil {
+			return err
+		}
+	}
+
+	changed, err := s.repo.TransitionPaymentStatus(ctx, p.PaymentID, yk.Status, from...)
+	if err != nil || !changed {
+		return err
+	}
+	p.Status = yk.Status
+	s.publish(p, n.Event, "", p.Amount)
+	return nil
+}
+
+func (s *PaymentService) applyRefundNotification(ctx context.Context, n *provider.Notification) error {
+	l := logger.GetLoggerFromCtx(ctx)
+
+	yk, err := s.provider.GetRefund(ctx, n.ObjectID)
+	if err != nil {
+		return fmt.Errorf("fetch refund: %w", err)
+	}
+	if yk.Status != provider.StatusSucceeded {
+		l.Info(ctx, "provider notification does not match refund status",
+			zap.String("yookassa_id", yk.ID), zap.String("status", yk.Status))
+		return nil
+	}
+
+	ref, err := s.repo.GetRefundByYookassaID(ctx, yk.ID)
+	if errors.Is(err, repository.ErrNotFound) {
+		// Возврат из личного кабинета провайдера или ещё не сохранённый RefundPayment — тот допишет статус сам
+		l.Info(ctx, "provider notification for unknown refund", zap.String("yookassa_id", yk.ID))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	p, err := s.repo.GetPaymentByID(ctx, ref.PaymentID)
+	if err != nil {
+		return err
+	}
+	if err := s.post(ctx, ledger.Refund(p.UserID, ref.RefundID, ref.Amount, p.Currency)); err != nil {
+		return err
+	}
+
+	paymentStatus, err := s.repo.FinishRefund(ctx, ref.RefundID, "succeeded", yk.ID)
+	if errors.Is(err, repository.ErrRefundFinished) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	p.Status = paymentStatus
+	s.publish(p, n.Event, ref.RefundID, ref.Amount)
+	return nil
+}
+
+func (s *PaymentService) publish(p *repository.PaymentRecord, event, refundID string, amount float64) {
+	s.updates.Publish(&pb.PaymentUpdate{
+		PaymentId: p.PaymentID,
+		RoomId:    p.RoomID,
+		UserId:    p.UserID,
+		Event:     event,
+		Status:    p.Status,
+		RefundId:  refundID,
+		Amount:    float32(amount),
+		Currency:  p.Currency,
+		UpdatedAt: time.Now().Format(time.RFC3339),
+	})
+}
+
+// StreamPaymentUpdates держит стрим обновлений платежей вызывающего пользователя
+func (s *PaymentService) StreamPaymentUpdates(_ *pb.StreamPaymentUpdatesRequest, stream grpc.ServerStreamingServer[pb.PaymentUpdate]) error {
+	caller, err := identity.Caller(stream.Context())
+	if err != nil {
+		return err
+	}
+	updates, cancel := s.updates.Subscribe(caller.UserID)
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case u, ok := <-updates:
+			if !ok {
+				return status.Error(codes.Unavailable, "update stream closed")
+			}
+			if err := stream.Send(u); err != nil {
+				return err
+			}
+		}
+	}
+}
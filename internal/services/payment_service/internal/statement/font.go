@@ -0,0 +1,343 @@
+package statement
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// Шрифты DejaVu Sans (лицензия в fonts/LICENSE): в них есть кириллица, и они встраиваются в PDF,
+// поэтому выписка выглядит одинаково в любом просмотрщике
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	regularTTF []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	boldTTF []byte
+)
+
+// loadFonts разбирает встроенные шрифты один раз на процесс
+var loadFonts = sync.OnceValues(func() ([2]*ttFont, error) {
+	regular, err := parseFont("DejaVuSans", regularTTF)
+	if err != nil {
+		return [2]*ttFont{}, fmt.Errorf("parse regular font: %w", err)
+	}
+	bold, err := parseFont("DejaVuSans-Bold", boldTTF)
+	if err != nil {
+		return [2]*ttFont{}, fmt.Errorf("parse bold font: %w", err)
+	}
+	return [2]*ttFont{regular, bold}, nil
+})
+
+// ttFont — шрифт TrueType: метрики, таблица символов и таблицы, нужные для встраивания подмножества
+type ttFont struct {
+	name       string // PostScript-имя
+	unitsPerEm int
+	ascent     int
+	descent    int
+	bbox       [4]int
+	cmap       map[rune]uint16
+	advances   []uint16 // ширина каждого глифа в единицах шрифта
+	loca       []uint32 // смещения глифов в glyf, numGlyphs+1 значений
+	tables     map[string][]byte
+}
+
+var errBadFont = errors.New("malformed TrueType font")
+
+// parseFont читает из файла TrueType таблицы head, hhea, maxp, hmtx, loca, glyf и cmap (формат 4)
+func parseFont(name string, data []byte) (*ttFont, error) {
+	if len(data) < 12 {
+		return nil, errBadFont
+	}
+	f := &ttFont{name: name, tables: map[string][]byte{}}
+	numTables := int(binary.BigEndian.Uint16(data[4:]))
+	for i := range numTables {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return nil, errBadFont
+		}
+		offset := int(binary.BigEndian.Uint32(data[rec+8:]))
+		length := int(binary.BigEndian.Uint32(data[rec+12:]))
+		if offset+length > len(data) {
+			return nil, errBadFont
+		}
+		f.tables[string(data[rec:rec+4])] = data[offset : offset+length]
+	}
+	for _, tag := range []string{"head", "hhea", "maxp", "hmtx", "loca", "glyf", "cmap"} {
+		if _, ok := f.tables[tag]; !ok {
+			return nil, fmt.Errorf("%w: no %s table", errBadFont, tag)
+		}
+	}
+
+	head, hhea := f.tables["head"], f.tables["hhea"]
+	if len(head) < 54 || len(hhea) < 36 || len(f.tables["maxp"]) < 6 {
+		return nil, errBadFont
+	}
+	f.unitsPerEm = int(binary.BigEndian.Uint16(head[18:]))
+	for i := range f.bbox {
+		f.bbox[i] = int(int16(binary.BigEndian.Uint16(head[36+2*i:])))
+	}
+	f.ascent = int(int16(binary.BigEndian.Uint16(hhea[4:])))
+	f.descent = int(int16(binary.BigEndian.Uint16(hhea[6:])))
+	numGlyphs := int(binary.BigEndian.Uint16(f.tables["maxp"][4:]))
+
+	// Глифы после numberOfHMetrics имеют ширину последнего из них
+	hmtx := f.tables["hmtx"]
+	numMetrics := int(binary.BigEndian.Uint16(hhea[34:]))
+	if numMetrics == 0 || numMetrics > numGlyphs || len(hmtx) < 4*numMetrics {
+		return nil, errBadFont
+	}
+	f.advances = make([]uint16, numGlyphs)
+	for g := range f.advances {
+		f.advances[g] = binary.BigEndian.Uint16(hmtx[4*min(g, numMetrics-1):])
+	}
+
+	loca := f.tables["loca"]
+	f.loca = make([]uint32, numGlyphs+1)
+	long := binary.BigEndian.Uint16(head[50:]) == 1
+	for g := range f.loca {
+		switch {
+		case long && len(loca) >= 4*(g+1):
+			f.loca[g] = binary.BigEndian.Uint32(loca[4*g:])
+		case !long && len(loca) >= 2*(g+1):
+			f.loca[g] = 2 * uint32(binary.BigEndian.Uint16(loca[2*g:]))
+		default:
+			return nil, errBadFont
+		}
+		if f.loca[g] > uint32(len(f.tables["glyf"])) || g > 0 && f.loca[g] < f.loca[g-1] {
+			return nil, errBadFont
+		}
+	}
+
+	cmap, err := parseCmap(f.tables["cmap"], numGlyphs)
+	if err != nil {
+		return nil, err
+	}
+	f.cmap = cmap
+	return f, nil
+}
+
+// parseCmap читает юникодную подтаблицу формата 4 (символы BMP)
+func parseCmap(data []byte, numGlyphs int) (map[rune]uint16, error) {
+	if len(data) < 4 {
+		return nil, errBadFont
+	}
+	var sub []byte
+	for i := range int(binary.BigEndian.Uint16(data[2:])) {
+		rec := 4 + 8*i
+		if rec+8 > len(data) {
+			return nil, errBadFont
+		}
+		platform, encoding := binary.BigEndian.Uint16(data[rec:]), binary.BigEndian.Uint16(data[rec+2:])
+		offset := int(binary.BigEndian.Uint32(data[rec+4:]))
+		if offset+4 > len(data) || binary.BigEndian.Uint16(data[offset:]) != 4 {
+			continue
+		}
+		if platform == 3 && encoding == 1 || platform == 0 {
+			sub = data[offset:]
+			break
+		}
+	}
+	if sub == nil || len(sub) < 14 {
+		return nil, fmt.Errorf("%w: no unicode cmap", errBadFont)
+	}
+
+	segCount := int(binary.BigEndian.Uint16(sub[6:])) / 2
+	ends, starts, deltas, ranges := 14, 16+2*segCount, 16+4*segCount, 16+6*segCount
+	if len(sub) < ranges+2*segCount {
+		return nil, errBadFont
+	}
+	cmap := map[rune]uint16{}
+	for i := range segCount {
+		end := int(binary.BigEndian.Uint16(sub[ends+2*i:]))
+		start := int(binary.BigEndian.Uint16(sub[starts+2*i:]))
+		delta := binary.BigEndian.Uint16(sub[deltas+2*i:])
+		rangeOffset := int(binary.BigEndian.Uint16(sub[ranges+2*i:]))
+		for c := start; c <= end && c != 0xffff; c++ {
+			g := uint16(c) + delta
+			if rangeOffset != 0 {
+				at := ranges + 2*i + rangeOffset + 2*(c-start)
+				if at+2 > len(sub) {
+					return nil, errBadFont
+				}
+				if g = binary.BigEndian.Uint16(sub[at:]); g != 0 {
+					g += delta
+				}
+			}
+			if g != 0 && int(g) < numGlyphs {
+				cmap[rune(c)] = g
+			}
+		}
+	}
+	return cmap, nil
+}
+
+// glyph — глиф символа r; символы, которых нет в шрифте, заменяются на '?'
+func (f *ttFont) glyph(r rune) uint16 {
+	if g, ok := f.cmap[r]; ok {
+		return g
+	}
+	return f.cmap['?']
+}
+
+// scale переводит единицы шрифта в тысячные доли кегля, как принято в PDF
+func (f *ttFont) scale(v int) int {
+	return v * 1000 / f.unitsPerEm
+}
+
+// width — ширина строки s кеглем size в пунктах
+func (f *ttFont) width(s string, size float64) float64 {
+	units := 0
+	for _, r := range s {
+		units += int(f.advances[f.glyph(r)])
+	}
+	return float64(units) * size / float64(f.unitsPerEm)
+}
+
+// subset собирает файл шрифта только с глифами glyphs (и составными частями их глифов).
+// Номера глифов сохраняются, поэтому в PDF используется CIDToGIDMap /Identity;
+// таблица cmap не нужна — символы в тексте уже заданы номерами глифов.
+func (f *ttFont) subset(glyphs []uint16) []byte {
+	keep := map[uint16]bool{0: true}
+	queue := slices.Clone(glyphs)
+	for len(queue) > 0 {
+		g := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+		if keep[g] || int(g) >= len(f.advances) {
+			continue
+		}
+		keep[g] = true
+		queue = append(queue, f.components(g)...)
+	}
+
+	glyf := f.tables["glyf"]
+	var newGlyf bytes.Buffer
+	newLoca := make([]byte, 4*len(f.loca))
+	for g := range f.advances {
+		binary.BigEndian.PutUint32(newLoca[4*g:], uint32(newGlyf.Len()))
+		if keep[uint16(g)] {
+			newGlyf.Write(glyf[f.loca[g]:f.loca[g+1]])
+			for newGlyf.Len()%4 != 0 {
+				newGlyf.WriteByte(0)
+			}
+		}
+	}
+	binary.BigEndian.PutUint32(newLoca[4*len(f.advances):], uint32(newGlyf.Len()))
+
+	head := slices.Clone(f.tables["head"])
+	binary.BigEndian.PutUint32(head[8:], 0)  // checkSumAdjustment считается ниже
+	binary.BigEndian.PutUint16(head[50:], 1) // loca в длинном формате
+	tables := map[string][]byte{
+		"head": head,
+		"hhea": f.tables["hhea"],
+		"maxp": f.tables["maxp"],
+		"hmtx": f.tables["hmtx"],
+		"loca": newLoca,
+		"glyf": newGlyf.Bytes(),
+	}
+	// Инструкции хинтинга глифов ссылаются на эти таблицы
+	for _, tag := range []string{"cvt ", "fpgm", "prep"} {
+		if t, ok := f.tables[tag]; ok {
+			tables[tag] = t
+		}
+	}
+	return writeFont(tables)
+}
+
+// components — глифы, из которых состоит составной глиф g
+func (f *ttFont) components(g uint16) []uint16 {
+	data := f.tables["glyf"][f.loca[g]:f.loca[g+1]]
+	if len(data) < 10 || int16(binary.BigEndian.Uint16(data)) >= 0 {
+		return nil
+	}
+	const (
+		argWords     = 0x0001
+		haveScale    = 0x0008
+		moreParts    = 0x0020
+		haveXYScale  = 0x0040
+		haveTwoByTwo = 0x0080
+	)
+	var parts []uint16
+	for at := 10; at+4 <= len(data); {
+		flags := binary.BigEndian.Uint16(data[at:])
+		parts = append(parts, binary.BigEndian.Uint16(data[at+2:]))
+		at += 4
+		if flags&argWords != 0 {
+			at += 4
+		} else {
+			at += 2
+		}
+		switch {
+		case flags&haveScale != 0:
+			at += 2
+		case flags&haveXYScale != 0:
+			at += 4
+		case flags&haveTwoByTwo != 0:
+			at += 8
+		}
+		if flags&moreParts == 0 {
+			break
+		}
+	}
+	return parts
+}
+
+// writeFont собирает файл TrueType из таблиц с контрольными суммами
+func writeFont(tables map[string][]byte) []byte {
+	tags := make([]string, 0, len(tables))
+	for tag := range tables {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+
+	n := len(tags)
+	entrySelector := 0
+	for 1<<(entrySelector+1) <= n {
+		entrySelector++
+	}
+	searchRange := 16 << entrySelector
+
+	var buf bytes.Buffer
+	header := make([]byte, 12+16*n)
+	binary.BigEndian.PutUint32(header, 0x00010000)
+	binary.BigEndian.PutUint16(header[4:], uint16(n))
+	binary.BigEndian.PutUint16(header[6:], uint16(searchRange))
+	binary.BigEndian.PutUint16(header[8:], uint16(entrySelector))
+	binary.BigEndian.PutUint16(header[10:], uint16(16*n-searchRange))
+	buf.Write(header)
+
+	headAt := 0
+	for i, tag := range tags {
+		t := tables[tag]
+		if tag == "head" {
+			headAt = buf.Len()
+		}
+		rec := header[12+16*i:]
+		copy(rec, tag)
+		binary.BigEndian.PutUint32(rec[4:], fontChecksum(t))
+		binary.BigEndian.PutUint32(rec[8:], uint32(buf.Len()))
+		binary.BigEndian.PutUint32(rec[12:], uint32(len(t)))
+		buf.Write(t)
+		for buf.Len()%4 != 0 {
+			buf.WriteByte(0)
+		}
+	}
+	font := buf.Bytes()
+	copy(font, header)
+	binary.BigEndian.PutUint32(font[headAt+8:], 0xb1b0afba-fontChecksum(font))
+	return font
+}
+
+// fontChecksum — сумма 32-битных слов, как в таблицах TrueType
+func fontChecksum(data []byte) uint32 {
+	var sum uint32
+	for i := 0; i < len(data); i += 4 {
+		var word [4]byte
+		copy(word[:], data[i:])
+		sum += binary.BigEndian.Uint32(word[:])
+	}
+	return sum
+}
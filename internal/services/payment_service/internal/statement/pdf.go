@@ -0,0 +1,295 @@
+package statement
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"strings"
+	"unicode/utf16"
+)
+
+// Страница A4 в альбомной ориентации; размеры в пунктах
+const (
+	pageWidth  = 842.0
+	pageHeight = 595.0
+	margin     = 36.0
+	titleSize  = 12
+	fontSize   = 8
+	lineHeight = 12.0
+	cellPad    = 8.0
+)
+
+// pdfFont — встроенный шрифт документа. В текст пишутся номера глифов (кодировка Identity-H),
+// used запоминает, какие глифы встретились и каким символам они соответствуют: только они
+// попадают во встроенное подмножество шрифта и в таблицу ToUnicode для копирования и поиска.
+type pdfFont struct {
+	*ttFont
+	id   string // имя ресурса на странице, F1 или F2
+	used map[uint16]rune
+}
+
+// encode переводит строку в шестнадцатеричную строку PDF из номеров глифов
+func (f *pdfFont) encode(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, r := range s {
+		if r < 0x20 {
+			r = ' '
+		}
+		g := f.glyph(r)
+		if _, ok := f.used[g]; !ok {
+			if _, inFont := f.cmap[r]; !inFont {
+				r = '?'
+			}
+			f.used[g] = r
+		}
+		fmt.Fprintf(&b, "%04X", g)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// pdfLine — строка таблицы на странице
+type pdfLine struct {
+	cells []string
+	bold  bool
+}
+
+// WritePDF пишет таблицу в PDF: шапка повторяется на каждой странице, итоги — после строк.
+// Ширина столбцов считается по ширине текста; не поместившийся текст обрезается многоточием.
+// Шрифт DejaVu Sans встраивается подмножеством из использованных глифов.
+func WritePDF(w io.Writer, t Table) error {
+	fonts, err := loadFonts()
+	if err != nil {
+		return err
+	}
+	regular := &pdfFont{ttFont: fonts[0], id: "F1", used: map[uint16]rune{}}
+	bold := &pdfFont{ttFont: fonts[1], id: "F2", used: map[uint16]rune{}}
+	widths := columnWidths(t, regular, bold)
+
+	lines := make([]pdfLine, 0, len(t.Rows)+len(t.Totals)+1)
+	for _, r := range t.Rows {
+		lines = append(lines, pdfLine{cells: r})
+	}
+	if len(t.Totals) > 0 {
+		lines = append(lines, pdfLine{})
+		for _, r := range t.Totals {
+			lines = append(lines, pdfLine{cells: r, bold: true})
+		}
+	}
+
+	// На первой странице место под заголовок, на каждой — под шапку и номер страницы
+	height := pageHeight - 2*margin
+	perPage := int(height/lineHeight) - 2
+	firstPage := perPage - 2
+	var pages [][]pdfLine
+	for first := true; first || len(lines) > 0; first = false {
+		n := perPage
+		if first {
+			n = firstPage
+		}
+		n = min(n, len(lines))
+		pages = append(pages, lines[:n])
+		lines = lines[n:]
+	}
+
+	var contents [][]byte
+	for i, page := range pages {
+		var c bytes.Buffer
+		y := pageHeight - margin - lineHeight
+		if i == 0 && t.Title != "" {
+			pdfText(&c, bold, titleSize, margin, y, truncate(bold, titleSize, t.Title, pageWidth-2*margin))
+			y -= 2 * lineHeight
+		}
+		y = pdfRow(&c, pdfLine{cells: t.Header, bold: true}, regular, bold, widths, y)
+		for _, l := range page {
+			y = pdfRow(&c, l, regular, bold, widths, y)
+		}
+		number := fmt.Sprintf("%d / %d", i+1, len(pages))
+		pdfText(&c, regular, fontSize, pageWidth-margin-regular.width(number, fontSize), margin/2, number)
+		contents = append(contents, c.Bytes())
+	}
+	return writePDFObjects(w, contents, []*pdfFont{regular, bold})
+}
+
+// columnWidths делит ширину страницы между столбцами пропорционально самому широкому тексту в них
+func columnWidths(t Table, regular, bold *pdfFont) []float64 {
+	widths := make([]float64, len(t.Header))
+	measure := func(font *pdfFont, row []string) {
+		for i, cell := range row {
+			if i < len(widths) {
+				widths[i] = max(widths[i], font.width(cell, fontSize)+cellPad)
+			}
+		}
+	}
+	measure(bold, t.Header)
+	for _, r := range t.Rows {
+		measure(regular, r)
+	}
+	for _, r := range t.Totals {
+		measure(bold, r)
+	}
+	total := 0.0
+	for _, w := range widths {
+		total += w
+	}
+	if available := pageWidth - 2*margin; total > available {
+		for i := range widths {
+			widths[i] *= available / total
+		}
+	}
+	return widths
+}
+
+// pdfRow выводит строку таблицы на высоте y и возвращает высоту следующей строки
+func pdfRow(c *bytes.Buffer, l pdfLine, regular, bold *pdfFont, widths []float64, y float64) float64 {
+	font := regular
+	if l.bold {
+		font = bold
+	}
+	x := margin
+	for i, cell := range l.cells {
+		if i >= len(widths) {
+			break
+		}
+		pdfText(c, font, fontSize, x, y, truncate(font, fontSize, cell, widths[i]-cellPad))
+		x += widths[i]
+	}
+	return y - lineHeight
+}
+
+// truncate обрезает строку с многоточием, чтобы она уместилась в width пунктов
+func truncate(font *pdfFont, size float64, s string, width float64) string {
+	if font.width(s, size) <= width {
+		return s
+	}
+	r := []rune(s)
+	for n := len(r) - 1; n > 0; n-- {
+		if cut := string(r[:n]) + "…"; font.width(cut, size) <= width {
+			return cut
+		}
+	}
+	return ""
+}
+
+func pdfText(c *bytes.Buffer, font *pdfFont, size float64, x, y float64, s string) {
+	if s == "" {
+		return
+	}
+	fmt.Fprintf(c, "BT /%s %.0f Tf %.2f %.2f Td %s Tj ET\n", font.id, size, x, y, font.encode(s))
+}
+
+// fontObjects — объекты PDF встроенного шрифта, начиная с номера first: шрифт Type0,
+// CIDFontType2 с ширинами глифов, дескриптор, файл подмножества шрифта и таблица ToUnicode
+func fontObjects(f *pdfFont, first int) []string {
+	glyphs := make([]uint16, 0, len(f.used))
+	for g := range f.used {
+		glyphs = append(glyphs, g)
+	}
+	slices.Sort(glyphs)
+
+	// Префикс подмножества — шесть заглавных букв, разные для разных наборов глифов
+	h := fnv.New32a()
+	var widths, toUnicode strings.Builder
+	for _, g := range glyphs {
+		fmt.Fprintf(h, "%d,", g)
+		fmt.Fprintf(&widths, "%d [%d] ", g, f.scale(int(f.advances[g])))
+		fmt.Fprintf(&toUnicode, "<%04X> <%s>\n", g, utf16Hex(f.used[g]))
+	}
+	tag := make([]byte, 6)
+	for i, sum := 0, h.Sum32(); i < len(tag); i, sum = i+1, sum/26 {
+		tag[i] = 'A' + byte(sum%26)
+	}
+	name := string(tag) + "+" + f.name
+
+	var file bytes.Buffer
+	font := f.subset(glyphs)
+	zw := zlib.NewWriter(&file)
+	_, _ = zw.Write(font)
+	_ = zw.Close()
+
+	cmap := fmt.Sprintf(`/CIDInit /ProcSet findresource begin
+12 dict begin
+begincmap
+/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def
+/CMapName /Adobe-Identity-UCS def
+/CMapType 2 def
+1 begincodespacerange
+<0000> <FFFF>
+endcodespacerange
+%d beginbfchar
+%sendbfchar
+endcmap
+CMapName currentdict /CMap defineresource pop
+end
+end`, len(glyphs), toUnicode.String())
+
+	return []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+			name, first+1, first+4),
+		fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /CIDToGIDMap /Identity /W [%s] >>",
+			name, first+2, strings.TrimSpace(widths.String())),
+		fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 32 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+			name, f.scale(f.bbox[0]), f.scale(f.bbox[1]), f.scale(f.bbox[2]), f.scale(f.bbox[3]),
+			f.scale(f.ascent), f.scale(f.descent), f.scale(f.ascent), first+3),
+		fmt.Sprintf("<< /Length %d /Length1 %d /Filter /FlateDecode >>\nstream\n%s\nendstream", file.Len(), len(font), file.Bytes()),
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(cmap), cmap),
+	}
+}
+
+// utf16Hex — символ в UTF-16BE шестнадцатеричными цифрами для ToUnicode
+func utf16Hex(r rune) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune{r}) {
+		fmt.Fprintf(&b, "%04X", u)
+	}
+	return b.String()
+}
+
+// writePDFObjects собирает документ: каталог, дерево страниц, объекты шрифтов,
+// затем по странице и её содержимому на каждую страницу, таблицу xref и trailer
+func writePDFObjects(w io.Writer, contents [][]byte, fonts []*pdfFont) error {
+	const fontObjs = 5
+	firstPageObj := 3 + fontObjs*len(fonts)
+	kids := make([]string, len(contents))
+	for i := range contents {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+2*i)
+	}
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(contents)),
+	}
+	resources := make([]string, len(fonts))
+	for i, f := range fonts {
+		first := 3 + fontObjs*i
+		resources[i] = fmt.Sprintf("/%s %d 0 R", f.id, first)
+		objects = append(objects, fontObjects(f, first)...)
+	}
+	for i, c := range contents {
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << %s >> >> /Contents %d 0 R >>",
+				pageWidth, pageHeight, strings.Join(resources, " "), firstPageObj+2*i+1),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(c), c),
+		)
+	}
+
+	var buf bytes.Buffer
+	// Строка комментария с байтами выше 127 помечает файл как двоичный
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
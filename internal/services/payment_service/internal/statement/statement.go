@@ -0,0 +1,56 @@
+// Package statement — выписки за период в CSV и PDF. Выписка — таблица строк с итогами;
+// что в ней считается, решает сервис, пакет только раскладывает её по формату.
+package statement
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// Форматы выписки
+const (
+	FormatCSV = "csv"
+	FormatPDF = "pdf"
+)
+
+// Table — выписка: заголовок документа, шапка таблицы, строки и итоговые строки
+type Table struct {
+	Title  string // в CSV не попадает
+	Header []string
+	Rows   [][]string
+	Totals [][]string // выводятся после строк; пусто — без итогов
+}
+
+// ContentType — MIME-тип файла выписки в формате format
+func ContentType(format string) string {
+	if format == FormatPDF {
+		return "application/pdf"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Write пишет таблицу в формате format
+func Write(w io.Writer, format string, t Table) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, t)
+	case FormatPDF:
+		return WritePDF(w, t)
+	}
+	return fmt.Errorf("unknown statement format %q", format)
+}
+
+// WriteCSV пишет таблицу в CSV в UTF-8 с BOM, чтобы Excel открыл кириллицу без перекодировки.
+// Итоги отделены от строк пустой строкой.
+func WriteCSV(w io.Writer, t Table) error {
+	if _, err := io.WriteString(w, "\uFEFF"); err != nil {
+		return err
+	}
+	cw := csv.NewWriter(w)
+	records := append([][]string{t.Header}, t.Rows...)
+	if len(t.Totals) > 0 {
+		records = append(append(records, []string{}), t.Totals...)
+	}
+	return cw.WriteAll(records)
+}
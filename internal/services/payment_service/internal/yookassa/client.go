@@ -20,6 +20,22 @@ const (
 	StatusCanceled          = "canceled"
 )
 
+// APIError — ответ ЮKassa с кодом ошибки
+type APIError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("yookassa error [%d]: %s", e.StatusCode, e.Body)
+}
+
+// Temporary — запрос можно повторить: ЮKassa ограничивает частоту запросов или недоступна.
+// Повтор безопасен, потому что запросы на изменение отправляются с ключом идемпотентности.
+func (e *APIError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= http.StatusInternalServerError
+}
+
 // Client HTTP-клиент для работы с ЮKassa API
 type Client struct {
 	shopID     string
@@ -38,10 +54,36 @@ func NewClient(shopID, secretKey string) *Client {
 // CreatePaymentRequest тело запроса на создание платежа
 type CreatePaymentRequest struct {
 	Amount       Amount            `json:"amount"`
-	Confirmation Confirmation      `json:"confirmation"`
+	Confirmation *Confirmation     `json:"confirmation,omitempty"` // не нужно при оплате сохранённым способом
 	Description  string            `json:"description"`
 	Capture      bool              `json:"capture"`
 	Metadata     map[string]string `json:"metadata,omitempty"`
+	// SavePaymentMethod сохраняет способ оплаты для автоплатежей, PaymentMethodID списывает сохранённым
+	SavePaymentMethod bool     `json:"save_payment_method,omitempty"`
+	PaymentMethodID   string   `json:"payment_method_id,omitempty"`
+	Receipt           *Receipt `json:"receipt,omitempty"`
+}
+
+// Receipt данные для формирования чека по 54-ФЗ
+type Receipt struct {
+	Customer      Customer      `json:"customer"`
+	Items         []ReceiptItem `json:"items"`
+	TaxSystemCode int           `json:"tax_system_code,omitempty"`
+}
+
+// Customer покупатель: чек уходит на email или телефон
+type Customer struct {
+	Email string `json:"email,omitempty"`
+	Phone string `json:"phone,omitempty"`
+}
+
+type ReceiptItem struct {
+	Description    string `json:"description"`
+	Quantity       string `json:"quantity"`
+	Amount         Amount `json:"amount"`
+	VatCode        int    `json:"vat_code"`
+	PaymentSubject string `json:"payment_subject,omitempty"`
+	PaymentMode    string `json:"payment_mode,omitempty"`
 }
 
 type Amount struct {
@@ -56,27 +98,39 @@ type Confirmation struct {
 
 // PaymentResponse ответ от ЮKassa на создание/получение платежа
 type PaymentResponse struct {
-	ID           string `json:"id"`
-	Status       string `json:"status"`
-	Amount       Amount `json:"amount"`
-	Description  string `json:"description"`
-	CreatedAt    string `json:"created_at"`
+	ID           string            `json:"id"`
+	Status       string            `json:"status"`
+	Amount       Amount            `json:"amount"`
+	Description  string            `json:"description"`
+	CreatedAt    string            `json:"created_at"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
 	Confirmation struct {
 		Type            string `json:"type"`
 		ConfirmationURL string `json:"confirmation_url,omitempty"`
 	} `json:"confirmation"`
+	PaymentMethod *PaymentMethod `json:"payment_method,omitempty"`
+}
+
+// PaymentMethod способ оплаты платежа; Saved — сохранён и доступен по ID для автоплатежей
+type PaymentMethod struct {
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Saved bool   `json:"saved"`
+	Title string `json:"title,omitempty"`
 }
 
 // CreateRefundRequest тело запроса на возврат
 type CreateRefundRequest struct {
-	PaymentID   string `json:"payment_id"`
-	Amount      Amount `json:"amount"`
-	Description string `json:"description"`
+	PaymentID   string   `json:"payment_id"`
+	Amount      Amount   `json:"amount"`
+	Description string   `json:"description"`
+	Receipt     *Receipt `json:"receipt,omitempty"`
 }
 
 // RefundResponse ответ от ЮKassa на создание возврата
 type RefundResponse struct {
 	ID        string `json:"id"`
+	PaymentID string `json:"payment_id"`
 	Status    string `json:"status"`
 	Amount    Amount `json:"amount"`
 	CreatedAt string `json:"created_at"`
@@ -110,7 +164,7 @@ func (c *Client) CreatePayment(ctx context.Context, idempotencyKey string, req C
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("yookassa error [%d]: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var payment PaymentResponse
@@ -122,23 +176,43 @@ func (c *Client) CreatePayment(ctx context.Context, idempotencyKey string, req C
 
 // GetPayment получает статус платежа по ID
 func (c *Client) GetPayment(ctx context.Context, paymentID string) (*PaymentResponse, error) {
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/payments/"+paymentID, nil)
+	var payment PaymentResponse
+	if err := c.get(ctx, "/payments/"+paymentID, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// GetRefund получает статус возврата по ID
+func (c *Client) GetRefund(ctx context.Context, refundID string) (*RefundResponse, error) {
+	var refund RefundResponse
+	if err := c.get(ctx, "/refunds/"+refundID, &refund); err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (c *Client) get(ctx context.Context, path string, out any) error {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return fmt.Errorf("create request: %w", err)
 	}
 	httpReq.SetBasicAuth(c.shopID, c.secretKey)
 
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("do request: %w", err)
+		return fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	var payment PaymentResponse
-	if err := json.NewDecoder(resp.Body).Decode(&payment); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(resp.Body)
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
-	return &payment, nil
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
 }
 
 // CreateRefund создаёт возврат для платежа
@@ -169,7 +243,7 @@ func (c *Client) CreateRefund(ctx context.Context, idempotencyKey string, req Cr
 	}
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("yookassa refund error [%d]: %s", resp.StatusCode, string(respBody))
+		return nil, &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
 	}
 
 	var refund RefundResponse
@@ -178,3 +252,61 @@ func (c *Client) CreateRefund(ctx context.Context, idempotencyKey string, req Cr
 	}
 	return &refund, nil
 }
+
+// CapturePaymentRequest тело запроса на списание холда; сумма может быть меньше авторизованной,
+// остаток возвращается покупателю
+type CapturePaymentRequest struct {
+	Amount  Amount   `json:"amount"`
+	Receipt *Receipt `json:"receipt,omitempty"`
+}
+
+// CapturePayment списывает платёж в статусе waiting_for_capture
+func (c *Client) CapturePayment(ctx context.Context, idempotencyKey, paymentID string, req CapturePaymentRequest) (*PaymentResponse, error) {
+	var payment PaymentResponse
+	if err := c.post(ctx, "/payments/"+paymentID+"/capture", idempotencyKey, req, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+// CancelPayment отменяет платёж в статусе waiting_for_capture, холд снимается с карты
+func (c *Client) CancelPayment(ctx context.Context, idempotencyKey, paymentID string) (*PaymentResponse, error) {
+	var payment PaymentResponse
+	if err := c.post(ctx, "/payments/"+paymentID+"/cancel", idempotencyKey, struct{}{}, &payment); err != nil {
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (c *Client) post(ctx context.Context, path, idempotencyKey string, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	httpReq.SetBasicAuth(c.shopID, c.secretKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Idempotence-Key", idempotencyKey)
+
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{StatusCode: resp.StatusCode, Body: string(respBody)}
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package yookassa
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// События HTTP-уведомлений ЮKassa, на которые подписан магазин
+const (
+	EventPaymentSucceeded         = "payment.succeeded"
+	EventPaymentWaitingForCapture = "payment.waiting_for_capture"
+	EventPaymentCanceled          = "payment.canceled"
+	EventRefundSucceeded          = "refund.succeeded"
+)
+
+// Notification — тело HTTP-уведомления. Уведомлению нельзя доверять как есть:
+// объект нужно перезапросить через API по Object.ID.
+type Notification struct {
+	Type   string `json:"type"`
+	Event  string `json:"event"`
+	Object struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	} `json:"object"`
+}
+
+// ParseNotification разбирает тело уведомления и проверяет обязательные поля
+func ParseNotification(body []byte) (*Notification, error) {
+	var n Notification
+	if err := json.Unmarshal(body, &n); err != nil {
+		return nil, fmt.Errorf("unmarshal notification: %w", err)
+	}
+	if n.Type != "notification" || n.Event == "" || n.Object.ID == "" {
+		return nil, errors.New("malformed notification")
+	}
+	return &n, nil
+}
@@ -0,0 +1,160 @@
+package yookassa
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"we_ride/internal/services/payment_service/internal/money"
+	"we_ride/internal/services/payment_service/internal/provider"
+)
+
+// Provider — ЮKassa как provider.PaymentProvider
+type Provider struct {
+	client *Client
+}
+
+func NewProvider(client *Client) *Provider {
+	return &Provider{client: client}
+}
+
+func (p *Provider) Name() string { return "yookassa" }
+
+func (p *Provider) CreatePayment(ctx context.Context, idempotencyKey string, req provider.CreatePaymentRequest) (*provider.Payment, error) {
+	ykReq := CreatePaymentRequest{
+		Amount:            toAmount(req.Amount, req.Currency),
+		Description:       req.Description,
+		Capture:           req.Capture,
+		Metadata:          req.Metadata,
+		SavePaymentMethod: req.SavePaymentMethod,
+		PaymentMethodID:   req.PaymentMethodID,
+		Receipt:           toReceipt(req.Receipt),
+	}
+	if req.PaymentMethodID == "" {
+		ykReq.Confirmation = &Confirmation{Type: "redirect", ReturnURL: req.ReturnURL}
+	}
+	resp, err := p.client.CreatePayment(ctx, idempotencyKey, ykReq)
+	if err != nil {
+		return nil, err
+	}
+	return fromPayment(resp), nil
+}
+
+func (p *Provider) GetPayment(ctx context.Context, paymentID string) (*provider.Payment, error) {
+	resp, err := p.client.GetPayment(ctx, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return fromPayment(resp), nil
+}
+
+func (p *Provider) CapturePayment(ctx context.Context, idempotencyKey, paymentID string, amount float64, currency string, receipt *provider.Receipt) (*provider.Payment, error) {
+	resp, err := p.client.CapturePayment(ctx, idempotencyKey, paymentID, CapturePaymentRequest{
+		Amount:  toAmount(amount, currency),
+		Receipt: toReceipt(receipt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromPayment(resp), nil
+}
+
+func (p *Provider) CancelPayment(ctx context.Context, idempotencyKey, paymentID string) (*provider.Payment, error) {
+	resp, err := p.client.CancelPayment(ctx, idempotencyKey, paymentID)
+	if err != nil {
+		return nil, err
+	}
+	return fromPayment(resp), nil
+}
+
+func (p *Provider) CreateRefund(ctx context.Context, idempotencyKey string, req provider.RefundRequest) (*provider.Refund, error) {
+	resp, err := p.client.CreateRefund(ctx, idempotencyKey, CreateRefundRequest{
+		PaymentID:   req.PaymentID,
+		Amount:      toAmount(req.Amount, req.Currency),
+		Description: req.Description,
+		Receipt:     toReceipt(req.Receipt),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return fromRefund(resp), nil
+}
+
+func (p *Provider) GetRefund(ctx context.Context, refundID string) (*provider.Refund, error) {
+	resp, err := p.client.GetRefund(ctx, refundID)
+	if err != nil {
+		return nil, err
+	}
+	return fromRefund(resp), nil
+}
+
+func (p *Provider) ParseNotification(body []byte) (*provider.Notification, error) {
+	n, err := ParseNotification(body)
+	if err != nil {
+		return nil, err
+	}
+	return &provider.Notification{Event: n.Event, ObjectID: n.Object.ID}, nil
+}
+
+func toAmount(value float64, currency string) Amount {
+	return Amount{Value: money.Format(value, currency), Currency: currency}
+}
+
+func toReceipt(r *provider.Receipt) *Receipt {
+	if r == nil {
+		return nil
+	}
+	receipt := &Receipt{
+		Customer:      Customer{Email: r.Email, Phone: r.Phone},
+		TaxSystemCode: r.TaxSystemCode,
+	}
+	for _, item := range r.Items {
+		receipt.Items = append(receipt.Items, ReceiptItem{
+			Description:    item.Description,
+			Quantity:       fmt.Sprintf("%.2f", item.Quantity),
+			Amount:         toAmount(item.Amount, item.Currency),
+			VatCode:        item.VatCode,
+			PaymentSubject: item.PaymentSubject,
+			PaymentMode:    item.PaymentMode,
+		})
+	}
+	return receipt
+}
+
+// parseAmount — сумма ЮKassa приходит строкой с копейками; нечитаемая считается нулевой
+func parseAmount(a Amount) float64 {
+	v, _ := strconv.ParseFloat(a.Value, 64)
+	return v
+}
+
+func fromPayment(r *PaymentResponse) *provider.Payment {
+	var method *provider.PaymentMethod
+	if r.PaymentMethod != nil {
+		method = &provider.PaymentMethod{
+			ID:    r.PaymentMethod.ID,
+			Type:  r.PaymentMethod.Type,
+			Title: r.PaymentMethod.Title,
+			Saved: r.PaymentMethod.Saved,
+		}
+	}
+	return &provider.Payment{
+		ID:              r.ID,
+		Status:          r.Status,
+		Amount:          parseAmount(r.Amount),
+		Currency:        r.Amount.Currency,
+		Description:     r.Description,
+		Metadata:        r.Metadata,
+		ConfirmationURL: r.Confirmation.ConfirmationURL,
+		PaymentMethod:   method,
+	}
+}
+
+func fromRefund(r *RefundResponse) *provider.Refund {
+	return &provider.Refund{
+		ID:        r.ID,
+		PaymentID: r.PaymentID,
+		Status:    r.Status,
+		Amount:    parseAmount(r.Amount),
+		Currency:  r.Amount.Currency,
+	}
+}
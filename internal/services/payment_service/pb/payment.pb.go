@@ -1,4 +1,7 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.28.0
 // source: payment.proto
 
 package pb
@@ -6,18 +9,19 @@ package pb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
 )
 
 const (
+	// Verify that this generated code is sufficiently up-to-date.
 	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// ---- Payment ----
-
 type Payment struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	PaymentId         string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
@@ -27,19 +31,30 @@ type Payment struct {
 	Currency          string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
 	Status            string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
 	YookassaPaymentId string                 `protobuf:"bytes,7,opt,name=yookassa_payment_id,json=yookassaPaymentId,proto3" json:"yookassa_payment_id,omitempty"`
-	CreatedAt         string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	Description       string                 `protobuf:"bytes,9,opt,name=description,proto3" json:"description,omitempty"`
+	AuthorizedAmount  float32                `protobuf:"fixed32,10,opt,name=authorized_amount,json=authorizedAmount,proto3" json:"authorized_amount,omitempty"` // сумма холда; 0 — платёж без предавторизации
+	ConfirmationUrl   string                 `protobuf:"bytes,11,opt,name=confirmation_url,json=confirmationUrl,proto3" json:"confirmation_url,omitempty"`      // куда отправить пассажира подтвердить платёж; пусто — подтверждение не нужно
+	Discount          float32                `protobuf:"fixed32,12,opt,name=discount,proto3" json:"discount,omitempty"`                                         // скидка по промокоду: amount — доля пассажира за вычетом скидки
+	PromoCode         string                 `protobuf:"bytes,13,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	Kind              string                 `protobuf:"bytes,14,opt,name=kind,proto3" json:"kind,omitempty"` // ride, cancellation_fee, no_show_fee
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields     protoimpl.UnknownFields
 	sizeCache         protoimpl.SizeCache
 }
 
 func (x *Payment) Reset() {
 	*x = Payment{}
+	mi := &file_payment_proto_msgTypes[0]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[0])
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payment) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (x *Payment) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*Payment) ProtoMessage()    {}
+
+func (*Payment) ProtoMessage() {}
+
 func (x *Payment) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[0]
 	if x != nil {
@@ -51,81 +66,139 @@ func (x *Payment) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*Payment) Descriptor() ([]byte, []int) { return file_payment_proto_rawDescGZIP(), []int{0} }
+
+// Deprecated: Use Payment.ProtoReflect.Descriptor instead.
+func (*Payment) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{0}
+}
+
 func (x *Payment) GetPaymentId() string {
 	if x != nil {
 		return x.PaymentId
 	}
 	return ""
 }
+
 func (x *Payment) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
+
 func (x *Payment) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
+
 func (x *Payment) GetAmount() float32 {
 	if x != nil {
 		return x.Amount
 	}
 	return 0
 }
+
 func (x *Payment) GetCurrency() string {
 	if x != nil {
 		return x.Currency
 	}
 	return ""
 }
+
 func (x *Payment) GetStatus() string {
 	if x != nil {
 		return x.Status
 	}
 	return ""
 }
+
 func (x *Payment) GetYookassaPaymentId() string {
 	if x != nil {
 		return x.YookassaPaymentId
 	}
 	return ""
 }
-func (x *Payment) GetCreatedAt() string {
+
+func (x *Payment) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Payment) GetAuthorizedAmount() float32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.AuthorizedAmount
+	}
+	return 0
+}
+
+func (x *Payment) GetConfirmationUrl() string {
+	if x != nil {
+		return x.ConfirmationUrl
 	}
 	return ""
 }
-func (x *Payment) GetDescription() string {
+
+func (x *Payment) GetDiscount() float32 {
 	if x != nil {
-		return x.Description
+		return x.Discount
+	}
+	return 0
+}
+
+func (x *Payment) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *Payment) GetKind() string {
+	if x != nil {
+		return x.Kind
 	}
 	return ""
 }
 
-// ---- ProcessPaymentRequest ----
+func (x *Payment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
 
+// Списание доли сверяется с room_service: вызывающий — создатель комнаты или admin, поездка завершена,
+// user_ids — текущие участники, amount_per_user — cost_per_member комнаты.
 type ProcessPaymentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
 	AmountPerUser float32                `protobuf:"fixed32,3,opt,name=amount_per_user,json=amountPerUser,proto3" json:"amount_per_user,omitempty"`
 	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	DriverId      string                 `protobuf:"bytes,5,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"` // заработок начисляется создателю комнаты; если указан, должен с ним совпадать
+	// Ключ идемпотентности запроса; пусто — room_id. Повтор с тем же ключом возвращает сохранённые платежи.
+	IdempotencyKey string `protobuf:"bytes,6,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Currency       string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217, валюта поездки; пусто — валюта сервиса по умолчанию
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProcessPaymentRequest) Reset() {
 	*x = ProcessPaymentRequest{}
+	mi := &file_payment_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[1])
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
-func (x *ProcessPaymentRequest) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*ProcessPaymentRequest) ProtoMessage()    {}
+
+func (*ProcessPaymentRequest) ProtoMessage() {}
+
 func (x *ProcessPaymentRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[1]
 	if x != nil {
@@ -137,27 +210,33 @@ func (x *ProcessPaymentRequest) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
+
+// Deprecated: Use ProcessPaymentRequest.ProtoReflect.Descriptor instead.
 func (*ProcessPaymentRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{1}
 }
+
 func (x *ProcessPaymentRequest) GetRoomId() string {
 	if x != nil {
 		return x.RoomId
 	}
 	return ""
 }
+
 func (x *ProcessPaymentRequest) GetUserIds() []string {
 	if x != nil {
 		return x.UserIds
 	}
 	return nil
 }
+
 func (x *ProcessPaymentRequest) GetAmountPerUser() float32 {
 	if x != nil {
 		return x.AmountPerUser
 	}
 	return 0
 }
+
 func (x *ProcessPaymentRequest) GetDescription() string {
 	if x != nil {
 		return x.Description
@@ -165,24 +244,53 @@ func (x *ProcessPaymentRequest) GetDescription() string {
 	return ""
 }
 
-// ---- ProcessPaymentResponse ----
+func (x *ProcessPaymentRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
 
-type ProcessPaymentResponse struct {
+func (x *ProcessPaymentRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *ProcessPaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Итог списания по пассажиру: succeeded — оплачено, pending — ждёт подтверждения пассажиром
+// или уведомления провайдера, failed — отказ или ошибка провайдера, причина в error.
+type PaymentResult struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Payment       *Payment               `protobuf:"bytes,3,opt,name=payment,proto3" json:"payment,omitempty"` // пусто, если платёж не удалось сохранить
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ProcessPaymentResponse) Reset() {
-	*x = ProcessPaymentResponse{}
+func (x *PaymentResult) Reset() {
+	*x = PaymentResult{}
+	mi := &file_payment_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[2])
+	ms.StoreMessageInfo(mi)
 }
-func (x *ProcessPaymentResponse) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*ProcessPaymentResponse) ProtoMessage()    {}
-func (x *ProcessPaymentResponse) ProtoReflect() protoreflect.Message {
+
+func (x *PaymentResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentResult) ProtoMessage() {}
+
+func (x *PaymentResult) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -193,40 +301,66 @@ func (x *ProcessPaymentResponse) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*ProcessPaymentResponse) Descriptor() ([]byte, []int) {
+
+// Deprecated: Use PaymentResult.ProtoReflect.Descriptor instead.
+func (*PaymentResult) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{2}
 }
-func (x *ProcessPaymentResponse) GetPayments() []*Payment {
+
+func (x *PaymentResult) GetUserId() string {
 	if x != nil {
-		return x.Payments
+		return x.UserId
 	}
-	return nil
+	return ""
 }
-func (x *ProcessPaymentResponse) GetSuccess() bool {
+
+func (x *PaymentResult) GetStatus() string {
 	if x != nil {
-		return x.Success
+		return x.Status
 	}
-	return false
+	return ""
+}
+
+func (x *PaymentResult) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
 }
 
-// ---- RefundPaymentRequest ----
+func (x *PaymentResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
 
-type RefundPaymentRequest struct {
+// payments — сохранённые платежи, в том числе неудавшиеся; results — итог по каждому пассажиру.
+// Ошибка по одному пассажиру не прерывает остальных: success=false, если хоть один failed.
+type ProcessPaymentResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
-	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // succeeded, pending, partial или failed — по всем пассажирам
+	Results       []*PaymentResult       `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefundPaymentRequest) Reset() {
-	*x = RefundPaymentRequest{}
+func (x *ProcessPaymentResponse) Reset() {
+	*x = ProcessPaymentResponse{}
+	mi := &file_payment_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[3])
+	ms.StoreMessageInfo(mi)
 }
-func (x *RefundPaymentRequest) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*RefundPaymentRequest) ProtoMessage()    {}
-func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+
+func (x *ProcessPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessPaymentResponse) ProtoMessage() {}
+
+func (x *ProcessPaymentResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -237,40 +371,65 @@ func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+
+// Deprecated: Use ProcessPaymentResponse.ProtoReflect.Descriptor instead.
+func (*ProcessPaymentResponse) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{3}
 }
-func (x *RefundPaymentRequest) GetRoomId() string {
+
+func (x *ProcessPaymentResponse) GetPayments() []*Payment {
 	if x != nil {
-		return x.RoomId
+		return x.Payments
 	}
-	return ""
+	return nil
 }
-func (x *RefundPaymentRequest) GetReason() string {
+
+func (x *ProcessPaymentResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Reason
+		return x.Success
+	}
+	return false
+}
+
+func (x *ProcessPaymentResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
 	}
 	return ""
 }
 
-// ---- RefundPaymentResponse ----
+func (x *ProcessPaymentResponse) GetResults() []*PaymentResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
 
-type RefundPaymentResponse struct {
+// Повторяет неудавшиеся платежи комнаты (или только пассажиров user_ids) новым платежом на ту же сумму.
+// Повторный вызов не создаёт новых платежей, пока прошлая попытка не завершилась неудачей.
+// Только для создателя комнаты и admin.
+type RetryFailedPaymentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Refunds       []*Payment             `protobuf:"bytes,1,rep,name=refunds,proto3" json:"refunds,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *RefundPaymentResponse) Reset() {
-	*x = RefundPaymentResponse{}
+func (x *RetryFailedPaymentsRequest) Reset() {
+	*x = RetryFailedPaymentsRequest{}
+	mi := &file_payment_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[4])
+	ms.StoreMessageInfo(mi)
 }
-func (x *RefundPaymentResponse) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*RefundPaymentResponse) ProtoMessage()    {}
-func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
+
+func (x *RetryFailedPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryFailedPaymentsRequest) ProtoMessage() {}
+
+func (x *RetryFailedPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -281,39 +440,48 @@ func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*RefundPaymentResponse) Descriptor() ([]byte, []int) {
+
+// Deprecated: Use RetryFailedPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*RetryFailedPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{4}
 }
-func (x *RefundPaymentResponse) GetRefunds() []*Payment {
+
+func (x *RetryFailedPaymentsRequest) GetRoomId() string {
 	if x != nil {
-		return x.Refunds
+		return x.RoomId
 	}
-	return nil
+	return ""
 }
-func (x *RefundPaymentResponse) GetSuccess() bool {
+
+func (x *RetryFailedPaymentsRequest) GetUserIds() []string {
 	if x != nil {
-		return x.Success
+		return x.UserIds
 	}
-	return false
+	return nil
 }
 
-// ---- GetPaymentHistoryRequest ----
-
-type GetPaymentHistoryRequest struct {
+// Кто из пассажиров комнаты оплатил свою долю. Водитель поездки и admin видят всех, пассажир — только себя.
+type GetRoomPaymentsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPaymentHistoryRequest) Reset() {
-	*x = GetPaymentHistoryRequest{}
+func (x *GetRoomPaymentsRequest) Reset() {
+	*x = GetRoomPaymentsRequest{}
+	mi := &file_payment_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[5])
+	ms.StoreMessageInfo(mi)
 }
-func (x *GetPaymentHistoryRequest) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*GetPaymentHistoryRequest) ProtoMessage()    {}
-func (x *GetPaymentHistoryRequest) ProtoReflect() protoreflect.Message {
+
+func (x *GetRoomPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomPaymentsRequest) ProtoMessage() {}
+
+func (x *GetRoomPaymentsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -324,33 +492,48 @@ func (x *GetPaymentHistoryRequest) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*GetPaymentHistoryRequest) Descriptor() ([]byte, []int) {
+
+// Deprecated: Use GetRoomPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomPaymentsRequest) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{5}
 }
-func (x *GetPaymentHistoryRequest) GetUserId() string {
+
+func (x *GetRoomPaymentsRequest) GetRoomId() string {
 	if x != nil {
-		return x.UserId
+		return x.RoomId
 	}
 	return ""
 }
 
-// ---- GetPaymentHistoryResponse ----
-
-type GetPaymentHistoryResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+// Доля пассажира: paid — оплачена, pending — ждёт подтверждения по confirmation_url,
+// authorized — холд подтверждён и спишется при завершении, failed — не оплачена.
+type RiderPayment struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status          string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	PaidAmount      float32                `protobuf:"fixed32,3,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	ConfirmationUrl string                 `protobuf:"bytes,4,opt,name=confirmation_url,json=confirmationUrl,proto3" json:"confirmation_url,omitempty"`
+	PaymentId       string                 `protobuf:"bytes,5,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"` // последний платёж пассажира
+	Reminders       int32                  `protobuf:"varint,6,opt,name=reminders,proto3" json:"reminders,omitempty"`                 // сколько раз напоминали подтвердить
+	Currency        string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *GetPaymentHistoryResponse) Reset() {
-	*x = GetPaymentHistoryResponse{}
+func (x *RiderPayment) Reset() {
+	*x = RiderPayment{}
+	mi := &file_payment_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(&file_payment_proto_msgTypes[6])
+	ms.StoreMessageInfo(mi)
 }
-func (x *GetPaymentHistoryResponse) String() string { return protoimpl.X.MessageStringOf(x) }
-func (*GetPaymentHistoryResponse) ProtoMessage()    {}
-func (x *GetPaymentHistoryResponse) ProtoReflect() protoreflect.Message {
+
+func (x *RiderPayment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RiderPayment) ProtoMessage() {}
+
+func (x *RiderPayment) ProtoReflect() protoreflect.Message {
 	mi := &file_payment_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -361,141 +544,4533 @@ func (x *GetPaymentHistoryResponse) ProtoReflect() protoreflect.Message {
 	}
 	return mi.MessageOf(x)
 }
-func (*GetPaymentHistoryResponse) Descriptor() ([]byte, []int) {
+
+// Deprecated: Use RiderPayment.ProtoReflect.Descriptor instead.
+func (*RiderPayment) Descriptor() ([]byte, []int) {
 	return file_payment_proto_rawDescGZIP(), []int{6}
 }
-func (x *GetPaymentHistoryResponse) GetPayments() []*Payment {
+
+func (x *RiderPayment) GetUserId() string {
 	if x != nil {
-		return x.Payments
+		return x.UserId
 	}
-	return nil
+	return ""
 }
 
-// ---- rawDesc & init ----
-
-var File_payment_proto protoreflect.FileDescriptor
+func (x *RiderPayment) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
 
-var file_payment_proto_rawDesc = string([]byte{
-	0x0a, 0x0d, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
-	0x07, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x22, 0x97, 0x02, 0x0a, 0x07, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07,
-	0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75,
-	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x18,
-	0x04, 0x20, 0x01, 0x28, 0x02, 0x52, 0x06, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x1a, 0x0a,
-	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x63, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x12, 0x2e, 0x0a, 0x13, 0x79, 0x6f, 0x6f, 0x6b, 0x61, 0x73, 0x73, 0x61, 0x5f, 0x70, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
-	0x79, 0x6f, 0x6f, 0x6b, 0x61, 0x73, 0x73, 0x61, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x49,
-	0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18,
-	0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74,
-	0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18,
-	0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x22, 0x95, 0x01, 0x0a, 0x15, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x50, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07,
-	0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
-	0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64,
-	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x73,
-	0x12, 0x26, 0x0a, 0x0f, 0x61, 0x6d, 0x6f, 0x75, 0x6e, 0x74, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x75,
-	0x73, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0d, 0x61, 0x6d, 0x6f, 0x75, 0x6e,
-	0x74, 0x50, 0x65, 0x72, 0x55, 0x73, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
-	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x60, 0x0a, 0x16, 0x50, 0x72,
-	0x6f, 0x63, 0x65, 0x73, 0x73, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x73,
-	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63, 0x63, 0x65, 0x73, 0x73, 0x22, 0x47, 0x0a, 0x14,
-	0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x6d, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x6d, 0x49, 0x64, 0x12, 0x16, 0x0a,
-	0x06, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72,
-	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x22, 0x5d, 0x0a, 0x15, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x50,
-	0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2a,
-	0x0a, 0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x10, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x52, 0x07, 0x72, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x73, 0x75,
-	0x63, 0x63, 0x65, 0x73, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x07, 0x73, 0x75, 0x63,
-	0x63, 0x65, 0x73, 0x73, 0x22, 0x33, 0x0a, 0x18, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x22, 0x49, 0x0a, 0x19, 0x47, 0x65, 0x74,
-	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x08, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e,
-	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x08, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x73, 0x32, 0x8f, 0x02, 0x0a, 0x0e, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x51, 0x0a, 0x0e, 0x50, 0x72, 0x6f, 0x63, 0x65,
-	0x73, 0x73, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1e, 0x2e, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x6f, 0x63, 0x65, 0x73, 0x73, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a, 0x0d, 0x52, 0x65,
-	0x66, 0x75, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x1d, 0x2e, 0x70, 0x61,
-	0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d,
-	0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x70, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x52, 0x65, 0x66, 0x75, 0x6e, 0x64, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5a, 0x0a, 0x11, 0x47, 0x65,
-	0x74, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x12,
-	0x21, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74, 0x50, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x22, 0x2e, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x47, 0x65, 0x74,
-	0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x48, 0x69, 0x73, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x31, 0x5a, 0x2f, 0x77, 0x65, 0x5f, 0x72, 0x69, 0x64,
-	0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x73, 0x65, 0x72, 0x76, 0x69,
-	0x63, 0x65, 0x73, 0x2f, 0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x72, 0x76,
-	0x69, 0x63, 0x65, 0x2f, 0x70, 0x62, 0x3b, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
-})
-
-// total bytes: 1185
+func (x *RiderPayment) GetPaidAmount() float32 {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return 0
+}
 
-var (
-	file_payment_proto_rawDescOnce sync.Once
-	file_payment_proto_rawDescData []byte
-)
+func (x *RiderPayment) GetConfirmationUrl() string {
+	if x != nil {
+		return x.ConfirmationUrl
+	}
+	return ""
+}
 
-func file_payment_proto_rawDescGZIP() []byte {
-	file_payment_proto_rawDescOnce.Do(func() {
-		file_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)))
-	})
-	return file_payment_proto_rawDescData
+func (x *RiderPayment) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
 }
 
-var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
-var file_payment_proto_goTypes = []any{
-	(*Payment)(nil),                   // 0: payment.Payment
-	(*ProcessPaymentRequest)(nil),     // 1: payment.ProcessPaymentRequest
-	(*ProcessPaymentResponse)(nil),    // 2: payment.ProcessPaymentResponse
-	(*RefundPaymentRequest)(nil),      // 3: payment.RefundPaymentRequest
-	(*RefundPaymentResponse)(nil),     // 4: payment.RefundPaymentResponse
-	(*GetPaymentHistoryRequest)(nil),  // 5: payment.GetPaymentHistoryRequest
-	(*GetPaymentHistoryResponse)(nil), // 6: payment.GetPaymentHistoryResponse
+func (x *RiderPayment) GetReminders() int32 {
+	if x != nil {
+		return x.Reminders
+	}
+	return 0
 }
-var file_payment_proto_depIdxs = []int32{
-	0, // 0: payment.ProcessPaymentResponse.payments -> payment.Payment
-	0, // 1: payment.RefundPaymentResponse.refunds   -> payment.Payment
-	0, // 2: payment.GetPaymentHistoryResponse.payments -> payment.Payment
-	1, // 3: payment.PaymentService.ProcessPayment:input_type
-	3, // 4: payment.PaymentService.RefundPayment:input_type
-	5, // 5: payment.PaymentService.GetPaymentHistory:input_type
-	2, // 6: payment.PaymentService.ProcessPayment:output_type
-	4, // 7: payment.PaymentService.RefundPayment:output_type
-	6, // 8: payment.PaymentService.GetPaymentHistory:output_type
-	6, // [6:9] is the sub-list for method output_type
-	3, // [3:6] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+
+func (x *RiderPayment) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
 }
 
-func init() { file_payment_proto_init() }
+type GetRoomPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Riders        []*RiderPayment        `protobuf:"bytes,2,rep,name=riders,proto3" json:"riders,omitempty"`
+	Paid          int32                  `protobuf:"varint,3,opt,name=paid,proto3" json:"paid,omitempty"`
+	Unpaid        int32                  `protobuf:"varint,4,opt,name=unpaid,proto3" json:"unpaid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomPaymentsResponse) Reset() {
+	*x = GetRoomPaymentsResponse{}
+	mi := &file_payment_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomPaymentsResponse) ProtoMessage() {}
+
+func (x *GetRoomPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetRoomPaymentsResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetRoomPaymentsResponse) GetRiders() []*RiderPayment {
+	if x != nil {
+		return x.Riders
+	}
+	return nil
+}
+
+func (x *GetRoomPaymentsResponse) GetPaid() int32 {
+	if x != nil {
+		return x.Paid
+	}
+	return 0
+}
+
+func (x *GetRoomPaymentsResponse) GetUnpaid() int32 {
+	if x != nil {
+		return x.Unpaid
+	}
+	return 0
+}
+
+type RetryFailedPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Results       []*PaymentResult       `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryFailedPaymentsResponse) Reset() {
+	*x = RetryFailedPaymentsResponse{}
+	mi := &file_payment_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryFailedPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryFailedPaymentsResponse) ProtoMessage() {}
+
+func (x *RetryFailedPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryFailedPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*RetryFailedPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RetryFailedPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *RetryFailedPaymentsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RetryFailedPaymentsResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *RetryFailedPaymentsResponse) GetResults() []*PaymentResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Без payment_ids возвращаются все успешные платежи комнаты (или только платежи user_ids).
+// Сумма по платежу берётся из amounts[payment_id], иначе amount, иначе весь остаток платежа.
+type RefundPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	PaymentIds    []string               `protobuf:"bytes,3,rep,name=payment_ids,json=paymentIds,proto3" json:"payment_ids,omitempty"`
+	UserIds       []string               `protobuf:"bytes,4,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	Amount        float32                `protobuf:"fixed32,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Amounts       map[string]float32     `protobuf:"bytes,6,rep,name=amounts,proto3" json:"amounts,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"fixed32,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentRequest) Reset() {
+	*x = RefundPaymentRequest{}
+	mi := &file_payment_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentRequest) ProtoMessage() {}
+
+func (x *RefundPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RefundPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RefundPaymentRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *RefundPaymentRequest) GetPaymentIds() []string {
+	if x != nil {
+		return x.PaymentIds
+	}
+	return nil
+}
+
+func (x *RefundPaymentRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *RefundPaymentRequest) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *RefundPaymentRequest) GetAmounts() map[string]float32 {
+	if x != nil {
+		return x.Amounts
+	}
+	return nil
+}
+
+type Refund struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	RefundId         string                 `protobuf:"bytes,1,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	PaymentId        string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	UserId           string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount           float32                `protobuf:"fixed32,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency         string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status           string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending, succeeded, failed
+	YookassaRefundId string                 `protobuf:"bytes,7,opt,name=yookassa_refund_id,json=yookassaRefundId,proto3" json:"yookassa_refund_id,omitempty"`
+	Error            string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt        string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Refund) Reset() {
+	*x = Refund{}
+	mi := &file_payment_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Refund) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Refund) ProtoMessage() {}
+
+func (x *Refund) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Refund.ProtoReflect.Descriptor instead.
+func (*Refund) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Refund) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *Refund) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Refund) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Refund) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Refund) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Refund) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Refund) GetYookassaRefundId() string {
+	if x != nil {
+		return x.YookassaRefundId
+	}
+	return ""
+}
+
+func (x *Refund) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Refund) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+// refunds — платежи с обновлённым статусом, items — результат по каждому возврату.
+// Ошибка одного возврата не прерывает остальные: success=false, причина в items[].error.
+type RefundPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Refunds       []*Payment             `protobuf:"bytes,1,rep,name=refunds,proto3" json:"refunds,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Items         []*Refund              `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RefundPaymentResponse) Reset() {
+	*x = RefundPaymentResponse{}
+	mi := &file_payment_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefundPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefundPaymentResponse) ProtoMessage() {}
+
+func (x *RefundPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefundPaymentResponse.ProtoReflect.Descriptor instead.
+func (*RefundPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RefundPaymentResponse) GetRefunds() []*Payment {
+	if x != nil {
+		return x.Refunds
+	}
+	return nil
+}
+
+func (x *RefundPaymentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *RefundPaymentResponse) GetItems() []*Refund {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+// Холд оценочной доли пассажира; вызывается room_service от имени вступающего пассажира.
+// Повторный вызов возвращает действующий холд.
+type AuthorizePaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount        float32                `protobuf:"fixed32,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"` // как в ProcessPaymentRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AuthorizePaymentRequest) Reset() {
+	*x = AuthorizePaymentRequest{}
+	mi := &file_payment_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizePaymentRequest) ProtoMessage() {}
+
+func (x *AuthorizePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizePaymentRequest.ProtoReflect.Descriptor instead.
+func (*AuthorizePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AuthorizePaymentRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *AuthorizePaymentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AuthorizePaymentRequest) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AuthorizePaymentRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *AuthorizePaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type AuthorizePaymentResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Payment         *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	ConfirmationUrl string                 `protobuf:"bytes,2,opt,name=confirmation_url,json=confirmationUrl,proto3" json:"confirmation_url,omitempty"` // куда отправить пассажира подтвердить холд; пусто — подтверждение не нужно
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AuthorizePaymentResponse) Reset() {
+	*x = AuthorizePaymentResponse{}
+	mi := &file_payment_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AuthorizePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AuthorizePaymentResponse) ProtoMessage() {}
+
+func (x *AuthorizePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AuthorizePaymentResponse.ProtoReflect.Descriptor instead.
+func (*AuthorizePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AuthorizePaymentResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+func (x *AuthorizePaymentResponse) GetConfirmationUrl() string {
+	if x != nil {
+		return x.ConfirmationUrl
+	}
+	return ""
+}
+
+// Списание итоговой доли: холд списывается на сумму доли (не больше холда), недостающее
+// и пассажиры без холда оплачиваются обычным платежом. Проверки — как в ProcessPaymentRequest.
+type CapturePaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	AmountPerUser float32                `protobuf:"fixed32,3,opt,name=amount_per_user,json=amountPerUser,proto3" json:"amount_per_user,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	DriverId      string                 `protobuf:"bytes,5,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"` // как в ProcessPaymentRequest
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`                 // как в ProcessPaymentRequest
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapturePaymentRequest) Reset() {
+	*x = CapturePaymentRequest{}
+	mi := &file_payment_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapturePaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapturePaymentRequest) ProtoMessage() {}
+
+func (x *CapturePaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapturePaymentRequest.ProtoReflect.Descriptor instead.
+func (*CapturePaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CapturePaymentRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CapturePaymentRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *CapturePaymentRequest) GetAmountPerUser() float32 {
+	if x != nil {
+		return x.AmountPerUser
+	}
+	return 0
+}
+
+func (x *CapturePaymentRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CapturePaymentRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *CapturePaymentRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type CapturePaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`   // как в ProcessPaymentResponse
+	Results       []*PaymentResult       `protobuf:"bytes,4,rep,name=results,proto3" json:"results,omitempty"` // итог по каждому пассажиру: худший из его платежей
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CapturePaymentResponse) Reset() {
+	*x = CapturePaymentResponse{}
+	mi := &file_payment_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CapturePaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CapturePaymentResponse) ProtoMessage() {}
+
+func (x *CapturePaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CapturePaymentResponse.ProtoReflect.Descriptor instead.
+func (*CapturePaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CapturePaymentResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *CapturePaymentResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CapturePaymentResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *CapturePaymentResponse) GetResults() []*PaymentResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Отмена холдов и не использованных промокодов: с user_id — пассажира (выход из комнаты, неявка),
+// без — всей комнаты (только admin)
+type VoidPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidPaymentRequest) Reset() {
+	*x = VoidPaymentRequest{}
+	mi := &file_payment_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidPaymentRequest) ProtoMessage() {}
+
+func (x *VoidPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidPaymentRequest.ProtoReflect.Descriptor instead.
+func (*VoidPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *VoidPaymentRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *VoidPaymentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *VoidPaymentRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type VoidPaymentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoidPaymentResponse) Reset() {
+	*x = VoidPaymentResponse{}
+	mi := &file_payment_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoidPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoidPaymentResponse) ProtoMessage() {}
+
+func (x *VoidPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoidPaymentResponse.ProtoReflect.Descriptor instead.
+func (*VoidPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *VoidPaymentResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+// ChargeFeeRequest — штраф пассажиру user_id. Сумма и валюта берутся из штрафа, записанного в комнате
+// (room_service GetRoomBilling), штраф начисляется создателю комнаты.
+type ChargeFeeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"` // cancellation, no_show
+	Description   string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChargeFeeRequest) Reset() {
+	*x = ChargeFeeRequest{}
+	mi := &file_payment_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChargeFeeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChargeFeeRequest) ProtoMessage() {}
+
+func (x *ChargeFeeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChargeFeeRequest.ProtoReflect.Descriptor instead.
+func (*ChargeFeeRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ChargeFeeRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ChargeFeeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ChargeFeeRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChargeFeeRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+type ChargeFeeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payment       *Payment               `protobuf:"bytes,1,opt,name=payment,proto3" json:"payment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChargeFeeResponse) Reset() {
+	*x = ChargeFeeResponse{}
+	mi := &file_payment_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChargeFeeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChargeFeeResponse) ProtoMessage() {}
+
+func (x *ChargeFeeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChargeFeeResponse.ProtoReflect.Descriptor instead.
+func (*ChargeFeeResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ChargeFeeResponse) GetPayment() *Payment {
+	if x != nil {
+		return x.Payment
+	}
+	return nil
+}
+
+// GetPaymentHistoryRequest — страница истории платежей, новые первыми; пустые фильтры не учитываются
+type GetPaymentHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`  // по умолчанию 20, не больше 100
+	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"` // next_cursor предыдущей страницы
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	RoomId        string                 `protobuf:"bytes,5,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,6,opt,name=kind,proto3" json:"kind,omitempty"` // ride, cancellation_fee, no_show_fee
+	From          *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=from,proto3" json:"from,omitempty"` // created_at >= from
+	To            *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=to,proto3" json:"to,omitempty"`     // created_at < to
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentHistoryRequest) Reset() {
+	*x = GetPaymentHistoryRequest{}
+	mi := &file_payment_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentHistoryRequest) ProtoMessage() {}
+
+func (x *GetPaymentHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPaymentHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetPaymentHistoryRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetPaymentHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryRequest) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *GetPaymentHistoryRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+type GetPaymentHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"` // пусто — страниц больше нет
+	Totals        []*PaymentTotals       `protobuf:"bytes,3,rep,name=totals,proto3" json:"totals,omitempty"`                           // по всем платежам под фильтрами, по валютам
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPaymentHistoryResponse) Reset() {
+	*x = GetPaymentHistoryResponse{}
+	mi := &file_payment_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPaymentHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPaymentHistoryResponse) ProtoMessage() {}
+
+func (x *GetPaymentHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPaymentHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPaymentHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetPaymentHistoryResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *GetPaymentHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *GetPaymentHistoryResponse) GetTotals() []*PaymentTotals {
+	if x != nil {
+		return x.Totals
+	}
+	return nil
+}
+
+// PaymentTotals — итоги истории в одной валюте: spent — списано, refunded — возвращено
+type PaymentTotals struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Currency      string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"`
+	Count         int32                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	Spent         float32                `protobuf:"fixed32,3,opt,name=spent,proto3" json:"spent,omitempty"`
+	Refunded      float32                `protobuf:"fixed32,4,opt,name=refunded,proto3" json:"refunded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentTotals) Reset() {
+	*x = PaymentTotals{}
+	mi := &file_payment_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentTotals) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentTotals) ProtoMessage() {}
+
+func (x *PaymentTotals) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentTotals.ProtoReflect.Descriptor instead.
+func (*PaymentTotals) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PaymentTotals) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentTotals) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *PaymentTotals) GetSpent() float32 {
+	if x != nil {
+		return x.Spent
+	}
+	return 0
+}
+
+func (x *PaymentTotals) GetRefunded() float32 {
+	if x != nil {
+		return x.Refunded
+	}
+	return 0
+}
+
+// AnonymizeUserPayments стирает персональные данные из платежей удаляемого пользователя.
+// Суммы и статусы остаются для бухгалтерии.
+type AnonymizeUserPaymentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeUserPaymentsRequest) Reset() {
+	*x = AnonymizeUserPaymentsRequest{}
+	mi := &file_payment_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeUserPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeUserPaymentsRequest) ProtoMessage() {}
+
+func (x *AnonymizeUserPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeUserPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*AnonymizeUserPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AnonymizeUserPaymentsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type AnonymizeUserPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Anonymized    int32                  `protobuf:"varint,1,opt,name=anonymized,proto3" json:"anonymized,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnonymizeUserPaymentsResponse) Reset() {
+	*x = AnonymizeUserPaymentsResponse{}
+	mi := &file_payment_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnonymizeUserPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnonymizeUserPaymentsResponse) ProtoMessage() {}
+
+func (x *AnonymizeUserPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnonymizeUserPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*AnonymizeUserPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *AnonymizeUserPaymentsResponse) GetAnonymized() int32 {
+	if x != nil {
+		return x.Anonymized
+	}
+	return 0
+}
+
+// Пустые поля не учитываются; даты в RFC3339
+type SearchPaymentsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,3,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	CreatedFrom   string                 `protobuf:"bytes,4,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     string                 `protobuf:"bytes,5,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPaymentsRequest) Reset() {
+	*x = SearchPaymentsRequest{}
+	mi := &file_payment_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPaymentsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPaymentsRequest) ProtoMessage() {}
+
+func (x *SearchPaymentsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPaymentsRequest.ProtoReflect.Descriptor instead.
+func (*SearchPaymentsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *SearchPaymentsRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *SearchPaymentsRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SearchPaymentsRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+func (x *SearchPaymentsRequest) GetCreatedFrom() string {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return ""
+}
+
+func (x *SearchPaymentsRequest) GetCreatedTo() string {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return ""
+}
+
+func (x *SearchPaymentsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *SearchPaymentsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchPaymentsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payments      []*Payment             `protobuf:"bytes,1,rep,name=payments,proto3" json:"payments,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchPaymentsResponse) Reset() {
+	*x = SearchPaymentsResponse{}
+	mi := &file_payment_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchPaymentsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchPaymentsResponse) ProtoMessage() {}
+
+func (x *SearchPaymentsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchPaymentsResponse.ProtoReflect.Descriptor instead.
+func (*SearchPaymentsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *SearchPaymentsResponse) GetPayments() []*Payment {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+type StreamPaymentUpdatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamPaymentUpdatesRequest) Reset() {
+	*x = StreamPaymentUpdatesRequest{}
+	mi := &file_payment_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamPaymentUpdatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamPaymentUpdatesRequest) ProtoMessage() {}
+
+func (x *StreamPaymentUpdatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamPaymentUpdatesRequest.ProtoReflect.Descriptor instead.
+func (*StreamPaymentUpdatesRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{27}
+}
+
+type PaymentUpdate struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId       string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	RoomId          string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Event           string                 `protobuf:"bytes,4,opt,name=event,proto3" json:"event,omitempty"`   // payment.succeeded, payment.canceled, refund.succeeded, payment.reminder
+	Status          string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // новый статус платежа
+	RefundId        string                 `protobuf:"bytes,6,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"`
+	Amount          float32                `protobuf:"fixed32,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency        string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`
+	UpdatedAt       string                 `protobuf:"bytes,9,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	ConfirmationUrl string                 `protobuf:"bytes,10,opt,name=confirmation_url,json=confirmationUrl,proto3" json:"confirmation_url,omitempty"` // в payment.reminder — ссылка для оплаты
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *PaymentUpdate) Reset() {
+	*x = PaymentUpdate{}
+	mi := &file_payment_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentUpdate) ProtoMessage() {}
+
+func (x *PaymentUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentUpdate.ProtoReflect.Descriptor instead.
+func (*PaymentUpdate) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PaymentUpdate) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetEvent() string {
+	if x != nil {
+		return x.Event
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentUpdate) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *PaymentUpdate) GetConfirmationUrl() string {
+	if x != nil {
+		return x.ConfirmationUrl
+	}
+	return ""
+}
+
+type PaymentMethod struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MethodId      string                 `protobuf:"bytes,1,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // pending — ждёт подтверждения, active
+	Type          string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`     // bank_card, sbp, ...
+	Title         string                 `protobuf:"bytes,5,opt,name=title,proto3" json:"title,omitempty"`   // например, «Bank card *4444»
+	IsDefault     bool                   `protobuf:"varint,6,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentMethod) Reset() {
+	*x = PaymentMethod{}
+	mi := &file_payment_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentMethod) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentMethod) ProtoMessage() {}
+
+func (x *PaymentMethod) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentMethod.ProtoReflect.Descriptor instead.
+func (*PaymentMethod) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PaymentMethod) GetMethodId() string {
+	if x != nil {
+		return x.MethodId
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *PaymentMethod) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *PaymentMethod) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListPaymentMethodsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentMethodsRequest) Reset() {
+	*x = ListPaymentMethodsRequest{}
+	mi := &file_payment_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentMethodsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentMethodsRequest) ProtoMessage() {}
+
+func (x *ListPaymentMethodsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentMethodsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentMethodsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListPaymentMethodsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListPaymentMethodsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Methods       []*PaymentMethod       `protobuf:"bytes,1,rep,name=methods,proto3" json:"methods,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPaymentMethodsResponse) Reset() {
+	*x = ListPaymentMethodsResponse{}
+	mi := &file_payment_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentMethodsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentMethodsResponse) ProtoMessage() {}
+
+func (x *ListPaymentMethodsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentMethodsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentMethodsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListPaymentMethodsResponse) GetMethods() []*PaymentMethod {
+	if x != nil {
+		return x.Methods
+	}
+	return nil
+}
+
+type AddPaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReturnUrl     string                 `protobuf:"bytes,2,opt,name=return_url,json=returnUrl,proto3" json:"return_url,omitempty"` // куда вернуть пользователя после подтверждения
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodRequest) Reset() {
+	*x = AddPaymentMethodRequest{}
+	mi := &file_payment_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodRequest) ProtoMessage() {}
+
+func (x *AddPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *AddPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AddPaymentMethodRequest) GetReturnUrl() string {
+	if x != nil {
+		return x.ReturnUrl
+	}
+	return ""
+}
+
+type AddPaymentMethodResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Method          *PaymentMethod         `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	ConfirmationUrl string                 `protobuf:"bytes,2,opt,name=confirmation_url,json=confirmationUrl,proto3" json:"confirmation_url,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AddPaymentMethodResponse) Reset() {
+	*x = AddPaymentMethodResponse{}
+	mi := &file_payment_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddPaymentMethodResponse) ProtoMessage() {}
+
+func (x *AddPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*AddPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AddPaymentMethodResponse) GetMethod() *PaymentMethod {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+func (x *AddPaymentMethodResponse) GetConfirmationUrl() string {
+	if x != nil {
+		return x.ConfirmationUrl
+	}
+	return ""
+}
+
+type RemovePaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MethodId      string                 `protobuf:"bytes,2,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePaymentMethodRequest) Reset() {
+	*x = RemovePaymentMethodRequest{}
+	mi := &file_payment_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePaymentMethodRequest) ProtoMessage() {}
+
+func (x *RemovePaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*RemovePaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *RemovePaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RemovePaymentMethodRequest) GetMethodId() string {
+	if x != nil {
+		return x.MethodId
+	}
+	return ""
+}
+
+type RemovePaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemovePaymentMethodResponse) Reset() {
+	*x = RemovePaymentMethodResponse{}
+	mi := &file_payment_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemovePaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemovePaymentMethodResponse) ProtoMessage() {}
+
+func (x *RemovePaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemovePaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*RemovePaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RemovePaymentMethodResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+type SetDefaultPaymentMethodRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MethodId      string                 `protobuf:"bytes,2,opt,name=method_id,json=methodId,proto3" json:"method_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultPaymentMethodRequest) Reset() {
+	*x = SetDefaultPaymentMethodRequest{}
+	mi := &file_payment_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultPaymentMethodRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultPaymentMethodRequest) ProtoMessage() {}
+
+func (x *SetDefaultPaymentMethodRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultPaymentMethodRequest.ProtoReflect.Descriptor instead.
+func (*SetDefaultPaymentMethodRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SetDefaultPaymentMethodRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetDefaultPaymentMethodRequest) GetMethodId() string {
+	if x != nil {
+		return x.MethodId
+	}
+	return ""
+}
+
+type SetDefaultPaymentMethodResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Method        *PaymentMethod         `protobuf:"bytes,1,opt,name=method,proto3" json:"method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDefaultPaymentMethodResponse) Reset() {
+	*x = SetDefaultPaymentMethodResponse{}
+	mi := &file_payment_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDefaultPaymentMethodResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDefaultPaymentMethodResponse) ProtoMessage() {}
+
+func (x *SetDefaultPaymentMethodResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDefaultPaymentMethodResponse.ProtoReflect.Descriptor instead.
+func (*SetDefaultPaymentMethodResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SetDefaultPaymentMethodResponse) GetMethod() *PaymentMethod {
+	if x != nil {
+		return x.Method
+	}
+	return nil
+}
+
+type GetWalletRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"` // сколько последних проводок вернуть, по умолчанию 50
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWalletRequest) Reset() {
+	*x = GetWalletRequest{}
+	mi := &file_payment_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWalletRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWalletRequest) ProtoMessage() {}
+
+func (x *GetWalletRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWalletRequest.ProtoReflect.Descriptor instead.
+func (*GetWalletRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *GetWalletRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetWalletRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type Account struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AccountId     string                 `protobuf:"bytes,1,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // rider, driver
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Balance       float32                `protobuf:"fixed32,4,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Account) Reset() {
+	*x = Account{}
+	mi := &file_payment_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Account) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Account) ProtoMessage() {}
+
+func (x *Account) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Account.ProtoReflect.Descriptor instead.
+func (*Account) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *Account) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *Account) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Account) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Account) GetBalance() float32 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+// Проводка по счёту; amount — изменение баланса счёта (отрицательное — уменьшение)
+type LedgerEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TxId          string                 `protobuf:"bytes,1,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`           // charge, refund, commission, payout
+	Reference     string                 `protobuf:"bytes,3,opt,name=reference,proto3" json:"reference,omitempty"` // id платежа, возврата или выплаты
+	AccountId     string                 `protobuf:"bytes,4,opt,name=account_id,json=accountId,proto3" json:"account_id,omitempty"`
+	Amount        float32                `protobuf:"fixed32,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description   string                 `protobuf:"bytes,6,opt,name=description,proto3" json:"description,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LedgerEntry) Reset() {
+	*x = LedgerEntry{}
+	mi := &file_payment_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LedgerEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LedgerEntry) ProtoMessage() {}
+
+func (x *LedgerEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LedgerEntry.ProtoReflect.Descriptor instead.
+func (*LedgerEntry) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *LedgerEntry) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetReference() string {
+	if x != nil {
+		return x.Reference
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAccountId() string {
+	if x != nil {
+		return x.AccountId
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *LedgerEntry) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *LedgerEntry) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetWalletResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Accounts      []*Account             `protobuf:"bytes,1,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	Entries       []*LedgerEntry         `protobuf:"bytes,2,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetWalletResponse) Reset() {
+	*x = GetWalletResponse{}
+	mi := &file_payment_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetWalletResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetWalletResponse) ProtoMessage() {}
+
+func (x *GetWalletResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetWalletResponse.ProtoReflect.Descriptor instead.
+func (*GetWalletResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetWalletResponse) GetAccounts() []*Account {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
+}
+
+func (x *GetWalletResponse) GetEntries() []*LedgerEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+// Даты в RFC3339; пустые — без ограничения
+type GetDriverEarningsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DriverId      string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	From          string                 `protobuf:"bytes,2,opt,name=from,proto3" json:"from,omitempty"`
+	To            string                 `protobuf:"bytes,3,opt,name=to,proto3" json:"to,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"` // валюта итогов; пусто — валюта отчётов сервиса
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverEarningsRequest) Reset() {
+	*x = GetDriverEarningsRequest{}
+	mi := &file_payment_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverEarningsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverEarningsRequest) ProtoMessage() {}
+
+func (x *GetDriverEarningsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverEarningsRequest.ProtoReflect.Descriptor instead.
+func (*GetDriverEarningsRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetDriverEarningsRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *GetDriverEarningsRequest) GetFrom() string {
+	if x != nil {
+		return x.From
+	}
+	return ""
+}
+
+func (x *GetDriverEarningsRequest) GetTo() string {
+	if x != nil {
+		return x.To
+	}
+	return ""
+}
+
+func (x *GetDriverEarningsRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+// Заработок за поездку: сумма по оплаченным долям пассажиров
+type RideEarning struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Gross         float32                `protobuf:"fixed32,2,opt,name=gross,proto3" json:"gross,omitempty"`           // оплачено пассажирами
+	Commission    float32                `protobuf:"fixed32,3,opt,name=commission,proto3" json:"commission,omitempty"` // комиссия платформы
+	Net           float32                `protobuf:"fixed32,4,opt,name=net,proto3" json:"net,omitempty"`               // водителю
+	Payments      int32                  `protobuf:"varint,5,opt,name=payments,proto3" json:"payments,omitempty"`
+	PaidOut       bool                   `protobuf:"varint,6,opt,name=paid_out,json=paidOut,proto3" json:"paid_out,omitempty"` // всё вошло в выплаты
+	EarnedAt      string                 `protobuf:"bytes,7,opt,name=earned_at,json=earnedAt,proto3" json:"earned_at,omitempty"`
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"` // валюта поездки
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RideEarning) Reset() {
+	*x = RideEarning{}
+	mi := &file_payment_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RideEarning) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RideEarning) ProtoMessage() {}
+
+func (x *RideEarning) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RideEarning.ProtoReflect.Descriptor instead.
+func (*RideEarning) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RideEarning) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RideEarning) GetGross() float32 {
+	if x != nil {
+		return x.Gross
+	}
+	return 0
+}
+
+func (x *RideEarning) GetCommission() float32 {
+	if x != nil {
+		return x.Commission
+	}
+	return 0
+}
+
+func (x *RideEarning) GetNet() float32 {
+	if x != nil {
+		return x.Net
+	}
+	return 0
+}
+
+func (x *RideEarning) GetPayments() int32 {
+	if x != nil {
+		return x.Payments
+	}
+	return 0
+}
+
+func (x *RideEarning) GetPaidOut() bool {
+	if x != nil {
+		return x.PaidOut
+	}
+	return false
+}
+
+func (x *RideEarning) GetEarnedAt() string {
+	if x != nil {
+		return x.EarnedAt
+	}
+	return ""
+}
+
+func (x *RideEarning) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Payout struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PayoutId      string                 `protobuf:"bytes,1,opt,name=payout_id,json=payoutId,proto3" json:"payout_id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Amount        float32                `protobuf:"fixed32,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // pending, succeeded, failed
+	PeriodEnd     string                 `protobuf:"bytes,6,opt,name=period_end,json=periodEnd,proto3" json:"period_end,omitempty"`
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Payout) Reset() {
+	*x = Payout{}
+	mi := &file_payment_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Payout) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Payout) ProtoMessage() {}
+
+func (x *Payout) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Payout.ProtoReflect.Descriptor instead.
+func (*Payout) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *Payout) GetPayoutId() string {
+	if x != nil {
+		return x.PayoutId
+	}
+	return ""
+}
+
+func (x *Payout) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *Payout) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Payout) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Payout) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Payout) GetPeriodEnd() string {
+	if x != nil {
+		return x.PeriodEnd
+	}
+	return ""
+}
+
+func (x *Payout) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Payout) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetDriverEarningsResponse struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DriverId   string                 `protobuf:"bytes,1,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	Gross      float32                `protobuf:"fixed32,2,opt,name=gross,proto3" json:"gross,omitempty"`
+	Commission float32                `protobuf:"fixed32,3,opt,name=commission,proto3" json:"commission,omitempty"`
+	Net        float32                `protobuf:"fixed32,4,opt,name=net,proto3" json:"net,omitempty"`
+	PaidOut    float32                `protobuf:"fixed32,5,opt,name=paid_out,json=paidOut,proto3" json:"paid_out,omitempty"` // вошло в выплаты
+	Unpaid     float32                `protobuf:"fixed32,6,opt,name=unpaid,proto3" json:"unpaid,omitempty"`                  // ждёт ближайшей выплаты
+	Rides      []*RideEarning         `protobuf:"bytes,7,rep,name=rides,proto3" json:"rides,omitempty"`
+	Payouts    []*Payout              `protobuf:"bytes,8,rep,name=payouts,proto3" json:"payouts,omitempty"`
+	// Валюта итогов: заработок в других валютах пересчитывается по курсам; поездки — в своей валюте
+	Currency      string `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDriverEarningsResponse) Reset() {
+	*x = GetDriverEarningsResponse{}
+	mi := &file_payment_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDriverEarningsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDriverEarningsResponse) ProtoMessage() {}
+
+func (x *GetDriverEarningsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDriverEarningsResponse.ProtoReflect.Descriptor instead.
+func (*GetDriverEarningsResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *GetDriverEarningsResponse) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *GetDriverEarningsResponse) GetGross() float32 {
+	if x != nil {
+		return x.Gross
+	}
+	return 0
+}
+
+func (x *GetDriverEarningsResponse) GetCommission() float32 {
+	if x != nil {
+		return x.Commission
+	}
+	return 0
+}
+
+func (x *GetDriverEarningsResponse) GetNet() float32 {
+	if x != nil {
+		return x.Net
+	}
+	return 0
+}
+
+func (x *GetDriverEarningsResponse) GetPaidOut() float32 {
+	if x != nil {
+		return x.PaidOut
+	}
+	return 0
+}
+
+func (x *GetDriverEarningsResponse) GetUnpaid() float32 {
+	if x != nil {
+		return x.Unpaid
+	}
+	return 0
+}
+
+func (x *GetDriverEarningsResponse) GetRides() []*RideEarning {
+	if x != nil {
+		return x.Rides
+	}
+	return nil
+}
+
+func (x *GetDriverEarningsResponse) GetPayouts() []*Payout {
+	if x != nil {
+		return x.Payouts
+	}
+	return nil
+}
+
+func (x *GetDriverEarningsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type SetReceiptContactRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,3,opt,name=phone,proto3" json:"phone,omitempty"` // с кодом страны, например +79001234567
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReceiptContactRequest) Reset() {
+	*x = SetReceiptContactRequest{}
+	mi := &file_payment_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReceiptContactRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReceiptContactRequest) ProtoMessage() {}
+
+func (x *SetReceiptContactRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReceiptContactRequest.ProtoReflect.Descriptor instead.
+func (*SetReceiptContactRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *SetReceiptContactRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetReceiptContactRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetReceiptContactRequest) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type SetReceiptContactResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Email         string                 `protobuf:"bytes,1,opt,name=email,proto3" json:"email,omitempty"`
+	Phone         string                 `protobuf:"bytes,2,opt,name=phone,proto3" json:"phone,omitempty"` // только цифры
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetReceiptContactResponse) Reset() {
+	*x = SetReceiptContactResponse{}
+	mi := &file_payment_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetReceiptContactResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetReceiptContactResponse) ProtoMessage() {}
+
+func (x *SetReceiptContactResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetReceiptContactResponse.ProtoReflect.Descriptor instead.
+func (*SetReceiptContactResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *SetReceiptContactResponse) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *SetReceiptContactResponse) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+type GetReceiptRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReceiptRequest) Reset() {
+	*x = GetReceiptRequest{}
+	mi := &file_payment_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReceiptRequest) ProtoMessage() {}
+
+func (x *GetReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReceiptRequest.ProtoReflect.Descriptor instead.
+func (*GetReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *GetReceiptRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type Receipt struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ReceiptId      string                 `protobuf:"bytes,1,opt,name=receipt_id,json=receiptId,proto3" json:"receipt_id,omitempty"`
+	PaymentId      string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	RefundId       string                 `protobuf:"bytes,3,opt,name=refund_id,json=refundId,proto3" json:"refund_id,omitempty"` // пусто у чека прихода
+	Type           string                 `protobuf:"bytes,4,opt,name=type,proto3" json:"type,omitempty"`                         // payment — приход, refund — возврат прихода
+	Email          string                 `protobuf:"bytes,5,opt,name=email,proto3" json:"email,omitempty"`
+	Phone          string                 `protobuf:"bytes,6,opt,name=phone,proto3" json:"phone,omitempty"`
+	Description    string                 `protobuf:"bytes,7,opt,name=description,proto3" json:"description,omitempty"`
+	Amount         float32                `protobuf:"fixed32,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency       string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	VatCode        int32                  `protobuf:"varint,10,opt,name=vat_code,json=vatCode,proto3" json:"vat_code,omitempty"`
+	TaxSystemCode  int32                  `protobuf:"varint,11,opt,name=tax_system_code,json=taxSystemCode,proto3" json:"tax_system_code,omitempty"`
+	PaymentSubject string                 `protobuf:"bytes,12,opt,name=payment_subject,json=paymentSubject,proto3" json:"payment_subject,omitempty"`
+	PaymentMode    string                 `protobuf:"bytes,13,opt,name=payment_mode,json=paymentMode,proto3" json:"payment_mode,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_payment_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *Receipt) GetReceiptId() string {
+	if x != nil {
+		return x.ReceiptId
+	}
+	return ""
+}
+
+func (x *Receipt) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Receipt) GetRefundId() string {
+	if x != nil {
+		return x.RefundId
+	}
+	return ""
+}
+
+func (x *Receipt) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Receipt) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Receipt) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *Receipt) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Receipt) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Receipt) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Receipt) GetVatCode() int32 {
+	if x != nil {
+		return x.VatCode
+	}
+	return 0
+}
+
+func (x *Receipt) GetTaxSystemCode() int32 {
+	if x != nil {
+		return x.TaxSystemCode
+	}
+	return 0
+}
+
+func (x *Receipt) GetPaymentSubject() string {
+	if x != nil {
+		return x.PaymentSubject
+	}
+	return ""
+}
+
+func (x *Receipt) GetPaymentMode() string {
+	if x != nil {
+		return x.PaymentMode
+	}
+	return ""
+}
+
+func (x *Receipt) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type GetReceiptResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId     string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Receipts      []*Receipt             `protobuf:"bytes,2,rep,name=receipts,proto3" json:"receipts,omitempty"`
+	Html          string                 `protobuf:"bytes,3,opt,name=html,proto3" json:"html,omitempty"` // чеки, отрисованные для просмотра и печати
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReceiptResponse) Reset() {
+	*x = GetReceiptResponse{}
+	mi := &file_payment_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReceiptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReceiptResponse) ProtoMessage() {}
+
+func (x *GetReceiptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReceiptResponse.ProtoReflect.Descriptor instead.
+func (*GetReceiptResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetReceiptResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *GetReceiptResponse) GetReceipts() []*Receipt {
+	if x != nil {
+		return x.Receipts
+	}
+	return nil
+}
+
+func (x *GetReceiptResponse) GetHtml() string {
+	if x != nil {
+		return x.Html
+	}
+	return ""
+}
+
+type PromoCode struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // percent — процент от доли пассажира, fixed — сумма в валюте currency
+	Value          float32                `protobuf:"fixed32,3,opt,name=value,proto3" json:"value,omitempty"`
+	Currency       string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`                                        // только у fixed
+	MaxUses        int32                  `protobuf:"varint,5,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`                          // всего применений; 0 — без ограничения
+	MaxUsesPerUser int32                  `protobuf:"varint,6,opt,name=max_uses_per_user,json=maxUsesPerUser,proto3" json:"max_uses_per_user,omitempty"` // сколько раз один пассажир может применить код
+	FirstRideOnly  bool                   `protobuf:"varint,7,opt,name=first_ride_only,json=firstRideOnly,proto3" json:"first_ride_only,omitempty"`      // только для пассажиров без оплаченных поездок
+	ValidFrom      string                 `protobuf:"bytes,8,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`                     // RFC3339; пусто — без ограничения
+	ValidUntil     string                 `protobuf:"bytes,9,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	Active         bool                   `protobuf:"varint,10,opt,name=active,proto3" json:"active,omitempty"`
+	Uses           int32                  `protobuf:"varint,11,opt,name=uses,proto3" json:"uses,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PromoCode) Reset() {
+	*x = PromoCode{}
+	mi := &file_payment_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoCode) ProtoMessage() {}
+
+func (x *PromoCode) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoCode.ProtoReflect.Descriptor instead.
+func (*PromoCode) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *PromoCode) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PromoCode) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PromoCode) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *PromoCode) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PromoCode) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *PromoCode) GetMaxUsesPerUser() int32 {
+	if x != nil {
+		return x.MaxUsesPerUser
+	}
+	return 0
+}
+
+func (x *PromoCode) GetFirstRideOnly() bool {
+	if x != nil {
+		return x.FirstRideOnly
+	}
+	return false
+}
+
+func (x *PromoCode) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *PromoCode) GetValidUntil() string {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return ""
+}
+
+func (x *PromoCode) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *PromoCode) GetUses() int32 {
+	if x != nil {
+		return x.Uses
+	}
+	return 0
+}
+
+func (x *PromoCode) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type CreatePromoCodeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Code           string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Type           string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Value          float32                `protobuf:"fixed32,3,opt,name=value,proto3" json:"value,omitempty"`
+	Currency       string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	MaxUses        int32                  `protobuf:"varint,5,opt,name=max_uses,json=maxUses,proto3" json:"max_uses,omitempty"`
+	MaxUsesPerUser int32                  `protobuf:"varint,6,opt,name=max_uses_per_user,json=maxUsesPerUser,proto3" json:"max_uses_per_user,omitempty"` // 0 — один раз
+	FirstRideOnly  bool                   `protobuf:"varint,7,opt,name=first_ride_only,json=firstRideOnly,proto3" json:"first_ride_only,omitempty"`
+	ValidFrom      string                 `protobuf:"bytes,8,opt,name=valid_from,json=validFrom,proto3" json:"valid_from,omitempty"`
+	ValidUntil     string                 `protobuf:"bytes,9,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreatePromoCodeRequest) Reset() {
+	*x = CreatePromoCodeRequest{}
+	mi := &file_payment_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromoCodeRequest) ProtoMessage() {}
+
+func (x *CreatePromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *CreatePromoCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreatePromoCodeRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreatePromoCodeRequest) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *CreatePromoCodeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreatePromoCodeRequest) GetMaxUses() int32 {
+	if x != nil {
+		return x.MaxUses
+	}
+	return 0
+}
+
+func (x *CreatePromoCodeRequest) GetMaxUsesPerUser() int32 {
+	if x != nil {
+		return x.MaxUsesPerUser
+	}
+	return 0
+}
+
+func (x *CreatePromoCodeRequest) GetFirstRideOnly() bool {
+	if x != nil {
+		return x.FirstRideOnly
+	}
+	return false
+}
+
+func (x *CreatePromoCodeRequest) GetValidFrom() string {
+	if x != nil {
+		return x.ValidFrom
+	}
+	return ""
+}
+
+func (x *CreatePromoCodeRequest) GetValidUntil() string {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return ""
+}
+
+type CreatePromoCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromoCode     *PromoCode             `protobuf:"bytes,1,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromoCodeResponse) Reset() {
+	*x = CreatePromoCodeResponse{}
+	mi := &file_payment_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromoCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromoCodeResponse) ProtoMessage() {}
+
+func (x *CreatePromoCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromoCodeResponse.ProtoReflect.Descriptor instead.
+func (*CreatePromoCodeResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CreatePromoCodeResponse) GetPromoCode() *PromoCode {
+	if x != nil {
+		return x.PromoCode
+	}
+	return nil
+}
+
+type ListPromoCodesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromoCodesRequest) Reset() {
+	*x = ListPromoCodesRequest{}
+	mi := &file_payment_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromoCodesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromoCodesRequest) ProtoMessage() {}
+
+func (x *ListPromoCodesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromoCodesRequest.ProtoReflect.Descriptor instead.
+func (*ListPromoCodesRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{54}
+}
+
+type ListPromoCodesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromoCodes    []*PromoCode           `protobuf:"bytes,1,rep,name=promo_codes,json=promoCodes,proto3" json:"promo_codes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPromoCodesResponse) Reset() {
+	*x = ListPromoCodesResponse{}
+	mi := &file_payment_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPromoCodesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPromoCodesResponse) ProtoMessage() {}
+
+func (x *ListPromoCodesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPromoCodesResponse.ProtoReflect.Descriptor instead.
+func (*ListPromoCodesResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *ListPromoCodesResponse) GetPromoCodes() []*PromoCode {
+	if x != nil {
+		return x.PromoCodes
+	}
+	return nil
+}
+
+type ApplyPromoCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	Currency      string                 `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"` // валюта поездки; фиксированная скидка применяется только в своей валюте
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyPromoCodeRequest) Reset() {
+	*x = ApplyPromoCodeRequest{}
+	mi := &file_payment_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPromoCodeRequest) ProtoMessage() {}
+
+func (x *ApplyPromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*ApplyPromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *ApplyPromoCodeRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type ApplyPromoCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	PromoCode     *PromoCode             `protobuf:"bytes,2,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyPromoCodeResponse) Reset() {
+	*x = ApplyPromoCodeResponse{}
+	mi := &file_payment_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPromoCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPromoCodeResponse) ProtoMessage() {}
+
+func (x *ApplyPromoCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPromoCodeResponse.ProtoReflect.Descriptor instead.
+func (*ApplyPromoCodeResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *ApplyPromoCodeResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeResponse) GetPromoCode() *PromoCode {
+	if x != nil {
+		return x.PromoCode
+	}
+	return nil
+}
+
+type Statement struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatementId   string                 `protobuf:"bytes,1,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Role          string                 `protobuf:"bytes,3,opt,name=role,proto3" json:"role,omitempty"`     // rider — оплаченные поездки, driver — заработок по поездкам
+	Month         string                 `protobuf:"bytes,4,opt,name=month,proto3" json:"month,omitempty"`   // YYYY-MM, по UTC
+	Format        string                 `protobuf:"bytes,5,opt,name=format,proto3" json:"format,omitempty"` // csv, pdf
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // pending, ready, failed
+	Error         string                 `protobuf:"bytes,7,opt,name=error,proto3" json:"error,omitempty"`   // причина ошибки при failed
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"` // пусто, пока выписка рендерится
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Statement) Reset() {
+	*x = Statement{}
+	mi := &file_payment_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Statement) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Statement) ProtoMessage() {}
+
+func (x *Statement) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Statement.ProtoReflect.Descriptor instead.
+func (*Statement) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *Statement) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+func (x *Statement) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Statement) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *Statement) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *Statement) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *Statement) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Statement) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Statement) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Statement) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type RequestStatementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Month         string                 `protobuf:"bytes,2,opt,name=month,proto3" json:"month,omitempty"`   // YYYY-MM
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"` // csv (по умолчанию) или pdf
+	Role          string                 `protobuf:"bytes,4,opt,name=role,proto3" json:"role,omitempty"`     // rider (по умолчанию) или driver
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestStatementRequest) Reset() {
+	*x = RequestStatementRequest{}
+	mi := &file_payment_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestStatementRequest) ProtoMessage() {}
+
+func (x *RequestStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestStatementRequest.ProtoReflect.Descriptor instead.
+func (*RequestStatementRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *RequestStatementRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RequestStatementRequest) GetMonth() string {
+	if x != nil {
+		return x.Month
+	}
+	return ""
+}
+
+func (x *RequestStatementRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *RequestStatementRequest) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+type RequestStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statement     *Statement             `protobuf:"bytes,1,opt,name=statement,proto3" json:"statement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestStatementResponse) Reset() {
+	*x = RequestStatementResponse{}
+	mi := &file_payment_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestStatementResponse) ProtoMessage() {}
+
+func (x *RequestStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestStatementResponse.ProtoReflect.Descriptor instead.
+func (*RequestStatementResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *RequestStatementResponse) GetStatement() *Statement {
+	if x != nil {
+		return x.Statement
+	}
+	return nil
+}
+
+type GetStatementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatementId   string                 `protobuf:"bytes,1,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatementRequest) Reset() {
+	*x = GetStatementRequest{}
+	mi := &file_payment_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatementRequest) ProtoMessage() {}
+
+func (x *GetStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatementRequest.ProtoReflect.Descriptor instead.
+func (*GetStatementRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *GetStatementRequest) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+type GetStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Statement     *Statement             `protobuf:"bytes,1,opt,name=statement,proto3" json:"statement,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatementResponse) Reset() {
+	*x = GetStatementResponse{}
+	mi := &file_payment_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatementResponse) ProtoMessage() {}
+
+func (x *GetStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatementResponse.ProtoReflect.Descriptor instead.
+func (*GetStatementResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *GetStatementResponse) GetStatement() *Statement {
+	if x != nil {
+		return x.Statement
+	}
+	return nil
+}
+
+type DownloadStatementRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	StatementId   string                 `protobuf:"bytes,1,opt,name=statement_id,json=statementId,proto3" json:"statement_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadStatementRequest) Reset() {
+	*x = DownloadStatementRequest{}
+	mi := &file_payment_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadStatementRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadStatementRequest) ProtoMessage() {}
+
+func (x *DownloadStatementRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadStatementRequest.ProtoReflect.Descriptor instead.
+func (*DownloadStatementRequest) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{63}
+}
 
+func (x *DownloadStatementRequest) GetStatementId() string {
+	if x != nil {
+		return x.StatementId
+	}
+	return ""
+}
+
+type DownloadStatementResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	FileName      string                 `protobuf:"bytes,1,opt,name=file_name,json=fileName,proto3" json:"file_name,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Content       []byte                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadStatementResponse) Reset() {
+	*x = DownloadStatementResponse{}
+	mi := &file_payment_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadStatementResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadStatementResponse) ProtoMessage() {}
+
+func (x *DownloadStatementResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payment_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadStatementResponse.ProtoReflect.Descriptor instead.
+func (*DownloadStatementResponse) Descriptor() ([]byte, []int) {
+	return file_payment_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *DownloadStatementResponse) GetFileName() string {
+	if x != nil {
+		return x.FileName
+	}
+	return ""
+}
+
+func (x *DownloadStatementResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *DownloadStatementResponse) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+var File_payment_proto protoreflect.FileDescriptor
+
+const file_payment_proto_rawDesc = "" +
+	"\n" +
+	"\rpayment.proto\x12\apayment\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe0\x03\n" +
+	"\aPayment\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x02R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12.\n" +
+	"\x13yookassa_payment_id\x18\a \x01(\tR\x11yookassaPaymentId\x12 \n" +
+	"\vdescription\x18\t \x01(\tR\vdescription\x12+\n" +
+	"\x11authorized_amount\x18\n" +
+	" \x01(\x02R\x10authorizedAmount\x12)\n" +
+	"\x10confirmation_url\x18\v \x01(\tR\x0fconfirmationUrl\x12\x1a\n" +
+	"\bdiscount\x18\f \x01(\x02R\bdiscount\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\r \x01(\tR\tpromoCode\x12\x12\n" +
+	"\x04kind\x18\x0e \x01(\tR\x04kind\x129\n" +
+	"\n" +
+	"created_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAtJ\x04\b\b\x10\t\"\xf7\x01\n" +
+	"\x15ProcessPaymentRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\x12&\n" +
+	"\x0famount_per_user\x18\x03 \x01(\x02R\ramountPerUser\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tdriver_id\x18\x05 \x01(\tR\bdriverId\x12'\n" +
+	"\x0fidempotency_key\x18\x06 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"\x82\x01\n" +
+	"\rPaymentResult\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12*\n" +
+	"\apayment\x18\x03 \x01(\v2\x10.payment.PaymentR\apayment\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"\xaa\x01\n" +
+	"\x16ProcessPaymentResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x120\n" +
+	"\aresults\x18\x04 \x03(\v2\x16.payment.PaymentResultR\aresults\"P\n" +
+	"\x1aRetryFailedPaymentsRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"1\n" +
+	"\x16GetRoomPaymentsRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\xe4\x01\n" +
+	"\fRiderPayment\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1f\n" +
+	"\vpaid_amount\x18\x03 \x01(\x02R\n" +
+	"paidAmount\x12)\n" +
+	"\x10confirmation_url\x18\x04 \x01(\tR\x0fconfirmationUrl\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x05 \x01(\tR\tpaymentId\x12\x1c\n" +
+	"\treminders\x18\x06 \x01(\x05R\treminders\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\"\x8d\x01\n" +
+	"\x17GetRoomPaymentsResponse\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12-\n" +
+	"\x06riders\x18\x02 \x03(\v2\x15.payment.RiderPaymentR\x06riders\x12\x12\n" +
+	"\x04paid\x18\x03 \x01(\x05R\x04paid\x12\x16\n" +
+	"\x06unpaid\x18\x04 \x01(\x05R\x06unpaid\"\xaf\x01\n" +
+	"\x1bRetryFailedPaymentsResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x120\n" +
+	"\aresults\x18\x04 \x03(\v2\x16.payment.PaymentResultR\aresults\"\x9d\x02\n" +
+	"\x14RefundPaymentRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\x12\x1f\n" +
+	"\vpayment_ids\x18\x03 \x03(\tR\n" +
+	"paymentIds\x12\x19\n" +
+	"\buser_ids\x18\x04 \x03(\tR\auserIds\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x02R\x06amount\x12D\n" +
+	"\aamounts\x18\x06 \x03(\v2*.payment.RefundPaymentRequest.AmountsEntryR\aamounts\x1a:\n" +
+	"\fAmountsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\x02R\x05value:\x028\x01\"\x8c\x02\n" +
+	"\x06Refund\x12\x1b\n" +
+	"\trefund_id\x18\x01 \x01(\tR\brefundId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x02R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12,\n" +
+	"\x12yookassa_refund_id\x18\a \x01(\tR\x10yookassaRefundId\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"\x84\x01\n" +
+	"\x15RefundPaymentResponse\x12*\n" +
+	"\arefunds\x18\x01 \x03(\v2\x10.payment.PaymentR\arefunds\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12%\n" +
+	"\x05items\x18\x03 \x03(\v2\x0f.payment.RefundR\x05items\"\xa1\x01\n" +
+	"\x17AuthorizePaymentRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x02R\x06amount\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"q\n" +
+	"\x18AuthorizePaymentResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\x12)\n" +
+	"\x10confirmation_url\x18\x02 \x01(\tR\x0fconfirmationUrl\"\xce\x01\n" +
+	"\x15CapturePaymentRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\x12&\n" +
+	"\x0famount_per_user\x18\x03 \x01(\x02R\ramountPerUser\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tdriver_id\x18\x05 \x01(\tR\bdriverId\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\"\xaa\x01\n" +
+	"\x16CapturePaymentResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x120\n" +
+	"\aresults\x18\x04 \x03(\v2\x16.payment.PaymentResultR\aresults\"^\n" +
+	"\x12VoidPaymentRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"C\n" +
+	"\x13VoidPaymentResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\"\x8c\x01\n" +
+	"\x10ChargeFeeRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescriptionJ\x04\b\x03\x10\x04J\x04\b\x05\x10\x06J\x04\b\x06\x10\a\"?\n" +
+	"\x11ChargeFeeResponse\x12*\n" +
+	"\apayment\x18\x01 \x01(\v2\x10.payment.PaymentR\apayment\"\x82\x02\n" +
+	"\x18GetPaymentHistoryRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x17\n" +
+	"\aroom_id\x18\x05 \x01(\tR\x06roomId\x12\x12\n" +
+	"\x04kind\x18\x06 \x01(\tR\x04kind\x12.\n" +
+	"\x04from\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\x02to\"\x9a\x01\n" +
+	"\x19GetPaymentHistoryResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\x12\x1f\n" +
+	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\x12.\n" +
+	"\x06totals\x18\x03 \x03(\v2\x16.payment.PaymentTotalsR\x06totals\"s\n" +
+	"\rPaymentTotals\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\x14\n" +
+	"\x05spent\x18\x03 \x01(\x02R\x05spent\x12\x1a\n" +
+	"\brefunded\x18\x04 \x01(\x02R\brefunded\"7\n" +
+	"\x1cAnonymizeUserPaymentsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"?\n" +
+	"\x1dAnonymizeUserPaymentsResponse\x12\x1e\n" +
+	"\n" +
+	"anonymized\x18\x01 \x01(\x05R\n" +
+	"anonymized\"\xda\x01\n" +
+	"\x15SearchPaymentsRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x19\n" +
+	"\buser_ids\x18\x03 \x03(\tR\auserIds\x12!\n" +
+	"\fcreated_from\x18\x04 \x01(\tR\vcreatedFrom\x12\x1d\n" +
+	"\n" +
+	"created_to\x18\x05 \x01(\tR\tcreatedTo\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\"F\n" +
+	"\x16SearchPaymentsResponse\x12,\n" +
+	"\bpayments\x18\x01 \x03(\v2\x10.payment.PaymentR\bpayments\"\x1d\n" +
+	"\x1bStreamPaymentUpdatesRequest\"\xa9\x02\n" +
+	"\rPaymentUpdate\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05event\x18\x04 \x01(\tR\x05event\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1b\n" +
+	"\trefund_id\x18\x06 \x01(\tR\brefundId\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x02R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\t \x01(\tR\tupdatedAt\x12)\n" +
+	"\x10confirmation_url\x18\n" +
+	" \x01(\tR\x0fconfirmationUrl\"\xc5\x01\n" +
+	"\rPaymentMethod\x12\x1b\n" +
+	"\tmethod_id\x18\x01 \x01(\tR\bmethodId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x14\n" +
+	"\x05title\x18\x05 \x01(\tR\x05title\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\x06 \x01(\bR\tisDefault\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"4\n" +
+	"\x19ListPaymentMethodsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"N\n" +
+	"\x1aListPaymentMethodsResponse\x120\n" +
+	"\amethods\x18\x01 \x03(\v2\x16.payment.PaymentMethodR\amethods\"Q\n" +
+	"\x17AddPaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"return_url\x18\x02 \x01(\tR\treturnUrl\"u\n" +
+	"\x18AddPaymentMethodResponse\x12.\n" +
+	"\x06method\x18\x01 \x01(\v2\x16.payment.PaymentMethodR\x06method\x12)\n" +
+	"\x10confirmation_url\x18\x02 \x01(\tR\x0fconfirmationUrl\"R\n" +
+	"\x1aRemovePaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tmethod_id\x18\x02 \x01(\tR\bmethodId\"7\n" +
+	"\x1bRemovePaymentMethodResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\"V\n" +
+	"\x1eSetDefaultPaymentMethodRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tmethod_id\x18\x02 \x01(\tR\bmethodId\"Q\n" +
+	"\x1fSetDefaultPaymentMethodResponse\x12.\n" +
+	"\x06method\x18\x01 \x01(\v2\x16.payment.PaymentMethodR\x06method\"A\n" +
+	"\x10GetWalletRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"r\n" +
+	"\aAccount\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x01 \x01(\tR\taccountId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x18\n" +
+	"\abalance\x18\x04 \x01(\x02R\abalance\"\xcc\x01\n" +
+	"\vLedgerEntry\x12\x13\n" +
+	"\x05tx_id\x18\x01 \x01(\tR\x04txId\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\x12\x1c\n" +
+	"\treference\x18\x03 \x01(\tR\treference\x12\x1d\n" +
+	"\n" +
+	"account_id\x18\x04 \x01(\tR\taccountId\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x02R\x06amount\x12 \n" +
+	"\vdescription\x18\x06 \x01(\tR\vdescription\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\"q\n" +
+	"\x11GetWalletResponse\x12,\n" +
+	"\baccounts\x18\x01 \x03(\v2\x10.payment.AccountR\baccounts\x12.\n" +
+	"\aentries\x18\x02 \x03(\v2\x14.payment.LedgerEntryR\aentries\"w\n" +
+	"\x18GetDriverEarningsRequest\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x12\n" +
+	"\x04from\x18\x02 \x01(\tR\x04from\x12\x0e\n" +
+	"\x02to\x18\x03 \x01(\tR\x02to\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"\xde\x01\n" +
+	"\vRideEarning\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x14\n" +
+	"\x05gross\x18\x02 \x01(\x02R\x05gross\x12\x1e\n" +
+	"\n" +
+	"commission\x18\x03 \x01(\x02R\n" +
+	"commission\x12\x10\n" +
+	"\x03net\x18\x04 \x01(\x02R\x03net\x12\x1a\n" +
+	"\bpayments\x18\x05 \x01(\x05R\bpayments\x12\x19\n" +
+	"\bpaid_out\x18\x06 \x01(\bR\apaidOut\x12\x1b\n" +
+	"\tearned_at\x18\a \x01(\tR\bearnedAt\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\"\xe2\x01\n" +
+	"\x06Payout\x12\x1b\n" +
+	"\tpayout_id\x18\x01 \x01(\tR\bpayoutId\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x02R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"period_end\x18\x06 \x01(\tR\tperiodEnd\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\b \x01(\tR\tcreatedAt\"\xa6\x02\n" +
+	"\x19GetDriverEarningsResponse\x12\x1b\n" +
+	"\tdriver_id\x18\x01 \x01(\tR\bdriverId\x12\x14\n" +
+	"\x05gross\x18\x02 \x01(\x02R\x05gross\x12\x1e\n" +
+	"\n" +
+	"commission\x18\x03 \x01(\x02R\n" +
+	"commission\x12\x10\n" +
+	"\x03net\x18\x04 \x01(\x02R\x03net\x12\x19\n" +
+	"\bpaid_out\x18\x05 \x01(\x02R\apaidOut\x12\x16\n" +
+	"\x06unpaid\x18\x06 \x01(\x02R\x06unpaid\x12*\n" +
+	"\x05rides\x18\a \x03(\v2\x14.payment.RideEarningR\x05rides\x12)\n" +
+	"\apayouts\x18\b \x03(\v2\x0f.payment.PayoutR\apayouts\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\"_\n" +
+	"\x18SetReceiptContactRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x03 \x01(\tR\x05phone\"G\n" +
+	"\x19SetReceiptContactResponse\x12\x14\n" +
+	"\x05email\x18\x01 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x02 \x01(\tR\x05phone\"2\n" +
+	"\x11GetReceiptRequest\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\"\xa8\x03\n" +
+	"\aReceipt\x12\x1d\n" +
+	"\n" +
+	"receipt_id\x18\x01 \x01(\tR\treceiptId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x12\x1b\n" +
+	"\trefund_id\x18\x03 \x01(\tR\brefundId\x12\x12\n" +
+	"\x04type\x18\x04 \x01(\tR\x04type\x12\x14\n" +
+	"\x05email\x18\x05 \x01(\tR\x05email\x12\x14\n" +
+	"\x05phone\x18\x06 \x01(\tR\x05phone\x12 \n" +
+	"\vdescription\x18\a \x01(\tR\vdescription\x12\x16\n" +
+	"\x06amount\x18\b \x01(\x02R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12\x19\n" +
+	"\bvat_code\x18\n" +
+	" \x01(\x05R\avatCode\x12&\n" +
+	"\x0ftax_system_code\x18\v \x01(\x05R\rtaxSystemCode\x12'\n" +
+	"\x0fpayment_subject\x18\f \x01(\tR\x0epaymentSubject\x12!\n" +
+	"\fpayment_mode\x18\r \x01(\tR\vpaymentMode\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\"u\n" +
+	"\x12GetReceiptResponse\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12,\n" +
+	"\breceipts\x18\x02 \x03(\v2\x10.payment.ReceiptR\breceipts\x12\x12\n" +
+	"\x04html\x18\x03 \x01(\tR\x04html\"\xde\x02\n" +
+	"\tPromoCode\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x02R\x05value\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x19\n" +
+	"\bmax_uses\x18\x05 \x01(\x05R\amaxUses\x12)\n" +
+	"\x11max_uses_per_user\x18\x06 \x01(\x05R\x0emaxUsesPerUser\x12&\n" +
+	"\x0ffirst_ride_only\x18\a \x01(\bR\rfirstRideOnly\x12\x1d\n" +
+	"\n" +
+	"valid_from\x18\b \x01(\tR\tvalidFrom\x12\x1f\n" +
+	"\vvalid_until\x18\t \x01(\tR\n" +
+	"validUntil\x12\x16\n" +
+	"\x06active\x18\n" +
+	" \x01(\bR\x06active\x12\x12\n" +
+	"\x04uses\x18\v \x01(\x05R\x04uses\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAt\"\xa0\x02\n" +
+	"\x16CreatePromoCodeRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x03 \x01(\x02R\x05value\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12\x19\n" +
+	"\bmax_uses\x18\x05 \x01(\x05R\amaxUses\x12)\n" +
+	"\x11max_uses_per_user\x18\x06 \x01(\x05R\x0emaxUsesPerUser\x12&\n" +
+	"\x0ffirst_ride_only\x18\a \x01(\bR\rfirstRideOnly\x12\x1d\n" +
+	"\n" +
+	"valid_from\x18\b \x01(\tR\tvalidFrom\x12\x1f\n" +
+	"\vvalid_until\x18\t \x01(\tR\n" +
+	"validUntil\"L\n" +
+	"\x17CreatePromoCodeResponse\x121\n" +
+	"\n" +
+	"promo_code\x18\x01 \x01(\v2\x12.payment.PromoCodeR\tpromoCode\"\x17\n" +
+	"\x15ListPromoCodesRequest\"M\n" +
+	"\x16ListPromoCodesResponse\x123\n" +
+	"\vpromo_codes\x18\x01 \x03(\v2\x12.payment.PromoCodeR\n" +
+	"promoCodes\"y\n" +
+	"\x15ApplyPromoCodeRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\"d\n" +
+	"\x16ApplyPromoCodeResponse\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x121\n" +
+	"\n" +
+	"promo_code\x18\x02 \x01(\v2\x12.payment.PromoCodeR\tpromoCode\"\xaf\x02\n" +
+	"\tStatement\x12!\n" +
+	"\fstatement_id\x18\x01 \x01(\tR\vstatementId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04role\x18\x03 \x01(\tR\x04role\x12\x14\n" +
+	"\x05month\x18\x04 \x01(\tR\x05month\x12\x16\n" +
+	"\x06format\x18\x05 \x01(\tR\x06format\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\a \x01(\tR\x05error\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vfinished_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"t\n" +
+	"\x17RequestStatementRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05month\x18\x02 \x01(\tR\x05month\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\x12\x12\n" +
+	"\x04role\x18\x04 \x01(\tR\x04role\"L\n" +
+	"\x18RequestStatementResponse\x120\n" +
+	"\tstatement\x18\x01 \x01(\v2\x12.payment.StatementR\tstatement\"8\n" +
+	"\x13GetStatementRequest\x12!\n" +
+	"\fstatement_id\x18\x01 \x01(\tR\vstatementId\"H\n" +
+	"\x14GetStatementResponse\x120\n" +
+	"\tstatement\x18\x01 \x01(\v2\x12.payment.StatementR\tstatement\"=\n" +
+	"\x18DownloadStatementRequest\x12!\n" +
+	"\fstatement_id\x18\x01 \x01(\tR\vstatementId\"u\n" +
+	"\x19DownloadStatementResponse\x12\x1b\n" +
+	"\tfile_name\x18\x01 \x01(\tR\bfileName\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\fR\acontent2\xdd\x11\n" +
+	"\x0ePaymentService\x12Q\n" +
+	"\x0eProcessPayment\x12\x1e.payment.ProcessPaymentRequest\x1a\x1f.payment.ProcessPaymentResponse\x12`\n" +
+	"\x13RetryFailedPayments\x12#.payment.RetryFailedPaymentsRequest\x1a$.payment.RetryFailedPaymentsResponse\x12T\n" +
+	"\x0fGetRoomPayments\x12\x1f.payment.GetRoomPaymentsRequest\x1a .payment.GetRoomPaymentsResponse\x12N\n" +
+	"\rRefundPayment\x12\x1d.payment.RefundPaymentRequest\x1a\x1e.payment.RefundPaymentResponse\x12W\n" +
+	"\x10AuthorizePayment\x12 .payment.AuthorizePaymentRequest\x1a!.payment.AuthorizePaymentResponse\x12Q\n" +
+	"\x0eCapturePayment\x12\x1e.payment.CapturePaymentRequest\x1a\x1f.payment.CapturePaymentResponse\x12H\n" +
+	"\vVoidPayment\x12\x1b.payment.VoidPaymentRequest\x1a\x1c.payment.VoidPaymentResponse\x12B\n" +
+	"\tChargeFee\x12\x19.payment.ChargeFeeRequest\x1a\x1a.payment.ChargeFeeResponse\x12Z\n" +
+	"\x11GetPaymentHistory\x12!.payment.GetPaymentHistoryRequest\x1a\".payment.GetPaymentHistoryResponse\x12f\n" +
+	"\x15AnonymizeUserPayments\x12%.payment.AnonymizeUserPaymentsRequest\x1a&.payment.AnonymizeUserPaymentsResponse\x12V\n" +
+	"\x14StreamPaymentUpdates\x12$.payment.StreamPaymentUpdatesRequest\x1a\x16.payment.PaymentUpdate0\x01\x12]\n" +
+	"\x12ListPaymentMethods\x12\".payment.ListPaymentMethodsRequest\x1a#.payment.ListPaymentMethodsResponse\x12W\n" +
+	"\x10AddPaymentMethod\x12 .payment.AddPaymentMethodRequest\x1a!.payment.AddPaymentMethodResponse\x12`\n" +
+	"\x13RemovePaymentMethod\x12#.payment.RemovePaymentMethodRequest\x1a$.payment.RemovePaymentMethodResponse\x12l\n" +
+	"\x17SetDefaultPaymentMethod\x12'.payment.SetDefaultPaymentMethodRequest\x1a(.payment.SetDefaultPaymentMethodResponse\x12B\n" +
+	"\tGetWallet\x12\x19.payment.GetWalletRequest\x1a\x1a.payment.GetWalletResponse\x12Z\n" +
+	"\x11GetDriverEarnings\x12!.payment.GetDriverEarningsRequest\x1a\".payment.GetDriverEarningsResponse\x12Z\n" +
+	"\x11SetReceiptContact\x12!.payment.SetReceiptContactRequest\x1a\".payment.SetReceiptContactResponse\x12E\n" +
+	"\n" +
+	"GetReceipt\x12\x1a.payment.GetReceiptRequest\x1a\x1b.payment.GetReceiptResponse\x12Q\n" +
+	"\x0eApplyPromoCode\x12\x1e.payment.ApplyPromoCodeRequest\x1a\x1f.payment.ApplyPromoCodeResponse\x12W\n" +
+	"\x10RequestStatement\x12 .payment.RequestStatementRequest\x1a!.payment.RequestStatementResponse\x12K\n" +
+	"\fGetStatement\x12\x1c.payment.GetStatementRequest\x1a\x1d.payment.GetStatementResponse\x12Z\n" +
+	"\x11DownloadStatement\x12!.payment.DownloadStatementRequest\x1a\".payment.DownloadStatementResponse\x12Q\n" +
+	"\x0eSearchPayments\x12\x1e.payment.SearchPaymentsRequest\x1a\x1f.payment.SearchPaymentsResponse\x12T\n" +
+	"\x0fCreatePromoCode\x12\x1f.payment.CreatePromoCodeRequest\x1a .payment.CreatePromoCodeResponse\x12Q\n" +
+	"\x0eListPromoCodes\x12\x1e.payment.ListPromoCodesRequest\x1a\x1f.payment.ListPromoCodesResponseB1Z/we_ride/internal/services/payment_service/pb;pbb\x06proto3"
+
+var (
+	file_payment_proto_rawDescOnce sync.Once
+	file_payment_proto_rawDescData []byte
+)
+
+func file_payment_proto_rawDescGZIP() []byte {
+	file_payment_proto_rawDescOnce.Do(func() {
+		file_payment_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)))
+	})
+	return file_payment_proto_rawDescData
+}
+
+var file_payment_proto_msgTypes = make([]protoimpl.MessageInfo, 66)
+var file_payment_proto_goTypes = []any{
+	(*Payment)(nil),                         // 0: payment.Payment
+	(*ProcessPaymentRequest)(nil),           // 1: payment.ProcessPaymentRequest
+	(*PaymentResult)(nil),                   // 2: payment.PaymentResult
+	(*ProcessPaymentResponse)(nil),          // 3: payment.ProcessPaymentResponse
+	(*RetryFailedPaymentsRequest)(nil),      // 4: payment.RetryFailedPaymentsRequest
+	(*GetRoomPaymentsRequest)(nil),          // 5: payment.GetRoomPaymentsRequest
+	(*RiderPayment)(nil),                    // 6: payment.RiderPayment
+	(*GetRoomPaymentsResponse)(nil),         // 7: payment.GetRoomPaymentsResponse
+	(*RetryFailedPaymentsResponse)(nil),     // 8: payment.RetryFailedPaymentsResponse
+	(*RefundPaymentRequest)(nil),            // 9: payment.RefundPaymentRequest
+	(*Refund)(nil),                          // 10: payment.Refund
+	(*RefundPaymentResponse)(nil),           // 11: payment.RefundPaymentResponse
+	(*AuthorizePaymentRequest)(nil),         // 12: payment.AuthorizePaymentRequest
+	(*AuthorizePaymentResponse)(nil),        // 13: payment.AuthorizePaymentResponse
+	(*CapturePaymentRequest)(nil),           // 14: payment.CapturePaymentRequest
+	(*CapturePaymentResponse)(nil),          // 15: payment.CapturePaymentResponse
+	(*VoidPaymentRequest)(nil),              // 16: payment.VoidPaymentRequest
+	(*VoidPaymentResponse)(nil),             // 17: payment.VoidPaymentResponse
+	(*ChargeFeeRequest)(nil),                // 18: payment.ChargeFeeRequest
+	(*ChargeFeeResponse)(nil),               // 19: payment.ChargeFeeResponse
+	(*GetPaymentHistoryRequest)(nil),        // 20: payment.GetPaymentHistoryRequest
+	(*GetPaymentHistoryResponse)(nil),       // 21: payment.GetPaymentHistoryResponse
+	(*PaymentTotals)(nil),                   // 22: payment.PaymentTotals
+	(*AnonymizeUserPaymentsRequest)(nil),    // 23: payment.AnonymizeUserPaymentsRequest
+	(*AnonymizeUserPaymentsResponse)(nil),   // 24: payment.AnonymizeUserPaymentsResponse
+	(*SearchPaymentsRequest)(nil),           // 25: payment.SearchPaymentsRequest
+	(*SearchPaymentsResponse)(nil),          // 26: payment.SearchPaymentsResponse
+	(*StreamPaymentUpdatesRequest)(nil),     // 27: payment.StreamPaymentUpdatesRequest
+	(*PaymentUpdate)(nil),                   // 28: payment.PaymentUpdate
+	(*PaymentMethod)(nil),                   // 29: payment.PaymentMethod
+	(*ListPaymentMethodsRequest)(nil),       // 30: payment.ListPaymentMethodsRequest
+	(*ListPaymentMethodsResponse)(nil),      // 31: payment.ListPaymentMethodsResponse
+	(*AddPaymentMethodRequest)(nil),         // 32: payment.AddPaymentMethodRequest
+	(*AddPaymentMethodResponse)(nil),        // 33: payment.AddPaymentMethodResponse
+	(*RemovePaymentMethodRequest)(nil),      // 34: payment.RemovePaymentMethodRequest
+	(*RemovePaymentMethodResponse)(nil),     // 35: payment.RemovePaymentMethodResponse
+	(*SetDefaultPaymentMethodRequest)(nil),  // 36: payment.SetDefaultPaymentMethodRequest
+	(*SetDefaultPaymentMethodResponse)(nil), // 37: payment.SetDefaultPaymentMethodResponse
+	(*GetWalletRequest)(nil),                // 38: payment.GetWalletRequest
+	(*Account)(nil),                         // 39: payment.Account
+	(*LedgerEntry)(nil),                     // 40: payment.LedgerEntry
+	(*GetWalletResponse)(nil),               // 41: payment.GetWalletResponse
+	(*GetDriverEarningsRequest)(nil),        // 42: payment.GetDriverEarningsRequest
+	(*RideEarning)(nil),                     // 43: payment.RideEarning
+	(*Payout)(nil),                          // 44: payment.Payout
+	(*GetDriverEarningsResponse)(nil),       // 45: payment.GetDriverEarningsResponse
+	(*SetReceiptContactRequest)(nil),        // 46: payment.SetReceiptContactRequest
+	(*SetReceiptContactResponse)(nil),       // 47: payment.SetReceiptContactResponse
+	(*GetReceiptRequest)(nil),               // 48: payment.GetReceiptRequest
+	(*Receipt)(nil),                         // 49: payment.Receipt
+	(*GetReceiptResponse)(nil),              // 50: payment.GetReceiptResponse
+	(*PromoCode)(nil),                       // 51: payment.PromoCode
+	(*CreatePromoCodeRequest)(nil),          // 52: payment.CreatePromoCodeRequest
+	(*CreatePromoCodeResponse)(nil),         // 53: payment.CreatePromoCodeResponse
+	(*ListPromoCodesRequest)(nil),           // 54: payment.ListPromoCodesRequest
+	(*ListPromoCodesResponse)(nil),          // 55: payment.ListPromoCodesResponse
+	(*ApplyPromoCodeRequest)(nil),           // 56: payment.ApplyPromoCodeRequest
+	(*ApplyPromoCodeResponse)(nil),          // 57: payment.ApplyPromoCodeResponse
+	(*Statement)(nil),                       // 58: payment.Statement
+	(*RequestStatementRequest)(nil),         // 59: payment.RequestStatementRequest
+	(*RequestStatementResponse)(nil),        // 60: payment.RequestStatementResponse
+	(*GetStatementRequest)(nil),             // 61: payment.GetStatementRequest
+	(*GetStatementResponse)(nil),            // 62: payment.GetStatementResponse
+	(*DownloadStatementRequest)(nil),        // 63: payment.DownloadStatementRequest
+	(*DownloadStatementResponse)(nil),       // 64: payment.DownloadStatementResponse
+	nil,                                     // 65: payment.RefundPaymentRequest.AmountsEntry
+	(*timestamppb.Timestamp)(nil),           // 66: google.protobuf.Timestamp
+}
+var file_payment_proto_depIdxs = []int32{
+	66, // 0: payment.Payment.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 1: payment.PaymentResult.payment:type_name -> payment.Payment
+	0,  // 2: payment.ProcessPaymentResponse.payments:type_name -> payment.Payment
+	2,  // 3: payment.ProcessPaymentResponse.results:type_name -> payment.PaymentResult
+	6,  // 4: payment.GetRoomPaymentsResponse.riders:type_name -> payment.RiderPayment
+	0,  // 5: payment.RetryFailedPaymentsResponse.payments:type_name -> payment.Payment
+	2,  // 6: payment.RetryFailedPaymentsResponse.results:type_name -> payment.PaymentResult
+	65, // 7: payment.RefundPaymentRequest.amounts:type_name -> payment.RefundPaymentRequest.AmountsEntry
+	0,  // 8: payment.RefundPaymentResponse.refunds:type_name -> payment.Payment
+	10, // 9: payment.RefundPaymentResponse.items:type_name -> payment.Refund
+	0,  // 10: payment.AuthorizePaymentResponse.payment:type_name -> payment.Payment
+	0,  // 11: payment.CapturePaymentResponse.payments:type_name -> payment.Payment
+	2,  // 12: payment.CapturePaymentResponse.results:type_name -> payment.PaymentResult
+	0,  // 13: payment.VoidPaymentResponse.payments:type_name -> payment.Payment
+	0,  // 14: payment.ChargeFeeResponse.payment:type_name -> payment.Payment
+	66, // 15: payment.GetPaymentHistoryRequest.from:type_name -> google.protobuf.Timestamp
+	66, // 16: payment.GetPaymentHistoryRequest.to:type_name -> google.protobuf.Timestamp
+	0,  // 17: payment.GetPaymentHistoryResponse.payments:type_name -> payment.Payment
+	22, // 18: payment.GetPaymentHistoryResponse.totals:type_name -> payment.PaymentTotals
+	0,  // 19: payment.SearchPaymentsResponse.payments:type_name -> payment.Payment
+	29, // 20: payment.ListPaymentMethodsResponse.methods:type_name -> payment.PaymentMethod
+	29, // 21: payment.AddPaymentMethodResponse.method:type_name -> payment.PaymentMethod
+	29, // 22: payment.SetDefaultPaymentMethodResponse.method:type_name -> payment.PaymentMethod
+	39, // 23: payment.GetWalletResponse.accounts:type_name -> payment.Account
+	40, // 24: payment.GetWalletResponse.entries:type_name -> payment.LedgerEntry
+	43, // 25: payment.GetDriverEarningsResponse.rides:type_name -> payment.RideEarning
+	44, // 26: payment.GetDriverEarningsResponse.payouts:type_name -> payment.Payout
+	49, // 27: payment.GetReceiptResponse.receipts:type_name -> payment.Receipt
+	51, // 28: payment.CreatePromoCodeResponse.promo_code:type_name -> payment.PromoCode
+	51, // 29: payment.ListPromoCodesResponse.promo_codes:type_name -> payment.PromoCode
+	51, // 30: payment.ApplyPromoCodeResponse.promo_code:type_name -> payment.PromoCode
+	66, // 31: payment.Statement.created_at:type_name -> google.protobuf.Timestamp
+	66, // 32: payment.Statement.finished_at:type_name -> google.protobuf.Timestamp
+	58, // 33: payment.RequestStatementResponse.statement:type_name -> payment.Statement
+	58, // 34: payment.GetStatementResponse.statement:type_name -> payment.Statement
+	1,  // 35: payment.PaymentService.ProcessPayment:input_type -> payment.ProcessPaymentRequest
+	4,  // 36: payment.PaymentService.RetryFailedPayments:input_type -> payment.RetryFailedPaymentsRequest
+	5,  // 37: payment.PaymentService.GetRoomPayments:input_type -> payment.GetRoomPaymentsRequest
+	9,  // 38: payment.PaymentService.RefundPayment:input_type -> payment.RefundPaymentRequest
+	12, // 39: payment.PaymentService.AuthorizePayment:input_type -> payment.AuthorizePaymentRequest
+	14, // 40: payment.PaymentService.CapturePayment:input_type -> payment.CapturePaymentRequest
+	16, // 41: payment.PaymentService.VoidPayment:input_type -> payment.VoidPaymentRequest
+	18, // 42: payment.PaymentService.ChargeFee:input_type -> payment.ChargeFeeRequest
+	20, // 43: payment.PaymentService.GetPaymentHistory:input_type -> payment.GetPaymentHistoryRequest
+	23, // 44: payment.PaymentService.AnonymizeUserPayments:input_type -> payment.AnonymizeUserPaymentsRequest
+	27, // 45: payment.PaymentService.StreamPaymentUpdates:input_type -> payment.StreamPaymentUpdatesRequest
+	30, // 46: payment.PaymentService.ListPaymentMethods:input_type -> payment.ListPaymentMethodsRequest
+	32, // 47: payment.PaymentService.AddPaymentMethod:input_type -> payment.AddPaymentMethodRequest
+	34, // 48: payment.PaymentService.RemovePaymentMethod:input_type -> payment.RemovePaymentMethodRequest
+	36, // 49: payment.PaymentService.SetDefaultPaymentMethod:input_type -> payment.SetDefaultPaymentMethodRequest
+	38, // 50: payment.PaymentService.GetWallet:input_type -> payment.GetWalletRequest
+	42, // 51: payment.PaymentService.GetDriverEarnings:input_type -> payment.GetDriverEarningsRequest
+	46, // 52: payment.PaymentService.SetReceiptContact:input_type -> payment.SetReceiptContactRequest
+	48, // 53: payment.PaymentService.GetReceipt:input_type -> payment.GetReceiptRequest
+	56, // 54: payment.PaymentService.ApplyPromoCode:input_type -> payment.ApplyPromoCodeRequest
+	59, // 55: payment.PaymentService.RequestStatement:input_type -> payment.RequestStatementRequest
+	61, // 56: payment.PaymentService.GetStatement:input_type -> payment.GetStatementRequest
+	63, // 57: payment.PaymentService.DownloadStatement:input_type -> payment.DownloadStatementRequest
+	25, // 58: payment.PaymentService.SearchPayments:input_type -> payment.SearchPaymentsRequest
+	52, // 59: payment.PaymentService.CreatePromoCode:input_type -> payment.CreatePromoCodeRequest
+	54, // 60: payment.PaymentService.ListPromoCodes:input_type -> payment.ListPromoCodesRequest
+	3,  // 61: payment.PaymentService.ProcessPayment:output_type -> payment.ProcessPaymentResponse
+	8,  // 62: payment.PaymentService.RetryFailedPayments:output_type -> payment.RetryFailedPaymentsResponse
+	7,  // 63: payment.PaymentService.GetRoomPayments:output_type -> payment.GetRoomPaymentsResponse
+	11, // 64: payment.PaymentService.RefundPayment:output_type -> payment.RefundPaymentResponse
+	13, // 65: payment.PaymentService.AuthorizePayment:output_type -> payment.AuthorizePaymentResponse
+	15, // 66: payment.PaymentService.CapturePayment:output_type -> payment.CapturePaymentResponse
+	17, // 67: payment.PaymentService.VoidPayment:output_type -> payment.VoidPaymentResponse
+	19, // 68: payment.PaymentService.ChargeFee:output_type -> payment.ChargeFeeResponse
+	21, // 69: payment.PaymentService.GetPaymentHistory:output_type -> payment.GetPaymentHistoryResponse
+	24, // 70: payment.PaymentService.AnonymizeUserPayments:output_type -> payment.AnonymizeUserPaymentsResponse
+	28, // 71: payment.PaymentService.StreamPaymentUpdates:output_type -> payment.PaymentUpdate
+	31, // 72: payment.PaymentService.ListPaymentMethods:output_type -> payment.ListPaymentMethodsResponse
+	33, // 73: payment.PaymentService.AddPaymentMethod:output_type -> payment.AddPaymentMethodResponse
+	35, // 74: payment.PaymentService.RemovePaymentMethod:output_type -> payment.RemovePaymentMethodResponse
+	37, // 75: payment.PaymentService.SetDefaultPaymentMethod:output_type -> payment.SetDefaultPaymentMethodResponse
+	41, // 76: payment.PaymentService.GetWallet:output_type -> payment.GetWalletResponse
+	45, // 77: payment.PaymentService.GetDriverEarnings:output_type -> payment.GetDriverEarningsResponse
+	47, // 78: payment.PaymentService.SetReceiptContact:output_type -> payment.SetReceiptContactResponse
+	50, // 79: payment.PaymentService.GetReceipt:output_type -> payment.GetReceiptResponse
+	57, // 80: payment.PaymentService.ApplyPromoCode:output_type -> payment.ApplyPromoCodeResponse
+	60, // 81: payment.PaymentService.RequestStatement:output_type -> payment.RequestStatementResponse
+	62, // 82: payment.PaymentService.GetStatement:output_type -> payment.GetStatementResponse
+	64, // 83: payment.PaymentService.DownloadStatement:output_type -> payment.DownloadStatementResponse
+	26, // 84: payment.PaymentService.SearchPayments:output_type -> payment.SearchPaymentsResponse
+	53, // 85: payment.PaymentService.CreatePromoCode:output_type -> payment.CreatePromoCodeResponse
+	55, // 86: payment.PaymentService.ListPromoCodes:output_type -> payment.ListPromoCodesResponse
+	61, // [61:87] is the sub-list for method output_type
+	35, // [35:61] is the sub-list for method input_type
+	35, // [35:35] is the sub-list for extension type_name
+	35, // [35:35] is the sub-list for extension extendee
+	0,  // [0:35] is the sub-list for field type_name
+}
+
+func init() { file_payment_proto_init() }
 func file_payment_proto_init() {
 	if File_payment_proto != nil {
 		return
@@ -506,7 +5081,7 @@ func file_payment_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payment_proto_rawDesc), len(file_payment_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   66,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -1,4 +1,7 @@
 // Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.0
 // source: payment.proto
 
 package pb
@@ -10,19 +13,80 @@ import (
 	status "google.golang.org/grpc/status"
 )
 
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PaymentService_ProcessPayment_FullMethodName    = "/payment.PaymentService/ProcessPayment"
-	PaymentService_RefundPayment_FullMethodName     = "/payment.PaymentService/RefundPayment"
-	PaymentService_GetPaymentHistory_FullMethodName = "/payment.PaymentService/GetPaymentHistory"
+	PaymentService_ProcessPayment_FullMethodName          = "/payment.PaymentService/ProcessPayment"
+	PaymentService_RetryFailedPayments_FullMethodName     = "/payment.PaymentService/RetryFailedPayments"
+	PaymentService_GetRoomPayments_FullMethodName         = "/payment.PaymentService/GetRoomPayments"
+	PaymentService_RefundPayment_FullMethodName           = "/payment.PaymentService/RefundPayment"
+	PaymentService_AuthorizePayment_FullMethodName        = "/payment.PaymentService/AuthorizePayment"
+	PaymentService_CapturePayment_FullMethodName          = "/payment.PaymentService/CapturePayment"
+	PaymentService_VoidPayment_FullMethodName             = "/payment.PaymentService/VoidPayment"
+	PaymentService_ChargeFee_FullMethodName               = "/payment.PaymentService/ChargeFee"
+	PaymentService_GetPaymentHistory_FullMethodName       = "/payment.PaymentService/GetPaymentHistory"
+	PaymentService_AnonymizeUserPayments_FullMethodName   = "/payment.PaymentService/AnonymizeUserPayments"
+	PaymentService_StreamPaymentUpdates_FullMethodName    = "/payment.PaymentService/StreamPaymentUpdates"
+	PaymentService_ListPaymentMethods_FullMethodName      = "/payment.PaymentService/ListPaymentMethods"
+	PaymentService_AddPaymentMethod_FullMethodName        = "/payment.PaymentService/AddPaymentMethod"
+	PaymentService_RemovePaymentMethod_FullMethodName     = "/payment.PaymentService/RemovePaymentMethod"
+	PaymentService_SetDefaultPaymentMethod_FullMethodName = "/payment.PaymentService/SetDefaultPaymentMethod"
+	PaymentService_GetWallet_FullMethodName               = "/payment.PaymentService/GetWallet"
+	PaymentService_GetDriverEarnings_FullMethodName       = "/payment.PaymentService/GetDriverEarnings"
+	PaymentService_SetReceiptContact_FullMethodName       = "/payment.PaymentService/SetReceiptContact"
+	PaymentService_GetReceipt_FullMethodName              = "/payment.PaymentService/GetReceipt"
+	PaymentService_ApplyPromoCode_FullMethodName          = "/payment.PaymentService/ApplyPromoCode"
+	PaymentService_RequestStatement_FullMethodName        = "/payment.PaymentService/RequestStatement"
+	PaymentService_GetStatement_FullMethodName            = "/payment.PaymentService/GetStatement"
+	PaymentService_DownloadStatement_FullMethodName       = "/payment.PaymentService/DownloadStatement"
+	PaymentService_SearchPayments_FullMethodName          = "/payment.PaymentService/SearchPayments"
+	PaymentService_CreatePromoCode_FullMethodName         = "/payment.PaymentService/CreatePromoCode"
+	PaymentService_ListPromoCodes_FullMethodName          = "/payment.PaymentService/ListPromoCodes"
 )
 
-// PaymentServiceClient is the client API for PaymentService.
+// PaymentServiceClient is the client API for PaymentService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PaymentServiceClient interface {
 	ProcessPayment(ctx context.Context, in *ProcessPaymentRequest, opts ...grpc.CallOption) (*ProcessPaymentResponse, error)
+	RetryFailedPayments(ctx context.Context, in *RetryFailedPaymentsRequest, opts ...grpc.CallOption) (*RetryFailedPaymentsResponse, error)
+	GetRoomPayments(ctx context.Context, in *GetRoomPaymentsRequest, opts ...grpc.CallOption) (*GetRoomPaymentsResponse, error)
 	RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundPaymentResponse, error)
+	// Двухстадийная оплата: холд при вступлении в комнату, списание при завершении поездки
+	AuthorizePayment(ctx context.Context, in *AuthorizePaymentRequest, opts ...grpc.CallOption) (*AuthorizePaymentResponse, error)
+	CapturePayment(ctx context.Context, in *CapturePaymentRequest, opts ...grpc.CallOption) (*CapturePaymentResponse, error)
+	VoidPayment(ctx context.Context, in *VoidPaymentRequest, opts ...grpc.CallOption) (*VoidPaymentResponse, error)
+	// Штраф за позднюю отмену или неявку; вызывается room_service по правилам отмены комнаты
+	ChargeFee(ctx context.Context, in *ChargeFeeRequest, opts ...grpc.CallOption) (*ChargeFeeResponse, error)
 	GetPaymentHistory(ctx context.Context, in *GetPaymentHistoryRequest, opts ...grpc.CallOption) (*GetPaymentHistoryResponse, error)
+	AnonymizeUserPayments(ctx context.Context, in *AnonymizeUserPaymentsRequest, opts ...grpc.CallOption) (*AnonymizeUserPaymentsResponse, error)
+	// Обновления платежей вызывающего пользователя (по уведомлениям ЮKassa)
+	StreamPaymentUpdates(ctx context.Context, in *StreamPaymentUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PaymentUpdate], error)
+	// Сохранённые способы оплаты: способ по умолчанию списывается без подтверждения
+	ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error)
+	AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error)
+	RemovePaymentMethod(ctx context.Context, in *RemovePaymentMethodRequest, opts ...grpc.CallOption) (*RemovePaymentMethodResponse, error)
+	SetDefaultPaymentMethod(ctx context.Context, in *SetDefaultPaymentMethodRequest, opts ...grpc.CallOption) (*SetDefaultPaymentMethodResponse, error)
+	// Счета пользователя во внутреннем журнале и последние проводки по ним
+	GetWallet(ctx context.Context, in *GetWalletRequest, opts ...grpc.CallOption) (*GetWalletResponse, error)
+	// Заработок водителя по поездкам за период и его выплаты; admin указывает driver_id
+	GetDriverEarnings(ctx context.Context, in *GetDriverEarningsRequest, opts ...grpc.CallOption) (*GetDriverEarningsResponse, error)
+	// Чеки по 54-ФЗ: контакт, на который ОФД отправляет чеки, и чеки платежа
+	SetReceiptContact(ctx context.Context, in *SetReceiptContactRequest, opts ...grpc.CallOption) (*SetReceiptContactResponse, error)
+	GetReceipt(ctx context.Context, in *GetReceiptRequest, opts ...grpc.CallOption) (*GetReceiptResponse, error)
+	// Промокод к участию пассажира в поездке; вызывается room_service после проверки участия
+	ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error)
+	// Выписки за месяц в CSV или PDF: файл рендерится в фоне, готовность видна в GetStatement
+	RequestStatement(ctx context.Context, in *RequestStatementRequest, opts ...grpc.CallOption) (*RequestStatementResponse, error)
+	GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*GetStatementResponse, error)
+	DownloadStatement(ctx context.Context, in *DownloadStatementRequest, opts ...grpc.CallOption) (*DownloadStatementResponse, error)
+	// Только для admin
+	SearchPayments(ctx context.Context, in *SearchPaymentsRequest, opts ...grpc.CallOption) (*SearchPaymentsResponse, error)
+	CreatePromoCode(ctx context.Context, in *CreatePromoCodeRequest, opts ...grpc.CallOption) (*CreatePromoCodeResponse, error)
+	ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (*ListPromoCodesResponse, error)
 }
 
 type paymentServiceClient struct {
@@ -43,6 +107,26 @@ func (c *paymentServiceClient) ProcessPayment(ctx context.Context, in *ProcessPa
 	return out, nil
 }
 
+func (c *paymentServiceClient) RetryFailedPayments(ctx context.Context, in *RetryFailedPaymentsRequest, opts ...grpc.CallOption) (*RetryFailedPaymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryFailedPaymentsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RetryFailedPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetRoomPayments(ctx context.Context, in *GetRoomPaymentsRequest, opts ...grpc.CallOption) (*GetRoomPaymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomPaymentsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetRoomPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *paymentServiceClient) RefundPayment(ctx context.Context, in *RefundPaymentRequest, opts ...grpc.CallOption) (*RefundPaymentResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(RefundPaymentResponse)
@@ -53,6 +137,46 @@ func (c *paymentServiceClient) RefundPayment(ctx context.Context, in *RefundPaym
 	return out, nil
 }
 
+func (c *paymentServiceClient) AuthorizePayment(ctx context.Context, in *AuthorizePaymentRequest, opts ...grpc.CallOption) (*AuthorizePaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AuthorizePaymentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AuthorizePayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CapturePayment(ctx context.Context, in *CapturePaymentRequest, opts ...grpc.CallOption) (*CapturePaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CapturePaymentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CapturePayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) VoidPayment(ctx context.Context, in *VoidPaymentRequest, opts ...grpc.CallOption) (*VoidPaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(VoidPaymentResponse)
+	err := c.cc.Invoke(ctx, PaymentService_VoidPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ChargeFee(ctx context.Context, in *ChargeFeeRequest, opts ...grpc.CallOption) (*ChargeFeeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChargeFeeResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ChargeFee_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *paymentServiceClient) GetPaymentHistory(ctx context.Context, in *GetPaymentHistoryRequest, opts ...grpc.CallOption) (*GetPaymentHistoryResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetPaymentHistoryResponse)
@@ -63,35 +187,328 @@ func (c *paymentServiceClient) GetPaymentHistory(ctx context.Context, in *GetPay
 	return out, nil
 }
 
-// PaymentServiceServer is the server API for PaymentService.
+func (c *paymentServiceClient) AnonymizeUserPayments(ctx context.Context, in *AnonymizeUserPaymentsRequest, opts ...grpc.CallOption) (*AnonymizeUserPaymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnonymizeUserPaymentsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AnonymizeUserPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) StreamPaymentUpdates(ctx context.Context, in *StreamPaymentUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[PaymentUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PaymentService_ServiceDesc.Streams[0], PaymentService_StreamPaymentUpdates_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamPaymentUpdatesRequest, PaymentUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_StreamPaymentUpdatesClient = grpc.ServerStreamingClient[PaymentUpdate]
+
+func (c *paymentServiceClient) ListPaymentMethods(ctx context.Context, in *ListPaymentMethodsRequest, opts ...grpc.CallOption) (*ListPaymentMethodsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPaymentMethodsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListPaymentMethods_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) AddPaymentMethod(ctx context.Context, in *AddPaymentMethodRequest, opts ...grpc.CallOption) (*AddPaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AddPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_AddPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RemovePaymentMethod(ctx context.Context, in *RemovePaymentMethodRequest, opts ...grpc.CallOption) (*RemovePaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RemovePaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RemovePaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SetDefaultPaymentMethod(ctx context.Context, in *SetDefaultPaymentMethodRequest, opts ...grpc.CallOption) (*SetDefaultPaymentMethodResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetDefaultPaymentMethodResponse)
+	err := c.cc.Invoke(ctx, PaymentService_SetDefaultPaymentMethod_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetWallet(ctx context.Context, in *GetWalletRequest, opts ...grpc.CallOption) (*GetWalletResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetWalletResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetWallet_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetDriverEarnings(ctx context.Context, in *GetDriverEarningsRequest, opts ...grpc.CallOption) (*GetDriverEarningsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetDriverEarningsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetDriverEarnings_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SetReceiptContact(ctx context.Context, in *SetReceiptContactRequest, opts ...grpc.CallOption) (*SetReceiptContactResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetReceiptContactResponse)
+	err := c.cc.Invoke(ctx, PaymentService_SetReceiptContact_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetReceipt(ctx context.Context, in *GetReceiptRequest, opts ...grpc.CallOption) (*GetReceiptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetReceiptResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyPromoCodeResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ApplyPromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) RequestStatement(ctx context.Context, in *RequestStatementRequest, opts ...grpc.CallOption) (*RequestStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequestStatementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_RequestStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) GetStatement(ctx context.Context, in *GetStatementRequest, opts ...grpc.CallOption) (*GetStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetStatementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_GetStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) DownloadStatement(ctx context.Context, in *DownloadStatementRequest, opts ...grpc.CallOption) (*DownloadStatementResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DownloadStatementResponse)
+	err := c.cc.Invoke(ctx, PaymentService_DownloadStatement_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) SearchPayments(ctx context.Context, in *SearchPaymentsRequest, opts ...grpc.CallOption) (*SearchPaymentsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchPaymentsResponse)
+	err := c.cc.Invoke(ctx, PaymentService_SearchPayments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) CreatePromoCode(ctx context.Context, in *CreatePromoCodeRequest, opts ...grpc.CallOption) (*CreatePromoCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePromoCodeResponse)
+	err := c.cc.Invoke(ctx, PaymentService_CreatePromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentServiceClient) ListPromoCodes(ctx context.Context, in *ListPromoCodesRequest, opts ...grpc.CallOption) (*ListPromoCodesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListPromoCodesResponse)
+	err := c.cc.Invoke(ctx, PaymentService_ListPromoCodes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentServiceServer is the server API for PaymentService service.
+// All implementations must embed UnimplementedPaymentServiceServer
+// for forward compatibility.
 type PaymentServiceServer interface {
 	ProcessPayment(context.Context, *ProcessPaymentRequest) (*ProcessPaymentResponse, error)
+	RetryFailedPayments(context.Context, *RetryFailedPaymentsRequest) (*RetryFailedPaymentsResponse, error)
+	GetRoomPayments(context.Context, *GetRoomPaymentsRequest) (*GetRoomPaymentsResponse, error)
 	RefundPayment(context.Context, *RefundPaymentRequest) (*RefundPaymentResponse, error)
+	// Двухстадийная оплата: холд при вступлении в комнату, списание при завершении поездки
+	AuthorizePayment(context.Context, *AuthorizePaymentRequest) (*AuthorizePaymentResponse, error)
+	CapturePayment(context.Context, *CapturePaymentRequest) (*CapturePaymentResponse, error)
+	VoidPayment(context.Context, *VoidPaymentRequest) (*VoidPaymentResponse, error)
+	// Штраф за позднюю отмену или неявку; вызывается room_service по правилам отмены комнаты
+	ChargeFee(context.Context, *ChargeFeeRequest) (*ChargeFeeResponse, error)
 	GetPaymentHistory(context.Context, *GetPaymentHistoryRequest) (*GetPaymentHistoryResponse, error)
+	AnonymizeUserPayments(context.Context, *AnonymizeUserPaymentsRequest) (*AnonymizeUserPaymentsResponse, error)
+	// Обновления платежей вызывающего пользователя (по уведомлениям ЮKassa)
+	StreamPaymentUpdates(*StreamPaymentUpdatesRequest, grpc.ServerStreamingServer[PaymentUpdate]) error
+	// Сохранённые способы оплаты: способ по умолчанию списывается без подтверждения
+	ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error)
+	AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error)
+	RemovePaymentMethod(context.Context, *RemovePaymentMethodRequest) (*RemovePaymentMethodResponse, error)
+	SetDefaultPaymentMethod(context.Context, *SetDefaultPaymentMethodRequest) (*SetDefaultPaymentMethodResponse, error)
+	// Счета пользователя во внутреннем журнале и последние проводки по ним
+	GetWallet(context.Context, *GetWalletRequest) (*GetWalletResponse, error)
+	// Заработок водителя по поездкам за период и его выплаты; admin указывает driver_id
+	GetDriverEarnings(context.Context, *GetDriverEarningsRequest) (*GetDriverEarningsResponse, error)
+	// Чеки по 54-ФЗ: контакт, на который ОФД отправляет чеки, и чеки платежа
+	SetReceiptContact(context.Context, *SetReceiptContactRequest) (*SetReceiptContactResponse, error)
+	GetReceipt(context.Context, *GetReceiptRequest) (*GetReceiptResponse, error)
+	// Промокод к участию пассажира в поездке; вызывается room_service после проверки участия
+	ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error)
+	// Выписки за месяц в CSV или PDF: файл рендерится в фоне, готовность видна в GetStatement
+	RequestStatement(context.Context, *RequestStatementRequest) (*RequestStatementResponse, error)
+	GetStatement(context.Context, *GetStatementRequest) (*GetStatementResponse, error)
+	DownloadStatement(context.Context, *DownloadStatementRequest) (*DownloadStatementResponse, error)
+	// Только для admin
+	SearchPayments(context.Context, *SearchPaymentsRequest) (*SearchPaymentsResponse, error)
+	CreatePromoCode(context.Context, *CreatePromoCodeRequest) (*CreatePromoCodeResponse, error)
+	ListPromoCodes(context.Context, *ListPromoCodesRequest) (*ListPromoCodesResponse, error)
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
-// UnimplementedPaymentServiceServer must be embedded to have forward compatible implementations.
+// UnimplementedPaymentServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
 type UnimplementedPaymentServiceServer struct{}
 
 func (UnimplementedPaymentServiceServer) ProcessPayment(context.Context, *ProcessPaymentRequest) (*ProcessPaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ProcessPayment not implemented")
 }
+func (UnimplementedPaymentServiceServer) RetryFailedPayments(context.Context, *RetryFailedPaymentsRequest) (*RetryFailedPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RetryFailedPayments not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetRoomPayments(context.Context, *GetRoomPaymentsRequest) (*GetRoomPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomPayments not implemented")
+}
 func (UnimplementedPaymentServiceServer) RefundPayment(context.Context, *RefundPaymentRequest) (*RefundPaymentResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method RefundPayment not implemented")
 }
+func (UnimplementedPaymentServiceServer) AuthorizePayment(context.Context, *AuthorizePaymentRequest) (*AuthorizePaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AuthorizePayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) CapturePayment(context.Context, *CapturePaymentRequest) (*CapturePaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CapturePayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) VoidPayment(context.Context, *VoidPaymentRequest) (*VoidPaymentResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VoidPayment not implemented")
+}
+func (UnimplementedPaymentServiceServer) ChargeFee(context.Context, *ChargeFeeRequest) (*ChargeFeeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChargeFee not implemented")
+}
 func (UnimplementedPaymentServiceServer) GetPaymentHistory(context.Context, *GetPaymentHistoryRequest) (*GetPaymentHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPaymentHistory not implemented")
 }
+func (UnimplementedPaymentServiceServer) AnonymizeUserPayments(context.Context, *AnonymizeUserPaymentsRequest) (*AnonymizeUserPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnonymizeUserPayments not implemented")
+}
+func (UnimplementedPaymentServiceServer) StreamPaymentUpdates(*StreamPaymentUpdatesRequest, grpc.ServerStreamingServer[PaymentUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamPaymentUpdates not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListPaymentMethods(context.Context, *ListPaymentMethodsRequest) (*ListPaymentMethodsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentMethods not implemented")
+}
+func (UnimplementedPaymentServiceServer) AddPaymentMethod(context.Context, *AddPaymentMethodRequest) (*AddPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) RemovePaymentMethod(context.Context, *RemovePaymentMethodRequest) (*RemovePaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemovePaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) SetDefaultPaymentMethod(context.Context, *SetDefaultPaymentMethodRequest) (*SetDefaultPaymentMethodResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetDefaultPaymentMethod not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetWallet(context.Context, *GetWalletRequest) (*GetWalletResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWallet not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetDriverEarnings(context.Context, *GetDriverEarningsRequest) (*GetDriverEarningsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDriverEarnings not implemented")
+}
+func (UnimplementedPaymentServiceServer) SetReceiptContact(context.Context, *SetReceiptContactRequest) (*SetReceiptContactResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SetReceiptContact not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetReceipt(context.Context, *GetReceiptRequest) (*GetReceiptResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetReceipt not implemented")
+}
+func (UnimplementedPaymentServiceServer) ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyPromoCode not implemented")
+}
+func (UnimplementedPaymentServiceServer) RequestStatement(context.Context, *RequestStatementRequest) (*RequestStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RequestStatement not implemented")
+}
+func (UnimplementedPaymentServiceServer) GetStatement(context.Context, *GetStatementRequest) (*GetStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStatement not implemented")
+}
+func (UnimplementedPaymentServiceServer) DownloadStatement(context.Context, *DownloadStatementRequest) (*DownloadStatementResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DownloadStatement not implemented")
+}
+func (UnimplementedPaymentServiceServer) SearchPayments(context.Context, *SearchPaymentsRequest) (*SearchPaymentsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchPayments not implemented")
+}
+func (UnimplementedPaymentServiceServer) CreatePromoCode(context.Context, *CreatePromoCodeRequest) (*CreatePromoCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePromoCode not implemented")
+}
+func (UnimplementedPaymentServiceServer) ListPromoCodes(context.Context, *ListPromoCodesRequest) (*ListPromoCodesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPromoCodes not implemented")
+}
 func (UnimplementedPaymentServiceServer) mustEmbedUnimplementedPaymentServiceServer() {}
 func (UnimplementedPaymentServiceServer) testEmbeddedByValue()                        {}
 
-// UnsafePaymentServiceServer may be embedded to opt out of forward compatibility.
+// UnsafePaymentServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentServiceServer will
+// result in compilation errors.
 type UnsafePaymentServiceServer interface {
 	mustEmbedUnimplementedPaymentServiceServer()
 }
 
 func RegisterPaymentServiceServer(s grpc.ServiceRegistrar, srv PaymentServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPaymentServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
 	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
 		t.testEmbeddedByValue()
 	}
@@ -106,13 +523,52 @@ func _PaymentService_ProcessPayment_Handler(srv interface{}, ctx context.Context
 	if interceptor == nil {
 		return srv.(PaymentServiceServer).ProcessPayment(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: PaymentService_ProcessPayment_FullMethodName}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ProcessPayment_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(PaymentServiceServer).ProcessPayment(ctx, req.(*ProcessPaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_RetryFailedPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryFailedPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RetryFailedPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RetryFailedPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RetryFailedPayments(ctx, req.(*RetryFailedPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetRoomPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetRoomPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetRoomPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetRoomPayments(ctx, req.(*GetRoomPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PaymentService_RefundPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(RefundPaymentRequest)
 	if err := dec(in); err != nil {
@@ -121,13 +577,88 @@ func _PaymentService_RefundPayment_Handler(srv interface{}, ctx context.Context,
 	if interceptor == nil {
 		return srv.(PaymentServiceServer).RefundPayment(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: PaymentService_RefundPayment_FullMethodName}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RefundPayment_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(PaymentServiceServer).RefundPayment(ctx, req.(*RefundPaymentRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_AuthorizePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AuthorizePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AuthorizePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AuthorizePayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AuthorizePayment(ctx, req.(*AuthorizePaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CapturePayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CapturePaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CapturePayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CapturePayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CapturePayment(ctx, req.(*CapturePaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_VoidPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VoidPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).VoidPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_VoidPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).VoidPayment(ctx, req.(*VoidPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ChargeFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChargeFeeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ChargeFee(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ChargeFee_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ChargeFee(ctx, req.(*ChargeFeeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _PaymentService_GetPaymentHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetPaymentHistoryRequest)
 	if err := dec(in); err != nil {
@@ -136,21 +667,429 @@ func _PaymentService_GetPaymentHistory_Handler(srv interface{}, ctx context.Cont
 	if interceptor == nil {
 		return srv.(PaymentServiceServer).GetPaymentHistory(ctx, in)
 	}
-	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: PaymentService_GetPaymentHistory_FullMethodName}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetPaymentHistory_FullMethodName,
+	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
 		return srv.(PaymentServiceServer).GetPaymentHistory(ctx, req.(*GetPaymentHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentService_AnonymizeUserPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnonymizeUserPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AnonymizeUserPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AnonymizeUserPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AnonymizeUserPayments(ctx, req.(*AnonymizeUserPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_StreamPaymentUpdates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamPaymentUpdatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PaymentServiceServer).StreamPaymentUpdates(m, &grpc.GenericServerStream[StreamPaymentUpdatesRequest, PaymentUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PaymentService_StreamPaymentUpdatesServer = grpc.ServerStreamingServer[PaymentUpdate]
+
+func _PaymentService_ListPaymentMethods_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentMethodsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListPaymentMethods(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListPaymentMethods_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListPaymentMethods(ctx, req.(*ListPaymentMethodsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_AddPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).AddPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_AddPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).AddPaymentMethod(ctx, req.(*AddPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RemovePaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemovePaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RemovePaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RemovePaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RemovePaymentMethod(ctx, req.(*RemovePaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SetDefaultPaymentMethod_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetDefaultPaymentMethodRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SetDefaultPaymentMethod(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_SetDefaultPaymentMethod_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SetDefaultPaymentMethod(ctx, req.(*SetDefaultPaymentMethodRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetWallet_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWalletRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetWallet(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetWallet_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetWallet(ctx, req.(*GetWalletRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetDriverEarnings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDriverEarningsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetDriverEarnings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetDriverEarnings_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetDriverEarnings(ctx, req.(*GetDriverEarningsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SetReceiptContact_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetReceiptContactRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SetReceiptContact(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_SetReceiptContact_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SetReceiptContact(ctx, req.(*SetReceiptContactRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetReceipt(ctx, req.(*GetReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ApplyPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ApplyPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ApplyPromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ApplyPromoCode(ctx, req.(*ApplyPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_RequestStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequestStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).RequestStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_RequestStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).RequestStatement(ctx, req.(*RequestStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_GetStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).GetStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_GetStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).GetStatement(ctx, req.(*GetStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_DownloadStatement_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DownloadStatementRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).DownloadStatement(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_DownloadStatement_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).DownloadStatement(ctx, req.(*DownloadStatementRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_SearchPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).SearchPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_SearchPayments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).SearchPayments(ctx, req.(*SearchPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_CreatePromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).CreatePromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_CreatePromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).CreatePromoCode(ctx, req.(*CreatePromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentService_ListPromoCodes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPromoCodesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentServiceServer).ListPromoCodes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentService_ListPromoCodes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentServiceServer).ListPromoCodes(ctx, req.(*ListPromoCodesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentService_ServiceDesc is the grpc.ServiceDesc for PaymentService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
 var PaymentService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "payment.PaymentService",
 	HandlerType: (*PaymentServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
-		{MethodName: "ProcessPayment", Handler: _PaymentService_ProcessPayment_Handler},
-		{MethodName: "RefundPayment", Handler: _PaymentService_RefundPayment_Handler},
-		{MethodName: "GetPaymentHistory", Handler: _PaymentService_GetPaymentHistory_Handler},
+		{
+			MethodName: "ProcessPayment",
+			Handler:    _PaymentService_ProcessPayment_Handler,
+		},
+		{
+			MethodName: "RetryFailedPayments",
+			Handler:    _PaymentService_RetryFailedPayments_Handler,
+		},
+		{
+			MethodName: "GetRoomPayments",
+			Handler:    _PaymentService_GetRoomPayments_Handler,
+		},
+		{
+			MethodName: "RefundPayment",
+			Handler:    _PaymentService_RefundPayment_Handler,
+		},
+		{
+			MethodName: "AuthorizePayment",
+			Handler:    _PaymentService_AuthorizePayment_Handler,
+		},
+		{
+			MethodName: "CapturePayment",
+			Handler:    _PaymentService_CapturePayment_Handler,
+		},
+		{
+			MethodName: "VoidPayment",
+			Handler:    _PaymentService_VoidPayment_Handler,
+		},
+		{
+			MethodName: "ChargeFee",
+			Handler:    _PaymentService_ChargeFee_Handler,
+		},
+		{
+			MethodName: "GetPaymentHistory",
+			Handler:    _PaymentService_GetPaymentHistory_Handler,
+		},
+		{
+			MethodName: "AnonymizeUserPayments",
+			Handler:    _PaymentService_AnonymizeUserPayments_Handler,
+		},
+		{
+			MethodName: "ListPaymentMethods",
+			Handler:    _PaymentService_ListPaymentMethods_Handler,
+		},
+		{
+			MethodName: "AddPaymentMethod",
+			Handler:    _PaymentService_AddPaymentMethod_Handler,
+		},
+		{
+			MethodName: "RemovePaymentMethod",
+			Handler:    _PaymentService_RemovePaymentMethod_Handler,
+		},
+		{
+			MethodName: "SetDefaultPaymentMethod",
+			Handler:    _PaymentService_SetDefaultPaymentMethod_Handler,
+		},
+		{
+			MethodName: "GetWallet",
+			Handler:    _PaymentService_GetWallet_Handler,
+		},
+		{
+			MethodName: "GetDriverEarnings",
+			Handler:    _PaymentService_GetDriverEarnings_Handler,
+		},
+		{
+			MethodName: "SetReceiptContact",
+			Handler:    _PaymentService_SetReceiptContact_Handler,
+		},
+		{
+			MethodName: "GetReceipt",
+			Handler:    _PaymentService_GetReceipt_Handler,
+		},
+		{
+			MethodName: "ApplyPromoCode",
+			Handler:    _PaymentService_ApplyPromoCode_Handler,
+		},
+		{
+			MethodName: "RequestStatement",
+			Handler:    _PaymentService_RequestStatement_Handler,
+		},
+		{
+			MethodName: "GetStatement",
+			Handler:    _PaymentService_GetStatement_Handler,
+		},
+		{
+			MethodName: "DownloadStatement",
+			Handler:    _PaymentService_DownloadStatement_Handler,
+		},
+		{
+			MethodName: "SearchPayments",
+			Handler:    _PaymentService_SearchPayments_Handler,
+		},
+		{
+			MethodName: "CreatePromoCode",
+			Handler:    _PaymentService_CreatePromoCode_Handler,
+		},
+		{
+			MethodName: "ListPromoCodes",
+			Handler:    _PaymentService_ListPromoCodes_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamPaymentUpdates",
+			Handler:       _PaymentService_StreamPaymentUpdates_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "payment.proto",
 }
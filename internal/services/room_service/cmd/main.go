@@ -12,6 +12,8 @@ import (
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/jwks"
 	"we_ride/internal/pkg/logger"
 	"we_ride/internal/services/room_service/config"
 	"we_ride/internal/services/room_service/database"
@@ -46,14 +48,25 @@ func main() {
 	defer pool.Close()
 
 	repo := repository.NewRepository(pool)
-	roomService := service.New(repo, cfg.UserServiceAddr, cfg.PaymentServiceAddr)
+	roomService := service.New(repo, cfg.UserServiceAddr, cfg.PaymentServiceAddr, cfg.Currency, &pb.CancellationPolicy{
+		FreeCancelMinutes: cfg.Cancellation.FreeMinutes,
+		CancellationFee:   cfg.Cancellation.Fee,
+		NoShowFee:         cfg.Cancellation.NoShowFee,
+	})
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(logger.Interceptor(ctx, l)))
+	keys := jwks.NewCache(cfg.JWKSURL, cfg.JWKSRefresh)
+	if err := keys.Start(ctx); err != nil {
+		l.Error(ctx, "failed to load JWKS, will retry on demand", zap.Error(err))
+	}
+	verifier := identity.NewJWKSVerifier(keys)
+
+	grpcServer := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(logger.Interceptor(ctx, l), identity.UnaryServerInterceptor(verifier)),
+		grpc.StreamInterceptor(identity.StreamServerInterceptor(verifier)),
+	)
 
 	// Регистрируем основные методы
 	pb.RegisterRoomServiceServer(grpcServer, roomService)
-	// Регистрируем CompleteRide как расширение
-	pb.RegisterCompleteRide(grpcServer, roomService)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%s", cfg.GRPCHost, cfg.GRPCPort))
 	if err != nil {
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 	"we_ride/internal/services/room_service/database"
@@ -18,6 +19,21 @@ type Config struct {
 
 	UserServiceAddr    string `env:"USER_SERVICE_ADDR"    env-default:"localhost:50052" yaml:"USER_SERVICE_ADDR"`
 	PaymentServiceAddr string `env:"PAYMENT_SERVICE_ADDR" env-default:"localhost:50053" yaml:"PAYMENT_SERVICE_ADDR"`
+
+	// Валюта цен новых комнат, ISO 4217; должна приниматься payment_service (CURRENCIES)
+	Currency string `env:"CURRENCY" env-default:"RUB" yaml:"CURRENCY"`
+
+	Cancellation Cancellation `yaml:"CANCELLATION"`
+
+	JWKSURL     string        `env:"JWKS_URL"              env-default:"http://localhost:8082/.well-known/jwks.json" yaml:"JWKS_URL"`
+	JWKSRefresh time.Duration `env:"JWKS_REFRESH_INTERVAL" env-default:"5m"                                          yaml:"JWKS_REFRESH_INTERVAL"`
+}
+
+// Cancellation — правила отмены новых комнат, если водитель их не указал; штрафы в валюте комнаты
+type Cancellation struct {
+	FreeMinutes int32   `yaml:"FREE_CANCEL_MINUTES" env:"FREE_CANCEL_MINUTES" env-default:"30"`
+	Fee         float32 `yaml:"CANCELLATION_FEE"    env:"CANCELLATION_FEE"    env-default:"100"`
+	NoShowFee   float32 `yaml:"NO_SHOW_FEE"         env:"NO_SHOW_FEE"         env-default:"200"`
 }
 
 func New() (*Config, error) {
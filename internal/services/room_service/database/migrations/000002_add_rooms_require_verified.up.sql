@@ -0,0 +1 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS require_verified BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS rooms_created_at_idx;
+DROP TABLE IF EXISTS room_events;
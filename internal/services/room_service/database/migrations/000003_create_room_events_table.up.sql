@@ -0,0 +1,12 @@
+-- Хронология комнаты для поддержки: создание, вход/выход участников, смена статуса
+CREATE TABLE IF NOT EXISTS room_events (
+    event_id   BIGSERIAL PRIMARY KEY,
+    room_id    UUID        NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    event_type VARCHAR(32) NOT NULL,
+    actor_id   UUID,
+    details    TEXT        NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS room_events_room_idx ON room_events(room_id, created_at);
+CREATE INDEX IF NOT EXISTS rooms_created_at_idx ON rooms(created_at);
@@ -0,0 +1 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS estimated_price REAL NOT NULL DEFAULT 0;
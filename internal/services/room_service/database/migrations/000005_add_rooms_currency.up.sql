@@ -0,0 +1 @@
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'RUB';
@@ -0,0 +1,3 @@
+ALTER TABLE rooms DROP COLUMN IF EXISTS no_show_fee;
+ALTER TABLE rooms DROP COLUMN IF EXISTS cancellation_fee;
+ALTER TABLE rooms DROP COLUMN IF EXISTS free_cancel_minutes;
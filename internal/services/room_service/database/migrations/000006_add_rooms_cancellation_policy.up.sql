@@ -0,0 +1,4 @@
+-- Правила отмены комнаты: бесплатный выход до free_cancel_minutes перед отправлением, штрафы в валюте комнаты
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS free_cancel_minutes INT  NOT NULL DEFAULT 0;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS cancellation_fee    REAL NOT NULL DEFAULT 0;
+ALTER TABLE rooms ADD COLUMN IF NOT EXISTS no_show_fee         REAL NOT NULL DEFAULT 0;
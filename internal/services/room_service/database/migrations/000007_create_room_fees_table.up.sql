@@ -0,0 +1,9 @@
+-- Штрафы за позднюю отмену и неявку: payment_service берёт из них сумму при списании
+CREATE TABLE IF NOT EXISTS room_fees (
+    room_id    UUID        NOT NULL REFERENCES rooms(room_id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL,
+    fee_type   VARCHAR(32) NOT NULL,
+    amount     REAL        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (room_id, user_id, fee_type)
+);
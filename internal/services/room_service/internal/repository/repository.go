@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"strings"
 	"time"
 	roomservice "we_ride/internal/services/room_service/pb"
 )
@@ -18,6 +19,21 @@ type Repository interface {
 	UpdateRoomStatus(ctx context.Context, roomID string, status roomservice.RoomStatus) error
 	GetRoomMembers(ctx context.Context, roomID string) ([]string, error)
 	CompleteRoom(ctx context.Context, roomID string, totalPrice, costPerMember float32) error
+	SearchRooms(ctx context.Context, f RoomFilter) ([]*roomservice.Room, error)
+	AddEvent(ctx context.Context, e *roomservice.RoomEvent) error
+	ListEvents(ctx context.Context, roomID string) ([]*roomservice.RoomEvent, error)
+	AddFee(ctx context.Context, roomID string, fee *roomservice.RoomFee) error
+	ListFees(ctx context.Context, roomID string) ([]*roomservice.RoomFee, error)
+}
+
+// RoomFilter — условия поиска комнат для поддержки, пустые поля не учитываются
+type RoomFilter struct {
+	RoomID      string
+	UserID      string // создатель или участник
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Status      roomservice.RoomStatus
+	Limit       int
 }
 
 type repository struct {
@@ -33,11 +49,17 @@ func (r *repository) CreateRoom(ctx context.Context, room *roomservice.Room) err
 	query := `
 	INSERT INTO rooms (
 		room_id, creator_id, start_latitude, start_longitude, end_latitude, end_longitude,
-		available_seats, status, created_at, scheduled_time, total_price, cost_per_member
+		available_seats, status, created_at, scheduled_time, total_price, cost_per_member, require_verified, estimated_price,
+		currency, free_cancel_minutes, cancellation_fee, no_show_fee
 	)
-	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12);
+	VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18);
 	`
 
+	policy := room.CancellationPolicy
+	if policy == nil {
+		policy = &roomservice.CancellationPolicy{}
+	}
+
 	_, err := r.db.Exec(ctx, query,
 		room.RoomId,
 		room.CreatorId,
@@ -51,6 +73,12 @@ func (r *repository) CreateRoom(ctx context.Context, room *roomservice.Room) err
 		room.ScheduledTime.AsTime(),
 		room.TotalPrice,
 		room.CostPerMember,
+		room.RequireVerified,
+		room.EstimatedPrice,
+		room.Currency,
+		policy.FreeCancelMinutes,
+		policy.CancellationFee,
+		policy.NoShowFee,
 	)
 	return err
 }
@@ -70,16 +98,18 @@ func (r *repository) RemoveMember(ctx context.Context, roomID, userID string) er
 
 func (r *repository) GetRoomByID(ctx context.Context, roomID string) (*roomservice.Room, error) {
 	query := `
-	SELECT room_id, creator_id, available_seats, status, total_price, cost_per_member, created_at, scheduled_time
+	SELECT room_id, creator_id, available_seats, status, total_price, cost_per_member, created_at, scheduled_time, require_verified, estimated_price, currency,
+	       free_cancel_minutes, cancellation_fee, no_show_fee
 	FROM rooms WHERE room_id=$1;
 	`
 
 	row := r.db.QueryRow(ctx, query, roomID)
 
-	room := &roomservice.Room{}
+	room := &roomservice.Room{CancellationPolicy: &roomservice.CancellationPolicy{}}
 	var createdAt, scheduled time.Time
 	err := row.Scan(&room.RoomId, &room.CreatorId, &room.AvailableSeats, &room.Status,
-		&room.TotalPrice, &room.CostPerMember, &createdAt, &scheduled)
+		&room.TotalPrice, &room.CostPerMember, &createdAt, &scheduled, &room.RequireVerified, &room.EstimatedPrice, &room.Currency,
+		&room.CancellationPolicy.FreeCancelMinutes, &room.CancellationPolicy.CancellationFee, &room.CancellationPolicy.NoShowFee)
 	if err != nil {
 		return nil, fmt.Errorf("GetRoomByID: %w", err)
 	}
@@ -90,7 +120,7 @@ func (r *repository) GetRoomByID(ctx context.Context, roomID string) (*roomservi
 }
 
 func (r *repository) ListAvailableRooms(ctx context.Context) ([]*roomservice.Room, error) {
-	query := `SELECT room_id, creator_id, available_seats, status FROM rooms WHERE status = 1;`
+	query := `SELECT room_id, creator_id, available_seats, status, require_verified, estimated_price, currency FROM rooms WHERE status = 1;`
 	rows, err := r.db.Query(ctx, query)
 	if err != nil {
 		return nil, err
@@ -100,7 +130,7 @@ func (r *repository) ListAvailableRooms(ctx context.Context) ([]*roomservice.Roo
 	var rooms []*roomservice.Room
 	for rows.Next() {
 		room := &roomservice.Room{}
-		err := rows.Scan(&room.RoomId, &room.CreatorId, &room.AvailableSeats, &room.Status)
+		err := rows.Scan(&room.RoomId, &room.CreatorId, &room.AvailableSeats, &room.Status, &room.RequireVerified, &room.EstimatedPrice, &room.Currency)
 		if err != nil {
 			return nil, err
 		}
@@ -148,3 +178,136 @@ func (r *repository) CompleteRoom(ctx context.Context, roomID string, totalPrice
 	)
 	return err
 }
+
+// SearchRooms ищет комнаты по id, участнику, статусу и дате создания
+func (r *repository) SearchRooms(ctx context.Context, f RoomFilter) ([]*roomservice.Room, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if f.RoomID != "" {
+		conds = append(conds, "r.room_id = "+arg(f.RoomID))
+	}
+	if f.UserID != "" {
+		p := arg(f.UserID)
+		conds = append(conds, "(r.creator_id = "+p+" OR EXISTS (SELECT 1 FROM room_members m WHERE m.room_id = r.room_id AND m.user_id = "+p+"))")
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "r.created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "r.created_at < "+arg(f.CreatedTo))
+	}
+	if f.Status != roomservice.RoomStatus_ROOM_STATUS_UNSPECIFIED {
+		conds = append(conds, "r.status = "+arg(f.Status))
+	}
+
+	query := `
+	SELECT r.room_id, r.creator_id, r.available_seats, r.status, r.total_price, r.cost_per_member,
+	       r.created_at, r.scheduled_time, r.require_verified, r.estimated_price, r.currency
+	FROM rooms r`
+	if len(conds) > 0 {
+		query += " WHERE " + strings.Join(conds, " AND ")
+	}
+	query += " ORDER BY r.created_at DESC LIMIT " + arg(f.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SearchRooms: %w", err)
+	}
+	defer rows.Close()
+
+	var rooms []*roomservice.Room
+	for rows.Next() {
+		room := &roomservice.Room{}
+		var createdAt, scheduled time.Time
+		if err := rows.Scan(&room.RoomId, &room.CreatorId, &room.AvailableSeats, &room.Status,
+			&room.TotalPrice, &room.CostPerMember, &createdAt, &scheduled, &room.RequireVerified, &room.EstimatedPrice, &room.Currency); err != nil {
+			return nil, fmt.Errorf("SearchRooms scan: %w", err)
+		}
+		room.CreatedAt = timestamppb.New(createdAt)
+		room.ScheduledTime = timestamppb.New(scheduled)
+		rooms = append(rooms, room)
+	}
+	return rooms, rows.Err()
+}
+
+// AddEvent добавляет событие в хронологию комнаты
+func (r *repository) AddEvent(ctx context.Context, e *roomservice.RoomEvent) error {
+	query := `
+	INSERT INTO room_events (room_id, event_type, actor_id, details)
+	VALUES ($1, $2, NULLIF($3, '')::uuid, $4);
+	`
+	_, err := r.db.Exec(ctx, query, e.RoomId, e.Type, e.ActorId, e.Details)
+	if err != nil {
+		return fmt.Errorf("AddEvent: %w", err)
+	}
+	return nil
+}
+
+// ListEvents возвращает хронологию комнаты в порядке возникновения
+func (r *repository) ListEvents(ctx context.Context, roomID string) ([]*roomservice.RoomEvent, error) {
+	query := `
+	SELECT event_id, room_id, event_type, COALESCE(actor_id::text, ''), details, created_at
+	FROM room_events WHERE room_id = $1
+	ORDER BY created_at, event_id;
+	`
+	rows, err := r.db.Query(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("ListEvents: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*roomservice.RoomEvent
+	for rows.Next() {
+		e := &roomservice.RoomEvent{}
+		var createdAt time.Time
+		if err := rows.Scan(&e.EventId, &e.RoomId, &e.Type, &e.ActorId, &e.Details, &createdAt); err != nil {
+			return nil, fmt.Errorf("ListEvents scan: %w", err)
+		}
+		e.CreatedAt = timestamppb.New(createdAt)
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// AddFee записывает штраф пассажиру; штраф одного вида начисляется один раз на комнату
+func (r *repository) AddFee(ctx context.Context, roomID string, fee *roomservice.RoomFee) error {
+	query := `
+	INSERT INTO room_fees (room_id, user_id, fee_type, amount)
+	VALUES ($1, $2, $3, $4)
+	ON CONFLICT (room_id, user_id, fee_type) DO NOTHING;
+	`
+	if _, err := r.db.Exec(ctx, query, roomID, fee.UserId, fee.Type, fee.Amount); err != nil {
+		return fmt.Errorf("AddFee: %w", err)
+	}
+	return nil
+}
+
+// ListFees возвращает штрафы комнаты в порядке начисления
+func (r *repository) ListFees(ctx context.Context, roomID string) ([]*roomservice.RoomFee, error) {
+	query := `
+	SELECT user_id, fee_type, amount
+	FROM room_fees WHERE room_id = $1
+	ORDER BY created_at;
+	`
+	rows, err := r.db.Query(ctx, query, roomID)
+	if err != nil {
+		return nil, fmt.Errorf("ListFees: %w", err)
+	}
+	defer rows.Close()
+
+	var fees []*roomservice.RoomFee
+	for rows.Next() {
+		fee := &roomservice.RoomFee{}
+		if err := rows.Scan(&fee.UserId, &fee.Type, &fee.Amount); err != nil {
+			return nil, fmt.Errorf("ListFees scan: %w", err)
+		}
+		fees = append(fees, fee)
+	}
+	return fees, rows.Err()
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	paymentpb "we_ride/internal/services/payment_service/pb"
+	"we_ride/internal/services/room_service/internal/repository"
+	roomservice "we_ride/internal/services/room_service/pb"
+)
+
+// Типы событий хронологии комнаты
+const (
+	EventCreated       = "created"
+	EventJoined        = "joined"
+	EventLeft          = "left"
+	EventFull          = "full"
+	EventStarted       = "started"
+	EventNoShow        = "no_show"
+	EventCompleted     = "completed"
+	EventCancelled     = "cancelled"
+	EventPaymentFailed = "payment_failed" // часть пассажиров не оплатила, см. RetryFailedPayments
+	EventFeeCharged    = "fee_charged"    // штраф за позднюю отмену или неявку
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// SearchRooms ищет комнаты по id, участнику, статусу и дате создания; только для admin
+func (s *RoomService) SearchRooms(ctx context.Context, req *roomservice.SearchRoomsRequest) (*roomservice.SearchRoomsResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	f := repository.RoomFilter{
+		RoomID: req.RoomId,
+		UserID: req.UserId,
+		Status: req.Status,
+		Limit:  defaultSearchLimit,
+	}
+	if req.Limit > 0 {
+		f.Limit = min(int(req.Limit), maxSearchLimit)
+	}
+	if req.CreatedFrom != nil {
+		f.CreatedFrom = req.CreatedFrom.AsTime()
+	}
+	if req.CreatedTo != nil {
+		f.CreatedTo = req.CreatedTo.AsTime()
+	}
+
+	rooms, err := s.repo.SearchRooms(ctx, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search rooms: %v", err)
+	}
+	return &roomservice.SearchRoomsResponse{Rooms: rooms}, nil
+}
+
+// CancelRoom принудительно отменяет комнату; только для admin.
+// Завершённую поездку отменить нельзя — для неё оформляется возврат.
+func (s *RoomService) CancelRoom(ctx context.Context, req *roomservice.CancelRoomRequest) (*roomservice.CancelRoomResponse, error) {
+	admin, err := identity.RequireRole(ctx, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
+	}
+	switch room.Status {
+	case roomservice.RoomStatus_ROOM_STATUS_CANCELLED:
+		return nil, status.Error(codes.FailedPrecondition, "room is already cancelled")
+	case roomservice.RoomStatus_ROOM_STATUS_COMPLETED:
+		return nil, status.Error(codes.FailedPrecondition, "ride is completed, issue a refund instead")
+	}
+
+	if err := s.repo.UpdateRoomStatus(ctx, room.RoomId, roomservice.RoomStatus_ROOM_STATUS_CANCELLED); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to cancel room: %v", err)
+	}
+	room.Status = roomservice.RoomStatus_ROOM_STATUS_CANCELLED
+	s.recordEvent(ctx, room.RoomId, EventCancelled, admin.UserID, req.Reason)
+	s.voidPayment(ctx, &paymentpb.VoidPaymentRequest{RoomId: room.RoomId, Reason: req.Reason})
+	return &roomservice.CancelRoomResponse{Room: room}, nil
+}
+
+// GetRoomTimeline возвращает комнату, её участников и хронологию событий; только для admin
+func (s *RoomService) GetRoomTimeline(ctx context.Context, req *roomservice.GetRoomTimelineRequest) (*roomservice.GetRoomTimelineResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
+	}
+	members, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room members: %v", err)
+	}
+	events, err := s.repo.ListEvents(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room events: %v", err)
+	}
+	return &roomservice.GetRoomTimelineResponse{Room: room, Members: members, Events: events}, nil
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	paymentpb "we_ride/internal/services/payment_service/pb"
+	roomservice "we_ride/internal/services/room_service/pb"
+)
+
+// Виды штрафов payment_service ChargeFee
+const (
+	feeCancellation = "cancellation"
+	feeNoShow       = "no_show"
+)
+
+// StartRide начинает поездку, только для создателя комнаты. Пассажиры из no_show_user_ids исключаются
+// из комнаты, их холды снимаются, и по правилам комнаты списывается штраф за неявку.
+func (s *RoomService) StartRide(ctx context.Context, req *roomservice.StartRideRequest) (*roomservice.StartRideResponse, error) {
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	driverID, err := identity.Authorize(ctx, req.DriverId)
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
+	}
+	if room.CreatorId != driverID {
+		return nil, status.Error(codes.PermissionDenied, "only the room creator can start the ride")
+	}
+	if room.Status != roomservice.RoomStatus_ROOM_STATUS_WAITING && room.Status != roomservice.RoomStatus_ROOM_STATUS_FULL {
+		return nil, status.Error(codes.FailedPrecondition, "ride has already started or finished")
+	}
+
+	memberIDs, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room members: %v", err)
+	}
+	var noShows []string
+	for _, userID := range req.NoShowUserIds {
+		if userID == room.CreatorId || !slices.Contains(memberIDs, userID) {
+			return nil, status.Errorf(codes.InvalidArgument, "user %s is not a passenger of this room", userID)
+		}
+		if !slices.Contains(noShows, userID) {
+			noShows = append(noShows, userID)
+		}
+	}
+
+	for _, userID := range noShows {
+		if err := s.repo.RemoveMember(ctx, room.RoomId, userID); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to remove no-show passenger: %v", err)
+		}
+		s.recordEvent(ctx, room.RoomId, EventNoShow, userID, "")
+		s.voidPayment(ctx, &paymentpb.VoidPaymentRequest{RoomId: room.RoomId, UserId: userID, Reason: "no show"})
+	}
+	if err := s.repo.UpdateRoomStatus(ctx, room.RoomId, roomservice.RoomStatus_ROOM_STATUS_ON_RIDE); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to update room status: %v", err)
+	}
+	room.Status = roomservice.RoomStatus_ROOM_STATUS_ON_RIDE
+	s.recordEvent(ctx, room.RoomId, EventStarted, driverID, fmt.Sprintf("no_show=%s", strings.Join(noShows, ",")))
+
+	resp := &roomservice.StartRideResponse{Room: room}
+	if fee := room.GetCancellationPolicy().GetNoShowFee(); fee > 0 {
+		for _, userID := range noShows {
+			if s.chargeFee(ctx, room, userID, feeNoShow, fee) {
+				resp.NoShowFees++
+			}
+		}
+	}
+	return resp, nil
+}
+
+// cancellationPolicy — правила отмены новой комнаты: указанные водителем или правила сервиса по умолчанию
+func (s *RoomService) cancellationPolicy(p *roomservice.CancellationPolicy) (*roomservice.CancellationPolicy, error) {
+	if p == nil {
+		return &roomservice.CancellationPolicy{
+			FreeCancelMinutes: s.policy.FreeCancelMinutes,
+			CancellationFee:   s.policy.CancellationFee,
+			NoShowFee:         s.policy.NoShowFee,
+		}, nil
+	}
+	if p.FreeCancelMinutes < 0 || p.CancellationFee < 0 || p.NoShowFee < 0 {
+		return nil, status.Error(codes.InvalidArgument, "cancellation policy values must not be negative")
+	}
+	return p, nil
+}
+
+// lateExitFee — штраф за выход из комнаты позже бесплатного окна. Создатель комнаты не штрафуется,
+// как и выход из начатой, завершённой или отменённой поездки.
+func lateExitFee(room *roomservice.Room, userID string, now time.Time) float32 {
+	policy := room.GetCancellationPolicy()
+	if userID == room.CreatorId || policy.GetCancellationFee() <= 0 || room.ScheduledTime == nil {
+		return 0
+	}
+	if room.Status != roomservice.RoomStatus_ROOM_STATUS_WAITING && room.Status != roomservice.RoomStatus_ROOM_STATUS_FULL {
+		return 0
+	}
+	freeUntil := room.ScheduledTime.AsTime().Add(-time.Duration(policy.FreeCancelMinutes) * time.Minute)
+	if now.Before(freeUntil) {
+		return 0
+	}
+	return policy.CancellationFee
+}
+
+// chargeFee записывает штраф в комнату и списывает его через payment_service в пользу водителя:
+// сумму payment_service берёт из комнаты (GetRoomBilling), а не из запроса.
+// Ошибка только логируется: выход из комнаты и начало поездки из-за неё не отменяются.
+func (s *RoomService) chargeFee(ctx context.Context, room *roomservice.Room, userID, feeType string, amount float32) bool {
+	err := s.repo.AddFee(ctx, room.RoomId, &roomservice.RoomFee{UserId: userID, Type: feeType, Amount: amount})
+	var resp *paymentpb.ChargeFeeResponse
+	if err == nil {
+		resp, err = s.payFee(ctx, &paymentpb.ChargeFeeRequest{RoomId: room.RoomId, UserId: userID, Type: feeType})
+	}
+	if err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to charge fee",
+			zap.String("room_id", room.RoomId), zap.String("user_id", userID), zap.String("type", feeType), zap.Error(err))
+		s.recordEvent(ctx, room.RoomId, EventPaymentFailed, userID, fmt.Sprintf("fee=%s amount=%.2f", feeType, amount))
+		return false
+	}
+	s.recordEvent(ctx, room.RoomId, EventFeeCharged, userID,
+		fmt.Sprintf("fee=%s amount=%.2f status=%s", feeType, amount, resp.Payment.GetStatus()))
+	return true
+}
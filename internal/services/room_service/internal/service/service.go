@@ -3,6 +3,9 @@ package service
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc"
@@ -11,6 +14,10 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"go.uber.org/zap"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
 	paymentpb "we_ride/internal/services/payment_service/pb"
 	"we_ride/internal/services/room_service/internal/repository"
 	roomservice "we_ride/internal/services/room_service/pb"
@@ -20,21 +27,33 @@ import (
 type paymentProcessor func(ctx context.Context, req *paymentpb.ProcessPaymentRequest) (*paymentpb.ProcessPaymentResponse, error)
 type routeSaver func(req *roomservice.CompleteRideRequest, memberIDs []string, startAddr, endAddr string, totalPrice float32)
 
+// holdAuthorized — статус холда payment_service, подтверждённого пассажиром: сумма заморожена на карте
+const holdAuthorized = "waiting_for_capture"
+
 type RoomService struct {
 	roomservice.UnimplementedRoomServiceServer
 	repo               repository.Repository
 	userServiceAddr    string
 	paymentServiceAddr string
+	currency           string                          // валюта новых комнат
+	policy             *roomservice.CancellationPolicy // правила отмены новых комнат по умолчанию
 
 	processPaymentFn paymentProcessor
 	saveRouteFn      routeSaver
+	paymentClient    paymentpb.PaymentServiceClient // подменяется в тестах
 }
 
-func New(repo repository.Repository, userServiceAddr, paymentServiceAddr string) *RoomService {
+// New создаёт сервис комнат; policy nil — комнаты без штрафов, если водитель не указал правила
+func New(repo repository.Repository, userServiceAddr, paymentServiceAddr, currency string, policy *roomservice.CancellationPolicy) *RoomService {
+	if policy == nil {
+		policy = &roomservice.CancellationPolicy{}
+	}
 	return &RoomService{
 		repo:               repo,
 		userServiceAddr:    userServiceAddr,
 		paymentServiceAddr: paymentServiceAddr,
+		currency:           currency,
+		policy:             policy,
 	}
 }
 
@@ -42,17 +61,28 @@ func (s *RoomService) CreateRoom(ctx context.Context, req *roomservice.CreateRoo
 	if req.StartLocation == nil || req.EndLocation == nil {
 		return nil, status.Error(codes.InvalidArgument, "start and end location are required")
 	}
-	if req.CreatorId == "" {
-		return nil, status.Error(codes.InvalidArgument, "creator_id is required")
-	}
 	if req.MaxMembers <= 0 {
 		return nil, status.Error(codes.InvalidArgument, "max_members must be greater than 0")
 	}
+	if req.EstimatedPrice < 0 {
+		return nil, status.Error(codes.InvalidArgument, "estimated_price must not be negative")
+	}
+	policy, err := s.cancellationPolicy(req.CancellationPolicy)
+	if err != nil {
+		return nil, err
+	}
+	creatorID, err := identity.Authorize(ctx, req.CreatorId)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := identity.RequireRole(ctx, identity.RoleDriver); err != nil {
+		return nil, status.Error(codes.PermissionDenied, "only drivers can create rooms")
+	}
 
 	roomID := uuid.New().String()
 	room := &roomservice.Room{
 		RoomId:         roomID,
-		CreatorId:      req.CreatorId,
+		CreatorId:      creatorID,
 		AvailableSeats: req.MaxMembers,
 		Status:         roomservice.RoomStatus_ROOM_STATUS_WAITING,
 		StartLocation:  req.StartLocation,
@@ -61,25 +91,43 @@ func (s *RoomService) CreateRoom(ctx context.Context, req *roomservice.CreateRoo
 		ScheduledTime:  req.ScheduledTime,
 		TotalPrice:     0,
 		CostPerMember:  0,
+
+		RequireVerified: req.RequireVerified,
+		EstimatedPrice:  req.EstimatedPrice,
+		Currency:        s.currency,
+
+		CancellationPolicy: policy,
 	}
 
 	if err := s.repo.CreateRoom(ctx, room); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to create room: %v", err)
 	}
-	if err := s.repo.AddMember(ctx, roomID, req.CreatorId); err != nil {
+	if err := s.repo.AddMember(ctx, roomID, creatorID); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to add creator as member: %v", err)
 	}
+	s.recordEvent(ctx, roomID, EventCreated, creatorID, "")
 	return &roomservice.CreateRoomResponse{Room: room}, nil
 }
 
 func (s *RoomService) JoinRoom(ctx context.Context, req *roomservice.JoinRoomRequest) (*roomservice.JoinRoomResponse, error) {
-	if req.RoomId == "" || req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "room_id and user_id are required")
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
 	}
 	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
 	}
+	if room.Status != roomservice.RoomStatus_ROOM_STATUS_WAITING && room.Status != roomservice.RoomStatus_ROOM_STATUS_FULL {
+		return nil, status.Error(codes.FailedPrecondition, "room is not open for joining")
+	}
+	// Флаг email_verified берётся из токена: после подтверждения нужно перелогиниться
+	if caller, _ := identity.FromContext(ctx); room.RequireVerified && !caller.EmailVerified {
+		return nil, status.Error(codes.PermissionDenied, "room requires a verified email")
+	}
 
 	memberIDs, err := s.repo.GetRoomMembers(ctx, req.RoomId)
 	if err != nil {
@@ -87,25 +135,81 @@ func (s *RoomService) JoinRoom(ctx context.Context, req *roomservice.JoinRoomReq
 	}
 
 	if len(memberIDs) >= int(room.AvailableSeats) {
-		if err := s.repo.UpdateRoomStatus(ctx, room.RoomId, roomservice.RoomStatus_ROOM_STATUS_FULL); err != nil {
-			return nil, status.Errorf(codes.Internal, "failed to update room status: %v", err)
+		if room.Status != roomservice.RoomStatus_ROOM_STATUS_FULL {
+			if err := s.repo.UpdateRoomStatus(ctx, room.RoomId, roomservice.RoomStatus_ROOM_STATUS_FULL); err != nil {
+				return nil, status.Errorf(codes.Internal, "failed to update room status: %v", err)
+			}
+			s.recordEvent(ctx, room.RoomId, EventFull, "", "")
+		}
+		if room.EstimatedPrice > 0 {
+			// Холд, подтверждённый после того, как место заняли, больше не нужен
+			s.voidPayment(ctx, &paymentpb.VoidPaymentRequest{RoomId: room.RoomId, UserId: userID, Reason: "room is full"})
 		}
 		return nil, status.Error(codes.FailedPrecondition, "room is full")
 	}
-	if err := s.repo.AddMember(ctx, req.RoomId, req.UserId); err != nil {
+
+	// Холд на оценочную долю с учётом вступающего: пассажир попадает в комнату, только когда холд подтверждён.
+	// До этого он получает ссылку на подтверждение и повторяет вступление после оплаты.
+	var confirmationURL string
+	if room.EstimatedPrice > 0 {
+		hold, err := s.authorizePayment(ctx, &paymentpb.AuthorizePaymentRequest{
+			RoomId:   room.RoomId,
+			UserId:   userID,
+			Amount:   room.EstimatedPrice / float32(len(passengers(room, memberIDs))+1),
+			Currency: room.Currency,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.FailedPrecondition, "payment authorization failed: %v", err)
+		}
+		confirmationURL = hold.ConfirmationUrl
+		if hold.GetPayment().GetStatus() != holdAuthorized {
+			return &roomservice.JoinRoomResponse{Room: room, PaymentConfirmationUrl: confirmationURL, PaymentPending: true}, nil
+		}
+	}
+
+	if err := s.repo.AddMember(ctx, req.RoomId, userID); err != nil {
+		if room.EstimatedPrice > 0 {
+			s.voidPayment(ctx, &paymentpb.VoidPaymentRequest{RoomId: room.RoomId, UserId: userID, Reason: "join failed"})
+		}
 		return nil, status.Errorf(codes.Internal, "failed to join room: %v", err)
 	}
-	return &roomservice.JoinRoomResponse{Room: room}, nil
+	s.recordEvent(ctx, req.RoomId, EventJoined, userID, "")
+	return &roomservice.JoinRoomResponse{Room: room, PaymentConfirmationUrl: confirmationURL}, nil
 }
 
 func (s *RoomService) ExitRoom(ctx context.Context, req *roomservice.ExitRoomRequest) (*roomservice.ExitRoomResponse, error) {
-	if req.RoomId == "" || req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "room_id and user_id are required")
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	userID, err := identity.Authorize(ctx, req.UserId)
+	if err != nil {
+		return nil, err
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
 	}
-	if err := s.repo.RemoveMember(ctx, req.RoomId, req.UserId); err != nil {
+	if room.Status == roomservice.RoomStatus_ROOM_STATUS_ON_RIDE {
+		return nil, status.Error(codes.FailedPrecondition, "ride is in progress")
+	}
+	memberIDs, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room members: %v", err)
+	}
+
+	if err := s.repo.RemoveMember(ctx, req.RoomId, userID); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to exit room: %v", err)
 	}
-	return &roomservice.ExitRoomResponse{Success: true}, nil
+	s.recordEvent(ctx, req.RoomId, EventLeft, userID, "")
+	s.voidPayment(ctx, &paymentpb.VoidPaymentRequest{RoomId: req.RoomId, UserId: userID, Reason: "left room"})
+
+	resp := &roomservice.ExitRoomResponse{Success: true}
+	if fee := lateExitFee(room, userID, time.Now()); fee > 0 && slices.Contains(memberIDs, userID) {
+		if s.chargeFee(ctx, room, userID, feeCancellation, fee) {
+			resp.CancellationFee = fee
+		}
+	}
+	return resp, nil
 }
 
 func (s *RoomService) FindRoom(ctx context.Context, _ *roomservice.FindRoomRequest) (*roomservice.FindRoomResponse, error) {
@@ -127,28 +231,73 @@ func (s *RoomService) GetRoomDetails(ctx context.Context, req *roomservice.GetRo
 	return &roomservice.GetRoomDetailsResponse{Room: room}, nil
 }
 
+// GetRoomBilling возвращает комнату с текущими участниками и штрафами для проверки списаний в payment_service.
+// Вызывается payment_service от имени водителя или admin, а при штрафе за выход — от имени пассажира:
+// пассажир видит только комнату и свои штрафы.
+func (s *RoomService) GetRoomBilling(ctx context.Context, req *roomservice.GetRoomBillingRequest) (*roomservice.GetRoomBillingResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.RoomId == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id is required")
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
+	}
+	fees, err := s.repo.ListFees(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room fees: %v", err)
+	}
+	if room.CreatorId != caller.UserID && !caller.HasRole(identity.RoleAdmin) {
+		own := slices.DeleteFunc(fees, func(f *roomservice.RoomFee) bool { return f.UserId != caller.UserID })
+		if len(own) == 0 {
+			return nil, status.Error(codes.PermissionDenied, "only the room creator can see its billing")
+		}
+		return &roomservice.GetRoomBillingResponse{Room: room, Fees: own}, nil
+	}
+	members, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room members: %v", err)
+	}
+	return &roomservice.GetRoomBillingResponse{Room: room, Members: members, Fees: fees}, nil
+}
+
 func (s *RoomService) CompleteRide(ctx context.Context, req *roomservice.CompleteRideRequest) (*roomservice.CompleteRideResponse, error) {
 	if req.RoomId == "" {
 		return nil, status.Error(codes.InvalidArgument, "room_id is required")
 	}
-	if req.DriverId == "" {
-		return nil, status.Error(codes.InvalidArgument, "driver_id is required")
+	driverID, err := identity.Authorize(ctx, req.DriverId)
+	if err != nil {
+		return nil, err
 	}
+	req.DriverId = driverID
 
 	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
 	}
-	if room.Status == roomservice.RoomStatus_ROOM_STATUS_COMPLETED {
+	if room.CreatorId != driverID {
+		return nil, status.Error(codes.PermissionDenied, "only the room creator can complete the ride")
+	}
+	switch room.Status {
+	case roomservice.RoomStatus_ROOM_STATUS_WAITING, roomservice.RoomStatus_ROOM_STATUS_FULL, roomservice.RoomStatus_ROOM_STATUS_ON_RIDE:
+	case roomservice.RoomStatus_ROOM_STATUS_COMPLETED:
 		return nil, status.Error(codes.AlreadyExists, "ride already completed")
+	default:
+		// Отменённую комнату нельзя завершить и списать за неё оплату
+		return nil, status.Error(codes.FailedPrecondition, "room is not open or in progress")
 	}
 
-	memberIDs, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	members, err := s.repo.GetRoomMembers(ctx, req.RoomId)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get members: %v", err)
 	}
+	// Долю платят только пассажиры: водитель — участник комнаты, но не платит сам себе
+	memberIDs := passengers(room, members)
 	if len(memberIDs) == 0 {
-		return nil, status.Error(codes.FailedPrecondition, "no members in room")
+		return nil, status.Error(codes.FailedPrecondition, "no passengers in room")
 	}
 
 	totalPrice := req.TotalPrice
@@ -157,6 +306,8 @@ func (s *RoomService) CompleteRide(ctx context.Context, req *roomservice.Complet
 	if err := s.repo.CompleteRoom(ctx, req.RoomId, totalPrice, costPerMember); err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to complete room: %v", err)
 	}
+	s.recordEvent(ctx, req.RoomId, EventCompleted, driverID,
+		fmt.Sprintf("total_price=%.2f cost_per_member=%.2f members=%d", totalPrice, costPerMember, len(memberIDs)))
 
 	startAddr := ""
 	if room.StartLocation != nil {
@@ -167,34 +318,123 @@ func (s *RoomService) CompleteRide(ctx context.Context, req *roomservice.Complet
 		endAddr = room.EndLocation.Address
 	}
 
-	s.saveRoute(req, memberIDs, startAddr, endAddr, totalPrice)
+	s.saveRoute(ctx, req, memberIDs, startAddr, endAddr, totalPrice)
 
-	payResp, err := s.processPayment(ctx, &paymentpb.ProcessPaymentRequest{
-	go s.saveRoute(req, memberIDs, startAddr, endAddr, totalPrice)
+	description := fmt.Sprintf("Поездка %s → %s", startAddr, endAddr)
+	var payments []*paymentpb.Payment
+	if room.EstimatedPrice > 0 {
+		// Под комнату ставились холды: списываем итоговую долю с них
+		capResp, err := s.capturePayment(ctx, &paymentpb.CapturePaymentRequest{
+			RoomId:        req.RoomId,
+			UserIds:       memberIDs,
+			AmountPerUser: costPerMember,
+			Description:   description,
+			DriverId:      driverID,
+			Currency:      room.Currency,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "payment failed: %v", err)
+		}
+		payments = capResp.Payments
+		if !capResp.Success {
+			s.recordPaymentFailed(ctx, req.RoomId, driverID, capResp.Status, capResp.Results)
+		}
+	} else {
+		payResp, err := s.processPayment(ctx, &paymentpb.ProcessPaymentRequest{
+			RoomId:        req.RoomId,
+			UserIds:       memberIDs,
+			AmountPerUser: costPerMember,
+			Description:   description,
+			DriverId:      driverID,
+			Currency:      room.Currency,
+		})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "payment failed: %v", err)
+		}
+		payments = payResp.Payments
+		if !payResp.Success {
+			s.recordPaymentFailed(ctx, req.RoomId, driverID, payResp.Status, payResp.Results)
+		}
+	}
+
+	return &roomservice.CompleteRideResponse{
+		Success:       true,
+		TotalPrice:    totalPrice,
+		CostPerMember: costPerMember,
+		PaymentsCount: int32(len(payments)),
+	}, nil
+}
 
-	paymentConn, err := grpc.NewClient(s.paymentServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+// ApplyPromoCode применяет промокод участника комнаты; скидка учитывается при оплате поездки
+func (s *RoomService) ApplyPromoCode(ctx context.Context, req *roomservice.ApplyPromoCodeRequest) (*roomservice.ApplyPromoCodeResponse, error) {
+	if req.RoomId == "" || req.Code == "" {
+		return nil, status.Error(codes.InvalidArgument, "room_id and code are required")
+	}
+	userID, err := identity.Authorize(ctx, req.UserId)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to connect to payment service: %v", err)
+		return nil, err
+	}
+	room, err := s.repo.GetRoomByID(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "room not found: %v", err)
+	}
+	if room.Status == roomservice.RoomStatus_ROOM_STATUS_COMPLETED || room.Status == roomservice.RoomStatus_ROOM_STATUS_CANCELLED {
+		return nil, status.Error(codes.FailedPrecondition, "ride is already finished")
+	}
+	memberIDs, err := s.repo.GetRoomMembers(ctx, req.RoomId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to get room members: %v", err)
+	}
+	if !slices.Contains(memberIDs, userID) {
+		return nil, status.Error(codes.PermissionDenied, "only room members can apply promo codes")
 	}
-	defer paymentConn.Close()
 
-	paymentClient := paymentpb.NewPaymentServiceClient(paymentConn)
-	payResp, err := paymentClient.ProcessPayment(ctx, &paymentpb.ProcessPaymentRequest{
-		RoomId:        req.RoomId,
-		UserIds:       memberIDs,
-		AmountPerUser: costPerMember,
-		Description:   fmt.Sprintf("Поездка %s → %s", startAddr, endAddr),
+	resp, err := s.applyPromoCode(ctx, &paymentpb.ApplyPromoCodeRequest{
+		RoomId:   room.RoomId,
+		UserId:   userID,
+		Code:     req.Code,
+		Currency: room.Currency,
 	})
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "payment failed: %v", err)
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Errorf(codes.Unavailable, "failed to apply promo code: %v", err)
+	}
+	promo := resp.PromoCode
+	return &roomservice.ApplyPromoCodeResponse{
+		RoomId:   room.RoomId,
+		Code:     promo.Code,
+		Type:     promo.Type,
+		Value:    promo.Value,
+		Currency: promo.Currency,
+	}, nil
+}
+
+// passengers — участники комнаты без её создателя-водителя
+func passengers(room *roomservice.Room, memberIDs []string) []string {
+	return slices.DeleteFunc(slices.Clone(memberIDs), func(id string) bool { return id == room.CreatorId })
+}
+
+// recordEvent пишет событие в хронологию комнаты; ошибка не прерывает основную операцию
+func (s *RoomService) recordEvent(ctx context.Context, roomID, eventType, actorID, details string) {
+	err := s.repo.AddEvent(ctx, &roomservice.RoomEvent{RoomId: roomID, Type: eventType, ActorId: actorID, Details: details})
+	if err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to record room event",
+			zap.String("room_id", roomID), zap.String("type", eventType), zap.Error(err))
 	}
+}
 
-	return &roomservice.CompleteRideResponse{
-		Success:       true,
-		TotalPrice:    totalPrice,
-		CostPerMember: costPerMember,
-		PaymentsCount: int32(len(payResp.Payments)),
-	}, nil
+// recordPaymentFailed записывает в хронологию комнаты пассажиров, которые не оплатили долю
+func (s *RoomService) recordPaymentFailed(ctx context.Context, roomID, driverID, paymentStatus string, results []*paymentpb.PaymentResult) {
+	var failed []string
+	for _, r := range results {
+		if r.Status == "failed" {
+			failed = append(failed, r.UserId)
+		}
+	}
+	s.recordEvent(ctx, roomID, EventPaymentFailed, driverID,
+		fmt.Sprintf("status=%s failed_users=%s", paymentStatus, strings.Join(failed, ",")))
 }
 
 func (s *RoomService) processPayment(ctx context.Context, req *paymentpb.ProcessPaymentRequest) (*paymentpb.ProcessPaymentResponse, error) {
@@ -202,31 +442,100 @@ func (s *RoomService) processPayment(ctx context.Context, req *paymentpb.Process
 		return s.processPaymentFn(ctx, req)
 	}
 
-	paymentConn, err := grpc.NewClient(s.paymentServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	paymentClient, closeConn, err := s.payments()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to payment service: %w", err)
+		return nil, err
 	}
-	defer paymentConn.Close()
-
-	paymentClient := paymentpb.NewPaymentServiceClient(paymentConn)
+	defer closeConn()
 	return paymentClient.ProcessPayment(ctx, req)
 }
 
-func (s *RoomService) saveRoute(req *roomservice.CompleteRideRequest, memberIDs []string, startAddr, endAddr string, totalPrice float32) {
+func (s *RoomService) authorizePayment(ctx context.Context, req *paymentpb.AuthorizePaymentRequest) (*paymentpb.AuthorizePaymentResponse, error) {
+	paymentClient, closeConn, err := s.payments()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+	return paymentClient.AuthorizePayment(ctx, req)
+}
+
+func (s *RoomService) capturePayment(ctx context.Context, req *paymentpb.CapturePaymentRequest) (*paymentpb.CapturePaymentResponse, error) {
+	paymentClient, closeConn, err := s.payments()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+	return paymentClient.CapturePayment(ctx, req)
+}
+
+func (s *RoomService) payFee(ctx context.Context, req *paymentpb.ChargeFeeRequest) (*paymentpb.ChargeFeeResponse, error) {
+	paymentClient, closeConn, err := s.payments()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+	return paymentClient.ChargeFee(ctx, req)
+}
+
+func (s *RoomService) applyPromoCode(ctx context.Context, req *paymentpb.ApplyPromoCodeRequest) (*paymentpb.ApplyPromoCodeResponse, error) {
+	paymentClient, closeConn, err := s.payments()
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+	return paymentClient.ApplyPromoCode(ctx, req)
+}
+
+// voidPayment снимает холды и не использованные промокоды, в том числе в комнатах без холдов; ошибка только
+// логируется — неснятый холд ЮKassa отменит сама по истечении срока
+func (s *RoomService) voidPayment(ctx context.Context, req *paymentpb.VoidPaymentRequest) {
+	paymentClient, closeConn, err := s.payments()
+	if err == nil {
+		defer closeConn()
+		_, err = paymentClient.VoidPayment(ctx, req)
+	}
+	if err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to void payment holds",
+			zap.String("room_id", req.RoomId), zap.String("user_id", req.UserId), zap.Error(err))
+	}
+}
+
+// payments открывает соединение с payment_service от имени вызывающего; closeConn закрывает его
+func (s *RoomService) payments() (client paymentpb.PaymentServiceClient, closeConn func(), err error) {
+	if s.paymentClient != nil {
+		return s.paymentClient, func() {}, nil
+	}
+
+	paymentConn, err := grpc.NewClient(s.paymentServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to payment service: %w", err)
+	}
+	return paymentpb.NewPaymentServiceClient(paymentConn), func() { _ = paymentConn.Close() }, nil
+}
+
+func (s *RoomService) saveRoute(ctx context.Context, req *roomservice.CompleteRideRequest, memberIDs []string, startAddr, endAddr string, totalPrice float32) {
 	if s.saveRouteFn != nil {
 		s.saveRouteFn(req, memberIDs, startAddr, endAddr, totalPrice)
 		return
 	}
 
+	// Сохраняем маршрут в фоне, но от имени водителя: токен остаётся в контексте
+	ctx = context.WithoutCancel(ctx)
 	go func() {
-		userConn, err := grpc.NewClient(s.userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		userConn, err := grpc.NewClient(s.userServiceAddr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+		)
 		if err != nil {
 			return
 		}
 		defer userConn.Close()
 
 		authClient := authpb.NewAuthClient(userConn)
-		_, _ = authClient.SaveRoute(context.Background(), &authpb.SaveRouteRequest{
+		_, _ = authClient.SaveRoute(ctx, &authpb.SaveRouteRequest{
 			RoomId:       req.RoomId,
 			DriverId:     req.DriverId,
 			StartPoint:   startAddr,
@@ -236,23 +545,6 @@ func (s *RoomService) saveRoute(req *roomservice.CompleteRideRequest, memberIDs
 			PassengerIds: memberIDs,
 		})
 	}()
-func (s *RoomService) saveRoute(req *roomservice.CompleteRideRequest, memberIDs []string, startAddr, endAddr string, totalPrice float32) {
-	userConn, err := grpc.NewClient(s.userServiceAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
-	if err != nil {
-		return
-	}
-	defer userConn.Close()
-
-	authClient := authpb.NewAuthClient(userConn)
-	_, _ = authClient.SaveRoute(context.Background(), &authpb.SaveRouteRequest{
-		RoomId:       req.RoomId,
-		DriverId:     req.DriverId,
-		StartPoint:   startAddr,
-		EndPoint:     endAddr,
-		Distance:     float64(req.DistanceKm),
-		TotalPrice:   float64(totalPrice),
-		PassengerIds: memberIDs,
-	})
 }
 
 func (s *RoomService) StreamRoomUpdates(
@@ -3,25 +3,32 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"sync"
 	"testing"
 	"time"
 
+	"we_ride/internal/pkg/identity"
 	paymentpb "we_ride/internal/services/payment_service/pb"
 	roomrepo "we_ride/internal/services/room_service/internal/repository"
 	roompb "we_ride/internal/services/room_service/pb"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type fakeRoomRepo struct {
 	rooms   map[string]*roompb.Room
 	members map[string][]string
+	events  []*roompb.RoomEvent
+	fees    map[string][]*roompb.RoomFee
 	mu      sync.Mutex
 }
 
 func newFakeRoomRepo() *fakeRoomRepo {
-	return &fakeRoomRepo{rooms: map[string]*roompb.Room{}, members: map[string][]string{}}
+	return &fakeRoomRepo{rooms: map[string]*roompb.Room{}, members: map[string][]string{}, fees: map[string][]*roompb.RoomFee{}}
 }
 
 func (f *fakeRoomRepo) CreateRoom(_ context.Context, room *roompb.Room) error {
@@ -90,14 +97,125 @@ func (f *fakeRoomRepo) CompleteRoom(_ context.Context, roomID string, totalPrice
 	return nil
 }
 
+func (f *fakeRoomRepo) SearchRooms(_ context.Context, filter roomrepo.RoomFilter) ([]*roompb.Room, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*roompb.Room
+	for _, room := range f.rooms {
+		if filter.RoomID != "" && room.RoomId != filter.RoomID {
+			continue
+		}
+		if filter.UserID != "" && room.CreatorId != filter.UserID {
+			continue
+		}
+		out = append(out, room)
+	}
+	return out, nil
+}
+func (f *fakeRoomRepo) AddEvent(_ context.Context, e *roompb.RoomEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	e.EventId = int64(len(f.events) + 1)
+	f.events = append(f.events, e)
+	return nil
+}
+func (f *fakeRoomRepo) ListEvents(_ context.Context, roomID string) ([]*roompb.RoomEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []*roompb.RoomEvent
+	for _, e := range f.events {
+		if e.RoomId == roomID {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+func (f *fakeRoomRepo) AddFee(_ context.Context, roomID string, fee *roompb.RoomFee) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, existing := range f.fees[roomID] {
+		if existing.UserId == fee.UserId && existing.Type == fee.Type {
+			return nil
+		}
+	}
+	f.fees[roomID] = append(f.fees[roomID], fee)
+	return nil
+}
+func (f *fakeRoomRepo) ListFees(_ context.Context, roomID string) ([]*roompb.RoomFee, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.fees[roomID]), nil
+}
+
 var _ roomrepo.Repository = (*fakeRoomRepo)(nil)
 
+// fakePaymentClient записывает вызовы холдов, штрафов и промокодов; остальные методы payment_service в тестах не нужны
+type fakePaymentClient struct {
+	paymentpb.PaymentServiceClient
+	authorized []*paymentpb.AuthorizePaymentRequest
+	captured   []*paymentpb.CapturePaymentRequest
+	voided     []*paymentpb.VoidPaymentRequest
+	promos     []*paymentpb.ApplyPromoCodeRequest
+	fees       []*paymentpb.ChargeFeeRequest
+	declined   bool
+	pending    bool     // холды остаются неподтверждёнными
+	unpaid     []string // пассажиры, чья доля не списывается при CapturePayment
+}
+
+func (f *fakePaymentClient) AuthorizePayment(_ context.Context, req *paymentpb.AuthorizePaymentRequest, _ ...grpc.CallOption) (*paymentpb.AuthorizePaymentResponse, error) {
+	if f.declined {
+		return nil, status.Error(codes.FailedPrecondition, "payment authorization declined")
+	}
+	f.authorized = append(f.authorized, req)
+	hold := &paymentpb.Payment{UserId: req.UserId, Amount: req.Amount, Status: holdAuthorized}
+	if f.pending {
+		hold.Status = "pending"
+	}
+	return &paymentpb.AuthorizePaymentResponse{Payment: hold, ConfirmationUrl: "https://pay.example/" + req.UserId}, nil
+}
+func (f *fakePaymentClient) CapturePayment(_ context.Context, req *paymentpb.CapturePaymentRequest, _ ...grpc.CallOption) (*paymentpb.CapturePaymentResponse, error) {
+	f.captured = append(f.captured, req)
+	resp := &paymentpb.CapturePaymentResponse{Success: true, Status: "succeeded"}
+	for _, id := range req.UserIds {
+		payment := &paymentpb.Payment{UserId: id, Amount: req.AmountPerUser, Status: "succeeded"}
+		result := &paymentpb.PaymentResult{UserId: id, Status: "succeeded", Payment: payment}
+		if slices.Contains(f.unpaid, id) {
+			payment.Status, result.Status = "failed", "failed"
+			resp.Success, resp.Status = false, "partial"
+		}
+		resp.Payments, resp.Results = append(resp.Payments, payment), append(resp.Results, result)
+	}
+	return resp, nil
+}
+func (f *fakePaymentClient) ChargeFee(_ context.Context, req *paymentpb.ChargeFeeRequest, _ ...grpc.CallOption) (*paymentpb.ChargeFeeResponse, error) {
+	f.fees = append(f.fees, req)
+	return &paymentpb.ChargeFeeResponse{Payment: &paymentpb.Payment{UserId: req.UserId, Status: "succeeded"}}, nil
+}
+func (f *fakePaymentClient) ApplyPromoCode(_ context.Context, req *paymentpb.ApplyPromoCodeRequest, _ ...grpc.CallOption) (*paymentpb.ApplyPromoCodeResponse, error) {
+	if req.Code != "WELCOME" {
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	}
+	f.promos = append(f.promos, req)
+	return &paymentpb.ApplyPromoCodeResponse{RoomId: req.RoomId, PromoCode: &paymentpb.PromoCode{Code: req.Code, Type: "percent", Value: 20}}, nil
+}
+func (f *fakePaymentClient) VoidPayment(_ context.Context, req *paymentpb.VoidPaymentRequest, _ ...grpc.CallOption) (*paymentpb.VoidPaymentResponse, error) {
+	f.voided = append(f.voided, req)
+	return &paymentpb.VoidPaymentResponse{}, nil
+}
+
+func asUser(userID string) context.Context {
+	return identity.WithIdentity(context.Background(), identity.Identity{UserID: userID})
+}
+
+func asDriver(userID string) context.Context {
+	return identity.WithIdentity(context.Background(), identity.Identity{UserID: userID, Role: identity.RoleDriver})
+}
+
 func TestCreateRoomAndJoinFlow(t *testing.T) {
 	repo := newFakeRoomRepo()
-	svc := New(repo, "", "")
+	svc := New(repo, "", "", "RUB", nil)
 
-	createResp, err := svc.CreateRoom(context.Background(), &roompb.CreateRoomRequest{
-		CreatorId:     "driver-1",
+	createResp, err := svc.CreateRoom(asDriver("driver-1"), &roompb.CreateRoomRequest{
 		MaxMembers:    2,
 		StartLocation: &roompb.Location{Address: "A"},
 		EndLocation:   &roompb.Location{Address: "B"},
@@ -107,18 +225,34 @@ func TestCreateRoomAndJoinFlow(t *testing.T) {
 		t.Fatalf("create room error: %v", err)
 	}
 
-	_, err = svc.JoinRoom(context.Background(), &roompb.JoinRoomRequest{RoomId: createResp.Room.RoomId, UserId: "passenger-1"})
+	_, err = svc.JoinRoom(asUser("passenger-1"), &roompb.JoinRoomRequest{RoomId: createResp.Room.RoomId})
 	if err != nil {
 		t.Fatalf("join room error: %v", err)
 	}
 
 	// third member should fail as room gets full (2 seats total)
-	_, err = svc.JoinRoom(context.Background(), &roompb.JoinRoomRequest{RoomId: createResp.Room.RoomId, UserId: "passenger-2"})
+	_, err = svc.JoinRoom(asUser("passenger-2"), &roompb.JoinRoomRequest{RoomId: createResp.Room.RoomId})
 	if err == nil {
 		t.Fatal("expected room full error")
 	}
 }
 
+func TestJoinRoomRequiresVerifiedEmail(t *testing.T) {
+	repo := newFakeRoomRepo()
+	repo.rooms["room-1"] = &roompb.Room{RoomId: "room-1", CreatorId: "driver-1", AvailableSeats: 3, Status: roompb.RoomStatus_ROOM_STATUS_WAITING, RequireVerified: true}
+	repo.members["room-1"] = []string{"driver-1"}
+	svc := New(repo, "", "", "RUB", nil)
+
+	if _, err := svc.JoinRoom(asUser("u2"), &roompb.JoinRoomRequest{RoomId: "room-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for unverified user, got %v", err)
+	}
+
+	verified := identity.WithIdentity(context.Background(), identity.Identity{UserID: "u3", EmailVerified: true})
+	if _, err := svc.JoinRoom(verified, &roompb.JoinRoomRequest{RoomId: "room-1"}); err != nil {
+		t.Fatalf("join room error: %v", err)
+	}
+}
+
 func TestCompleteRideSuccess(t *testing.T) {
 	repo := newFakeRoomRepo()
 	room := &roompb.Room{
@@ -132,40 +266,377 @@ func TestCompleteRideSuccess(t *testing.T) {
 	repo.rooms[room.RoomId] = room
 	repo.members[room.RoomId] = []string{"driver-1", "u2", "u3"}
 
-	svc := New(repo, "", "")
+	svc := New(repo, "", "", "RUB", nil)
 	var paymentReq *paymentpb.ProcessPaymentRequest
 	var routeSaved bool
 	svc.processPaymentFn = func(_ context.Context, req *paymentpb.ProcessPaymentRequest) (*paymentpb.ProcessPaymentResponse, error) {
 		paymentReq = req
-		return &paymentpb.ProcessPaymentResponse{Success: true, Payments: []*paymentpb.Payment{{PaymentId: "p1"}, {PaymentId: "p2"}, {PaymentId: "p3"}}}, nil
+		return &paymentpb.ProcessPaymentResponse{Success: true, Payments: []*paymentpb.Payment{{PaymentId: "p1"}, {PaymentId: "p2"}}}, nil
 	}
-	svc.saveRouteFn = func(_ *roompb.CompleteRideRequest, _ []string, _, _ string, _ float32) {
-		routeSaved = true
+	svc.saveRouteFn = func(_ *roompb.CompleteRideRequest, passengerIDs []string, _, _ string, _ float32) {
+		routeSaved = slices.Equal(passengerIDs, []string{"u2", "u3"})
 	}
 
-	resp, err := svc.CompleteRide(context.Background(), &roompb.CompleteRideRequest{RoomId: "room-1", DriverId: "driver-1", TotalPrice: 900, DistanceKm: 15})
+	resp, err := svc.CompleteRide(asUser("driver-1"), &roompb.CompleteRideRequest{RoomId: "room-1", DriverId: "driver-1", TotalPrice: 900, DistanceKm: 15})
 	if err != nil {
 		t.Fatalf("complete ride error: %v", err)
 	}
-	if !resp.Success || resp.PaymentsCount != 3 {
+	if !resp.Success || resp.PaymentsCount != 2 || resp.CostPerMember != 450 {
 		t.Fatalf("unexpected complete ride response: %+v", resp)
 	}
-	if paymentReq == nil || paymentReq.AmountPerUser != 300 {
+	// Водитель не платит долю за свою поездку
+	if paymentReq == nil || paymentReq.AmountPerUser != 450 || !slices.Equal(paymentReq.UserIds, []string{"u2", "u3"}) {
 		t.Fatalf("unexpected payment request: %+v", paymentReq)
 	}
 	if !routeSaved {
-		t.Fatal("expected route to be saved")
+		t.Fatal("expected route to be saved with passengers only")
 	}
 }
 
 func TestCompleteRideNoMembers(t *testing.T) {
 	repo := newFakeRoomRepo()
-	repo.rooms["room-1"] = &roompb.Room{RoomId: "room-1", Status: roompb.RoomStatus_ROOM_STATUS_WAITING}
-	repo.members["room-1"] = []string{}
+	repo.rooms["room-1"] = &roompb.Room{RoomId: "room-1", CreatorId: "driver-1", Status: roompb.RoomStatus_ROOM_STATUS_WAITING}
+	repo.members["room-1"] = []string{"driver-1"}
 
-	svc := New(repo, "", "")
-	_, err := svc.CompleteRide(context.Background(), &roompb.CompleteRideRequest{RoomId: "room-1", DriverId: "driver-1", TotalPrice: 100})
+	svc := New(repo, "", "", "RUB", nil)
+	_, err := svc.CompleteRide(asUser("driver-1"), &roompb.CompleteRideRequest{RoomId: "room-1", DriverId: "driver-1", TotalPrice: 100})
 	if err == nil {
 		t.Fatal("expected no members error")
 	}
 }
+
+func TestRoomCallsAuthorisedAgainstCaller(t *testing.T) {
+	repo := newFakeRoomRepo()
+	repo.rooms["room-1"] = &roompb.Room{RoomId: "room-1", CreatorId: "driver-1", AvailableSeats: 3, Status: roompb.RoomStatus_ROOM_STATUS_WAITING}
+	repo.members["room-1"] = []string{"driver-1", "u2"}
+	svc := New(repo, "", "", "RUB", nil)
+
+	if _, err := svc.JoinRoom(context.Background(), &roompb.JoinRoomRequest{RoomId: "room-1", UserId: "u3"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("expected Unauthenticated without identity, got %v", err)
+	}
+	if _, err := svc.CreateRoom(asUser("u2"), &roompb.CreateRoomRequest{
+		MaxMembers:    2,
+		StartLocation: &roompb.Location{Address: "A"},
+		EndLocation:   &roompb.Location{Address: "B"},
+	}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for rider creating a room, got %v", err)
+	}
+	if _, err := svc.ExitRoom(asUser("u2"), &roompb.ExitRoomRequest{RoomId: "room-1", UserId: "driver-1"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied when exiting on behalf of another user, got %v", err)
+	}
+	if _, err := svc.CompleteRide(asUser("u2"), &roompb.CompleteRideRequest{RoomId: "room-1", TotalPrice: 100}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-creator completing the ride, got %v", err)
+	}
+}
+
+func TestGetRoomBilling(t *testing.T) {
+	repo := newFakeRoomRepo()
+	repo.rooms["room-1"] = &roompb.Room{RoomId: "room-1", CreatorId: "driver-1", Status: roompb.RoomStatus_ROOM_STATUS_COMPLETED, CostPerMember: 300}
+	repo.members["room-1"] = []string{"driver-1", "u2"}
+	svc := New(repo, "", "", "RUB", nil)
+
+	for _, ctx := range []context.Context{asUser("u2"), asDriver("driver-2")} {
+		if _, err := svc.GetRoomBilling(ctx, &roompb.GetRoomBillingRequest{RoomId: "room-1"}); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("expected PermissionDenied for non-creator, got %v", err)
+		}
+	}
+	admin := identity.WithIdentity(context.Background(), identity.Identity{UserID: "admin-1", Role: identity.RoleAdmin})
+	for _, ctx := range []context.Context{asDriver("driver-1"), admin} {
+		resp, err := svc.GetRoomBilling(ctx, &roompb.GetRoomBillingRequest{RoomId: "room-1"})
+		if err != nil {
+			t.Fatalf("GetRoomBilling: %v", err)
+		}
+		if resp.Room.CostPerMember != 300 || len(resp.Members) != 2 {
+			t.Fatalf("unexpected billing: %+v", resp)
+		}
+	}
+}
+
+func TestAdminCancelRoomAndTimeline(t *testing.T) {
+	repo := newFakeRoomRepo()
+	payments := &fakePaymentClient{}
+	svc := New(repo, "", "", "RUB", nil)
+	svc.paymentClient = payments
+
+	createResp, err := svc.CreateRoom(asDriver("driver-1"), &roompb.CreateRoomRequest{
+		MaxMembers:    3,
+		StartLocation: &roompb.Location{Address: "A"},
+		EndLocation:   &roompb.Location{Address: "B"},
+		ScheduledTime: timestamppb.New(time.Now()),
+	})
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	roomID := createResp.Room.RoomId
+	if _, err := svc.JoinRoom(asUser("passenger-1"), &roompb.JoinRoomRequest{RoomId: roomID}); err != nil {
+		t.Fatalf("join room error: %v", err)
+	}
+
+	if _, err := svc.CancelRoom(asDriver("driver-1"), &roompb.CancelRoomRequest{RoomId: roomID}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-admin cancel, got %v", err)
+	}
+
+	admin := identity.WithIdentity(context.Background(), identity.Identity{UserID: "admin-1", Role: identity.RoleAdmin})
+	cancelResp, err := svc.CancelRoom(admin, &roompb.CancelRoomRequest{RoomId: roomID, Reason: "complaint"})
+	if err != nil {
+		t.Fatalf("cancel room error: %v", err)
+	}
+	if cancelResp.Room.Status != roompb.RoomStatus_ROOM_STATUS_CANCELLED {
+		t.Fatalf("expected cancelled room, got %v", cancelResp.Room.Status)
+	}
+	// Холдов в комнате нет, но применённые промокоды снимаются тоже
+	if len(payments.voided) != 1 || payments.voided[0].RoomId != roomID || payments.voided[0].UserId != "" {
+		t.Fatalf("expected the whole room to be voided, got %+v", payments.voided)
+	}
+	if _, err := svc.CancelRoom(admin, &roompb.CancelRoomRequest{RoomId: roomID}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition on repeated cancel, got %v", err)
+	}
+	if _, err := svc.CompleteRide(asDriver("driver-1"), &roompb.CompleteRideRequest{RoomId: roomID, TotalPrice: 500}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition when completing a cancelled room, got %v", err)
+	}
+	if room, _ := repo.GetRoomByID(context.Background(), roomID); room.Status != roompb.RoomStatus_ROOM_STATUS_CANCELLED {
+		t.Fatalf("cancelled room must stay cancelled, got %v", room.Status)
+	}
+
+	timeline, err := svc.GetRoomTimeline(admin, &roompb.GetRoomTimelineRequest{RoomId: roomID})
+	if err != nil {
+		t.Fatalf("timeline error: %v", err)
+	}
+	var types []string
+	for _, e := range timeline.Events {
+		types = append(types, e.Type)
+	}
+	want := []string{EventCreated, EventJoined, EventCancelled}
+	if len(types) != len(want) {
+		t.Fatalf("unexpected timeline %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("unexpected timeline %v, want %v", types, want)
+		}
+	}
+	if timeline.Events[2].ActorId != "admin-1" || timeline.Events[2].Details != "complaint" {
+		t.Fatalf("unexpected cancel event %+v", timeline.Events[2])
+	}
+}
+
+func TestHoldsFollowRoomLifecycle(t *testing.T) {
+	repo := newFakeRoomRepo()
+	payments := &fakePaymentClient{}
+	svc := New(repo, "", "", "KZT", nil)
+	svc.paymentClient = payments
+	svc.saveRouteFn = func(_ *roompb.CompleteRideRequest, _ []string, _, _ string, _ float32) {}
+
+	createResp, err := svc.CreateRoom(asDriver("driver-1"), &roompb.CreateRoomRequest{
+		MaxMembers:     4,
+		StartLocation:  &roompb.Location{Address: "A"},
+		EndLocation:    &roompb.Location{Address: "B"},
+		ScheduledTime:  timestamppb.New(time.Now()),
+		EstimatedPrice: 900,
+	})
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	roomID := createResp.Room.RoomId
+	if createResp.Room.Currency != "KZT" {
+		t.Fatalf("expected room priced in KZT, got %q", createResp.Room.Currency)
+	}
+
+	joinResp, err := svc.JoinRoom(asUser("u2"), &roompb.JoinRoomRequest{RoomId: roomID})
+	if err != nil {
+		t.Fatalf("join room error: %v", err)
+	}
+	if len(payments.authorized) != 1 || payments.authorized[0].Amount != 900 || payments.authorized[0].UserId != "u2" || payments.authorized[0].Currency != "KZT" {
+		t.Fatalf("expected hold of 900 for u2, got %+v", payments.authorized)
+	}
+	if joinResp.PaymentConfirmationUrl == "" {
+		t.Fatal("expected confirmation url in join response")
+	}
+	if _, err := svc.JoinRoom(asUser("u3"), &roompb.JoinRoomRequest{RoomId: roomID}); err != nil {
+		t.Fatalf("join room error: %v", err)
+	}
+	if payments.authorized[1].Amount != 450 {
+		t.Fatalf("expected hold of 450 for u3, got %+v", payments.authorized[1])
+	}
+
+	if _, err := svc.ExitRoom(asUser("u3"), &roompb.ExitRoomRequest{RoomId: roomID}); err != nil {
+		t.Fatalf("exit room error: %v", err)
+	}
+	if len(payments.voided) != 1 || payments.voided[0].UserId != "u3" {
+		t.Fatalf("expected hold of u3 to be voided, got %+v", payments.voided)
+	}
+
+	payments.declined = true
+	if _, err := svc.JoinRoom(asUser("u4"), &roompb.JoinRoomRequest{RoomId: roomID}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for declined hold, got %v", err)
+	}
+	if members, _ := repo.GetRoomMembers(context.Background(), roomID); len(members) != 2 {
+		t.Fatalf("declined user must not join, members %v", members)
+	}
+
+	// Неподтверждённый холд не даёт места: пассажир получает ссылку и вступает после оплаты
+	payments.declined, payments.pending = false, true
+	pending, err := svc.JoinRoom(asUser("u5"), &roompb.JoinRoomRequest{RoomId: roomID})
+	if err != nil || !pending.PaymentPending || pending.PaymentConfirmationUrl == "" {
+		t.Fatalf("expected pending join with confirmation url, got %+v, %v", pending, err)
+	}
+	if members, _ := repo.GetRoomMembers(context.Background(), roomID); slices.Contains(members, "u5") {
+		t.Fatalf("user with unconfirmed hold must not join, members %v", members)
+	}
+
+	payments.unpaid = []string{"u2"}
+	resp, err := svc.CompleteRide(asDriver("driver-1"), &roompb.CompleteRideRequest{RoomId: roomID, TotalPrice: 1000})
+	if err != nil {
+		t.Fatalf("complete ride error: %v", err)
+	}
+	if len(payments.captured) != 1 || payments.captured[0].AmountPerUser != 1000 || payments.captured[0].Currency != "KZT" ||
+		!slices.Equal(payments.captured[0].UserIds, []string{"u2"}) || resp.PaymentsCount != 1 {
+		t.Fatalf("expected capture of 1000 from u2 only, got %+v", payments.captured)
+	}
+	events, _ := repo.ListEvents(context.Background(), roomID)
+	if last := events[len(events)-1]; last.Type != EventPaymentFailed || last.Details != "status=partial failed_users=u2" {
+		t.Fatalf("expected payment_failed event for u2, got %+v", last)
+	}
+}
+
+func TestApplyPromoCode(t *testing.T) {
+	repo := newFakeRoomRepo()
+	payments := &fakePaymentClient{}
+	svc := New(repo, "", "", "KZT", nil)
+	svc.paymentClient = payments
+
+	createResp, err := svc.CreateRoom(asDriver("driver-1"), &roompb.CreateRoomRequest{
+		MaxMembers:    3,
+		StartLocation: &roompb.Location{Address: "A"},
+		EndLocation:   &roompb.Location{Address: "B"},
+		ScheduledTime: timestamppb.New(time.Now()),
+	})
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	roomID := createResp.Room.RoomId
+	if _, err := svc.JoinRoom(asUser("passenger-1"), &roompb.JoinRoomRequest{RoomId: roomID}); err != nil {
+		t.Fatalf("join room error: %v", err)
+	}
+
+	_, err = svc.ApplyPromoCode(asUser("passenger-2"), &roompb.ApplyPromoCodeRequest{RoomId: roomID, Code: "WELCOME"})
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for non-member, got %v", err)
+	}
+	_, err = svc.ApplyPromoCode(asUser("passenger-1"), &roompb.ApplyPromoCodeRequest{RoomId: roomID, Code: "UNKNOWN"})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected payment service status to pass through, got %v", err)
+	}
+	resp, err := svc.ApplyPromoCode(asUser("passenger-1"), &roompb.ApplyPromoCodeRequest{RoomId: roomID, Code: "WELCOME"})
+	if err != nil {
+		t.Fatalf("apply promo code error: %v", err)
+	}
+	if resp.Code != "WELCOME" || resp.Value != 20 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if len(payments.promos) != 1 || payments.promos[0].UserId != "passenger-1" || payments.promos[0].Currency != "KZT" {
+		t.Fatalf("expected promo code applied with room currency, got %+v", payments.promos)
+	}
+}
+
+func TestCancellationFees(t *testing.T) {
+	repo := newFakeRoomRepo()
+	payments := &fakePaymentClient{}
+	svc := New(repo, "", "", "RUB", &roompb.CancellationPolicy{FreeCancelMinutes: 30, CancellationFee: 100, NoShowFee: 200})
+	svc.paymentClient = payments
+	createRoom := func(departure time.Duration, policy *roompb.CancellationPolicy) (string, error) {
+		resp, err := svc.CreateRoom(asDriver("driver-1"), &roompb.CreateRoomRequest{
+			MaxMembers:         4,
+			StartLocation:      &roompb.Location{Address: "A"},
+			EndLocation:        &roompb.Location{Address: "B"},
+			ScheduledTime:      timestamppb.New(time.Now().Add(departure)),
+			CancellationPolicy: policy,
+		})
+		if err != nil {
+			return "", err
+		}
+		return resp.Room.RoomId, nil
+	}
+
+	if _, err := createRoom(time.Hour, &roompb.CancellationPolicy{CancellationFee: -1}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for negative fee, got %v", err)
+	}
+	early, err := createRoom(2*time.Hour, nil)
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	soon, err := createRoom(10*time.Minute, nil)
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	custom, err := createRoom(10*time.Minute, &roompb.CancellationPolicy{FreeCancelMinutes: 5, NoShowFee: 50})
+	if err != nil {
+		t.Fatalf("create room error: %v", err)
+	}
+	for _, roomID := range []string{early, soon, custom} {
+		for _, user := range []string{"passenger-1", "passenger-2"} {
+			if _, err := svc.JoinRoom(asUser(user), &roompb.JoinRoomRequest{RoomId: roomID}); err != nil {
+				t.Fatalf("join room error: %v", err)
+			}
+		}
+	}
+
+	// Бесплатное окно: выход за два часа до отправления
+	if resp, err := svc.ExitRoom(asUser("passenger-1"), &roompb.ExitRoomRequest{RoomId: early}); err != nil || resp.CancellationFee != 0 {
+		t.Fatalf("expected free exit, got %+v, %v", resp, err)
+	}
+	// Выход за 10 минут до отправления при бесплатном окне в 30 минут
+	resp, err := svc.ExitRoom(asUser("passenger-1"), &roompb.ExitRoomRequest{RoomId: soon})
+	if err != nil || resp.CancellationFee != 100 {
+		t.Fatalf("expected cancellation fee, got %+v, %v", resp, err)
+	}
+	// Повторный выход уже не участника не штрафуется
+	if resp, err := svc.ExitRoom(asUser("passenger-1"), &roompb.ExitRoomRequest{RoomId: soon}); err != nil || resp.CancellationFee != 0 {
+		t.Fatalf("expected no fee for non-member, got %+v, %v", resp, err)
+	}
+	// В комнате без штрафа за отмену выход бесплатный
+	if resp, err := svc.ExitRoom(asUser("passenger-1"), &roompb.ExitRoomRequest{RoomId: custom}); err != nil || resp.CancellationFee != 0 {
+		t.Fatalf("expected free exit under room policy, got %+v, %v", resp, err)
+	}
+	if len(payments.fees) != 1 || payments.fees[0].Type != "cancellation" || payments.fees[0].UserId != "passenger-1" {
+		t.Fatalf("unexpected fees %+v", payments.fees)
+	}
+	// Сумму штрафа payment_service берёт из комнаты; вышедший пассажир видит только свой штраф
+	billing, err := svc.GetRoomBilling(asUser("passenger-1"), &roompb.GetRoomBillingRequest{RoomId: soon})
+	if err != nil || len(billing.Fees) != 1 || billing.Fees[0].Amount != 100 || len(billing.Members) != 0 {
+		t.Fatalf("expected own cancellation fee of 100, got %+v, %v", billing, err)
+	}
+	if _, err := svc.GetRoomBilling(asUser("passenger-2"), &roompb.GetRoomBillingRequest{RoomId: soon}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for passenger without fees, got %v", err)
+	}
+
+	if _, err := svc.StartRide(asDriver("driver-2"), &roompb.StartRideRequest{RoomId: custom}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected PermissionDenied for another driver, got %v", err)
+	}
+	if _, err := svc.StartRide(asDriver("driver-1"), &roompb.StartRideRequest{RoomId: custom, NoShowUserIds: []string{"passenger-1"}}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument for non-member no-show, got %v", err)
+	}
+	started, err := svc.StartRide(asDriver("driver-1"), &roompb.StartRideRequest{RoomId: custom, NoShowUserIds: []string{"passenger-2"}})
+	if err != nil {
+		t.Fatalf("start ride error: %v", err)
+	}
+	if started.NoShowFees != 1 || started.Room.Status != roompb.RoomStatus_ROOM_STATUS_ON_RIDE {
+		t.Fatalf("unexpected start response %+v", started)
+	}
+	if fee := payments.fees[1]; fee.Type != "no_show" || fee.UserId != "passenger-2" {
+		t.Fatalf("unexpected no-show fee %+v", fee)
+	}
+	if fees, _ := repo.ListFees(context.Background(), custom); len(fees) != 1 || fees[0].Amount != 50 {
+		t.Fatalf("expected no-show fee of 50 in the room, got %+v", fees)
+	}
+	if members, _ := repo.GetRoomMembers(context.Background(), custom); len(members) != 1 {
+		t.Fatalf("expected no-show passenger removed, got %v", members)
+	}
+
+	if _, err := svc.ExitRoom(asUser("driver-1"), &roompb.ExitRoomRequest{RoomId: custom}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for exit during ride, got %v", err)
+	}
+	if _, err := svc.JoinRoom(asUser("passenger-3"), &roompb.JoinRoomRequest{RoomId: custom}); status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition for join after start, got %v", err)
+	}
+}
@@ -202,21 +202,25 @@ func (x *Vehicle) GetPlateNumber() string {
 }
 
 type Room struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	RoomId         string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`                           // Уникальный идентификатор комнаты
-	CreatorId      string                 `protobuf:"bytes,2,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`                  // ID создателя комнаты
-	Members        []string               `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`                                       // ID участников
-	StartLocation  *Location              `protobuf:"bytes,4,opt,name=start_location,json=startLocation,proto3" json:"start_location,omitempty"`      // Место посадки
-	EndLocation    *Location              `protobuf:"bytes,5,opt,name=end_location,json=endLocation,proto3" json:"end_location,omitempty"`            // Место назначения
-	AvailableSeats int32                  `protobuf:"varint,6,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`  // Максимальное количество участников
-	Status         RoomStatus             `protobuf:"varint,7,opt,name=status,proto3,enum=service.room.v1.RoomStatus" json:"status,omitempty"`        // Текущий статус комнаты
-	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                  // Время создания
-	ScheduledTime  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`      // Запланированное время поездки
-	TotalPrice     float32                `protobuf:"fixed32,10,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`            // Общая стоимость поездки
-	CostPerMember  float32                `protobuf:"fixed32,11,opt,name=cost_per_member,json=costPerMember,proto3" json:"cost_per_member,omitempty"` // Стоимость на одного участника
-	Vehicle        *Vehicle               `protobuf:"bytes,12,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	RoomId             string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`                           // Уникальный идентификатор комнаты
+	CreatorId          string                 `protobuf:"bytes,2,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`                  // ID создателя комнаты
+	Members            []string               `protobuf:"bytes,3,rep,name=members,proto3" json:"members,omitempty"`                                       // ID участников
+	StartLocation      *Location              `protobuf:"bytes,4,opt,name=start_location,json=startLocation,proto3" json:"start_location,omitempty"`      // Место посадки
+	EndLocation        *Location              `protobuf:"bytes,5,opt,name=end_location,json=endLocation,proto3" json:"end_location,omitempty"`            // Место назначения
+	AvailableSeats     int32                  `protobuf:"varint,6,opt,name=available_seats,json=availableSeats,proto3" json:"available_seats,omitempty"`  // Максимальное количество участников
+	Status             RoomStatus             `protobuf:"varint,7,opt,name=status,proto3,enum=service.room.v1.RoomStatus" json:"status,omitempty"`        // Текущий статус комнаты
+	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`                  // Время создания
+	ScheduledTime      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`      // Запланированное время поездки
+	TotalPrice         float32                `protobuf:"fixed32,10,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`            // Общая стоимость поездки
+	CostPerMember      float32                `protobuf:"fixed32,11,opt,name=cost_per_member,json=costPerMember,proto3" json:"cost_per_member,omitempty"` // Стоимость на одного участника
+	Vehicle            *Vehicle               `protobuf:"bytes,12,opt,name=vehicle,proto3" json:"vehicle,omitempty"`
+	RequireVerified    bool                   `protobuf:"varint,13,opt,name=require_verified,json=requireVerified,proto3" json:"require_verified,omitempty"`         // Только участники с подтверждённым email
+	EstimatedPrice     float32                `protobuf:"fixed32,14,opt,name=estimated_price,json=estimatedPrice,proto3" json:"estimated_price,omitempty"`           // Оценочная стоимость поездки, под неё ставятся холды
+	Currency           string                 `protobuf:"bytes,15,opt,name=currency,proto3" json:"currency,omitempty"`                                               // Валюта цен поездки, ISO 4217
+	CancellationPolicy *CancellationPolicy    `protobuf:"bytes,16,opt,name=cancellation_policy,json=cancellationPolicy,proto3" json:"cancellation_policy,omitempty"` // Правила отмены и неявки
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *Room) Reset() {
@@ -333,6 +337,95 @@ func (x *Room) GetVehicle() *Vehicle {
 	return nil
 }
 
+func (x *Room) GetRequireVerified() bool {
+	if x != nil {
+		return x.RequireVerified
+	}
+	return false
+}
+
+func (x *Room) GetEstimatedPrice() float32 {
+	if x != nil {
+		return x.EstimatedPrice
+	}
+	return 0
+}
+
+func (x *Room) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Room) GetCancellationPolicy() *CancellationPolicy {
+	if x != nil {
+		return x.CancellationPolicy
+	}
+	return nil
+}
+
+// CancellationPolicy — штрафы в валюте комнаты; нулевой штраф не списывается
+type CancellationPolicy struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	FreeCancelMinutes int32                  `protobuf:"varint,1,opt,name=free_cancel_minutes,json=freeCancelMinutes,proto3" json:"free_cancel_minutes,omitempty"` // Бесплатный выход не позже чем за столько минут до scheduled_time
+	CancellationFee   float32                `protobuf:"fixed32,2,opt,name=cancellation_fee,json=cancellationFee,proto3" json:"cancellation_fee,omitempty"`        // Штраф за более поздний выход
+	NoShowFee         float32                `protobuf:"fixed32,3,opt,name=no_show_fee,json=noShowFee,proto3" json:"no_show_fee,omitempty"`                        // Штраф за неявку к началу поездки
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CancellationPolicy) Reset() {
+	*x = CancellationPolicy{}
+	mi := &file_room_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancellationPolicy) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancellationPolicy) ProtoMessage() {}
+
+func (x *CancellationPolicy) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancellationPolicy.ProtoReflect.Descriptor instead.
+func (*CancellationPolicy) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancellationPolicy) GetFreeCancelMinutes() int32 {
+	if x != nil {
+		return x.FreeCancelMinutes
+	}
+	return 0
+}
+
+func (x *CancellationPolicy) GetCancellationFee() float32 {
+	if x != nil {
+		return x.CancellationFee
+	}
+	return 0
+}
+
+func (x *CancellationPolicy) GetNoShowFee() float32 {
+	if x != nil {
+		return x.NoShowFee
+	}
+	return 0
+}
+
 type UserInfo struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`          // ID пользователя
@@ -345,7 +438,7 @@ type UserInfo struct {
 
 func (x *UserInfo) Reset() {
 	*x = UserInfo{}
-	mi := &file_room_proto_msgTypes[3]
+	mi := &file_room_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -357,7 +450,7 @@ func (x *UserInfo) String() string {
 func (*UserInfo) ProtoMessage() {}
 
 func (x *UserInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[3]
+	mi := &file_room_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -370,7 +463,7 @@ func (x *UserInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
 func (*UserInfo) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{3}
+	return file_room_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *UserInfo) GetUserId() string {
@@ -402,19 +495,22 @@ func (x *UserInfo) GetRating() float32 {
 }
 
 type CreateRoomRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CreatorId     string                 `protobuf:"bytes,1,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`             // ID создателя
-	StartLocation *Location              `protobuf:"bytes,2,opt,name=start_location,json=startLocation,proto3" json:"start_location,omitempty"` // Место посадки
-	EndLocation   *Location              `protobuf:"bytes,3,opt,name=end_location,json=endLocation,proto3" json:"end_location,omitempty"`       // Место назначения
-	ScheduledTime *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"` // Запланированное время
-	MaxMembers    int32                  `protobuf:"varint,5,opt,name=max_members,json=maxMembers,proto3" json:"max_members,omitempty"`         // Максимальное количество участников
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	CreatorId          string                 `protobuf:"bytes,1,opt,name=creator_id,json=creatorId,proto3" json:"creator_id,omitempty"`                            // ID создателя
+	StartLocation      *Location              `protobuf:"bytes,2,opt,name=start_location,json=startLocation,proto3" json:"start_location,omitempty"`                // Место посадки
+	EndLocation        *Location              `protobuf:"bytes,3,opt,name=end_location,json=endLocation,proto3" json:"end_location,omitempty"`                      // Место назначения
+	ScheduledTime      *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=scheduled_time,json=scheduledTime,proto3" json:"scheduled_time,omitempty"`                // Запланированное время
+	MaxMembers         int32                  `protobuf:"varint,5,opt,name=max_members,json=maxMembers,proto3" json:"max_members,omitempty"`                        // Максимальное количество участников
+	RequireVerified    bool                   `protobuf:"varint,6,opt,name=require_verified,json=requireVerified,proto3" json:"require_verified,omitempty"`         // Только участники с подтверждённым email
+	EstimatedPrice     float32                `protobuf:"fixed32,7,opt,name=estimated_price,json=estimatedPrice,proto3" json:"estimated_price,omitempty"`           // Оценочная стоимость; 0 — оплата без холда после поездки
+	CancellationPolicy *CancellationPolicy    `protobuf:"bytes,8,opt,name=cancellation_policy,json=cancellationPolicy,proto3" json:"cancellation_policy,omitempty"` // Не указаны — правила сервиса по умолчанию
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *CreateRoomRequest) Reset() {
 	*x = CreateRoomRequest{}
-	mi := &file_room_proto_msgTypes[4]
+	mi := &file_room_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -426,7 +522,7 @@ func (x *CreateRoomRequest) String() string {
 func (*CreateRoomRequest) ProtoMessage() {}
 
 func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[4]
+	mi := &file_room_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -439,7 +535,7 @@ func (x *CreateRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomRequest.ProtoReflect.Descriptor instead.
 func (*CreateRoomRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{4}
+	return file_room_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *CreateRoomRequest) GetCreatorId() string {
@@ -477,6 +573,27 @@ func (x *CreateRoomRequest) GetMaxMembers() int32 {
 	return 0
 }
 
+func (x *CreateRoomRequest) GetRequireVerified() bool {
+	if x != nil {
+		return x.RequireVerified
+	}
+	return false
+}
+
+func (x *CreateRoomRequest) GetEstimatedPrice() float32 {
+	if x != nil {
+		return x.EstimatedPrice
+	}
+	return 0
+}
+
+func (x *CreateRoomRequest) GetCancellationPolicy() *CancellationPolicy {
+	if x != nil {
+		return x.CancellationPolicy
+	}
+	return nil
+}
+
 type CreateRoomResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"` // Созданная комната
@@ -486,7 +603,7 @@ type CreateRoomResponse struct {
 
 func (x *CreateRoomResponse) Reset() {
 	*x = CreateRoomResponse{}
-	mi := &file_room_proto_msgTypes[5]
+	mi := &file_room_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -498,7 +615,7 @@ func (x *CreateRoomResponse) String() string {
 func (*CreateRoomResponse) ProtoMessage() {}
 
 func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[5]
+	mi := &file_room_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -511,7 +628,7 @@ func (x *CreateRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateRoomResponse.ProtoReflect.Descriptor instead.
 func (*CreateRoomResponse) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{5}
+	return file_room_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *CreateRoomResponse) GetRoom() *Room {
@@ -531,7 +648,7 @@ type JoinRoomRequest struct {
 
 func (x *JoinRoomRequest) Reset() {
 	*x = JoinRoomRequest{}
-	mi := &file_room_proto_msgTypes[6]
+	mi := &file_room_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -543,7 +660,7 @@ func (x *JoinRoomRequest) String() string {
 func (*JoinRoomRequest) ProtoMessage() {}
 
 func (x *JoinRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[6]
+	mi := &file_room_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -556,7 +673,7 @@ func (x *JoinRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomRequest.ProtoReflect.Descriptor instead.
 func (*JoinRoomRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{6}
+	return file_room_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *JoinRoomRequest) GetRoomId() string {
@@ -574,15 +691,18 @@ func (x *JoinRoomRequest) GetUserId() string {
 }
 
 type JoinRoomResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"` // Обновленная информация о комнате
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Room                   *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`                                                                     // Обновленная информация о комнате
+	PaymentConfirmationUrl string                 `protobuf:"bytes,2,opt,name=payment_confirmation_url,json=paymentConfirmationUrl,proto3" json:"payment_confirmation_url,omitempty"` // Куда отправить пользователя для подтверждения холда
+	// Холд ещё не подтверждён: пассажир не вступил, после подтверждения по ссылке вступление повторяется
+	PaymentPending bool `protobuf:"varint,3,opt,name=payment_pending,json=paymentPending,proto3" json:"payment_pending,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *JoinRoomResponse) Reset() {
 	*x = JoinRoomResponse{}
-	mi := &file_room_proto_msgTypes[7]
+	mi := &file_room_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -594,7 +714,7 @@ func (x *JoinRoomResponse) String() string {
 func (*JoinRoomResponse) ProtoMessage() {}
 
 func (x *JoinRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[7]
+	mi := &file_room_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -607,7 +727,7 @@ func (x *JoinRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use JoinRoomResponse.ProtoReflect.Descriptor instead.
 func (*JoinRoomResponse) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{7}
+	return file_room_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *JoinRoomResponse) GetRoom() *Room {
@@ -617,6 +737,20 @@ func (x *JoinRoomResponse) GetRoom() *Room {
 	return nil
 }
 
+func (x *JoinRoomResponse) GetPaymentConfirmationUrl() string {
+	if x != nil {
+		return x.PaymentConfirmationUrl
+	}
+	return ""
+}
+
+func (x *JoinRoomResponse) GetPaymentPending() bool {
+	if x != nil {
+		return x.PaymentPending
+	}
+	return false
+}
+
 type ExitRoomRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"` // ID комнаты
@@ -627,7 +761,7 @@ type ExitRoomRequest struct {
 
 func (x *ExitRoomRequest) Reset() {
 	*x = ExitRoomRequest{}
-	mi := &file_room_proto_msgTypes[8]
+	mi := &file_room_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -639,7 +773,7 @@ func (x *ExitRoomRequest) String() string {
 func (*ExitRoomRequest) ProtoMessage() {}
 
 func (x *ExitRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[8]
+	mi := &file_room_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -652,7 +786,7 @@ func (x *ExitRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExitRoomRequest.ProtoReflect.Descriptor instead.
 func (*ExitRoomRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{8}
+	return file_room_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ExitRoomRequest) GetRoomId() string {
@@ -670,15 +804,16 @@ func (x *ExitRoomRequest) GetUserId() string {
 }
 
 type ExitRoomResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"` // Флаг успешного выхода
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Success         bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`                                         // Флаг успешного выхода
+	CancellationFee float32                `protobuf:"fixed32,2,opt,name=cancellation_fee,json=cancellationFee,proto3" json:"cancellation_fee,omitempty"` // Списанный штраф за позднюю отмену; 0 — выход бесплатный
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ExitRoomResponse) Reset() {
 	*x = ExitRoomResponse{}
-	mi := &file_room_proto_msgTypes[9]
+	mi := &file_room_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -690,7 +825,7 @@ func (x *ExitRoomResponse) String() string {
 func (*ExitRoomResponse) ProtoMessage() {}
 
 func (x *ExitRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[9]
+	mi := &file_room_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -703,7 +838,7 @@ func (x *ExitRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ExitRoomResponse.ProtoReflect.Descriptor instead.
 func (*ExitRoomResponse) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{9}
+	return file_room_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ExitRoomResponse) GetSuccess() bool {
@@ -713,6 +848,13 @@ func (x *ExitRoomResponse) GetSuccess() bool {
 	return false
 }
 
+func (x *ExitRoomResponse) GetCancellationFee() float32 {
+	if x != nil {
+		return x.CancellationFee
+	}
+	return 0
+}
+
 type FindRoomRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	PickupLocation  *Location              `protobuf:"bytes,1,opt,name=pickup_location,json=pickupLocation,proto3" json:"pickup_location,omitempty"`    // Желаемое место посадки
@@ -727,7 +869,7 @@ type FindRoomRequest struct {
 
 func (x *FindRoomRequest) Reset() {
 	*x = FindRoomRequest{}
-	mi := &file_room_proto_msgTypes[10]
+	mi := &file_room_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -739,7 +881,7 @@ func (x *FindRoomRequest) String() string {
 func (*FindRoomRequest) ProtoMessage() {}
 
 func (x *FindRoomRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[10]
+	mi := &file_room_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -752,7 +894,7 @@ func (x *FindRoomRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindRoomRequest.ProtoReflect.Descriptor instead.
 func (*FindRoomRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{10}
+	return file_room_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *FindRoomRequest) GetPickupLocation() *Location {
@@ -806,7 +948,7 @@ type FindRoomResponse struct {
 
 func (x *FindRoomResponse) Reset() {
 	*x = FindRoomResponse{}
-	mi := &file_room_proto_msgTypes[11]
+	mi := &file_room_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -818,7 +960,7 @@ func (x *FindRoomResponse) String() string {
 func (*FindRoomResponse) ProtoMessage() {}
 
 func (x *FindRoomResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[11]
+	mi := &file_room_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -831,7 +973,7 @@ func (x *FindRoomResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FindRoomResponse.ProtoReflect.Descriptor instead.
 func (*FindRoomResponse) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{11}
+	return file_room_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *FindRoomResponse) GetAvailableRooms() []*Room {
@@ -850,7 +992,7 @@ type GetRoomDetailsRequest struct {
 
 func (x *GetRoomDetailsRequest) Reset() {
 	*x = GetRoomDetailsRequest{}
-	mi := &file_room_proto_msgTypes[12]
+	mi := &file_room_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -862,7 +1004,7 @@ func (x *GetRoomDetailsRequest) String() string {
 func (*GetRoomDetailsRequest) ProtoMessage() {}
 
 func (x *GetRoomDetailsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[12]
+	mi := &file_room_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -875,7 +1017,7 @@ func (x *GetRoomDetailsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRoomDetailsRequest.ProtoReflect.Descriptor instead.
 func (*GetRoomDetailsRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{12}
+	return file_room_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *GetRoomDetailsRequest) GetRoomId() string {
@@ -895,7 +1037,7 @@ type GetRoomDetailsResponse struct {
 
 func (x *GetRoomDetailsResponse) Reset() {
 	*x = GetRoomDetailsResponse{}
-	mi := &file_room_proto_msgTypes[13]
+	mi := &file_room_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -907,7 +1049,7 @@ func (x *GetRoomDetailsResponse) String() string {
 func (*GetRoomDetailsResponse) ProtoMessage() {}
 
 func (x *GetRoomDetailsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[13]
+	mi := &file_room_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -920,7 +1062,7 @@ func (x *GetRoomDetailsResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetRoomDetailsResponse.ProtoReflect.Descriptor instead.
 func (*GetRoomDetailsResponse) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{13}
+	return file_room_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *GetRoomDetailsResponse) GetRoom() *Room {
@@ -947,7 +1089,7 @@ type StreamRoomUpdatesRequest struct {
 
 func (x *StreamRoomUpdatesRequest) Reset() {
 	*x = StreamRoomUpdatesRequest{}
-	mi := &file_room_proto_msgTypes[14]
+	mi := &file_room_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -959,7 +1101,7 @@ func (x *StreamRoomUpdatesRequest) String() string {
 func (*StreamRoomUpdatesRequest) ProtoMessage() {}
 
 func (x *StreamRoomUpdatesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[14]
+	mi := &file_room_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -972,7 +1114,7 @@ func (x *StreamRoomUpdatesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use StreamRoomUpdatesRequest.ProtoReflect.Descriptor instead.
 func (*StreamRoomUpdatesRequest) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{14}
+	return file_room_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *StreamRoomUpdatesRequest) GetRoomId() string {
@@ -1005,7 +1147,7 @@ type RoomUpdate struct {
 
 func (x *RoomUpdate) Reset() {
 	*x = RoomUpdate{}
-	mi := &file_room_proto_msgTypes[15]
+	mi := &file_room_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1017,7 +1159,7 @@ func (x *RoomUpdate) String() string {
 func (*RoomUpdate) ProtoMessage() {}
 
 func (x *RoomUpdate) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[15]
+	mi := &file_room_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1030,7 +1172,7 @@ func (x *RoomUpdate) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomUpdate.ProtoReflect.Descriptor instead.
 func (*RoomUpdate) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{15}
+	return file_room_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *RoomUpdate) GetUpdate() isRoomUpdate_Update {
@@ -1128,7 +1270,7 @@ type MemberJoined struct {
 
 func (x *MemberJoined) Reset() {
 	*x = MemberJoined{}
-	mi := &file_room_proto_msgTypes[16]
+	mi := &file_room_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1140,7 +1282,7 @@ func (x *MemberJoined) String() string {
 func (*MemberJoined) ProtoMessage() {}
 
 func (x *MemberJoined) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[16]
+	mi := &file_room_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1153,7 +1295,7 @@ func (x *MemberJoined) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MemberJoined.ProtoReflect.Descriptor instead.
 func (*MemberJoined) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{16}
+	return file_room_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *MemberJoined) GetUser() *UserInfo {
@@ -1172,7 +1314,7 @@ type MemberLeft struct {
 
 func (x *MemberLeft) Reset() {
 	*x = MemberLeft{}
-	mi := &file_room_proto_msgTypes[17]
+	mi := &file_room_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1184,7 +1326,7 @@ func (x *MemberLeft) String() string {
 func (*MemberLeft) ProtoMessage() {}
 
 func (x *MemberLeft) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[17]
+	mi := &file_room_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1197,7 +1339,7 @@ func (x *MemberLeft) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MemberLeft.ProtoReflect.Descriptor instead.
 func (*MemberLeft) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{17}
+	return file_room_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *MemberLeft) GetUserId() string {
@@ -1216,7 +1358,7 @@ type RoomStatusChanged struct {
 
 func (x *RoomStatusChanged) Reset() {
 	*x = RoomStatusChanged{}
-	mi := &file_room_proto_msgTypes[18]
+	mi := &file_room_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1228,7 +1370,7 @@ func (x *RoomStatusChanged) String() string {
 func (*RoomStatusChanged) ProtoMessage() {}
 
 func (x *RoomStatusChanged) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[18]
+	mi := &file_room_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1241,7 +1383,7 @@ func (x *RoomStatusChanged) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RoomStatusChanged.ProtoReflect.Descriptor instead.
 func (*RoomStatusChanged) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{18}
+	return file_room_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *RoomStatusChanged) GetNewStatus() RoomStatus {
@@ -1261,7 +1403,7 @@ type LocationUpdated struct {
 
 func (x *LocationUpdated) Reset() {
 	*x = LocationUpdated{}
-	mi := &file_room_proto_msgTypes[19]
+	mi := &file_room_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1273,7 +1415,7 @@ func (x *LocationUpdated) String() string {
 func (*LocationUpdated) ProtoMessage() {}
 
 func (x *LocationUpdated) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[19]
+	mi := &file_room_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1286,7 +1428,7 @@ func (x *LocationUpdated) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LocationUpdated.ProtoReflect.Descriptor instead.
 func (*LocationUpdated) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{19}
+	return file_room_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *LocationUpdated) GetNewLocation() *Location {
@@ -1312,7 +1454,7 @@ type PaymentUpdated struct {
 
 func (x *PaymentUpdated) Reset() {
 	*x = PaymentUpdated{}
-	mi := &file_room_proto_msgTypes[20]
+	mi := &file_room_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1324,7 +1466,7 @@ func (x *PaymentUpdated) String() string {
 func (*PaymentUpdated) ProtoMessage() {}
 
 func (x *PaymentUpdated) ProtoReflect() protoreflect.Message {
-	mi := &file_room_proto_msgTypes[20]
+	mi := &file_room_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1337,7 +1479,7 @@ func (x *PaymentUpdated) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PaymentUpdated.ProtoReflect.Descriptor instead.
 func (*PaymentUpdated) Descriptor() ([]byte, []int) {
-	return file_room_proto_rawDescGZIP(), []int{20}
+	return file_room_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *PaymentUpdated) GetNewCostPerMember() float32 {
@@ -1347,63 +1489,1041 @@ func (x *PaymentUpdated) GetNewCostPerMember() float32 {
 	return 0
 }
 
-var File_room_proto protoreflect.FileDescriptor
+// StartRide — водитель начинает поездку, no_show_user_ids — не пришедшие пассажиры
+type StartRideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	NoShowUserIds []string               `protobuf:"bytes,3,rep,name=no_show_user_ids,json=noShowUserIds,proto3" json:"no_show_user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-const file_room_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"room.proto\x12\x0fservice.room.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"^\n" +
-	"\bLocation\x12\x1a\n" +
-	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
-	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x18\n" +
-	"\aaddress\x18\x03 \x01(\tR\aaddress\"X\n" +
-	"\aVehicle\x12\x14\n" +
-	"\x05model\x18\x01 \x01(\tR\x05model\x12\x14\n" +
-	"\x05color\x18\x02 \x01(\tR\x05color\x12!\n" +
-	"\fplate_number\x18\x03 \x01(\tR\vplateNumber\"\xb1\x04\n" +
-	"\x04Room\x12\x17\n" +
-	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
-	"\n" +
-	"creator_id\x18\x02 \x01(\tR\tcreatorId\x12\x18\n" +
-	"\amembers\x18\x03 \x03(\tR\amembers\x12@\n" +
-	"\x0estart_location\x18\x04 \x01(\v2\x19.service.room.v1.LocationR\rstartLocation\x12<\n" +
-	"\fend_location\x18\x05 \x01(\v2\x19.service.room.v1.LocationR\vendLocation\x12'\n" +
-	"\x0favailable_seats\x18\x06 \x01(\x05R\x0eavailableSeats\x123\n" +
-	"\x06status\x18\a \x01(\x0e2\x1b.service.room.v1.RoomStatusR\x06status\x129\n" +
-	"\n" +
-	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12A\n" +
-	"\x0escheduled_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12\x1f\n" +
-	"\vtotal_price\x18\n" +
-	" \x01(\x02R\n" +
-	"totalPrice\x12&\n" +
-	"\x0fcost_per_member\x18\v \x01(\x02R\rcostPerMember\x122\n" +
-	"\avehicle\x18\f \x01(\v2\x18.service.room.v1.VehicleR\avehicle\"n\n" +
-	"\bUserInfo\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
-	"\n" +
-	"avatar_url\x18\x03 \x01(\tR\tavatarUrl\x12\x16\n" +
-	"\x06rating\x18\x04 \x01(\x02R\x06rating\"\x96\x02\n" +
-	"\x11CreateRoomRequest\x12\x1d\n" +
-	"\n" +
-	"creator_id\x18\x01 \x01(\tR\tcreatorId\x12@\n" +
-	"\x0estart_location\x18\x02 \x01(\v2\x19.service.room.v1.LocationR\rstartLocation\x12<\n" +
-	"\fend_location\x18\x03 \x01(\v2\x19.service.room.v1.LocationR\vendLocation\x12A\n" +
-	"\x0escheduled_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12\x1f\n" +
-	"\vmax_members\x18\x05 \x01(\x05R\n" +
-	"maxMembers\"?\n" +
+func (x *StartRideRequest) Reset() {
+	*x = StartRideRequest{}
+	mi := &file_room_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRideRequest) ProtoMessage() {}
+
+func (x *StartRideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRideRequest.ProtoReflect.Descriptor instead.
+func (*StartRideRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *StartRideRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *StartRideRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *StartRideRequest) GetNoShowUserIds() []string {
+	if x != nil {
+		return x.NoShowUserIds
+	}
+	return nil
+}
+
+type StartRideResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	NoShowFees    int32                  `protobuf:"varint,2,opt,name=no_show_fees,json=noShowFees,proto3" json:"no_show_fees,omitempty"` // Сколько штрафов за неявку списано
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRideResponse) Reset() {
+	*x = StartRideResponse{}
+	mi := &file_room_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRideResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRideResponse) ProtoMessage() {}
+
+func (x *StartRideResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRideResponse.ProtoReflect.Descriptor instead.
+func (*StartRideResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *StartRideResponse) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *StartRideResponse) GetNoShowFees() int32 {
+	if x != nil {
+		return x.NoShowFees
+	}
+	return 0
+}
+
+// CompleteRide — завершает поездку, триггерит оплату
+type CompleteRideRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	DriverId      string                 `protobuf:"bytes,2,opt,name=driver_id,json=driverId,proto3" json:"driver_id,omitempty"`
+	TotalPrice    float32                `protobuf:"fixed32,3,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	DistanceKm    float32                `protobuf:"fixed32,4,opt,name=distance_km,json=distanceKm,proto3" json:"distance_km,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteRideRequest) Reset() {
+	*x = CompleteRideRequest{}
+	mi := &file_room_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteRideRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteRideRequest) ProtoMessage() {}
+
+func (x *CompleteRideRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteRideRequest.ProtoReflect.Descriptor instead.
+func (*CompleteRideRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CompleteRideRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CompleteRideRequest) GetDriverId() string {
+	if x != nil {
+		return x.DriverId
+	}
+	return ""
+}
+
+func (x *CompleteRideRequest) GetTotalPrice() float32 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *CompleteRideRequest) GetDistanceKm() float32 {
+	if x != nil {
+		return x.DistanceKm
+	}
+	return 0
+}
+
+type CompleteRideResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	TotalPrice    float32                `protobuf:"fixed32,2,opt,name=total_price,json=totalPrice,proto3" json:"total_price,omitempty"`
+	CostPerMember float32                `protobuf:"fixed32,3,opt,name=cost_per_member,json=costPerMember,proto3" json:"cost_per_member,omitempty"`
+	PaymentsCount int32                  `protobuf:"varint,4,opt,name=payments_count,json=paymentsCount,proto3" json:"payments_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CompleteRideResponse) Reset() {
+	*x = CompleteRideResponse{}
+	mi := &file_room_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CompleteRideResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CompleteRideResponse) ProtoMessage() {}
+
+func (x *CompleteRideResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CompleteRideResponse.ProtoReflect.Descriptor instead.
+func (*CompleteRideResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CompleteRideResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *CompleteRideResponse) GetTotalPrice() float32 {
+	if x != nil {
+		return x.TotalPrice
+	}
+	return 0
+}
+
+func (x *CompleteRideResponse) GetCostPerMember() float32 {
+	if x != nil {
+		return x.CostPerMember
+	}
+	return 0
+}
+
+func (x *CompleteRideResponse) GetPaymentsCount() int32 {
+	if x != nil {
+		return x.PaymentsCount
+	}
+	return 0
+}
+
+// Пустые поля не учитываются
+type ApplyPromoCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Code          string                 `protobuf:"bytes,3,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyPromoCodeRequest) Reset() {
+	*x = ApplyPromoCodeRequest{}
+	mi := &file_room_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPromoCodeRequest) ProtoMessage() {}
+
+func (x *ApplyPromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*ApplyPromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ApplyPromoCodeRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type ApplyPromoCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Code          string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`         // percent, fixed
+	Value         float32                `protobuf:"fixed32,4,opt,name=value,proto3" json:"value,omitempty"`     // процент или сумма скидки
+	Currency      string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"` // для fixed
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApplyPromoCodeResponse) Reset() {
+	*x = ApplyPromoCodeResponse{}
+	mi := &file_room_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApplyPromoCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApplyPromoCodeResponse) ProtoMessage() {}
+
+func (x *ApplyPromoCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApplyPromoCodeResponse.ProtoReflect.Descriptor instead.
+func (*ApplyPromoCodeResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ApplyPromoCodeResponse) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeResponse) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeResponse) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ApplyPromoCodeResponse) GetValue() float32 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+func (x *ApplyPromoCodeResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type SearchRoomsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // создатель или участник
+	CreatedFrom   *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	Status        RoomStatus             `protobuf:"varint,5,opt,name=status,proto3,enum=service.room.v1.RoomStatus" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,6,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRoomsRequest) Reset() {
+	*x = SearchRoomsRequest{}
+	mi := &file_room_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRoomsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRoomsRequest) ProtoMessage() {}
+
+func (x *SearchRoomsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRoomsRequest.ProtoReflect.Descriptor instead.
+func (*SearchRoomsRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *SearchRoomsRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *SearchRoomsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SearchRoomsRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *SearchRoomsRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *SearchRoomsRequest) GetStatus() RoomStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RoomStatus_ROOM_STATUS_UNSPECIFIED
+}
+
+func (x *SearchRoomsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type SearchRoomsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Rooms         []*Room                `protobuf:"bytes,1,rep,name=rooms,proto3" json:"rooms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRoomsResponse) Reset() {
+	*x = SearchRoomsResponse{}
+	mi := &file_room_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRoomsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRoomsResponse) ProtoMessage() {}
+
+func (x *SearchRoomsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRoomsResponse.ProtoReflect.Descriptor instead.
+func (*SearchRoomsResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *SearchRoomsResponse) GetRooms() []*Room {
+	if x != nil {
+		return x.Rooms
+	}
+	return nil
+}
+
+type CancelRoomRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRoomRequest) Reset() {
+	*x = CancelRoomRequest{}
+	mi := &file_room_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRoomRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRoomRequest) ProtoMessage() {}
+
+func (x *CancelRoomRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRoomRequest.ProtoReflect.Descriptor instead.
+func (*CancelRoomRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *CancelRoomRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *CancelRoomRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type CancelRoomResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRoomResponse) Reset() {
+	*x = CancelRoomResponse{}
+	mi := &file_room_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRoomResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRoomResponse) ProtoMessage() {}
+
+func (x *CancelRoomResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRoomResponse.ProtoReflect.Descriptor instead.
+func (*CancelRoomResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *CancelRoomResponse) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+type RoomEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       int64                  `protobuf:"varint,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	RoomId        string                 `protobuf:"bytes,2,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	Type          string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"` // created, joined, left, full, started, no_show, completed, cancelled, fee_charged, payment_failed
+	ActorId       string                 `protobuf:"bytes,4,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Details       string                 `protobuf:"bytes,5,opt,name=details,proto3" json:"details,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomEvent) Reset() {
+	*x = RoomEvent{}
+	mi := &file_room_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomEvent) ProtoMessage() {}
+
+func (x *RoomEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomEvent.ProtoReflect.Descriptor instead.
+func (*RoomEvent) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *RoomEvent) GetEventId() int64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *RoomEvent) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetDetails() string {
+	if x != nil {
+		return x.Details
+	}
+	return ""
+}
+
+func (x *RoomEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GetRoomTimelineRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomTimelineRequest) Reset() {
+	*x = GetRoomTimelineRequest{}
+	mi := &file_room_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTimelineRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTimelineRequest) ProtoMessage() {}
+
+func (x *GetRoomTimelineRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTimelineRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomTimelineRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *GetRoomTimelineRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type GetRoomTimelineResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"`
+	Events        []*RoomEvent           `protobuf:"bytes,3,rep,name=events,proto3" json:"events,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomTimelineResponse) Reset() {
+	*x = GetRoomTimelineResponse{}
+	mi := &file_room_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomTimelineResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomTimelineResponse) ProtoMessage() {}
+
+func (x *GetRoomTimelineResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomTimelineResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomTimelineResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GetRoomTimelineResponse) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *GetRoomTimelineResponse) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *GetRoomTimelineResponse) GetEvents() []*RoomEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+type GetRoomBillingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RoomId        string                 `protobuf:"bytes,1,opt,name=room_id,json=roomId,proto3" json:"room_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomBillingRequest) Reset() {
+	*x = GetRoomBillingRequest{}
+	mi := &file_room_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomBillingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomBillingRequest) ProtoMessage() {}
+
+func (x *GetRoomBillingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomBillingRequest.ProtoReflect.Descriptor instead.
+func (*GetRoomBillingRequest) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *GetRoomBillingRequest) GetRoomId() string {
+	if x != nil {
+		return x.RoomId
+	}
+	return ""
+}
+
+type GetRoomBillingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Room          *Room                  `protobuf:"bytes,1,opt,name=room,proto3" json:"room,omitempty"`
+	Members       []string               `protobuf:"bytes,2,rep,name=members,proto3" json:"members,omitempty"` // Текущие участники, включая создателя
+	Fees          []*RoomFee             `protobuf:"bytes,3,rep,name=fees,proto3" json:"fees,omitempty"`       // Штрафы за позднюю отмену и неявку
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRoomBillingResponse) Reset() {
+	*x = GetRoomBillingResponse{}
+	mi := &file_room_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRoomBillingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRoomBillingResponse) ProtoMessage() {}
+
+func (x *GetRoomBillingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRoomBillingResponse.ProtoReflect.Descriptor instead.
+func (*GetRoomBillingResponse) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GetRoomBillingResponse) GetRoom() *Room {
+	if x != nil {
+		return x.Room
+	}
+	return nil
+}
+
+func (x *GetRoomBillingResponse) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *GetRoomBillingResponse) GetFees() []*RoomFee {
+	if x != nil {
+		return x.Fees
+	}
+	return nil
+}
+
+// RoomFee — штраф пассажиру по правилам отмены комнаты, в валюте комнаты
+type RoomFee struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // cancellation, no_show
+	Amount        float32                `protobuf:"fixed32,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RoomFee) Reset() {
+	*x = RoomFee{}
+	mi := &file_room_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RoomFee) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RoomFee) ProtoMessage() {}
+
+func (x *RoomFee) ProtoReflect() protoreflect.Message {
+	mi := &file_room_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RoomFee.ProtoReflect.Descriptor instead.
+func (*RoomFee) Descriptor() ([]byte, []int) {
+	return file_room_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *RoomFee) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RoomFee) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *RoomFee) GetAmount() float32 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+var File_room_proto protoreflect.FileDescriptor
+
+const file_room_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"room.proto\x12\x0fservice.room.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"^\n" +
+	"\bLocation\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\x12\x18\n" +
+	"\aaddress\x18\x03 \x01(\tR\aaddress\"X\n" +
+	"\aVehicle\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x14\n" +
+	"\x05color\x18\x02 \x01(\tR\x05color\x12!\n" +
+	"\fplate_number\x18\x03 \x01(\tR\vplateNumber\"\xf7\x05\n" +
+	"\x04Room\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1d\n" +
+	"\n" +
+	"creator_id\x18\x02 \x01(\tR\tcreatorId\x12\x18\n" +
+	"\amembers\x18\x03 \x03(\tR\amembers\x12@\n" +
+	"\x0estart_location\x18\x04 \x01(\v2\x19.service.room.v1.LocationR\rstartLocation\x12<\n" +
+	"\fend_location\x18\x05 \x01(\v2\x19.service.room.v1.LocationR\vendLocation\x12'\n" +
+	"\x0favailable_seats\x18\x06 \x01(\x05R\x0eavailableSeats\x123\n" +
+	"\x06status\x18\a \x01(\x0e2\x1b.service.room.v1.RoomStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12A\n" +
+	"\x0escheduled_time\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12\x1f\n" +
+	"\vtotal_price\x18\n" +
+	" \x01(\x02R\n" +
+	"totalPrice\x12&\n" +
+	"\x0fcost_per_member\x18\v \x01(\x02R\rcostPerMember\x122\n" +
+	"\avehicle\x18\f \x01(\v2\x18.service.room.v1.VehicleR\avehicle\x12)\n" +
+	"\x10require_verified\x18\r \x01(\bR\x0frequireVerified\x12'\n" +
+	"\x0festimated_price\x18\x0e \x01(\x02R\x0eestimatedPrice\x12\x1a\n" +
+	"\bcurrency\x18\x0f \x01(\tR\bcurrency\x12T\n" +
+	"\x13cancellation_policy\x18\x10 \x01(\v2#.service.room.v1.CancellationPolicyR\x12cancellationPolicy\"\x8f\x01\n" +
+	"\x12CancellationPolicy\x12.\n" +
+	"\x13free_cancel_minutes\x18\x01 \x01(\x05R\x11freeCancelMinutes\x12)\n" +
+	"\x10cancellation_fee\x18\x02 \x01(\x02R\x0fcancellationFee\x12\x1e\n" +
+	"\vno_show_fee\x18\x03 \x01(\x02R\tnoShowFee\"n\n" +
+	"\bUserInfo\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"avatar_url\x18\x03 \x01(\tR\tavatarUrl\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x02R\x06rating\"\xc0\x03\n" +
+	"\x11CreateRoomRequest\x12\x1d\n" +
+	"\n" +
+	"creator_id\x18\x01 \x01(\tR\tcreatorId\x12@\n" +
+	"\x0estart_location\x18\x02 \x01(\v2\x19.service.room.v1.LocationR\rstartLocation\x12<\n" +
+	"\fend_location\x18\x03 \x01(\v2\x19.service.room.v1.LocationR\vendLocation\x12A\n" +
+	"\x0escheduled_time\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\rscheduledTime\x12\x1f\n" +
+	"\vmax_members\x18\x05 \x01(\x05R\n" +
+	"maxMembers\x12)\n" +
+	"\x10require_verified\x18\x06 \x01(\bR\x0frequireVerified\x12'\n" +
+	"\x0festimated_price\x18\a \x01(\x02R\x0eestimatedPrice\x12T\n" +
+	"\x13cancellation_policy\x18\b \x01(\v2#.service.room.v1.CancellationPolicyR\x12cancellationPolicy\"?\n" +
 	"\x12CreateRoomResponse\x12)\n" +
 	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\"C\n" +
 	"\x0fJoinRoomRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\"=\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\xa0\x01\n" +
 	"\x10JoinRoomResponse\x12)\n" +
-	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\"C\n" +
+	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\x128\n" +
+	"\x18payment_confirmation_url\x18\x02 \x01(\tR\x16paymentConfirmationUrl\x12'\n" +
+	"\x0fpayment_pending\x18\x03 \x01(\bR\x0epaymentPending\"C\n" +
 	"\x0fExitRoomRequest\x12\x17\n" +
 	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
-	"\auser_id\x18\x02 \x01(\tR\x06userId\",\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"W\n" +
 	"\x10ExitRoomResponse\x12\x18\n" +
-	"\asuccess\x18\x01 \x01(\bR\asuccess\"\xed\x02\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12)\n" +
+	"\x10cancellation_fee\x18\x02 \x01(\x02R\x0fcancellationFee\"\xed\x02\n" +
 	"\x0fFindRoomRequest\x12B\n" +
 	"\x0fpickup_location\x18\x01 \x01(\v2\x19.service.room.v1.LocationR\x0epickupLocation\x12D\n" +
 	"\x10dropoff_location\x18\x02 \x01(\v2\x19.service.room.v1.LocationR\x0fdropoffLocation\x12D\n" +
@@ -1442,7 +2562,77 @@ const file_room_proto_rawDesc = "" +
 	"\fnew_location\x18\x01 \x01(\v2\x19.service.room.v1.LocationR\vnewLocation\x12\x1b\n" +
 	"\tis_pickup\x18\x02 \x01(\bR\bisPickup\"?\n" +
 	"\x0ePaymentUpdated\x12-\n" +
-	"\x13new_cost_per_member\x18\x02 \x01(\x02R\x10newCostPerMember*\xa7\x01\n" +
+	"\x13new_cost_per_member\x18\x02 \x01(\x02R\x10newCostPerMember\"q\n" +
+	"\x10StartRideRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12'\n" +
+	"\x10no_show_user_ids\x18\x03 \x03(\tR\rnoShowUserIds\"`\n" +
+	"\x11StartRideResponse\x12)\n" +
+	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\x12 \n" +
+	"\fno_show_fees\x18\x02 \x01(\x05R\n" +
+	"noShowFees\"\x8d\x01\n" +
+	"\x13CompleteRideRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x1b\n" +
+	"\tdriver_id\x18\x02 \x01(\tR\bdriverId\x12\x1f\n" +
+	"\vtotal_price\x18\x03 \x01(\x02R\n" +
+	"totalPrice\x12\x1f\n" +
+	"\vdistance_km\x18\x04 \x01(\x02R\n" +
+	"distanceKm\"\xa0\x01\n" +
+	"\x14CompleteRideResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x1f\n" +
+	"\vtotal_price\x18\x02 \x01(\x02R\n" +
+	"totalPrice\x12&\n" +
+	"\x0fcost_per_member\x18\x03 \x01(\x02R\rcostPerMember\x12%\n" +
+	"\x0epayments_count\x18\x04 \x01(\x05R\rpaymentsCount\"]\n" +
+	"\x15ApplyPromoCodeRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04code\x18\x03 \x01(\tR\x04code\"\x8b\x01\n" +
+	"\x16ApplyPromoCodeResponse\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x14\n" +
+	"\x05value\x18\x04 \x01(\x02R\x05value\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"\x8b\x02\n" +
+	"\x12SearchRoomsRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12=\n" +
+	"\fcreated_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x123\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1b.service.room.v1.RoomStatusR\x06status\x12\x14\n" +
+	"\x05limit\x18\x06 \x01(\x05R\x05limit\"B\n" +
+	"\x13SearchRoomsResponse\x12+\n" +
+	"\x05rooms\x18\x01 \x03(\v2\x15.service.room.v1.RoomR\x05rooms\"D\n" +
+	"\x11CancelRoomRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"?\n" +
+	"\x12CancelRoomResponse\x12)\n" +
+	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\"\xc3\x01\n" +
+	"\tRoomEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\x03R\aeventId\x12\x17\n" +
+	"\aroom_id\x18\x02 \x01(\tR\x06roomId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x19\n" +
+	"\bactor_id\x18\x04 \x01(\tR\aactorId\x12\x18\n" +
+	"\adetails\x18\x05 \x01(\tR\adetails\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"1\n" +
+	"\x16GetRoomTimelineRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\x92\x01\n" +
+	"\x17GetRoomTimelineResponse\x12)\n" +
+	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\x122\n" +
+	"\x06events\x18\x03 \x03(\v2\x1a.service.room.v1.RoomEventR\x06events\"0\n" +
+	"\x15GetRoomBillingRequest\x12\x17\n" +
+	"\aroom_id\x18\x01 \x01(\tR\x06roomId\"\x8b\x01\n" +
+	"\x16GetRoomBillingResponse\x12)\n" +
+	"\x04room\x18\x01 \x01(\v2\x15.service.room.v1.RoomR\x04room\x12\x18\n" +
+	"\amembers\x18\x02 \x03(\tR\amembers\x12,\n" +
+	"\x04fees\x18\x03 \x03(\v2\x18.service.room.v1.RoomFeeR\x04fees\"N\n" +
+	"\aRoomFee\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x02R\x06amount*\xa7\x01\n" +
 	"\n" +
 	"RoomStatus\x12\x1b\n" +
 	"\x17ROOM_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
@@ -1450,7 +2640,7 @@ const file_room_proto_rawDesc = "" +
 	"\x10ROOM_STATUS_FULL\x10\x02\x12\x17\n" +
 	"\x13ROOM_STATUS_ON_RIDE\x10\x03\x12\x19\n" +
 	"\x15ROOM_STATUS_COMPLETED\x10\x04\x12\x19\n" +
-	"\x15ROOM_STATUS_CANCELLED\x10\x052\x99\x04\n" +
+	"\x15ROOM_STATUS_CANCELLED\x10\x052\xa7\t\n" +
 	"\vRoomService\x12U\n" +
 	"\n" +
 	"CreateRoom\x12\".service.room.v1.CreateRoomRequest\x1a#.service.room.v1.CreateRoomResponse\x12O\n" +
@@ -1458,7 +2648,15 @@ const file_room_proto_rawDesc = "" +
 	"\bExitRoom\x12 .service.room.v1.ExitRoomRequest\x1a!.service.room.v1.ExitRoomResponse\x12O\n" +
 	"\bFindRoom\x12 .service.room.v1.FindRoomRequest\x1a!.service.room.v1.FindRoomResponse\x12a\n" +
 	"\x0eGetRoomDetails\x12&.service.room.v1.GetRoomDetailsRequest\x1a'.service.room.v1.GetRoomDetailsResponse\x12]\n" +
-	"\x11StreamRoomUpdates\x12).service.room.v1.StreamRoomUpdatesRequest\x1a\x1b.service.room.v1.RoomUpdate0\x01B\x0eZ\f/roomserviceb\x06proto3"
+	"\x11StreamRoomUpdates\x12).service.room.v1.StreamRoomUpdatesRequest\x1a\x1b.service.room.v1.RoomUpdate0\x01\x12R\n" +
+	"\tStartRide\x12!.service.room.v1.StartRideRequest\x1a\".service.room.v1.StartRideResponse\x12[\n" +
+	"\fCompleteRide\x12$.service.room.v1.CompleteRideRequest\x1a%.service.room.v1.CompleteRideResponse\x12a\n" +
+	"\x0eApplyPromoCode\x12&.service.room.v1.ApplyPromoCodeRequest\x1a'.service.room.v1.ApplyPromoCodeResponse\x12X\n" +
+	"\vSearchRooms\x12#.service.room.v1.SearchRoomsRequest\x1a$.service.room.v1.SearchRoomsResponse\x12U\n" +
+	"\n" +
+	"CancelRoom\x12\".service.room.v1.CancelRoomRequest\x1a#.service.room.v1.CancelRoomResponse\x12d\n" +
+	"\x0fGetRoomTimeline\x12'.service.room.v1.GetRoomTimelineRequest\x1a(.service.room.v1.GetRoomTimelineResponse\x12a\n" +
+	"\x0eGetRoomBilling\x12&.service.room.v1.GetRoomBillingRequest\x1a'.service.room.v1.GetRoomBillingResponseB\x0eZ\f/roomserviceb\x06proto3"
 
 var (
 	file_room_proto_rawDescOnce sync.Once
@@ -1473,76 +2671,120 @@ func file_room_proto_rawDescGZIP() []byte {
 }
 
 var file_room_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_room_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
+var file_room_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_room_proto_goTypes = []any{
 	(RoomStatus)(0),                  // 0: service.room.v1.RoomStatus
 	(*Location)(nil),                 // 1: service.room.v1.Location
 	(*Vehicle)(nil),                  // 2: service.room.v1.Vehicle
 	(*Room)(nil),                     // 3: service.room.v1.Room
-	(*UserInfo)(nil),                 // 4: service.room.v1.UserInfo
-	(*CreateRoomRequest)(nil),        // 5: service.room.v1.CreateRoomRequest
-	(*CreateRoomResponse)(nil),       // 6: service.room.v1.CreateRoomResponse
-	(*JoinRoomRequest)(nil),          // 7: service.room.v1.JoinRoomRequest
-	(*JoinRoomResponse)(nil),         // 8: service.room.v1.JoinRoomResponse
-	(*ExitRoomRequest)(nil),          // 9: service.room.v1.ExitRoomRequest
-	(*ExitRoomResponse)(nil),         // 10: service.room.v1.ExitRoomResponse
-	(*FindRoomRequest)(nil),          // 11: service.room.v1.FindRoomRequest
-	(*FindRoomResponse)(nil),         // 12: service.room.v1.FindRoomResponse
-	(*GetRoomDetailsRequest)(nil),    // 13: service.room.v1.GetRoomDetailsRequest
-	(*GetRoomDetailsResponse)(nil),   // 14: service.room.v1.GetRoomDetailsResponse
-	(*StreamRoomUpdatesRequest)(nil), // 15: service.room.v1.StreamRoomUpdatesRequest
-	(*RoomUpdate)(nil),               // 16: service.room.v1.RoomUpdate
-	(*MemberJoined)(nil),             // 17: service.room.v1.MemberJoined
-	(*MemberLeft)(nil),               // 18: service.room.v1.MemberLeft
-	(*RoomStatusChanged)(nil),        // 19: service.room.v1.RoomStatusChanged
-	(*LocationUpdated)(nil),          // 20: service.room.v1.LocationUpdated
-	(*PaymentUpdated)(nil),           // 21: service.room.v1.PaymentUpdated
-	(*timestamppb.Timestamp)(nil),    // 22: google.protobuf.Timestamp
+	(*CancellationPolicy)(nil),       // 4: service.room.v1.CancellationPolicy
+	(*UserInfo)(nil),                 // 5: service.room.v1.UserInfo
+	(*CreateRoomRequest)(nil),        // 6: service.room.v1.CreateRoomRequest
+	(*CreateRoomResponse)(nil),       // 7: service.room.v1.CreateRoomResponse
+	(*JoinRoomRequest)(nil),          // 8: service.room.v1.JoinRoomRequest
+	(*JoinRoomResponse)(nil),         // 9: service.room.v1.JoinRoomResponse
+	(*ExitRoomRequest)(nil),          // 10: service.room.v1.ExitRoomRequest
+	(*ExitRoomResponse)(nil),         // 11: service.room.v1.ExitRoomResponse
+	(*FindRoomRequest)(nil),          // 12: service.room.v1.FindRoomRequest
+	(*FindRoomResponse)(nil),         // 13: service.room.v1.FindRoomResponse
+	(*GetRoomDetailsRequest)(nil),    // 14: service.room.v1.GetRoomDetailsRequest
+	(*GetRoomDetailsResponse)(nil),   // 15: service.room.v1.GetRoomDetailsResponse
+	(*StreamRoomUpdatesRequest)(nil), // 16: service.room.v1.StreamRoomUpdatesRequest
+	(*RoomUpdate)(nil),               // 17: service.room.v1.RoomUpdate
+	(*MemberJoined)(nil),             // 18: service.room.v1.MemberJoined
+	(*MemberLeft)(nil),               // 19: service.room.v1.MemberLeft
+	(*RoomStatusChanged)(nil),        // 20: service.room.v1.RoomStatusChanged
+	(*LocationUpdated)(nil),          // 21: service.room.v1.LocationUpdated
+	(*PaymentUpdated)(nil),           // 22: service.room.v1.PaymentUpdated
+	(*StartRideRequest)(nil),         // 23: service.room.v1.StartRideRequest
+	(*StartRideResponse)(nil),        // 24: service.room.v1.StartRideResponse
+	(*CompleteRideRequest)(nil),      // 25: service.room.v1.CompleteRideRequest
+	(*CompleteRideResponse)(nil),     // 26: service.room.v1.CompleteRideResponse
+	(*ApplyPromoCodeRequest)(nil),    // 27: service.room.v1.ApplyPromoCodeRequest
+	(*ApplyPromoCodeResponse)(nil),   // 28: service.room.v1.ApplyPromoCodeResponse
+	(*SearchRoomsRequest)(nil),       // 29: service.room.v1.SearchRoomsRequest
+	(*SearchRoomsResponse)(nil),      // 30: service.room.v1.SearchRoomsResponse
+	(*CancelRoomRequest)(nil),        // 31: service.room.v1.CancelRoomRequest
+	(*CancelRoomResponse)(nil),       // 32: service.room.v1.CancelRoomResponse
+	(*RoomEvent)(nil),                // 33: service.room.v1.RoomEvent
+	(*GetRoomTimelineRequest)(nil),   // 34: service.room.v1.GetRoomTimelineRequest
+	(*GetRoomTimelineResponse)(nil),  // 35: service.room.v1.GetRoomTimelineResponse
+	(*GetRoomBillingRequest)(nil),    // 36: service.room.v1.GetRoomBillingRequest
+	(*GetRoomBillingResponse)(nil),   // 37: service.room.v1.GetRoomBillingResponse
+	(*RoomFee)(nil),                  // 38: service.room.v1.RoomFee
+	(*timestamppb.Timestamp)(nil),    // 39: google.protobuf.Timestamp
 }
 var file_room_proto_depIdxs = []int32{
 	1,  // 0: service.room.v1.Room.start_location:type_name -> service.room.v1.Location
 	1,  // 1: service.room.v1.Room.end_location:type_name -> service.room.v1.Location
 	0,  // 2: service.room.v1.Room.status:type_name -> service.room.v1.RoomStatus
-	22, // 3: service.room.v1.Room.created_at:type_name -> google.protobuf.Timestamp
-	22, // 4: service.room.v1.Room.scheduled_time:type_name -> google.protobuf.Timestamp
+	39, // 3: service.room.v1.Room.created_at:type_name -> google.protobuf.Timestamp
+	39, // 4: service.room.v1.Room.scheduled_time:type_name -> google.protobuf.Timestamp
 	2,  // 5: service.room.v1.Room.vehicle:type_name -> service.room.v1.Vehicle
-	1,  // 6: service.room.v1.CreateRoomRequest.start_location:type_name -> service.room.v1.Location
-	1,  // 7: service.room.v1.CreateRoomRequest.end_location:type_name -> service.room.v1.Location
-	22, // 8: service.room.v1.CreateRoomRequest.scheduled_time:type_name -> google.protobuf.Timestamp
-	3,  // 9: service.room.v1.CreateRoomResponse.room:type_name -> service.room.v1.Room
-	3,  // 10: service.room.v1.JoinRoomResponse.room:type_name -> service.room.v1.Room
-	1,  // 11: service.room.v1.FindRoomRequest.pickup_location:type_name -> service.room.v1.Location
-	1,  // 12: service.room.v1.FindRoomRequest.dropoff_location:type_name -> service.room.v1.Location
-	22, // 13: service.room.v1.FindRoomRequest.time_range_start:type_name -> google.protobuf.Timestamp
-	22, // 14: service.room.v1.FindRoomRequest.time_range_end:type_name -> google.protobuf.Timestamp
-	3,  // 15: service.room.v1.FindRoomResponse.available_rooms:type_name -> service.room.v1.Room
-	3,  // 16: service.room.v1.GetRoomDetailsResponse.room:type_name -> service.room.v1.Room
-	4,  // 17: service.room.v1.GetRoomDetailsResponse.members:type_name -> service.room.v1.UserInfo
-	17, // 18: service.room.v1.RoomUpdate.member_joined:type_name -> service.room.v1.MemberJoined
-	18, // 19: service.room.v1.RoomUpdate.member_left:type_name -> service.room.v1.MemberLeft
-	19, // 20: service.room.v1.RoomUpdate.status_changed:type_name -> service.room.v1.RoomStatusChanged
-	20, // 21: service.room.v1.RoomUpdate.location_updated:type_name -> service.room.v1.LocationUpdated
-	21, // 22: service.room.v1.RoomUpdate.payment_updated:type_name -> service.room.v1.PaymentUpdated
-	4,  // 23: service.room.v1.MemberJoined.user:type_name -> service.room.v1.UserInfo
-	0,  // 24: service.room.v1.RoomStatusChanged.new_status:type_name -> service.room.v1.RoomStatus
-	1,  // 25: service.room.v1.LocationUpdated.new_location:type_name -> service.room.v1.Location
-	5,  // 26: service.room.v1.RoomService.CreateRoom:input_type -> service.room.v1.CreateRoomRequest
-	7,  // 27: service.room.v1.RoomService.JoinRoom:input_type -> service.room.v1.JoinRoomRequest
-	9,  // 28: service.room.v1.RoomService.ExitRoom:input_type -> service.room.v1.ExitRoomRequest
-	11, // 29: service.room.v1.RoomService.FindRoom:input_type -> service.room.v1.FindRoomRequest
-	13, // 30: service.room.v1.RoomService.GetRoomDetails:input_type -> service.room.v1.GetRoomDetailsRequest
-	15, // 31: service.room.v1.RoomService.StreamRoomUpdates:input_type -> service.room.v1.StreamRoomUpdatesRequest
-	6,  // 32: service.room.v1.RoomService.CreateRoom:output_type -> service.room.v1.CreateRoomResponse
-	8,  // 33: service.room.v1.RoomService.JoinRoom:output_type -> service.room.v1.JoinRoomResponse
-	10, // 34: service.room.v1.RoomService.ExitRoom:output_type -> service.room.v1.ExitRoomResponse
-	12, // 35: service.room.v1.RoomService.FindRoom:output_type -> service.room.v1.FindRoomResponse
-	14, // 36: service.room.v1.RoomService.GetRoomDetails:output_type -> service.room.v1.GetRoomDetailsResponse
-	16, // 37: service.room.v1.RoomService.StreamRoomUpdates:output_type -> service.room.v1.RoomUpdate
-	32, // [32:38] is the sub-list for method output_type
-	26, // [26:32] is the sub-list for method input_type
-	26, // [26:26] is the sub-list for extension type_name
-	26, // [26:26] is the sub-list for extension extendee
-	0,  // [0:26] is the sub-list for field type_name
+	4,  // 6: service.room.v1.Room.cancellation_policy:type_name -> service.room.v1.CancellationPolicy
+	1,  // 7: service.room.v1.CreateRoomRequest.start_location:type_name -> service.room.v1.Location
+	1,  // 8: service.room.v1.CreateRoomRequest.end_location:type_name -> service.room.v1.Location
+	39, // 9: service.room.v1.CreateRoomRequest.scheduled_time:type_name -> google.protobuf.Timestamp
+	4,  // 10: service.room.v1.CreateRoomRequest.cancellation_policy:type_name -> service.room.v1.CancellationPolicy
+	3,  // 11: service.room.v1.CreateRoomResponse.room:type_name -> service.room.v1.Room
+	3,  // 12: service.room.v1.JoinRoomResponse.room:type_name -> service.room.v1.Room
+	1,  // 13: service.room.v1.FindRoomRequest.pickup_location:type_name -> service.room.v1.Location
+	1,  // 14: service.room.v1.FindRoomRequest.dropoff_location:type_name -> service.room.v1.Location
+	39, // 15: service.room.v1.FindRoomRequest.time_range_start:type_name -> google.protobuf.Timestamp
+	39, // 16: service.room.v1.FindRoomRequest.time_range_end:type_name -> google.protobuf.Timestamp
+	3,  // 17: service.room.v1.FindRoomResponse.available_rooms:type_name -> service.room.v1.Room
+	3,  // 18: service.room.v1.GetRoomDetailsResponse.room:type_name -> service.room.v1.Room
+	5,  // 19: service.room.v1.GetRoomDetailsResponse.members:type_name -> service.room.v1.UserInfo
+	18, // 20: service.room.v1.RoomUpdate.member_joined:type_name -> service.room.v1.MemberJoined
+	19, // 21: service.room.v1.RoomUpdate.member_left:type_name -> service.room.v1.MemberLeft
+	20, // 22: service.room.v1.RoomUpdate.status_changed:type_name -> service.room.v1.RoomStatusChanged
+	21, // 23: service.room.v1.RoomUpdate.location_updated:type_name -> service.room.v1.LocationUpdated
+	22, // 24: service.room.v1.RoomUpdate.payment_updated:type_name -> service.room.v1.PaymentUpdated
+	5,  // 25: service.room.v1.MemberJoined.user:type_name -> service.room.v1.UserInfo
+	0,  // 26: service.room.v1.RoomStatusChanged.new_status:type_name -> service.room.v1.RoomStatus
+	1,  // 27: service.room.v1.LocationUpdated.new_location:type_name -> service.room.v1.Location
+	3,  // 28: service.room.v1.StartRideResponse.room:type_name -> service.room.v1.Room
+	39, // 29: service.room.v1.SearchRoomsRequest.created_from:type_name -> google.protobuf.Timestamp
+	39, // 30: service.room.v1.SearchRoomsRequest.created_to:type_name -> google.protobuf.Timestamp
+	0,  // 31: service.room.v1.SearchRoomsRequest.status:type_name -> service.room.v1.RoomStatus
+	3,  // 32: service.room.v1.SearchRoomsResponse.rooms:type_name -> service.room.v1.Room
+	3,  // 33: service.room.v1.CancelRoomResponse.room:type_name -> service.room.v1.Room
+	39, // 34: service.room.v1.RoomEvent.created_at:type_name -> google.protobuf.Timestamp
+	3,  // 35: service.room.v1.GetRoomTimelineResponse.room:type_name -> service.room.v1.Room
+	33, // 36: service.room.v1.GetRoomTimelineResponse.events:type_name -> service.room.v1.RoomEvent
+	3,  // 37: service.room.v1.GetRoomBillingResponse.room:type_name -> service.room.v1.Room
+	38, // 38: service.room.v1.GetRoomBillingResponse.fees:type_name -> service.room.v1.RoomFee
+	6,  // 39: service.room.v1.RoomService.CreateRoom:input_type -> service.room.v1.CreateRoomRequest
+	8,  // 40: service.room.v1.RoomService.JoinRoom:input_type -> service.room.v1.JoinRoomRequest
+	10, // 41: service.room.v1.RoomService.ExitRoom:input_type -> service.room.v1.ExitRoomRequest
+	12, // 42: service.room.v1.RoomService.FindRoom:input_type -> service.room.v1.FindRoomRequest
+	14, // 43: service.room.v1.RoomService.GetRoomDetails:input_type -> service.room.v1.GetRoomDetailsRequest
+	16, // 44: service.room.v1.RoomService.StreamRoomUpdates:input_type -> service.room.v1.StreamRoomUpdatesRequest
+	23, // 45: service.room.v1.RoomService.StartRide:input_type -> service.room.v1.StartRideRequest
+	25, // 46: service.room.v1.RoomService.CompleteRide:input_type -> service.room.v1.CompleteRideRequest
+	27, // 47: service.room.v1.RoomService.ApplyPromoCode:input_type -> service.room.v1.ApplyPromoCodeRequest
+	29, // 48: service.room.v1.RoomService.SearchRooms:input_type -> service.room.v1.SearchRoomsRequest
+	31, // 49: service.room.v1.RoomService.CancelRoom:input_type -> service.room.v1.CancelRoomRequest
+	34, // 50: service.room.v1.RoomService.GetRoomTimeline:input_type -> service.room.v1.GetRoomTimelineRequest
+	36, // 51: service.room.v1.RoomService.GetRoomBilling:input_type -> service.room.v1.GetRoomBillingRequest
+	7,  // 52: service.room.v1.RoomService.CreateRoom:output_type -> service.room.v1.CreateRoomResponse
+	9,  // 53: service.room.v1.RoomService.JoinRoom:output_type -> service.room.v1.JoinRoomResponse
+	11, // 54: service.room.v1.RoomService.ExitRoom:output_type -> service.room.v1.ExitRoomResponse
+	13, // 55: service.room.v1.RoomService.FindRoom:output_type -> service.room.v1.FindRoomResponse
+	15, // 56: service.room.v1.RoomService.GetRoomDetails:output_type -> service.room.v1.GetRoomDetailsResponse
+	17, // 57: service.room.v1.RoomService.StreamRoomUpdates:output_type -> service.room.v1.RoomUpdate
+	24, // 58: service.room.v1.RoomService.StartRide:output_type -> service.room.v1.StartRideResponse
+	26, // 59: service.room.v1.RoomService.CompleteRide:output_type -> service.room.v1.CompleteRideResponse
+	28, // 60: service.room.v1.RoomService.ApplyPromoCode:output_type -> service.room.v1.ApplyPromoCodeResponse
+	30, // 61: service.room.v1.RoomService.SearchRooms:output_type -> service.room.v1.SearchRoomsResponse
+	32, // 62: service.room.v1.RoomService.CancelRoom:output_type -> service.room.v1.CancelRoomResponse
+	35, // 63: service.room.v1.RoomService.GetRoomTimeline:output_type -> service.room.v1.GetRoomTimelineResponse
+	37, // 64: service.room.v1.RoomService.GetRoomBilling:output_type -> service.room.v1.GetRoomBillingResponse
+	52, // [52:65] is the sub-list for method output_type
+	39, // [39:52] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_room_proto_init() }
@@ -1550,7 +2792,7 @@ func file_room_proto_init() {
 	if File_room_proto != nil {
 		return
 	}
-	file_room_proto_msgTypes[15].OneofWrappers = []any{
+	file_room_proto_msgTypes[16].OneofWrappers = []any{
 		(*RoomUpdate_MemberJoined)(nil),
 		(*RoomUpdate_MemberLeft)(nil),
 		(*RoomUpdate_StatusChanged)(nil),
@@ -1563,7 +2805,7 @@ func file_room_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_room_proto_rawDesc), len(file_room_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   21,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -25,6 +25,13 @@ const (
 	RoomService_FindRoom_FullMethodName          = "/service.room.v1.RoomService/FindRoom"
 	RoomService_GetRoomDetails_FullMethodName    = "/service.room.v1.RoomService/GetRoomDetails"
 	RoomService_StreamRoomUpdates_FullMethodName = "/service.room.v1.RoomService/StreamRoomUpdates"
+	RoomService_StartRide_FullMethodName         = "/service.room.v1.RoomService/StartRide"
+	RoomService_CompleteRide_FullMethodName      = "/service.room.v1.RoomService/CompleteRide"
+	RoomService_ApplyPromoCode_FullMethodName    = "/service.room.v1.RoomService/ApplyPromoCode"
+	RoomService_SearchRooms_FullMethodName       = "/service.room.v1.RoomService/SearchRooms"
+	RoomService_CancelRoom_FullMethodName        = "/service.room.v1.RoomService/CancelRoom"
+	RoomService_GetRoomTimeline_FullMethodName   = "/service.room.v1.RoomService/GetRoomTimeline"
+	RoomService_GetRoomBilling_FullMethodName    = "/service.room.v1.RoomService/GetRoomBilling"
 )
 
 // RoomServiceClient is the client API for RoomService service.
@@ -43,6 +50,19 @@ type RoomServiceClient interface {
 	GetRoomDetails(ctx context.Context, in *GetRoomDetailsRequest, opts ...grpc.CallOption) (*GetRoomDetailsResponse, error)
 	// StreamRoomUpdates предоставляет обновления комнаты в реальном времени
 	StreamRoomUpdates(ctx context.Context, in *StreamRoomUpdatesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RoomUpdate], error)
+	// StartRide начинает поездку; не пришедшие пассажиры исключаются со штрафом за неявку
+	StartRide(ctx context.Context, in *StartRideRequest, opts ...grpc.CallOption) (*StartRideResponse, error)
+	// CompleteRide завершает поездку и запускает оплату
+	CompleteRide(ctx context.Context, in *CompleteRideRequest, opts ...grpc.CallOption) (*CompleteRideResponse, error)
+	// ApplyPromoCode применяет промокод участника к оплате поездки
+	ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error)
+	// Только для admin: поиск комнат, принудительная отмена и хронология событий
+	SearchRooms(ctx context.Context, in *SearchRoomsRequest, opts ...grpc.CallOption) (*SearchRoomsResponse, error)
+	CancelRoom(ctx context.Context, in *CancelRoomRequest, opts ...grpc.CallOption) (*CancelRoomResponse, error)
+	GetRoomTimeline(ctx context.Context, in *GetRoomTimelineRequest, opts ...grpc.CallOption) (*GetRoomTimelineResponse, error)
+	// GetRoomBilling — комната, её текущие участники и штрафы, по которым payment_service проверяет списания.
+	// Создателю комнаты и admin — всё; пассажиру со штрафом — комната и только его штрафы
+	GetRoomBilling(ctx context.Context, in *GetRoomBillingRequest, opts ...grpc.CallOption) (*GetRoomBillingResponse, error)
 }
 
 type roomServiceClient struct {
@@ -122,6 +142,76 @@ func (c *roomServiceClient) StreamRoomUpdates(ctx context.Context, in *StreamRoo
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type RoomService_StreamRoomUpdatesClient = grpc.ServerStreamingClient[RoomUpdate]
 
+func (c *roomServiceClient) StartRide(ctx context.Context, in *StartRideRequest, opts ...grpc.CallOption) (*StartRideResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartRideResponse)
+	err := c.cc.Invoke(ctx, RoomService_StartRide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CompleteRide(ctx context.Context, in *CompleteRideRequest, opts ...grpc.CallOption) (*CompleteRideResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CompleteRideResponse)
+	err := c.cc.Invoke(ctx, RoomService_CompleteRide_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) ApplyPromoCode(ctx context.Context, in *ApplyPromoCodeRequest, opts ...grpc.CallOption) (*ApplyPromoCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ApplyPromoCodeResponse)
+	err := c.cc.Invoke(ctx, RoomService_ApplyPromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) SearchRooms(ctx context.Context, in *SearchRoomsRequest, opts ...grpc.CallOption) (*SearchRoomsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchRoomsResponse)
+	err := c.cc.Invoke(ctx, RoomService_SearchRooms_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) CancelRoom(ctx context.Context, in *CancelRoomRequest, opts ...grpc.CallOption) (*CancelRoomResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelRoomResponse)
+	err := c.cc.Invoke(ctx, RoomService_CancelRoom_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRoomTimeline(ctx context.Context, in *GetRoomTimelineRequest, opts ...grpc.CallOption) (*GetRoomTimelineResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomTimelineResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetRoomTimeline_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *roomServiceClient) GetRoomBilling(ctx context.Context, in *GetRoomBillingRequest, opts ...grpc.CallOption) (*GetRoomBillingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRoomBillingResponse)
+	err := c.cc.Invoke(ctx, RoomService_GetRoomBilling_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // RoomServiceServer is the server API for RoomService service.
 // All implementations must embed UnimplementedRoomServiceServer
 // for forward compatibility.
@@ -138,6 +228,19 @@ type RoomServiceServer interface {
 	GetRoomDetails(context.Context, *GetRoomDetailsRequest) (*GetRoomDetailsResponse, error)
 	// StreamRoomUpdates предоставляет обновления комнаты в реальном времени
 	StreamRoomUpdates(*StreamRoomUpdatesRequest, grpc.ServerStreamingServer[RoomUpdate]) error
+	// StartRide начинает поездку; не пришедшие пассажиры исключаются со штрафом за неявку
+	StartRide(context.Context, *StartRideRequest) (*StartRideResponse, error)
+	// CompleteRide завершает поездку и запускает оплату
+	CompleteRide(context.Context, *CompleteRideRequest) (*CompleteRideResponse, error)
+	// ApplyPromoCode применяет промокод участника к оплате поездки
+	ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error)
+	// Только для admin: поиск комнат, принудительная отмена и хронология событий
+	SearchRooms(context.Context, *SearchRoomsRequest) (*SearchRoomsResponse, error)
+	CancelRoom(context.Context, *CancelRoomRequest) (*CancelRoomResponse, error)
+	GetRoomTimeline(context.Context, *GetRoomTimelineRequest) (*GetRoomTimelineResponse, error)
+	// GetRoomBilling — комната, её текущие участники и штрафы, по которым payment_service проверяет списания.
+	// Создателю комнаты и admin — всё; пассажиру со штрафом — комната и только его штрафы
+	GetRoomBilling(context.Context, *GetRoomBillingRequest) (*GetRoomBillingResponse, error)
 	mustEmbedUnimplementedRoomServiceServer()
 }
 
@@ -166,6 +269,27 @@ func (UnimplementedRoomServiceServer) GetRoomDetails(context.Context, *GetRoomDe
 func (UnimplementedRoomServiceServer) StreamRoomUpdates(*StreamRoomUpdatesRequest, grpc.ServerStreamingServer[RoomUpdate]) error {
 	return status.Errorf(codes.Unimplemented, "method StreamRoomUpdates not implemented")
 }
+func (UnimplementedRoomServiceServer) StartRide(context.Context, *StartRideRequest) (*StartRideResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartRide not implemented")
+}
+func (UnimplementedRoomServiceServer) CompleteRide(context.Context, *CompleteRideRequest) (*CompleteRideResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CompleteRide not implemented")
+}
+func (UnimplementedRoomServiceServer) ApplyPromoCode(context.Context, *ApplyPromoCodeRequest) (*ApplyPromoCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApplyPromoCode not implemented")
+}
+func (UnimplementedRoomServiceServer) SearchRooms(context.Context, *SearchRoomsRequest) (*SearchRoomsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchRooms not implemented")
+}
+func (UnimplementedRoomServiceServer) CancelRoom(context.Context, *CancelRoomRequest) (*CancelRoomResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelRoom not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRoomTimeline(context.Context, *GetRoomTimelineRequest) (*GetRoomTimelineResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomTimeline not implemented")
+}
+func (UnimplementedRoomServiceServer) GetRoomBilling(context.Context, *GetRoomBillingRequest) (*GetRoomBillingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRoomBilling not implemented")
+}
 func (UnimplementedRoomServiceServer) mustEmbedUnimplementedRoomServiceServer() {}
 func (UnimplementedRoomServiceServer) testEmbeddedByValue()                     {}
 
@@ -288,6 +412,132 @@ func _RoomService_StreamRoomUpdates_Handler(srv interface{}, stream grpc.ServerS
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type RoomService_StreamRoomUpdatesServer = grpc.ServerStreamingServer[RoomUpdate]
 
+func _RoomService_StartRide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartRideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).StartRide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_StartRide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).StartRide(ctx, req.(*StartRideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CompleteRide_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CompleteRideRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CompleteRide(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CompleteRide_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CompleteRide(ctx, req.(*CompleteRideRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_ApplyPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApplyPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).ApplyPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_ApplyPromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).ApplyPromoCode(ctx, req.(*ApplyPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_SearchRooms_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRoomsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).SearchRooms(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_SearchRooms_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).SearchRooms(ctx, req.(*SearchRoomsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_CancelRoom_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelRoomRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).CancelRoom(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_CancelRoom_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).CancelRoom(ctx, req.(*CancelRoomRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRoomTimeline_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomTimelineRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRoomTimeline(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRoomTimeline_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRoomTimeline(ctx, req.(*GetRoomTimelineRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _RoomService_GetRoomBilling_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRoomBillingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(RoomServiceServer).GetRoomBilling(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: RoomService_GetRoomBilling_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(RoomServiceServer).GetRoomBilling(ctx, req.(*GetRoomBillingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // RoomService_ServiceDesc is the grpc.ServiceDesc for RoomService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -315,6 +565,34 @@ var RoomService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetRoomDetails",
 			Handler:    _RoomService_GetRoomDetails_Handler,
 		},
+		{
+			MethodName: "StartRide",
+			Handler:    _RoomService_StartRide_Handler,
+		},
+		{
+			MethodName: "CompleteRide",
+			Handler:    _RoomService_CompleteRide_Handler,
+		},
+		{
+			MethodName: "ApplyPromoCode",
+			Handler:    _RoomService_ApplyPromoCode_Handler,
+		},
+		{
+			MethodName: "SearchRooms",
+			Handler:    _RoomService_SearchRooms_Handler,
+		},
+		{
+			MethodName: "CancelRoom",
+			Handler:    _RoomService_CancelRoom_Handler,
+		},
+		{
+			MethodName: "GetRoomTimeline",
+			Handler:    _RoomService_GetRoomTimeline_Handler,
+		},
+		{
+			MethodName: "GetRoomBilling",
+			Handler:    _RoomService_GetRoomBilling_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -2,17 +2,23 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"log"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/jwks"
 	"we_ride/internal/services/user_service/db/postgres"
 	"we_ride/internal/services/user_service/internal/config"
+	"we_ride/internal/services/user_service/internal/jwt"
+	"we_ride/internal/services/user_service/internal/mailer"
 	"we_ride/internal/services/user_service/internal/repository"
 	"we_ride/internal/services/user_service/internal/service"
 	pb "we_ride/internal/services/user_service/protoc/gen/go"
@@ -41,10 +47,48 @@ func main() {
 	}
 	defer pool.Close()
 
-	repo := repository.NewRepository(pool, cfg.JWTAccessTokenTTL, cfg.JwtSecret)
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(logger.Interceptor(ctx, log)))
+	var keys *jwt.KeySet
+	if cfg.JWTKeysDir != "" {
+		keys, err = jwt.LoadKeySet(cfg.JWTKeysDir, cfg.JWTActiveKID)
+	} else {
+		log.Info(ctx, "JWT_KEYS_DIR is not set; signing tokens with an ephemeral key")
+		keys, err = jwt.GenerateKeySet()
+	}
+	if err != nil {
+		log.Fatal(ctx, "failed to load jwt keys", zap.Error(err))
+	}
+	log.Info(ctx, "jwt keys loaded", zap.String("active_kid", keys.ActiveKID()))
+
+	repo := repository.NewRepository(pool, cfg.JWTAccessTokenTTL, keys)
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logger.Interceptor(ctx, log),
+		identity.UnaryServerInterceptor(jwt.NewVerifier(keys), service.PublicMethods...),
+	))
+
+	var mail mailer.Mailer
+	switch cfg.Mail.Driver {
+	case "smtp":
+		mail = mailer.NewSMTPMailer(cfg.Mail.SMTPHost, cfg.Mail.SMTPPort, cfg.Mail.SMTPUser, cfg.Mail.SMTPPass, cfg.Mail.From)
+	case "log":
+		mail = mailer.NewLogMailer(cfg.Mail.LogFile)
+	default:
+		log.Fatal(ctx, "unknown MAIL_DRIVER", zap.String("driver", cfg.Mail.Driver))
+	}
 
-	srv := service.New(repo)
+	srv := service.New(&repo, mail, service.Options{
+		BaseURL:            cfg.Mail.AppBaseURL,
+		VerifyTokenTTL:     cfg.Mail.VerifyTokenTTL,
+		ResetTokenTTL:      cfg.Mail.ResetTokenTTL,
+		PaymentServiceAddr: cfg.PaymentServiceAddr,
+		GatewaySecret:      cfg.GatewaySecret,
+		Login: service.LoginPolicy{
+			MaxFailures:   cfg.Login.MaxFailures,
+			MaxIPFailures: cfg.Login.MaxIPFailures,
+			Window:        cfg.Login.Window,
+			BaseLockout:   cfg.Login.BaseLockout,
+			MaxLockout:    cfg.Login.MaxLockout,
+		},
+	})
 	pb.RegisterAuthServer(grpcServer, srv)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf("0.0.0.0:%s", cfg.GRPCPort))
@@ -60,8 +104,21 @@ func main() {
 		}
 	}()
 
+	mux := http.NewServeMux()
+	mux.Handle(jwks.Path, jwks.Handler(keys.JWKS))
+	httpServer := &http.Server{Addr: fmt.Sprintf("0.0.0.0:%s", cfg.RESTPort), Handler: mux}
+
+	log.Info(ctx, "user service HTTP server started", zap.String("port", cfg.RESTPort))
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(ctx, "failed to serve HTTP", zap.Error(err))
+		}
+	}()
+
 	<-ctx.Done()
 	log.Info(ctx, "shutting down gracefully...")
+	_ = httpServer.Shutdown(context.Background())
 	grpcServer.GracefulStop()
 	log.Info(ctx, "user service stopped")
 }
@@ -0,0 +1,12 @@
+-- Одноразовые токены для подтверждения email и сброса пароля.
+-- Хранится только sha256 от токена, сам токен уходит пользователю в письме.
+CREATE TABLE IF NOT EXISTS public.user_tokens (
+    token_hash  BYTEA PRIMARY KEY,
+    user_id     UUID        NOT NULL REFERENCES public.users(user_id) ON DELETE CASCADE,
+    purpose     VARCHAR(32) NOT NULL,
+    expires_at  TIMESTAMPTZ NOT NULL,
+    used_at     TIMESTAMPTZ,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS user_tokens_user_idx ON public.user_tokens(user_id, purpose);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS public.login_attempts;
+DROP TABLE IF EXISTS public.login_throttle;
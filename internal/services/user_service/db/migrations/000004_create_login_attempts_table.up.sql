@@ -0,0 +1,22 @@
+-- Счётчики неудачных входов по ключу "email:<email>" или "ip:<addr>".
+-- Счётчик сбрасывается успешным входом или после окна без ошибок.
+CREATE TABLE IF NOT EXISTS public.login_throttle (
+    throttle_key    VARCHAR(320) PRIMARY KEY,
+    failures        INT         NOT NULL DEFAULT 0,
+    last_failure_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until    TIMESTAMPTZ
+);
+
+-- Журнал всех попыток входа
+CREATE TABLE IF NOT EXISTS public.login_attempts (
+    attempt_id BIGSERIAL PRIMARY KEY,
+    email      VARCHAR(255) NOT NULL,
+    user_id    UUID,
+    ip         VARCHAR(64)  NOT NULL DEFAULT '',
+    success    BOOLEAN      NOT NULL,
+    reason     VARCHAR(32)  NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS login_attempts_email_idx ON public.login_attempts(email, created_at DESC);
+CREATE INDEX IF NOT EXISTS login_attempts_ip_idx ON public.login_attempts(ip, created_at DESC);
@@ -0,0 +1,2 @@
+-- Удалённые аккаунты не стираются, а обезличиваются: на user_id ссылаются поездки и платежи
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
@@ -0,0 +1,4 @@
+-- Роль пользователя попадает в JWT клеймом role
+ALTER TABLE public.users
+    ADD COLUMN IF NOT EXISTS role VARCHAR(16) NOT NULL DEFAULT 'rider'
+    CHECK (role IN ('rider', 'driver', 'admin'));
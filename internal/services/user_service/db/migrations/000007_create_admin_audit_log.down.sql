@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS public.admin_audit_log;
+ALTER TABLE public.users DROP COLUMN IF EXISTS blocked_reason;
+ALTER TABLE public.users DROP COLUMN IF EXISTS blocked_at;
@@ -0,0 +1,17 @@
+-- Блокировка аккаунта поддержкой
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS blocked_at TIMESTAMPTZ;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS blocked_reason TEXT NOT NULL DEFAULT '';
+
+-- Журнал действий администраторов (пишет API Gateway)
+CREATE TABLE IF NOT EXISTS public.admin_audit_log (
+    event_id   BIGSERIAL PRIMARY KEY,
+    actor_id   UUID         NOT NULL,
+    action     VARCHAR(128) NOT NULL,
+    target     VARCHAR(255) NOT NULL DEFAULT '',
+    details    TEXT         NOT NULL DEFAULT '',
+    status     INT          NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ  NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_actor_idx ON public.admin_audit_log(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_idx ON public.admin_audit_log(target, created_at DESC);
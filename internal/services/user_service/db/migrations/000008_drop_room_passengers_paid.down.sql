@@ -0,0 +1,2 @@
+ALTER TABLE public.room_passengers
+    ADD COLUMN IF NOT EXISTS paid BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,2 @@
+-- Оплату доли отслеживает payment_service (GetRoomPayments); флаг здесь никто не обновлял
+ALTER TABLE public.room_passengers DROP COLUMN IF EXISTS paid;
@@ -12,9 +12,44 @@ type Config struct {
 	Postgres postgres.Config `yaml:"POSTGRES"`
 
 	GRPCPort string `yaml:"GRPC_PORT" env:"GRPC_PORT" env-default:"50052"`
+	RESTPort string `yaml:"REST_PORT" env:"REST_PORT" env-default:"8082"`
+
+	PaymentServiceAddr string `yaml:"PAYMENT_SERVICE_ADDR" env:"PAYMENT_SERVICE_ADDR" env-default:"localhost:50053"`
+	// GatewaySecret — общий с gateway секрет; пустой — IP клиента из метаданных не принимается
+	GatewaySecret string `yaml:"GATEWAY_SECRET" env:"GATEWAY_SECRET"`
 
 	JWTAccessTokenTTL time.Duration `yaml:"jwt_access_token_ttl" env:"JWT_ACCESS_TOKEN_TTL" env-default:"15m"`
-	JwtSecret         string        `yaml:"JWT_SECRET"           env:"JWT_SECRET"           env-default:"secret"`
+	// Каталог с приватными ключами *.pem (RSA или Ed25519), имя файла — kid.
+	// Пустое значение — одноразовый ключ, только для локального запуска.
+	JWTKeysDir   string `yaml:"JWT_KEYS_DIR"   env:"JWT_KEYS_DIR"`
+	JWTActiveKID string `yaml:"JWT_ACTIVE_KID" env:"JWT_ACTIVE_KID"`
+
+	Mail  Mail  `yaml:"MAIL"`
+	Login Login `yaml:"LOGIN"`
+}
+
+// Login — защита от перебора паролей: счётчики ошибок по email и по IP с растущей блокировкой
+type Login struct {
+	MaxFailures   int           `yaml:"LOGIN_MAX_FAILURES"    env:"LOGIN_MAX_FAILURES"    env-default:"5"`
+	MaxIPFailures int           `yaml:"LOGIN_MAX_IP_FAILURES" env:"LOGIN_MAX_IP_FAILURES" env-default:"20"`
+	Window        time.Duration `yaml:"LOGIN_FAILURE_WINDOW"  env:"LOGIN_FAILURE_WINDOW"  env-default:"15m"`
+	BaseLockout   time.Duration `yaml:"LOGIN_LOCKOUT_BASE"    env:"LOGIN_LOCKOUT_BASE"    env-default:"1m"`
+	MaxLockout    time.Duration `yaml:"LOGIN_LOCKOUT_MAX"     env:"LOGIN_LOCKOUT_MAX"     env-default:"1h"`
+}
+
+// Mail — настройки отправки писем. Driver: "log" (в лог или MAIL_LOG_FILE) или "smtp".
+type Mail struct {
+	Driver   string `yaml:"MAIL_DRIVER"   env:"MAIL_DRIVER"   env-default:"log"`
+	LogFile  string `yaml:"MAIL_LOG_FILE" env:"MAIL_LOG_FILE"`
+	SMTPHost string `yaml:"SMTP_HOST"     env:"SMTP_HOST"`
+	SMTPPort string `yaml:"SMTP_PORT"     env:"SMTP_PORT"     env-default:"587"`
+	SMTPUser string `yaml:"SMTP_USER"     env:"SMTP_USER"`
+	SMTPPass string `yaml:"SMTP_PASS"     env:"SMTP_PASS"`
+	From     string `yaml:"MAIL_FROM"     env:"MAIL_FROM"     env-default:"WeRide <no-reply@weride.app>"`
+
+	AppBaseURL     string        `yaml:"APP_BASE_URL"     env:"APP_BASE_URL"     env-default:"https://weride.app"`
+	VerifyTokenTTL time.Duration `yaml:"VERIFY_TOKEN_TTL" env:"VERIFY_TOKEN_TTL" env-default:"24h"`
+	ResetTokenTTL  time.Duration `yaml:"RESET_TOKEN_TTL"  env:"RESET_TOKEN_TTL"  env-default:"1h"`
 }
 
 func New() (*Config, error) {
@@ -1,45 +1,76 @@
 package jwt
 
 import (
+	"context"
 	"fmt"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"time"
 
+	"we_ride/internal/pkg/identity"
 	"we_ride/internal/services/user_service/internal/models"
 )
 
 type Claims struct {
-	UserID uuid.UUID `json:"uid"`
-	Email  string    `json:"email"`
+	UserID        uuid.UUID `json:"uid"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"email_verified"`
+	Role          string    `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func NewToken(user models.User, secret string, duration time.Duration) (string, error) {
+func NewToken(user models.User, keys *KeySet, duration time.Duration) (string, error) {
+	now := time.Now()
 	claims := Claims{
-		UserID: user.UserID,
-		Email:  user.Email,
+		UserID:        user.UserID,
+		Email:         user.Email,
+		EmailVerified: user.Verified,
+		Role:          user.Role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			Subject:   user.UserID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(duration)),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token := jwt.NewWithClaims(keys.active.Method, claims)
+	token.Header["kid"] = keys.active.ID
 
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(keys.active.Private)
 	if err != nil {
 		return "", fmt.Errorf("error signing token: %v", err)
 	}
 	return tokenString, nil
 }
 
-func ValidateToken(tokenStr string, secretKey string) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return []byte(secretKey), nil
-	})
+func ValidateToken(tokenStr string, keys *KeySet) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenStr, &Claims{}, keys.verificationKey,
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
 
 	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
 		return claims, nil
 	}
-	return nil, err
+	return nil, fmt.Errorf("invalid token")
+}
+
+// Verifier проверяет токены локальным набором ключей — для gRPC-интерцептора user_service
+type Verifier struct {
+	keys *KeySet
+}
+
+func NewVerifier(keys *KeySet) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+func (v *Verifier) Verify(_ context.Context, token string) (identity.Identity, error) {
+	claims, err := ValidateToken(token, v.keys)
+	if err != nil {
+		return identity.Identity{}, err
+	}
+	return identity.Identity{UserID: claims.UserID.String(), Email: claims.Email, EmailVerified: claims.EmailVerified, Role: claims.Role}, nil
 }
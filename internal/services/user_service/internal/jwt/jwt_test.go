@@ -0,0 +1,118 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"we_ride/internal/services/user_service/internal/models"
+)
+
+func newTestKeySet(t *testing.T) *KeySet {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generate rsa key: %v", err)
+	}
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("generate ed25519 key: %v", err)
+	}
+	ks, err := NewKeySet("new",
+		&Key{ID: "old", Method: jwt.SigningMethodRS256, Private: rsaKey},
+		&Key{ID: "new", Method: jwt.SigningMethodEdDSA, Private: edKey},
+	)
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	return ks
+}
+
+func TestTokenRoundTripWithKid(t *testing.T) {
+	ks := newTestKeySet(t)
+	user := models.User{UserID: uuid.New(), Email: "a@b.c", Role: "driver"}
+
+	token, err := NewToken(user, ks, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	claims, err := ValidateToken(token, ks)
+	if err != nil {
+		t.Fatalf("ValidateToken: %v", err)
+	}
+	if claims.UserID != user.UserID || claims.Role != "driver" {
+		t.Fatalf("unexpected claims uid=%s role=%q", claims.UserID, claims.Role)
+	}
+
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &Claims{})
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if parsed.Header["kid"] != "new" || parsed.Method.Alg() != "EdDSA" {
+		t.Fatalf("unexpected header %v", parsed.Header)
+	}
+}
+
+func TestValidateTokenAcceptsRetiredKey(t *testing.T) {
+	ks := newTestKeySet(t)
+	old, err := NewKeySet("old", ks.keys["old"])
+	if err != nil {
+		t.Fatalf("NewKeySet: %v", err)
+	}
+	token, err := NewToken(models.User{UserID: uuid.New()}, old, time.Minute)
+	if err != nil {
+		t.Fatalf("NewToken: %v", err)
+	}
+	if _, err := ValidateToken(token, ks); err != nil {
+		t.Fatalf("token signed by retired key must still validate: %v", err)
+	}
+}
+
+func TestValidateTokenRejectsHMAC(t *testing.T) {
+	ks := newTestKeySet(t)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, Claims{
+		UserID:           uuid.New(),
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))},
+	})
+	token.Header["kid"] = "new"
+	signed, err := token.SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	if _, err := ValidateToken(signed, ks); err == nil {
+		t.Fatal("expected HS256 token to be rejected")
+	}
+}
+
+func TestJWKSPublishesAllKeys(t *testing.T) {
+	ks := newTestKeySet(t)
+	set := ks.JWKS()
+	if len(set.Keys) != 2 {
+		t.Fatalf("expected 2 keys, got %d", len(set.Keys))
+	}
+	for _, k := range set.Keys {
+		if _, err := k.PublicKey(); err != nil {
+			t.Fatalf("key %s does not decode: %v", k.Kid, err)
+		}
+	}
+}
+
+func TestGenerateKeySetUsesUniqueKid(t *testing.T) {
+	first, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("GenerateKeySet: %v", err)
+	}
+	second, err := GenerateKeySet()
+	if err != nil {
+		t.Fatalf("GenerateKeySet: %v", err)
+	}
+	// Одинаковый kid после перезапуска оставил бы в кэше JWKS других сервисов старый ключ
+	if first.ActiveKID() == second.ActiveKID() {
+		t.Fatalf("expected distinct kids, got %q twice", first.ActiveKID())
+	}
+}
@@ -0,0 +1,162 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"we_ride/internal/pkg/jwks"
+)
+
+// Key — ключ подписи с идентификатором kid
+type Key struct {
+	ID      string
+	Method  jwt.SigningMethod
+	Private crypto.Signer
+}
+
+// KeySet — набор ключей для ротации: токены подписываются активным ключом,
+// а проверяются любым ключом из набора, пока его не удалят из каталога.
+type KeySet struct {
+	active *Key
+	keys   map[string]*Key
+	order  []string
+}
+
+// LoadKeySet читает приватные ключи (*.pem) из каталога. Имя файла без
+// расширения становится kid. Активный ключ задаётся activeKID, иначе
+// берётся последний по имени файла (например, 2026-01.pem после 2025-12.pem).
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, fmt.Errorf("list keys: %w", err)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.pem keys found in %s", dir)
+	}
+	sort.Strings(paths)
+
+	var keys []*Key
+	for _, p := range paths {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("read key %s: %w", p, err)
+		}
+		kid := strings.TrimSuffix(filepath.Base(p), filepath.Ext(p))
+		key, err := parsePrivateKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("parse key %s: %w", p, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if activeKID == "" {
+		activeKID = keys[len(keys)-1].ID
+	}
+	return NewKeySet(activeKID, keys...)
+}
+
+// GenerateKeySet создаёт одноразовый Ed25519 ключ — для локального запуска без каталога ключей.
+// kid уникален для процесса: после перезапуска сервисы не найдут его в кэше JWKS и перечитают ключи.
+func GenerateKeySet() (*KeySet, error) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	kid := "ephemeral-" + uuid.NewString()
+	return NewKeySet(kid, &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: priv})
+}
+
+func NewKeySet(activeKID string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, k := range keys {
+		if _, ok := ks.keys[k.ID]; ok {
+			return nil, fmt.Errorf("duplicate kid %q", k.ID)
+		}
+		ks.keys[k.ID] = k
+		ks.order = append(ks.order, k.ID)
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeKID)
+	}
+	ks.active = active
+	return ks, nil
+}
+
+// ActiveKID возвращает kid ключа, которым подписываются новые токены
+func (ks *KeySet) ActiveKID() string {
+	return ks.active.ID
+}
+
+// JWKS возвращает публичные части всех ключей набора
+func (ks *KeySet) JWKS() jwks.Set {
+	set := jwks.Set{Keys: make([]jwks.Key, 0, len(ks.order))}
+	for _, kid := range ks.order {
+		k := ks.keys[kid]
+		jwk, err := jwks.FromPublicKey(k.ID, k.Method.Alg(), k.Private.Public())
+		if err != nil {
+			// parsePrivateKey пропускает только RSA и Ed25519
+			continue
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	return set
+}
+
+func (ks *KeySet) verificationKey(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	k, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown kid %q", kid)
+	}
+	if token.Method.Alg() != k.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %q", token.Method.Alg())
+	}
+	return k.Private.Public(), nil
+}
+
+func parsePrivateKey(kid string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var (
+		parsed any
+		err    error
+	)
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodRS256, Private: k}, nil
+	case ed25519.PrivateKey:
+		return &Key{ID: kid, Method: jwt.SigningMethodEdDSA, Private: k}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"we_ride/internal/pkg/logger"
+)
+
+// Message — письмо пользователю
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer отправляет письма пользователям (подтверждение email, сброс пароля)
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// SMTPMailer отправляет письма через SMTP-сервер
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: host + ":" + port, from: from, auth: auth}
+}
+
+// errHeaderInjection — перевод строки в заголовке позволил бы дописать в письмо свои заголовки
+var errHeaderInjection = errors.New("mail header contains a line break")
+
+func (m *SMTPMailer) Send(_ context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return errHeaderInjection
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	// Темы писем на русском: без кодирования заголовок не в ASCII
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=\"UTF-8\"\r\n\r\n")
+	b.WriteString(msg.Body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send mail: %w", err)
+	}
+	return nil
+}
+
+// LogMailer не отправляет письма, а пишет их в лог или в файл — для локального запуска
+type LogMailer struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewLogMailer создаёт LogMailer; при пустом path письма пишутся в лог сервиса
+func NewLogMailer(path string) *LogMailer {
+	return &LogMailer{path: path}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	if m.path == "" {
+		logger.GetLoggerFromCtx(ctx).Info(ctx, "mail",
+			zap.String("to", msg.To),
+			zap.String("subject", msg.Subject),
+			zap.String("body", msg.Body),
+		)
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("open mail file: %w", err)
+	}
+	defer f.Close()
+
+	_, err = fmt.Fprintf(f, "Date: %s\nTo: %s\nSubject: %s\n\n%s\n\n----\n",
+		time.Now().Format(time.RFC3339), msg.To, msg.Subject, msg.Body)
+	if err != nil {
+		return fmt.Errorf("write mail file: %w", err)
+	}
+	return nil
+}
@@ -6,10 +6,56 @@ import (
 )
 
 type User struct {
-	UserID    uuid.UUID
-	Email     string
-	PassHash  []byte
-	FirstName string
-	LastName  string
+	UserID       uuid.UUID
+	Email        string
+	PassHash     []byte
+	FirstName    string
+	LastName     string
+	AvatarURL    string
+	Gender       int64
+	Rating       float64
+	Role         string
+	Verified     bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	LastActiveAt *time.Time
+	BlockedAt    *time.Time
+	DeletedAt    *time.Time
+}
+
+// UserFilter — условия поиска пользователей для поддержки, пустые поля не учитываются
+type UserFilter struct {
+	UserID      *uuid.UUID
+	Email       string // подстрока, без учёта регистра
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+	Limit       int
+}
+
+// AuditEvent — действие администратора
+type AuditEvent struct {
+	EventID   int64
+	ActorID   uuid.UUID
+	Action    string
+	Target    string
+	Details   string
+	Status    int
 	CreatedAt time.Time
 }
+
+// AuditFilter — условия выборки журнала действий
+type AuditFilter struct {
+	ActorID *uuid.UUID
+	Target  string
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+// ProfileUpdate — изменяемые поля профиля, nil означает «не менять»
+type ProfileUpdate struct {
+	FirstName *string
+	LastName  *string
+	AvatarURL *string
+	Gender    *int64
+}
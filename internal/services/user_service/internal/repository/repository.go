@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -17,27 +21,44 @@ import (
 	pb "we_ride/internal/services/user_service/protoc/gen/go"
 )
 
+// Назначение одноразовых токенов из user_tokens
+const (
+	TokenPurposeVerifyEmail   = "verify_email"
+	TokenPurposeResetPassword = "reset_password"
+)
+
+var (
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidToken       = errors.New("token is invalid or expired")
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrAccountBlocked     = errors.New("account is blocked")
+)
+
+// dummyHash сравнивается с паролем, когда пользователя нет,
+// чтобы время ответа не выдавало зарегистрированные email
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("we_ride dummy password"), bcrypt.DefaultCost)
+
 type Repository struct {
 	db       *pgxpool.Pool
 	tokenTTL time.Duration
-	Secret   string
+	keys     *jwt.KeySet
 }
 
-func NewRepository(db *pgxpool.Pool, tokenTTL time.Duration, secret string) Repository {
-	return Repository{db: db, tokenTTL: tokenTTL, Secret: secret}
+func NewRepository(db *pgxpool.Pool, tokenTTL time.Duration, keys *jwt.KeySet) Repository {
+	return Repository{db: db, tokenTTL: tokenTTL, keys: keys}
 }
 
-func (r *Repository) SaveUser(ctx context.Context, email, password, firstName, lastName string, gender int64) (string, error) {
+func (r *Repository) SaveUser(ctx context.Context, email, password, firstName, lastName string, gender int64, role string) (string, error) {
 	id := uuid.New().String()
 	passHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
 	if err != nil {
 		return "", fmt.Errorf("error hashing password: %v", err)
 	}
 	query := `
-		INSERT INTO public.users (user_id, email, password_hash, first_name, last_name, gender, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO public.users (user_id, email, password_hash, first_name, last_name, gender, role, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	`
-	_, err = r.db.Exec(ctx, query, id, email, passHash, firstName, lastName, gender, time.Now())
+	_, err = r.db.Exec(ctx, query, id, email, passHash, firstName, lastName, gender, role, time.Now())
 	if err != nil {
 		if IsUniqueViolation(err) {
 			return "", errors.New("user with this email already exists")
@@ -47,36 +68,46 @@ func (r *Repository) SaveUser(ctx context.Context, email, password, firstName, l
 	return id, nil
 }
 
+// LoginUser проверяет пароль и выпускает access token.
+// Неизвестный email и неверный пароль неразличимы: оба дают ErrInvalidCredentials.
 func (r *Repository) LoginUser(ctx context.Context, email, password string) (string, error) {
 	query := `
-		SELECT user_id, email, password_hash, first_name, last_name, created_at
+		SELECT user_id, email, password_hash, first_name, last_name, COALESCE(verified, false), role, created_at, blocked_at
 		FROM public.users
-		WHERE email = $1
+		WHERE email = $1 AND deleted_at IS NULL
 	`
 	row := r.db.QueryRow(ctx, query, email)
 	var user models.User
-	err := row.Scan(&user.UserID, &user.Email, &user.PassHash, &user.FirstName, &user.LastName, &user.CreatedAt)
+	err := row.Scan(&user.UserID, &user.Email, &user.PassHash, &user.FirstName, &user.LastName, &user.Verified, &user.Role, &user.CreatedAt, &user.BlockedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return "", errors.New("user not found")
+			_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+			return "", ErrInvalidCredentials
 		}
 		return "", fmt.Errorf("error querying user: %w", err)
 	}
 
 	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
-		return "", fmt.Errorf("error checking password: %v", err)
+		return "", ErrInvalidCredentials
+	}
+	// О блокировке сообщаем только после верного пароля
+	if user.BlockedAt != nil {
+		return "", ErrAccountBlocked
 	}
 
-	token, err := jwt.NewToken(user, r.Secret, r.tokenTTL)
+	token, err := jwt.NewToken(user, r.keys, r.tokenTTL)
 	if err != nil {
 		return "", fmt.Errorf("error creating token: %v", err)
 	}
+	if _, err := r.db.Exec(ctx, `UPDATE public.users SET last_active_at = NOW() WHERE user_id = $1`, user.UserID); err != nil {
+		return "", fmt.Errorf("error updating last_active_at: %w", err)
+	}
 	return token, nil
 }
 
-// GetUserRoutes возвращает историю поездок пользователя.
-// Работает через таблицы public.routes и public.room_passengers.
-func (r *Repository) GetUserRoutes(ctx context.Context, userID uuid.UUID) ([]*pb.Route, error) {
+// GetUserRoutes возвращает историю поездок пользователя — и как пассажира, и как водителя.
+// Работает через таблицы public.routes и public.room_passengers; roomIDs сужает выборку до этих комнат.
+func (r *Repository) GetUserRoutes(ctx context.Context, userID uuid.UUID, roomIDs []uuid.UUID) ([]*pb.Route, error) {
 	query := `
 		SELECT
 			rt.route_id,
@@ -84,13 +115,20 @@ func (r *Repository) GetUserRoutes(ctx context.Context, userID uuid.UUID) ([]*pb
 			rt.total_price::text,
 			rt.start_point,
 			rt.end_point,
-			rt.distance::text
-		FROM public.room_passengers rp
-		JOIN public.routes rt ON rp.route_id = rt.route_id
-		WHERE rp.user_id = $1
-		ORDER BY rt.completed_at DESC
-	`
-	rows, err := r.db.Query(ctx, query, userID)
+			rt.distance::text,
+			rt.room_id,
+			rt.completed_at
+		FROM public.routes rt
+		WHERE (rt.driver_id = $1 OR EXISTS (
+			SELECT 1 FROM public.room_passengers rp WHERE rp.route_id = rt.route_id AND rp.user_id = $1
+		))`
+	args := []any{userID}
+	if len(roomIDs) > 0 {
+		query += ` AND rt.room_id = ANY($2)`
+		args = append(args, roomIDs)
+	}
+	query += ` ORDER BY rt.completed_at DESC`
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("GetUserRoutes query: %w", err)
 	}
@@ -99,6 +137,7 @@ func (r *Repository) GetUserRoutes(ctx context.Context, userID uuid.UUID) ([]*pb
 	var routes []*pb.Route
 	for rows.Next() {
 		route := &pb.Route{}
+		var completedAt time.Time
 		if err := rows.Scan(
 			&route.RouteId,
 			&route.DriverId,
@@ -106,9 +145,12 @@ func (r *Repository) GetUserRoutes(ctx context.Context, userID uuid.UUID) ([]*pb
 			&route.StartPoint,
 			&route.EndPoint,
 			&route.Distance,
+			&route.RoomId,
+			&completedAt,
 		); err != nil {
 			return nil, fmt.Errorf("GetUserRoutes scan: %w", err)
 		}
+		route.CompletedAt = completedAt.Format(time.RFC3339)
 		routes = append(routes, route)
 	}
 	if err := rows.Err(); err != nil {
@@ -153,6 +195,506 @@ func (r *Repository) SaveRoute(ctx context.Context, roomID, driverID, startPoint
 	return routeID, nil
 }
 
+// GetUserByEmail возвращает пользователя по email или ErrUserNotFound
+func (r *Repository) GetUserByEmail(ctx context.Context, email string) (models.User, error) {
+	return r.getUser(ctx, `WHERE email = $1`, email)
+}
+
+// GetUserByID возвращает пользователя по id или ErrUserNotFound
+func (r *Repository) GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error) {
+	return r.getUser(ctx, `WHERE user_id = $1`, userID)
+}
+
+const userColumns = `
+	user_id, email, password_hash, first_name, last_name, COALESCE(avatar_url, ''), gender,
+	COALESCE(rating, 5)::float8, COALESCE(verified, false), role, created_at, updated_at, last_active_at,
+	blocked_at, deleted_at`
+
+func scanUser(row pgx.Row) (models.User, error) {
+	var user models.User
+	err := row.Scan(
+		&user.UserID, &user.Email, &user.PassHash, &user.FirstName, &user.LastName, &user.AvatarURL, &user.Gender,
+		&user.Rating, &user.Verified, &user.Role, &user.CreatedAt, &user.UpdatedAt, &user.LastActiveAt,
+		&user.BlockedAt, &user.DeletedAt,
+	)
+	return user, err
+}
+
+func (r *Repository) getUser(ctx context.Context, where string, arg any) (models.User, error) {
+	query := `SELECT ` + userColumns + ` FROM public.users ` + where + ` AND deleted_at IS NULL`
+	user, err := scanUser(r.db.QueryRow(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("getUser: %w", err)
+	}
+	return user, nil
+}
+
+// CreateOneTimeToken выпускает одноразовый токен с заданным назначением.
+// В базе хранится только хэш, открытый токен возвращается для отправки в письме.
+func (r *Repository) CreateOneTimeToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO public.user_tokens (token_hash, user_id, purpose, expires_at)
+		VALUES ($1, $2, $3, $4)
+	`, HashToken(token), userID, purpose, time.Now().Add(ttl))
+	if err != nil {
+		return "", fmt.Errorf("CreateOneTimeToken: %w", err)
+	}
+	return token, nil
+}
+
+// VerifyEmail гасит токен подтверждения и помечает email пользователя подтверждённым
+func (r *Repository) VerifyEmail(ctx context.Context, token string) (uuid.UUID, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeToken(ctx, tx, token, TokenPurposeVerifyEmail)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.users SET verified = true, updated_at = NOW() WHERE user_id = $1
+	`, userID); err != nil {
+		return uuid.Nil, fmt.Errorf("mark verified: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return uuid.Nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return userID, nil
+}
+
+// ResetPassword гасит токен сброса, меняет пароль и отзывает остальные токены сброса
+func (r *Repository) ResetPassword(ctx context.Context, token, newPassword string) error {
+	passHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	userID, err := consumeToken(ctx, tx, token, TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.users SET password_hash = $1, updated_at = NOW() WHERE user_id = $2
+	`, passHash, userID); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.user_tokens SET used_at = NOW()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, TokenPurposeResetPassword); err != nil {
+		return fmt.Errorf("revoke reset tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+func consumeToken(ctx context.Context, tx pgx.Tx, token, purpose string) (uuid.UUID, error) {
+	var userID uuid.UUID
+	err := tx.QueryRow(ctx, `
+		UPDATE public.user_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND purpose = $2 AND used_at IS NULL AND expires_at > NOW()
+		RETURNING user_id
+	`, HashToken(token), purpose).Scan(&userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return uuid.Nil, ErrInvalidToken
+		}
+		return uuid.Nil, fmt.Errorf("consume token: %w", err)
+	}
+	return userID, nil
+}
+
+// escapeLike экранирует спецсимволы шаблона LIKE
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// HashToken — хэш одноразового токена, под которым он хранится в user_tokens
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
+
+// LoginLockedUntil возвращает самую позднюю блокировку среди ключей (нулевое время — блокировки нет)
+func (r *Repository) LoginLockedUntil(ctx context.Context, keys ...string) (time.Time, error) {
+	var until *time.Time
+	err := r.db.QueryRow(ctx, `
+		SELECT MAX(locked_until) FROM public.login_throttle
+		WHERE throttle_key = ANY($1) AND locked_until > NOW()
+	`, keys).Scan(&until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("LoginLockedUntil: %w", err)
+	}
+	if until == nil {
+		return time.Time{}, nil
+	}
+	return *until, nil
+}
+
+// RegisterLoginFailure увеличивает счётчик ошибок ключа и возвращает новое значение.
+// Если последняя ошибка была раньше window, счёт начинается заново.
+func (r *Repository) RegisterLoginFailure(ctx context.Context, key string, window time.Duration) (int, error) {
+	var failures int
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO public.login_throttle (throttle_key, failures, last_failure_at)
+		VALUES ($1, 1, NOW())
+		ON CONFLICT (throttle_key) DO UPDATE SET
+			failures = CASE
+				WHEN login_throttle.last_failure_at < NOW() - make_interval(secs => $2) THEN 1
+				ELSE login_throttle.failures + 1
+			END,
+			last_failure_at = NOW()
+		RETURNING failures
+	`, key, window.Seconds()).Scan(&failures)
+	if err != nil {
+		return 0, fmt.Errorf("RegisterLoginFailure: %w", err)
+	}
+	return failures, nil
+}
+
+// LockLogin блокирует вход по ключу до until
+func (r *Repository) LockLogin(ctx context.Context, key string, until time.Time) error {
+	_, err := r.db.Exec(ctx, `
+		UPDATE public.login_throttle SET locked_until = $2 WHERE throttle_key = $1
+	`, key, until)
+	if err != nil {
+		return fmt.Errorf("LockLogin: %w", err)
+	}
+	return nil
+}
+
+// ResetLoginFailures сбрасывает счётчик и блокировку ключа
+func (r *Repository) ResetLoginFailures(ctx context.Context, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM public.login_throttle WHERE throttle_key = $1`, key)
+	if err != nil {
+		return fmt.Errorf("ResetLoginFailures: %w", err)
+	}
+	return nil
+}
+
+// RecordLoginAttempt пишет попытку входа в журнал; user_id заполняется, если email зарегистрирован
+func (r *Repository) RecordLoginAttempt(ctx context.Context, email, ip string, success bool, reason string) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO public.login_attempts (email, user_id, ip, success, reason)
+		VALUES ($1, (SELECT user_id FROM public.users WHERE email = $1), $2, $3, $4)
+	`, email, ip, success, reason)
+	if err != nil {
+		return fmt.Errorf("RecordLoginAttempt: %w", err)
+	}
+	return nil
+}
+
+// UpdateProfile меняет заданные поля профиля и возвращает обновлённого пользователя
+func (r *Repository) UpdateProfile(ctx context.Context, userID uuid.UUID, upd models.ProfileUpdate) (models.User, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE public.users SET
+			first_name = COALESCE($2, first_name),
+			last_name  = COALESCE($3, last_name),
+			avatar_url = COALESCE($4, avatar_url),
+			gender     = COALESCE($5, gender),
+			updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`, userID, upd.FirstName, upd.LastName, upd.AvatarURL, upd.Gender)
+	if err != nil {
+		return models.User{}, fmt.Errorf("UpdateProfile: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.User{}, ErrUserNotFound
+	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// SetRole меняет роль пользователя и возвращает обновлённого пользователя
+func (r *Repository) SetRole(ctx context.Context, userID uuid.UUID, role string) (models.User, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE public.users SET role = $2, updated_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL
+	`, userID, role)
+	if err != nil {
+		return models.User{}, fmt.Errorf("SetRole: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.User{}, ErrUserNotFound
+	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// SearchUsers ищет пользователей, включая удалённых и заблокированных
+func (r *Repository) SearchUsers(ctx context.Context, f models.UserFilter) ([]models.User, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if f.UserID != nil {
+		conds = append(conds, "user_id = "+arg(*f.UserID))
+	}
+	if f.Email != "" {
+		conds = append(conds, "email ILIKE "+arg("%"+escapeLike(f.Email)+"%"))
+	}
+	if !f.CreatedFrom.IsZero() {
+		conds = append(conds, "created_at >= "+arg(f.CreatedFrom))
+	}
+	if !f.CreatedTo.IsZero() {
+		conds = append(conds, "created_at < "+arg(f.CreatedTo))
+	}
+
+	query := `SELECT ` + userColumns + ` FROM public.users`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += ` ORDER BY created_at DESC LIMIT ` + arg(f.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("SearchUsers query: %w", err)
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("SearchUsers scan: %w", err)
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("SearchUsers rows: %w", err)
+	}
+	return users, nil
+}
+
+// BlockUser блокирует или разблокирует вход пользователя
+func (r *Repository) BlockUser(ctx context.Context, userID uuid.UUID, blocked bool, reason string) (models.User, error) {
+	tag, err := r.db.Exec(ctx, `
+		UPDATE public.users SET
+			blocked_at = CASE WHEN $2 THEN COALESCE(blocked_at, NOW()) END,
+			blocked_reason = CASE WHEN $2 THEN $3 ELSE '' END,
+			updated_at = NOW()
+		WHERE user_id = $1 AND deleted_at IS NULL
+	`, userID, blocked, reason)
+	if err != nil {
+		return models.User{}, fmt.Errorf("BlockUser: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return models.User{}, ErrUserNotFound
+	}
+	return r.GetUserByID(ctx, userID)
+}
+
+// RecordAuditEvent пишет действие администратора в журнал
+func (r *Repository) RecordAuditEvent(ctx context.Context, e models.AuditEvent) (int64, error) {
+	var id int64
+	err := r.db.QueryRow(ctx, `
+		INSERT INTO public.admin_audit_log (actor_id, action, target, details, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING event_id
+	`, e.ActorID, e.Action, e.Target, e.Details, e.Status).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("RecordAuditEvent: %w", err)
+	}
+	return id, nil
+}
+
+// ListAuditEvents возвращает журнал действий администраторов, новые сверху
+func (r *Repository) ListAuditEvents(ctx context.Context, f models.AuditFilter) ([]models.AuditEvent, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	if f.ActorID != nil {
+		conds = append(conds, "actor_id = "+arg(*f.ActorID))
+	}
+	if f.Target != "" {
+		conds = append(conds, "target = "+arg(f.Target))
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, "created_at >= "+arg(f.From))
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, "created_at < "+arg(f.To))
+	}
+
+	query := `SELECT event_id, actor_id, action, target, details, status, created_at FROM public.admin_audit_log`
+	if len(conds) > 0 {
+		query += ` WHERE ` + strings.Join(conds, " AND ")
+	}
+	query += ` ORDER BY created_at DESC, event_id DESC LIMIT ` + arg(f.Limit)
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ListAuditEvents query: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.AuditEvent
+	for rows.Next() {
+		var e models.AuditEvent
+		if err := rows.Scan(&e.EventID, &e.ActorID, &e.Action, &e.Target, &e.Details, &e.Status, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("ListAuditEvents scan: %w", err)
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ListAuditEvents rows: %w", err)
+	}
+	return events, nil
+}
+
+// CheckPassword сверяет пароль пользователя, при несовпадении возвращает ErrInvalidCredentials
+func (r *Repository) CheckPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := r.GetUserByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if err := bcrypt.CompareHashAndPassword(user.PassHash, []byte(password)); err != nil {
+		return ErrInvalidCredentials
+	}
+	return nil
+}
+
+// ChangePassword меняет пароль и отзывает неиспользованные токены сброса
+func (r *Repository) ChangePassword(ctx context.Context, userID uuid.UUID, newPassword string) error {
+	passHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.users SET password_hash = $1, updated_at = NOW() WHERE user_id = $2
+	`, passHash, userID); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.user_tokens SET used_at = NOW()
+		WHERE user_id = $1 AND purpose = $2 AND used_at IS NULL
+	`, userID, TokenPurposeResetPassword); err != nil {
+		return fmt.Errorf("revoke reset tokens: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount обезличивает пользователя вместо удаления строки: user_id остаётся
+// валидной ссылкой для поездок и платежей, а email, имя, аватар и пароль стираются.
+// Адреса поездок стираются, если среди участников не осталось действующих пользователей.
+func (r *Repository) DeleteAccount(ctx context.Context, userID uuid.UUID) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var email string
+	err = tx.QueryRow(ctx, `
+		SELECT email FROM public.users WHERE user_id = $1 AND deleted_at IS NULL FOR UPDATE
+	`, userID).Scan(&email)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("lock user: %w", err)
+	}
+
+	// Случайный хэш: войти в удалённый аккаунт невозможно
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return fmt.Errorf("generate password: %w", err)
+	}
+	passHash, err := bcrypt.GenerateFromPassword([]byte(base64.RawURLEncoding.EncodeToString(raw)), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("error hashing password: %v", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.users SET
+			email = 'deleted+' || user_id::text || '@weride.invalid',
+			password_hash = $2,
+			first_name = 'Удалённый',
+			last_name = 'пользователь',
+			avatar_url = NULL,
+			verified = false,
+			deleted_at = NOW(),
+			updated_at = NOW()
+		WHERE user_id = $1
+	`, userID, passHash); err != nil {
+		return fmt.Errorf("anonymize user: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `
+		UPDATE public.routes rt SET start_point = '', end_point = ''
+		WHERE rt.route_id IN (
+			SELECT route_id FROM public.room_passengers WHERE user_id = $1
+			UNION
+			SELECT route_id FROM public.routes WHERE driver_id = $1
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM (
+				SELECT rt.driver_id AS user_id
+				UNION
+				SELECT rp.user_id FROM public.room_passengers rp WHERE rp.route_id = rt.route_id
+			) p
+			JOIN public.users u ON u.user_id = p.user_id
+			WHERE u.deleted_at IS NULL
+		)
+	`, userID); err != nil {
+		return fmt.Errorf("anonymize routes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM public.user_tokens WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("delete tokens: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `UPDATE public.login_attempts SET email = '', ip = '' WHERE user_id = $1`, userID); err != nil {
+		return fmt.Errorf("anonymize login attempts: %w", err)
+	}
+	if _, err := tx.Exec(ctx, `DELETE FROM public.login_throttle WHERE throttle_key = $1`, "email:"+strings.ToLower(email)); err != nil {
+		return fmt.Errorf("delete login throttle: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
 func IsUniqueViolation(err error) bool {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
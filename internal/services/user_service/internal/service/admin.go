@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/services/user_service/internal/models"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// SearchUsers ищет пользователей по id, email или дате регистрации; только для admin
+func (s *ServerAPI) SearchUsers(ctx context.Context, req *pb.SearchUsersRequest) (*pb.SearchUsersResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	f := models.UserFilter{Email: req.GetEmail(), Limit: searchLimit(req.GetLimit())}
+	if req.GetUserId() != "" {
+		id, err := uuid.Parse(req.GetUserId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+		}
+		f.UserID = &id
+	}
+	var err error
+	if f.CreatedFrom, f.CreatedTo, err = parseRange(req.GetCreatedFrom(), req.GetCreatedTo()); err != nil {
+		return nil, err
+	}
+
+	users, err := s.repo.SearchUsers(ctx, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to search users: %v", err)
+	}
+	resp := &pb.SearchUsersResponse{Users: make([]*pb.Profile, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, toProfile(u))
+	}
+	return resp, nil
+}
+
+// BlockUser блокирует или разблокирует вход пользователя; только для admin.
+// Уже выданные токены действуют до истечения JWT_ACCESS_TOKEN_TTL.
+func (s *ServerAPI) BlockUser(ctx context.Context, req *pb.BlockUserRequest) (*pb.BlockUserResponse, error) {
+	admin, err := identity.RequireRole(ctx, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	if req.GetBlocked() && userID.String() == admin.UserID {
+		return nil, status.Error(codes.InvalidArgument, "cannot block yourself")
+	}
+
+	user, err := s.repo.BlockUser(ctx, userID, req.GetBlocked(), req.GetReason())
+	if err != nil {
+		return nil, userError(err, "failed to block user")
+	}
+	return &pb.BlockUserResponse{Profile: toProfile(user)}, nil
+}
+
+// RecordAuditEvent пишет действие администратора в журнал от имени вызывающего
+func (s *ServerAPI) RecordAuditEvent(ctx context.Context, req *pb.RecordAuditEventRequest) (*pb.RecordAuditEventResponse, error) {
+	admin, err := identity.RequireRole(ctx, identity.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetAction() == "" {
+		return nil, status.Error(codes.InvalidArgument, "action is required")
+	}
+	actorID, err := uuid.Parse(admin.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+
+	id, err := s.repo.RecordAuditEvent(ctx, models.AuditEvent{
+		ActorID: actorID,
+		Action:  req.GetAction(),
+		Target:  req.GetTarget(),
+		Details: req.GetDetails(),
+		Status:  int(req.GetStatus()),
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to record audit event: %v", err)
+	}
+	return &pb.RecordAuditEventResponse{EventId: id}, nil
+}
+
+// ListAuditEvents возвращает журнал действий администраторов; только для admin
+func (s *ServerAPI) ListAuditEvents(ctx context.Context, req *pb.ListAuditEventsRequest) (*pb.ListAuditEventsResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+
+	f := models.AuditFilter{Target: req.GetTarget(), Limit: searchLimit(req.GetLimit())}
+	if req.GetActorId() != "" {
+		id, err := uuid.Parse(req.GetActorId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid actor_id format")
+		}
+		f.ActorID = &id
+	}
+	var err error
+	if f.From, f.To, err = parseRange(req.GetFrom(), req.GetTo()); err != nil {
+		return nil, err
+	}
+
+	events, err := s.repo.ListAuditEvents(ctx, f)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list audit events: %v", err)
+	}
+	resp := &pb.ListAuditEventsResponse{Events: make([]*pb.AuditEvent, 0, len(events))}
+	for _, e := range events {
+		resp.Events = append(resp.Events, &pb.AuditEvent{
+			EventId:   e.EventID,
+			ActorId:   e.ActorID.String(),
+			Action:    e.Action,
+			Target:    e.Target,
+			Details:   e.Details,
+			Status:    int32(e.Status),
+			CreatedAt: e.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return resp, nil
+}
+
+func searchLimit(n int32) int {
+	if n <= 0 {
+		return defaultSearchLimit
+	}
+	return min(int(n), maxSearchLimit)
+}
+
+// parseRange разбирает границы периода в RFC3339, пустая граница — без ограничения
+func parseRange(from, to string) (time.Time, time.Time, error) {
+	var f, t time.Time
+	var err error
+	if from != "" {
+		if f, err = time.Parse(time.RFC3339, from); err != nil {
+			return f, t, status.Error(codes.InvalidArgument, "from must be RFC3339")
+		}
+	}
+	if to != "" {
+		if t, err = time.Parse(time.RFC3339, to); err != nil {
+			return f, t, status.Error(codes.InvalidArgument, "to must be RFC3339")
+		}
+	}
+	if !f.IsZero() && !t.IsZero() && !f.Before(t) {
+		return f, t, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	return f, t, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/user_service/internal/repository"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+// Ключи gRPC-метаданных: gateway передаёт IP клиента и подтверждает его общим секретом
+const (
+	ClientIPMetadataKey      = "x-real-ip"
+	GatewaySecretMetadataKey = "x-gateway-secret"
+)
+
+// Причины в журнале попыток входа
+const (
+	loginReasonOK        = "ok"
+	loginReasonInvalid   = "invalid_credentials"
+	loginReasonLocked    = "locked"
+	loginReasonLockedNow = "lockout_started"
+	loginReasonBlocked   = "blocked"
+)
+
+// LoginPolicy — ограничения на неудачные попытки входа.
+// После MaxFailures ошибок по email (MaxIPFailures по IP) вход блокируется на BaseLockout,
+// каждая следующая ошибка удваивает блокировку вплоть до MaxLockout.
+type LoginPolicy struct {
+	MaxFailures   int
+	MaxIPFailures int
+	Window        time.Duration
+	BaseLockout   time.Duration
+	MaxLockout    time.Duration
+}
+
+// lockout возвращает длительность блокировки после failures ошибок при пороге limit
+func (p LoginPolicy) lockout(failures, limit int) time.Duration {
+	if limit <= 0 || failures < limit {
+		return 0
+	}
+	d := p.BaseLockout
+	for i := limit; i < failures && d < p.MaxLockout; i++ {
+		d *= 2
+	}
+	return min(d, p.MaxLockout)
+}
+
+func (s *ServerAPI) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Email is required")
+	}
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Password is required")
+	}
+
+	log := logger.GetLoggerFromCtx(ctx)
+	ip := s.clientIP(ctx)
+	emailKey := "email:" + strings.ToLower(strings.TrimSpace(req.GetEmail()))
+	ipKey := "ip:" + ip
+
+	until, err := s.repo.LoginLockedUntil(ctx, emailKey, ipKey)
+	if err != nil {
+		log.Error(ctx, "failed to check login lockout", zap.Error(err))
+	}
+	if !until.IsZero() {
+		s.recordLogin(ctx, req.GetEmail(), ip, false, loginReasonLocked)
+		return nil, status.Error(codes.ResourceExhausted, "too many failed login attempts, try again later")
+	}
+
+	token, err := s.repo.LoginUser(ctx, req.GetEmail(), req.GetPassword())
+	if errors.Is(err, repository.ErrAccountBlocked) {
+		s.recordLogin(ctx, req.GetEmail(), ip, false, loginReasonBlocked)
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if err != nil {
+		if !errors.Is(err, repository.ErrInvalidCredentials) {
+			return nil, status.Error(codes.Internal, "failed to login")
+		}
+		reason := loginReasonInvalid
+		if s.registerLoginFailure(ctx, emailKey, s.opts.Login.MaxFailures) ||
+			s.registerLoginFailure(ctx, ipKey, s.opts.Login.MaxIPFailures) {
+			reason = loginReasonLockedNow
+		}
+		s.recordLogin(ctx, req.GetEmail(), ip, false, reason)
+		return nil, status.Error(codes.Unauthenticated, repository.ErrInvalidCredentials.Error())
+	}
+
+	// Счётчик по IP не сбрасываем: иначе один свой аккаунт позволит перебирать чужие
+	if err := s.repo.ResetLoginFailures(ctx, emailKey); err != nil {
+		log.Error(ctx, "failed to reset login failures", zap.Error(err))
+	}
+	s.recordLogin(ctx, req.GetEmail(), ip, true, loginReasonOK)
+	return &pb.LoginResponse{Token: token}, nil
+}
+
+// checkPassword проверяет пароль вызывающего по тем же счётчикам и блокировкам, что и Login:
+// иначе украденный токен позволил бы перебирать пароль через ChangePassword и DeleteAccount
+func (s *ServerAPI) checkPassword(ctx context.Context, userID uuid.UUID, password string) error {
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return userError(err, "failed to check password")
+	}
+
+	log := logger.GetLoggerFromCtx(ctx)
+	ip := s.clientIP(ctx)
+	emailKey := "email:" + strings.ToLower(strings.TrimSpace(user.Email))
+	ipKey := "ip:" + ip
+
+	until, err := s.repo.LoginLockedUntil(ctx, emailKey, ipKey)
+	if err != nil {
+		log.Error(ctx, "failed to check login lockout", zap.Error(err))
+	}
+	if !until.IsZero() {
+		s.recordLogin(ctx, user.Email, ip, false, loginReasonLocked)
+		return status.Error(codes.ResourceExhausted, "too many failed password attempts, try again later")
+	}
+
+	err = s.repo.CheckPassword(ctx, userID, password)
+	if errors.Is(err, repository.ErrInvalidCredentials) {
+		reason := loginReasonInvalid
+		if s.registerLoginFailure(ctx, emailKey, s.opts.Login.MaxFailures) ||
+			s.registerLoginFailure(ctx, ipKey, s.opts.Login.MaxIPFailures) {
+			reason = loginReasonLockedNow
+		}
+		s.recordLogin(ctx, user.Email, ip, false, reason)
+		return status.Error(codes.PermissionDenied, "invalid password")
+	}
+	if err != nil {
+		return userError(err, "failed to check password")
+	}
+	if err := s.repo.ResetLoginFailures(ctx, emailKey); err != nil {
+		log.Error(ctx, "failed to reset login failures", zap.Error(err))
+	}
+	return nil
+}
+
+// registerLoginFailure учитывает ошибку по ключу и сообщает, была ли выставлена блокировка
+func (s *ServerAPI) registerLoginFailure(ctx context.Context, key string, limit int) bool {
+	log := logger.GetLoggerFromCtx(ctx)
+	failures, err := s.repo.RegisterLoginFailure(ctx, key, s.opts.Login.Window)
+	if err != nil {
+		log.Error(ctx, "failed to register login failure", zap.Error(err))
+		return false
+	}
+	d := s.opts.Login.lockout(failures, limit)
+	if d == 0 {
+		return false
+	}
+	if err := s.repo.LockLogin(ctx, key, time.Now().Add(d)); err != nil {
+		log.Error(ctx, "failed to lock login", zap.Error(err))
+		return false
+	}
+	log.Info(ctx, "login locked", zap.String("key", key), zap.Int("failures", failures), zap.Duration("for", d))
+	return true
+}
+
+// recordLogin пишет попытку в журнал; ошибка журнала не должна ломать вход
+func (s *ServerAPI) recordLogin(ctx context.Context, email, ip string, success bool, reason string) {
+	if err := s.repo.RecordLoginAttempt(ctx, email, ip, success, reason); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to record login attempt", zap.Error(err))
+	}
+}
+
+// clientIP берёт IP клиента из метаданных, если их выставил gateway с верным секретом,
+// иначе — адрес соединения. Без GatewaySecret метаданным не верим вовсе.
+func (s *ServerAPI) clientIP(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok && s.opts.GatewaySecret != "" {
+		secret, ip := md.Get(GatewaySecretMetadataKey), md.Get(ClientIPMetadataKey)
+		if len(secret) > 0 && subtle.ConstantTimeCompare([]byte(secret[0]), []byte(s.opts.GatewaySecret)) == 1 &&
+			len(ip) > 0 && ip[0] != "" {
+			return ip[0]
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		if host, _, err := net.SplitHostPort(p.Addr.String()); err == nil {
+			return host
+		}
+		return p.Addr.String()
+	}
+	return ""
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	paymentpb "we_ride/internal/services/payment_service/pb"
+	"we_ride/internal/services/user_service/internal/models"
+	"we_ride/internal/services/user_service/internal/repository"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+const (
+	maxNameLength      = 100
+	maxAvatarURLLength = 2048
+)
+
+// GetProfile возвращает профиль вызывающего пользователя
+func (s *ServerAPI) GetProfile(ctx context.Context, _ *pb.GetProfileRequest) (*pb.GetProfileResponse, error) {
+	userID, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, userError(err, "failed to get profile")
+	}
+	return &pb.GetProfileResponse{Profile: toProfile(user)}, nil
+}
+
+// UpdateProfile меняет имя, аватар и пол; незаданные поля остаются прежними
+func (s *ServerAPI) UpdateProfile(ctx context.Context, req *pb.UpdateProfileRequest) (*pb.UpdateProfileResponse, error) {
+	userID, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	upd := models.ProfileUpdate{Gender: req.Gender}
+	if req.FirstName != nil {
+		name := strings.TrimSpace(req.GetFirstName())
+		if name == "" || len(name) > maxNameLength {
+			return nil, status.Errorf(codes.InvalidArgument, "first name must be 1-%d characters", maxNameLength)
+		}
+		upd.FirstName = &name
+	}
+	if req.LastName != nil {
+		name := strings.TrimSpace(req.GetLastName())
+		if name == "" || len(name) > maxNameLength {
+			return nil, status.Errorf(codes.InvalidArgument, "last name must be 1-%d characters", maxNameLength)
+		}
+		upd.LastName = &name
+	}
+	if req.AvatarUrl != nil {
+		if err := validateAvatarURL(req.GetAvatarUrl()); err != nil {
+			return nil, err
+		}
+		upd.AvatarURL = req.AvatarUrl
+	}
+	if req.Gender != nil && req.GetGender() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "gender is invalid")
+	}
+
+	user, err := s.repo.UpdateProfile(ctx, userID, upd)
+	if err != nil {
+		return nil, userError(err, "failed to update profile")
+	}
+	return &pb.UpdateProfileResponse{Profile: toProfile(user)}, nil
+}
+
+// ChangePassword меняет пароль после проверки текущего
+func (s *ServerAPI) ChangePassword(ctx context.Context, req *pb.ChangePasswordRequest) (*pb.ChangePasswordResponse, error) {
+	userID, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetCurrentPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "current password is required")
+	}
+	if len(req.GetNewPassword()) < minPasswordLength {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at least %d characters", minPasswordLength)
+	}
+	if req.GetNewPassword() == req.GetCurrentPassword() {
+		return nil, status.Error(codes.InvalidArgument, "new password must differ from the current one")
+	}
+
+	if err := s.checkPassword(ctx, userID, req.GetCurrentPassword()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.ChangePassword(ctx, userID, req.GetNewPassword()); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to change password: %v", err)
+	}
+	return &pb.ChangePasswordResponse{Success: true}, nil
+}
+
+// DeleteAccount обезличивает аккаунт вызывающего пользователя.
+// Сначала чистятся платежи: если payment_service недоступен, аккаунт остаётся и запрос можно повторить.
+func (s *ServerAPI) DeleteAccount(ctx context.Context, req *pb.DeleteAccountRequest) (*pb.DeleteAccountResponse, error) {
+	userID, err := callerUUID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password is required")
+	}
+	if err := s.checkPassword(ctx, userID, req.GetPassword()); err != nil {
+		return nil, err
+	}
+
+	if err := s.anonymizePayments(ctx, userID.String()); err != nil {
+		return nil, status.Errorf(codes.Unavailable, "failed to anonymize payments: %v", err)
+	}
+	if err := s.repo.DeleteAccount(ctx, userID); err != nil {
+		return nil, userError(err, "failed to delete account")
+	}
+	return &pb.DeleteAccountResponse{Success: true}, nil
+}
+
+func (s *ServerAPI) anonymizePayments(ctx context.Context, userID string) error {
+	client, closeConn, err := s.payments()
+	if err != nil {
+		return err
+	}
+	defer closeConn()
+
+	_, err = client.AnonymizeUserPayments(ctx, &paymentpb.AnonymizeUserPaymentsRequest{UserId: userID})
+	return err
+}
+
+func (s *ServerAPI) payments() (client paymentpb.PaymentServiceClient, closeConn func(), err error) {
+	if s.paymentClient != nil {
+		return s.paymentClient, func() {}, nil
+	}
+	conn, err := grpc.NewClient(s.opts.PaymentServiceAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(identity.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return paymentpb.NewPaymentServiceClient(conn), func() { _ = conn.Close() }, nil
+}
+
+// SetUserRole назначает роль пользователю; доступно только admin.
+// Новая роль попадёт в токен при следующем входе пользователя.
+func (s *ServerAPI) SetUserRole(ctx context.Context, req *pb.SetUserRoleRequest) (*pb.SetUserRoleResponse, error) {
+	if _, err := identity.RequireRole(ctx, identity.RoleAdmin); err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(req.GetUserId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	if !identity.ValidRole(req.GetRole()) {
+		return nil, status.Error(codes.InvalidArgument, "unknown role")
+	}
+	user, err := s.repo.SetRole(ctx, userID, req.GetRole())
+	if err != nil {
+		return nil, userError(err, "failed to set role")
+	}
+	return &pb.SetUserRoleResponse{Profile: toProfile(user)}, nil
+}
+
+func callerUUID(ctx context.Context) (uuid.UUID, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	userID, err := uuid.Parse(caller.UserID)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	return userID, nil
+}
+
+// userError переводит ошибки репозитория в gRPC-статусы
+func userError(err error, msg string) error {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		return status.Error(codes.NotFound, "user not found")
+	case errors.Is(err, repository.ErrInvalidCredentials):
+		return status.Error(codes.PermissionDenied, "invalid password")
+	default:
+		return status.Errorf(codes.Internal, "%s: %v", msg, err)
+	}
+}
+
+func validateAvatarURL(raw string) error {
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || len(raw) > maxAvatarURLLength {
+		return status.Error(codes.InvalidArgument, "avatar_url must be an http(s) URL")
+	}
+	return nil
+}
+
+func toProfile(u models.User) *pb.Profile {
+	p := &pb.Profile{
+		UserId:    u.UserID.String(),
+		Email:     u.Email,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+		AvatarUrl: u.AvatarURL,
+		Gender:    u.Gender,
+		Rating:    u.Rating,
+		Verified:  u.Verified,
+		Role:      u.Role,
+		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+	}
+	if u.LastActiveAt != nil {
+		p.LastActiveAt = u.LastActiveAt.Format(time.RFC3339)
+	}
+	if u.BlockedAt != nil {
+		p.BlockedAt = u.BlockedAt.Format(time.RFC3339)
+	}
+	if u.DeletedAt != nil {
+		p.DeletedAt = u.DeletedAt.Format(time.RFC3339)
+	}
+	return p
+}
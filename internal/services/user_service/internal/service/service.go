@@ -2,37 +2,87 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 
-	"we_ride/internal/services/user_service/internal/repository"
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	paymentpb "we_ride/internal/services/payment_service/pb"
+	"we_ride/internal/services/user_service/internal/mailer"
+	"we_ride/internal/services/user_service/internal/models"
 	pb "we_ride/internal/services/user_service/protoc/gen/go"
 )
 
+// PublicMethods — методы, доступные без access token
+var PublicMethods = []string{
+	pb.Auth_Register_FullMethodName,
+	pb.Auth_Login_FullMethodName,
+	pb.Auth_VerifyEmail_FullMethodName,
+	pb.Auth_RequestPasswordReset_FullMethodName,
+	pb.Auth_ResetPassword_FullMethodName,
+}
+
+// Options — настройки ServerAPI
+type Options struct {
+	// BaseURL — адрес фронтенда, ссылки в письмах ведут на BaseURL/verify-email и BaseURL/reset-password
+	BaseURL        string
+	VerifyTokenTTL time.Duration
+	ResetTokenTTL  time.Duration
+
+	Login LoginPolicy
+	// GatewaySecret — секрет gateway; только с ним принимается IP клиента из метаданных x-real-ip
+	GatewaySecret string
+
+	// PaymentServiceAddr — payment_service, в котором обезличиваются платежи удалённого аккаунта
+	PaymentServiceAddr string
+}
+
+// Store — хранилище пользователей, которым пользуется ServerAPI; реализуется *repository.Repository
+type Store interface {
+	SaveUser(ctx context.Context, email, password, firstName, lastName string, gender int64, role string) (string, error)
+	LoginUser(ctx context.Context, email, password string) (string, error)
+	GetUserRoutes(ctx context.Context, userID uuid.UUID, roomIDs []uuid.UUID) ([]*pb.Route, error)
+	SaveRoute(ctx context.Context, roomID, driverID, startPoint, endPoint string, distance, totalPrice float64, passengerIDs []string) (string, error)
+	GetUserByEmail(ctx context.Context, email string) (models.User, error)
+	GetUserByID(ctx context.Context, userID uuid.UUID) (models.User, error)
+
+	CreateOneTimeToken(ctx context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error)
+	VerifyEmail(ctx context.Context, token string) (uuid.UUID, error)
+	ResetPassword(ctx context.Context, token, newPassword string) error
+
+	LoginLockedUntil(ctx context.Context, keys ...string) (time.Time, error)
+	RegisterLoginFailure(ctx context.Context, key string, window time.Duration) (int, error)
+	LockLogin(ctx context.Context, key string, until time.Time) error
+	ResetLoginFailures(ctx context.Context, key string) error
+	RecordLoginAttempt(ctx context.Context, email, ip string, success bool, reason string) error
+
+	UpdateProfile(ctx context.Context, userID uuid.UUID, upd models.ProfileUpdate) (models.User, error)
+	CheckPassword(ctx context.Context, userID uuid.UUID, password string) error
+	ChangePassword(ctx context.Context, userID uuid.UUID, newPassword string) error
+	DeleteAccount(ctx context.Context, userID uuid.UUID) error
+
+	SetRole(ctx context.Context, userID uuid.UUID, role string) (models.User, error)
+	SearchUsers(ctx context.Context, f models.UserFilter) ([]models.User, error)
+	BlockUser(ctx context.Context, userID uuid.UUID, blocked bool, reason string) (models.User, error)
+	RecordAuditEvent(ctx context.Context, e models.AuditEvent) (int64, error)
+	ListAuditEvents(ctx context.Context, f models.AuditFilter) ([]models.AuditEvent, error)
+}
+
 type ServerAPI struct {
 	pb.UnimplementedAuthServer
-	repo repository.Repository
-}
+	repo Store
+	mail mailer.Mailer
+	opts Options
 
-func New(repo repository.Repository) *ServerAPI {
-	return &ServerAPI{repo: repo}
+	paymentClient paymentpb.PaymentServiceClient // подменяется в тестах
 }
 
-func (s *ServerAPI) Login(ctx context.Context, req *pb.LoginRequest) (*pb.LoginResponse, error) {
-	if req.GetEmail() == "" {
-		return nil, status.Error(codes.InvalidArgument, "Email is required")
-	}
-	if req.GetPassword() == "" {
-		return nil, status.Error(codes.InvalidArgument, "Password is required")
-	}
-	token, err := s.repo.LoginUser(ctx, req.GetEmail(), req.GetPassword())
-	if err != nil {
-		return nil, status.Error(codes.Unauthenticated, err.Error())
-	}
-	return &pb.LoginResponse{Token: token}, nil
+func New(repo Store, mail mailer.Mailer, opts Options) *ServerAPI {
+	return &ServerAPI{repo: repo, mail: mail, opts: opts}
 }
 
 func (s *ServerAPI) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.RegisterResponse, error) {
@@ -51,28 +101,43 @@ func (s *ServerAPI) Register(ctx context.Context, req *pb.RegisterRequest) (*pb.
 	if req.GetGender() == 0 {
 		return nil, status.Error(codes.InvalidArgument, "Gender is required")
 	}
-	userID, err := s.repo.SaveUser(ctx, req.GetEmail(), req.GetPassword(), req.GetFirstName(), req.GetLastName(), req.GetGender())
+	// Регистрируются только пассажиры: роли driver и admin выдаёт admin через SetUserRole
+	if role := req.GetRole(); role != "" && role != identity.RoleRider {
+		return nil, status.Error(codes.InvalidArgument, "only riders can register, driver role is granted by an admin")
+	}
+	userID, err := s.repo.SaveUser(ctx, req.GetEmail(), req.GetPassword(), req.GetFirstName(), req.GetLastName(), req.GetGender(), identity.RoleRider)
 	if err != nil {
 		return nil, status.Error(codes.AlreadyExists, err.Error())
 	}
+
+	// Письмо с подтверждением не должно ломать регистрацию: его можно запросить повторно
+	user := models.User{UserID: uuid.MustParse(userID), Email: req.GetEmail(), FirstName: req.GetFirstName()}
+	if err := s.sendVerification(ctx, user); err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to send verification email", zap.Error(err))
+	}
 	return &pb.RegisterResponse{UserId: userID}, nil
 }
 
 func (s *ServerAPI) HistoryOfRoutes(ctx context.Context, req *pb.HistoryOfRoutesRequest) (*pb.HistoryOfRoutesResponse, error) {
-	md, ok := metadata.FromIncomingContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Unauthenticated, "metadata is missing")
-	}
-	userIDs := md.Get("user_id")
-	if len(userIDs) == 0 {
-		return nil, status.Error(codes.Unauthenticated, "user_id not found in metadata")
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
 	}
-	userID, err := uuid.Parse(userIDs[0])
+	userID, err := uuid.Parse(caller.UserID)
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
 	}
 
-	routes, err := s.repo.GetUserRoutes(ctx, userID)
+	roomIDs := make([]uuid.UUID, 0, len(req.GetRoomIds()))
+	for _, id := range req.GetRoomIds() {
+		roomID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid room_id format")
+		}
+		roomIDs = append(roomIDs, roomID)
+	}
+
+	routes, err := s.repo.GetUserRoutes(ctx, userID, roomIDs)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "failed to get routes: %v", err)
 	}
@@ -80,12 +145,14 @@ func (s *ServerAPI) HistoryOfRoutes(ctx context.Context, req *pb.HistoryOfRoutes
 	resp := &pb.HistoryOfRoutesResponse{}
 	for _, route := range routes {
 		resp.Routes = append(resp.Routes, &pb.Route{
-			RouteId:    route.GetRouteId(),
-			DriverId:   route.GetDriverId(),
-			TotalPrice: route.GetTotalPrice(),
-			StartPoint: route.GetStartPoint(),
-			EndPoint:   route.GetEndPoint(),
-			Distance:   route.GetDistance(),
+			RouteId:     route.GetRouteId(),
+			DriverId:    route.GetDriverId(),
+			TotalPrice:  route.GetTotalPrice(),
+			StartPoint:  route.GetStartPoint(),
+			EndPoint:    route.GetEndPoint(),
+			Distance:    route.GetDistance(),
+			RoomId:      route.GetRoomId(),
+			CompletedAt: route.GetCompletedAt(),
 		})
 	}
 	return resp, nil
@@ -102,6 +169,10 @@ func (s *ServerAPI) SaveRoute(ctx context.Context, req *pb.SaveRouteRequest) (*p
 	if len(req.GetPassengerIds()) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "passenger_ids must not be empty")
 	}
+	// room_service вызывает SaveRoute от имени водителя, завершившего поездку
+	if _, err := identity.Authorize(ctx, req.GetDriverId()); err != nil {
+		return nil, err
+	}
 
 	routeID, err := s.repo.SaveRoute(ctx,
 		req.GetRoomId(),
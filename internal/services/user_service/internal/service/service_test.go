@@ -0,0 +1,684 @@
+package service
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	paymentpb "we_ride/internal/services/payment_service/pb"
+	"we_ride/internal/services/user_service/internal/mailer"
+	"we_ride/internal/services/user_service/internal/models"
+	"we_ride/internal/services/user_service/internal/repository"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+func TestLoginPolicyLockout(t *testing.T) {
+	p := LoginPolicy{BaseLockout: time.Minute, MaxLockout: 10 * time.Minute}
+
+	cases := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 4, want: 0},
+		{failures: 5, want: time.Minute},
+		{failures: 6, want: 2 * time.Minute},
+		{failures: 8, want: 8 * time.Minute},
+		{failures: 9, want: 10 * time.Minute},
+		{failures: 50, want: 10 * time.Minute},
+	}
+	for _, c := range cases {
+		if got := p.lockout(c.failures, 5); got != c.want {
+			t.Errorf("lockout(%d) = %v, want %v", c.failures, got, c.want)
+		}
+	}
+	if got := p.lockout(100, 0); got != 0 {
+		t.Errorf("zero limit must disable lockout, got %v", got)
+	}
+}
+
+func TestClientIPTrustsOnlyGateway(t *testing.T) {
+	srv := &ServerAPI{opts: Options{GatewaySecret: "secret"}}
+	base := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.5"), Port: 4242}})
+	withMD := func(kv ...string) context.Context {
+		return metadata.NewIncomingContext(base, metadata.Pairs(kv...))
+	}
+
+	cases := []struct {
+		name string
+		ctx  context.Context
+		want string
+	}{
+		{"no metadata", base, "10.0.0.5"},
+		{"ip without secret", withMD(ClientIPMetadataKey, "1.2.3.4"), "10.0.0.5"},
+		{"wrong secret", withMD(ClientIPMetadataKey, "1.2.3.4", GatewaySecretMetadataKey, "guess"), "10.0.0.5"},
+		{"gateway", withMD(ClientIPMetadataKey, "1.2.3.4", GatewaySecretMetadataKey, "secret"), "1.2.3.4"},
+	}
+	for _, c := range cases {
+		if got := srv.clientIP(c.ctx); got != c.want {
+			t.Errorf("%s: clientIP = %q, want %q", c.name, got, c.want)
+		}
+	}
+
+	// Без настроенного секрета метаданным не верим, даже если секрет пустой и у вызывающего
+	open := &ServerAPI{}
+	if got := open.clientIP(withMD(ClientIPMetadataKey, "1.2.3.4", GatewaySecretMetadataKey, "")); got != "10.0.0.5" {
+		t.Errorf("no gateway secret configured: clientIP = %q, want peer address", got)
+	}
+}
+
+func TestValidateAvatarURL(t *testing.T) {
+	for _, ok := range []string{"", "https://cdn.weride.app/a.png", "http://localhost/a.png"} {
+		if err := validateAvatarURL(ok); err != nil {
+			t.Errorf("validateAvatarURL(%q) = %v, want nil", ok, err)
+		}
+	}
+	for _, bad := range []string{"img", "javascript:alert(1)", "ftp://host/a.png", "https:///a.png"} {
+		if err := validateAvatarURL(bad); err == nil {
+			t.Errorf("validateAvatarURL(%q) = nil, want error", bad)
+		}
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	from, to, err := parseRange("2026-01-01T00:00:00Z", "")
+	if err != nil || from.IsZero() || !to.IsZero() {
+		t.Fatalf("open range: from=%v to=%v err=%v", from, to, err)
+	}
+	if _, _, err := parseRange("2026-01-01", ""); err == nil {
+		t.Error("expected error for non-RFC3339 date")
+	}
+	if _, _, err := parseRange("2026-02-01T00:00:00Z", "2026-01-01T00:00:00Z"); err == nil {
+		t.Error("expected error for reversed range")
+	}
+	if got := searchLimit(0); got != defaultSearchLimit {
+		t.Errorf("searchLimit(0) = %d", got)
+	}
+	if got := searchLimit(10000); got != maxSearchLimit {
+		t.Errorf("searchLimit(10000) = %d", got)
+	}
+}
+
+// fakeStore — хранилище в памяти; токены, как и в базе, лежат под своим хэшем.
+// Методы, которые тесты не вызывают, достаются от nil-интерфейса и паникуют.
+type fakeStore struct {
+	Store
+
+	mu        sync.Mutex
+	now       time.Time
+	users     map[uuid.UUID]*models.User
+	passwords map[uuid.UUID]string
+	tokens    map[string]*fakeToken
+	failures  map[string]int
+	locked    map[string]time.Time
+	attempts  []string // причины из журнала попыток входа
+}
+
+type fakeToken struct {
+	userID    uuid.UUID
+	purpose   string
+	expiresAt time.Time
+	used      bool
+}
+
+func newFakeStore() *fakeStore {
+	return &fakeStore{
+		now:       time.Now(),
+		users:     map[uuid.UUID]*models.User{},
+		passwords: map[uuid.UUID]string{},
+		tokens:    map[string]*fakeToken{},
+		failures:  map[string]int{},
+		locked:    map[string]time.Time{},
+	}
+}
+
+func (f *fakeStore) addUser(email, password string) models.User {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u := &models.User{UserID: uuid.New(), Email: email, FirstName: "Test", Role: identity.RoleRider}
+	f.users[u.UserID] = u
+	f.passwords[u.UserID] = password
+	return *u
+}
+
+func (f *fakeStore) SaveUser(_ context.Context, email, password, firstName, lastName string, gender int64, role string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u := &models.User{UserID: uuid.New(), Email: email, FirstName: firstName, LastName: lastName, Gender: gender, Role: role}
+	f.users[u.UserID] = u
+	f.passwords[u.UserID] = password
+	return u.UserID.String(), nil
+}
+
+func (f *fakeStore) GetUserByEmail(_ context.Context, email string) (models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, u := range f.users {
+		if u.Email == email && u.DeletedAt == nil {
+			return *u, nil
+		}
+	}
+	return models.User{}, repository.ErrUserNotFound
+}
+
+func (f *fakeStore) GetUserByID(_ context.Context, userID uuid.UUID) (models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[userID]
+	if !ok {
+		return models.User{}, repository.ErrUserNotFound
+	}
+	return *u, nil
+}
+
+func (f *fakeStore) CreateOneTimeToken(_ context.Context, userID uuid.UUID, purpose string, ttl time.Duration) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	token := uuid.NewString()
+	f.tokens[string(repository.HashToken(token))] = &fakeToken{userID: userID, purpose: purpose, expiresAt: f.now.Add(ttl)}
+	return token, nil
+}
+
+func (f *fakeStore) consume(token, purpose string) (uuid.UUID, error) {
+	t, ok := f.tokens[string(repository.HashToken(token))]
+	if !ok || t.purpose != purpose || t.used || !f.now.Before(t.expiresAt) {
+		return uuid.Nil, repository.ErrInvalidToken
+	}
+	t.used = true
+	return t.userID, nil
+}
+
+func (f *fakeStore) VerifyEmail(_ context.Context, token string) (uuid.UUID, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userID, err := f.consume(token, repository.TokenPurposeVerifyEmail)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	f.users[userID].Verified = true
+	return userID, nil
+}
+
+func (f *fakeStore) ResetPassword(_ context.Context, token, newPassword string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	userID, err := f.consume(token, repository.TokenPurposeResetPassword)
+	if err != nil {
+		return err
+	}
+	f.passwords[userID] = newPassword
+	for _, t := range f.tokens {
+		if t.userID == userID && t.purpose == repository.TokenPurposeResetPassword {
+			t.used = true
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) LoginUser(_ context.Context, email, password string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for id, u := range f.users {
+		if u.Email == email && f.passwords[id] == password {
+			return "token-" + id.String(), nil
+		}
+	}
+	return "", repository.ErrInvalidCredentials
+}
+
+func (f *fakeStore) LoginLockedUntil(_ context.Context, keys ...string) (time.Time, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var until time.Time
+	for _, k := range keys {
+		if t := f.locked[k]; t.After(time.Now()) && t.After(until) {
+			until = t
+		}
+	}
+	return until, nil
+}
+
+func (f *fakeStore) RegisterLoginFailure(_ context.Context, key string, _ time.Duration) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[key]++
+	return f.failures[key], nil
+}
+
+func (f *fakeStore) LockLogin(_ context.Context, key string, until time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.locked[key] = until
+	return nil
+}
+
+func (f *fakeStore) ResetLoginFailures(_ context.Context, key string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.failures, key)
+	return nil
+}
+
+func (f *fakeStore) RecordLoginAttempt(_ context.Context, _, _ string, _ bool, reason string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.attempts = append(f.attempts, reason)
+	return nil
+}
+
+func (f *fakeStore) UpdateProfile(_ context.Context, userID uuid.UUID, upd models.ProfileUpdate) (models.User, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[userID]
+	if !ok || u.DeletedAt != nil {
+		return models.User{}, repository.ErrUserNotFound
+	}
+	if upd.FirstName != nil {
+		u.FirstName = *upd.FirstName
+	}
+	if upd.LastName != nil {
+		u.LastName = *upd.LastName
+	}
+	if upd.AvatarURL != nil {
+		u.AvatarURL = *upd.AvatarURL
+	}
+	if upd.Gender != nil {
+		u.Gender = *upd.Gender
+	}
+	return *u, nil
+}
+
+func (f *fakeStore) CheckPassword(_ context.Context, userID uuid.UUID, password string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.users[userID]; !ok {
+		return repository.ErrUserNotFound
+	}
+	if f.passwords[userID] != password {
+		return repository.ErrInvalidCredentials
+	}
+	return nil
+}
+
+func (f *fakeStore) ChangePassword(_ context.Context, userID uuid.UUID, newPassword string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.passwords[userID] = newPassword
+	return nil
+}
+
+func (f *fakeStore) DeleteAccount(_ context.Context, userID uuid.UUID) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	u, ok := f.users[userID]
+	if !ok || u.DeletedAt != nil {
+		return repository.ErrUserNotFound
+	}
+	now := f.now
+	u.Email = "deleted+" + userID.String() + "@weride.invalid"
+	u.FirstName, u.LastName, u.AvatarURL = "Удалённый", "пользователь", ""
+	u.Verified = false
+	u.DeletedAt = &now
+	f.passwords[userID] = uuid.NewString()
+	for hash, t := range f.tokens {
+		if t.userID == userID {
+			delete(f.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (f *fakeStore) advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+// fakeMailer запоминает отправленные письма
+type fakeMailer struct {
+	mu   sync.Mutex
+	sent []mailer.Message
+}
+
+func (m *fakeMailer) Send(_ context.Context, msg mailer.Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+var tokenInLink = regexp.MustCompile(`\?token=(\S+)`)
+
+// lastToken достаёт токен из ссылки в последнем письме
+func (m *fakeMailer) lastToken(t *testing.T) string {
+	t.Helper()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		t.Fatal("no mail sent")
+	}
+	match := tokenInLink.FindStringSubmatch(m.sent[len(m.sent)-1].Body)
+	if match == nil {
+		t.Fatalf("no token link in mail: %q", m.sent[len(m.sent)-1].Body)
+	}
+	token, err := url.QueryUnescape(match[1])
+	if err != nil {
+		t.Fatalf("unescape token: %v", err)
+	}
+	return token
+}
+
+// fakePayments — payment_service, в котором обезличиваются платежи удаляемого аккаунта
+type fakePayments struct {
+	paymentpb.PaymentServiceClient
+
+	err        error
+	anonymized []string
+}
+
+func (p *fakePayments) AnonymizeUserPayments(_ context.Context, in *paymentpb.AnonymizeUserPaymentsRequest, _ ...grpc.CallOption) (*paymentpb.AnonymizeUserPaymentsResponse, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.anonymized = append(p.anonymized, in.GetUserId())
+	return &paymentpb.AnonymizeUserPaymentsResponse{}, nil
+}
+
+func newTestServer(t *testing.T) (*ServerAPI, *fakeStore, *fakeMailer) {
+	t.Helper()
+	store, mail := newFakeStore(), &fakeMailer{}
+	srv := New(store, mail, Options{
+		BaseURL:        "https://weride.test",
+		VerifyTokenTTL: 24 * time.Hour,
+		ResetTokenTTL:  time.Hour,
+		Login:          LoginPolicy{MaxFailures: 3, MaxIPFailures: 100, Window: time.Hour, BaseLockout: time.Minute, MaxLockout: time.Hour},
+	})
+	srv.paymentClient = &fakePayments{}
+	return srv, store, mail
+}
+
+func loggerCtx(t *testing.T) context.Context {
+	t.Helper()
+	ctx, err := logger.New(context.Background())
+	if err != nil {
+		t.Fatalf("logger: %v", err)
+	}
+	return ctx
+}
+
+func asUser(t *testing.T, u models.User) context.Context {
+	t.Helper()
+	return identity.WithIdentity(loggerCtx(t), identity.Identity{UserID: u.UserID.String(), Email: u.Email, Role: u.Role})
+}
+
+func TestVerifyEmailToken(t *testing.T) {
+	srv, store, mail := newTestServer(t)
+	user := store.addUser("rider@weride.test", "password1")
+	ctx := asUser(t, user)
+
+	if resp, err := srv.ResendVerification(ctx, &pb.ResendVerificationRequest{}); err != nil || !resp.Sent {
+		t.Fatalf("ResendVerification = %v, %v", resp, err)
+	}
+	token := mail.lastToken(t)
+
+	// В хранилище только хэш: открытый токен из письма напрямую не находится
+	if _, ok := store.tokens[token]; ok {
+		t.Fatal("raw token must not be stored")
+	}
+	if _, ok := store.tokens[string(repository.HashToken(token))]; !ok {
+		t.Fatal("token must be stored under its hash")
+	}
+
+	if _, err := srv.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: token}); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	if u, _ := store.GetUserByID(ctx, user.UserID); !u.Verified {
+		t.Fatal("email must be verified")
+	}
+	// Токен одноразовый
+	if _, err := srv.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: token}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("reused token: %v, want InvalidArgument", err)
+	}
+	// Подтверждённому пользователю письмо больше не отправляется
+	if resp, err := srv.ResendVerification(asUser(t, user), &pb.ResendVerificationRequest{}); err != nil || resp.Sent {
+		t.Fatalf("ResendVerification for verified user = %v, %v", resp, err)
+	}
+
+	other := store.addUser("other@weride.test", "password1")
+	if _, err := srv.ResendVerification(asUser(t, other), &pb.ResendVerificationRequest{}); err != nil {
+		t.Fatalf("ResendVerification: %v", err)
+	}
+	expired := mail.lastToken(t)
+	store.advance(25 * time.Hour)
+	if _, err := srv.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: expired}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expired token: %v, want InvalidArgument", err)
+	}
+	if u, _ := store.GetUserByID(ctx, other.UserID); u.Verified {
+		t.Fatal("expired token must not verify email")
+	}
+
+	for _, bad := range []string{"", "unknown"} {
+		if _, err := srv.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: bad}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("VerifyEmail(%q) = %v, want InvalidArgument", bad, err)
+		}
+	}
+}
+
+func TestResetPasswordToken(t *testing.T) {
+	srv, store, mail := newTestServer(t)
+	user := store.addUser("rider@weride.test", "password1")
+	ctx := loggerCtx(t)
+
+	// Ответ для незарегистрированного email такой же, но письмо не уходит
+	if _, err := srv.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: "nobody@weride.test"}); err != nil {
+		t.Fatalf("RequestPasswordReset unknown email: %v", err)
+	}
+	if len(mail.sent) != 0 {
+		t.Fatalf("mail sent to unknown email: %v", mail.sent)
+	}
+
+	if _, err := srv.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	first := mail.lastToken(t)
+	if _, err := srv.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	second := mail.lastToken(t)
+
+	// Токен сброса не подтверждает email
+	if _, err := srv.VerifyEmail(ctx, &pb.VerifyEmailRequest{Token: first}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("reset token used for VerifyEmail: %v, want InvalidArgument", err)
+	}
+	if _, err := srv.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: first, NewPassword: "short"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("short password: %v, want InvalidArgument", err)
+	}
+	if _, err := srv.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: first, NewPassword: "new-password"}); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+	if got := store.passwords[user.UserID]; got != "new-password" {
+		t.Fatalf("password = %q, want new-password", got)
+	}
+	// Использованный токен и остальные токены сброса больше не действуют
+	for _, token := range []string{first, second} {
+		if _, err := srv.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: token, NewPassword: "another-password"}); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ResetPassword after reset: %v, want InvalidArgument", err)
+		}
+	}
+
+	if _, err := srv.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	expired := mail.lastToken(t)
+	store.advance(time.Hour)
+	if _, err := srv.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: expired, NewPassword: "another-password"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expired token: %v, want InvalidArgument", err)
+	}
+	if got := store.passwords[user.UserID]; got != "new-password" {
+		t.Fatalf("expired token changed password to %q", got)
+	}
+}
+
+func TestRegisterCreatesRiders(t *testing.T) {
+	srv, store, _ := newTestServer(t)
+	ctx := loggerCtx(t)
+	req := func(email, role string) *pb.RegisterRequest {
+		return &pb.RegisterRequest{Email: email, Password: "password1", FirstName: "Test", LastName: "User", Gender: 1, Role: role}
+	}
+
+	for _, role := range []string{identity.RoleDriver, identity.RoleAdmin, "owner"} {
+		if _, err := srv.Register(ctx, req(role+"@weride.test", role)); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Register as %s: %v, want InvalidArgument", role, err)
+		}
+	}
+	for _, role := range []string{"", identity.RoleRider} {
+		resp, err := srv.Register(ctx, req("rider"+role+"@weride.test", role))
+		if err != nil {
+			t.Fatalf("Register(%q): %v", role, err)
+		}
+		if u, _ := store.GetUserByID(ctx, uuid.MustParse(resp.UserId)); u.Role != identity.RoleRider {
+			t.Errorf("Register(%q) role = %q, want rider", role, u.Role)
+		}
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	srv, store, _ := newTestServer(t)
+	user := store.addUser("rider@weride.test", "password1")
+	ctx := asUser(t, user)
+
+	first, avatar, gender := "  Анна ", "https://cdn.weride.test/a.png", int64(2)
+	resp, err := srv.UpdateProfile(ctx, &pb.UpdateProfileRequest{FirstName: &first, AvatarUrl: &avatar, Gender: &gender})
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if p := resp.Profile; p.FirstName != "Анна" || p.AvatarUrl != avatar || p.Gender != 2 || p.Email != user.Email {
+		t.Fatalf("profile = %+v", p)
+	}
+
+	// Незаданные поля не меняются
+	last := "Иванова"
+	resp, err = srv.UpdateProfile(ctx, &pb.UpdateProfileRequest{LastName: &last})
+	if err != nil {
+		t.Fatalf("UpdateProfile: %v", err)
+	}
+	if p := resp.Profile; p.FirstName != "Анна" || p.LastName != last || p.AvatarUrl != avatar {
+		t.Fatalf("partial update changed other fields: %+v", p)
+	}
+
+	blank, badAvatar, badGender := " ", "javascript:alert(1)", int64(0)
+	for name, req := range map[string]*pb.UpdateProfileRequest{
+		"blank name":  {FirstName: &blank},
+		"bad avatar":  {AvatarUrl: &badAvatar},
+		"zero gender": {Gender: &badGender},
+	} {
+		if _, err := srv.UpdateProfile(ctx, req); status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: %v, want InvalidArgument", name, err)
+		}
+	}
+	if u, _ := store.GetUserByID(ctx, user.UserID); u.FirstName != "Анна" || u.Gender != 2 {
+		t.Fatalf("invalid update changed profile: %+v", u)
+	}
+
+	if _, err := srv.UpdateProfile(loggerCtx(t), &pb.UpdateProfileRequest{LastName: &last}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("anonymous UpdateProfile: %v, want Unauthenticated", err)
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	srv, store, _ := newTestServer(t)
+	user := store.addUser("rider@weride.test", "password1")
+	ctx := asUser(t, user)
+
+	if _, err := srv.ChangePassword(ctx, &pb.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "password1"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("same password: %v, want InvalidArgument", err)
+	}
+	if _, err := srv.ChangePassword(ctx, &pb.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "short"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("short password: %v, want InvalidArgument", err)
+	}
+	if _, err := srv.ChangePassword(ctx, &pb.ChangePasswordRequest{CurrentPassword: "password1", NewPassword: "password2"}); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+	if got := store.passwords[user.UserID]; got != "password2" {
+		t.Fatalf("password = %q, want password2", got)
+	}
+
+	// Неверный текущий пароль считается как неудачный вход: после MaxFailures включается блокировка
+	for i := 0; i < 3; i++ {
+		if _, err := srv.ChangePassword(ctx, &pb.ChangePasswordRequest{CurrentPassword: "guess", NewPassword: "password3"}); status.Code(err) != codes.PermissionDenied {
+			t.Fatalf("attempt %d: %v, want PermissionDenied", i+1, err)
+		}
+	}
+	if _, err := srv.ChangePassword(ctx, &pb.ChangePasswordRequest{CurrentPassword: "password2", NewPassword: "password3"}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("locked ChangePassword: %v, want ResourceExhausted", err)
+	}
+	if got := store.passwords[user.UserID]; got != "password2" {
+		t.Fatalf("locked ChangePassword changed password to %q", got)
+	}
+	// Блокировка общая со входом
+	if _, err := srv.Login(loggerCtx(t), &pb.LoginRequest{Email: user.Email, Password: "password2"}); status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Login after password guessing: %v, want ResourceExhausted", err)
+	}
+	want := []string{loginReasonInvalid, loginReasonInvalid, loginReasonLockedNow, loginReasonLocked, loginReasonLocked}
+	if len(store.attempts) != len(want) {
+		t.Fatalf("attempts = %v, want %v", store.attempts, want)
+	}
+	for i := range want {
+		if store.attempts[i] != want[i] {
+			t.Fatalf("attempts = %v, want %v", store.attempts, want)
+		}
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	srv, store, mail := newTestServer(t)
+	payments := srv.paymentClient.(*fakePayments)
+	user := store.addUser("rider@weride.test", "password1")
+	ctx := asUser(t, user)
+	if _, err := srv.RequestPasswordReset(ctx, &pb.RequestPasswordResetRequest{Email: user.Email}); err != nil {
+		t.Fatalf("RequestPasswordReset: %v", err)
+	}
+	resetToken := mail.lastToken(t)
+
+	if _, err := srv.DeleteAccount(ctx, &pb.DeleteAccountRequest{Password: "guess"}); status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("wrong password: %v, want PermissionDenied", err)
+	}
+	// Платежи обезличиваются первыми: если payment_service недоступен, аккаунт остаётся
+	payments.err = status.Error(codes.Unavailable, "down")
+	if _, err := srv.DeleteAccount(ctx, &pb.DeleteAccountRequest{Password: "password1"}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("payments down: %v, want Unavailable", err)
+	}
+	if u, _ := store.GetUserByID(ctx, user.UserID); u.DeletedAt != nil {
+		t.Fatal("account deleted although payments were not anonymized")
+	}
+
+	payments.err = nil
+	if _, err := srv.DeleteAccount(ctx, &pb.DeleteAccountRequest{Password: "password1"}); err != nil {
+		t.Fatalf("DeleteAccount: %v", err)
+	}
+	if len(payments.anonymized) != 1 || payments.anonymized[0] != user.UserID.String() {
+		t.Fatalf("anonymized payments = %v", payments.anonymized)
+	}
+	u, _ := store.GetUserByID(ctx, user.UserID)
+	if u.DeletedAt == nil || u.Email == user.Email || u.FirstName == user.FirstName {
+		t.Fatalf("account not anonymized: %+v", u)
+	}
+	if _, err := srv.Login(loggerCtx(t), &pb.LoginRequest{Email: user.Email, Password: "password1"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("Login to deleted account: %v, want Unauthenticated", err)
+	}
+	if _, err := srv.ResetPassword(ctx, &pb.ResetPasswordRequest{Token: resetToken, NewPassword: "password2"}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("reset token of deleted account: %v, want InvalidArgument", err)
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"we_ride/internal/pkg/identity"
+	"we_ride/internal/pkg/logger"
+	"we_ride/internal/services/user_service/internal/mailer"
+	"we_ride/internal/services/user_service/internal/models"
+	"we_ride/internal/services/user_service/internal/repository"
+	pb "we_ride/internal/services/user_service/protoc/gen/go"
+)
+
+const minPasswordLength = 8
+
+// VerifyEmail подтверждает email по токену из письма
+func (s *ServerAPI) VerifyEmail(ctx context.Context, req *pb.VerifyEmailRequest) (*pb.VerifyEmailResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if _, err := s.repo.VerifyEmail(ctx, req.GetToken()); err != nil {
+		if errors.Is(err, repository.ErrInvalidToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to verify email: %v", err)
+	}
+	return &pb.VerifyEmailResponse{Verified: true}, nil
+}
+
+// ResendVerification повторно отправляет письмо с подтверждением текущему пользователю
+func (s *ServerAPI) ResendVerification(ctx context.Context, _ *pb.ResendVerificationRequest) (*pb.ResendVerificationResponse, error) {
+	caller, err := identity.Caller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := uuid.Parse(caller.UserID)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id format")
+	}
+	user, err := s.repo.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "user not found: %v", err)
+	}
+	if user.Verified {
+		return &pb.ResendVerificationResponse{Sent: false}, nil
+	}
+	if err := s.sendVerification(ctx, user); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to send verification email: %v", err)
+	}
+	return &pb.ResendVerificationResponse{Sent: true}, nil
+}
+
+// RequestPasswordReset отправляет письмо со ссылкой на сброс пароля.
+// Ответ не зависит от того, зарегистрирован ли email.
+func (s *ServerAPI) RequestPasswordReset(ctx context.Context, req *pb.RequestPasswordResetRequest) (*pb.RequestPasswordResetResponse, error) {
+	if req.GetEmail() == "" {
+		return nil, status.Error(codes.InvalidArgument, "Email is required")
+	}
+
+	user, err := s.repo.GetUserByEmail(ctx, req.GetEmail())
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			logger.GetLoggerFromCtx(ctx).Error(ctx, "password reset lookup failed", zap.Error(err))
+		}
+		return &pb.RequestPasswordResetResponse{}, nil
+	}
+
+	token, err := s.repo.CreateOneTimeToken(ctx, user.UserID, repository.TokenPurposeResetPassword, s.opts.ResetTokenTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create reset token: %v", err)
+	}
+	err = s.mail.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "WeRide: сброс пароля",
+		Body: fmt.Sprintf("Чтобы задать новый пароль, перейдите по ссылке:\n%s\n\nСсылка действует %s. Если вы не запрашивали сброс, просто проигнорируйте письмо.",
+			s.link("/reset-password", token), s.opts.ResetTokenTTL),
+	})
+	if err != nil {
+		logger.GetLoggerFromCtx(ctx).Error(ctx, "failed to send password reset email", zap.Error(err))
+	}
+	return &pb.RequestPasswordResetResponse{}, nil
+}
+
+// ResetPassword задаёт новый пароль по токену из письма
+func (s *ServerAPI) ResetPassword(ctx context.Context, req *pb.ResetPasswordRequest) (*pb.ResetPasswordResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+	if len(req.GetNewPassword()) < minPasswordLength {
+		return nil, status.Errorf(codes.InvalidArgument, "password must be at least %d characters", minPasswordLength)
+	}
+	if err := s.repo.ResetPassword(ctx, req.GetToken(), req.GetNewPassword()); err != nil {
+		if errors.Is(err, repository.ErrInvalidToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		return nil, status.Errorf(codes.Internal, "failed to reset password: %v", err)
+	}
+	return &pb.ResetPasswordResponse{Success: true}, nil
+}
+
+func (s *ServerAPI) sendVerification(ctx context.Context, user models.User) error {
+	token, err := s.repo.CreateOneTimeToken(ctx, user.UserID, repository.TokenPurposeVerifyEmail, s.opts.VerifyTokenTTL)
+	if err != nil {
+		return err
+	}
+	return s.mail.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: "WeRide: подтвердите email",
+		Body: fmt.Sprintf("Здравствуйте, %s!\n\nПодтвердите email, перейдя по ссылке:\n%s\n\nСсылка действует %s.",
+			user.FirstName, s.link("/verify-email", token), s.opts.VerifyTokenTTL),
+	})
+}
+
+func (s *ServerAPI) link(path, token string) string {
+	return s.opts.BaseURL + path + "?token=" + url.QueryEscape(token)
+}